@@ -0,0 +1,300 @@
+package jsh
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Codec names the default JSON implementation jsh serializes documents with, see
+// JSON.
+const Codec = "encoding/json"
+
+/*
+Configuration is a snapshot of the settings jsh is enforcing, as reported by
+AdminHandler. It reflects the package level variables and registered resources
+at the time it was taken.
+*/
+type Configuration struct {
+	Version    string                  `json:"version"`
+	Codec      string                  `json:"codec"`
+	BaseURL    string                  `json:"base_url"`
+	Parsing    ParsingConfiguration    `json:"parsing"`
+	Strict     bool                    `json:"strict"`
+	Pagination PaginationConfiguration `json:"pagination"`
+	Responses  ResponseConfiguration   `json:"responses"`
+	Streaming  StreamConfiguration     `json:"streaming"`
+	Hooks      HookConfiguration       `json:"hooks"`
+	Resources  []ResourceConfiguration `json:"resources"`
+}
+
+// ParsingConfiguration reports the DefaultParseOptions.
+type ParsingConfiguration struct {
+	ClientIDs            string `json:"client_ids"`
+	ValidateID           bool   `json:"validate_id"`
+	GenerateID           bool   `json:"generate_id"`
+	MemberNames          string `json:"member_names"`
+	LenientContentType   bool   `json:"lenient_content_type"`
+	ForbidUnknownMembers bool   `json:"forbid_unknown_members"`
+	ForbidDataAndErrors  bool   `json:"forbid_data_and_errors"`
+	RequireDataOrMeta    bool   `json:"require_data_or_meta"`
+	ForbidDuplicates     bool   `json:"forbid_duplicates"`
+	Deduplicate          bool   `json:"deduplicate"`
+	PreserveUnknown      bool   `json:"preserve_unknown_members"`
+	// MaxBodySize and MaxDepth are the effective caps, zero if disabled
+	MaxBodySize int64 `json:"max_body_size"`
+	MaxDepth    int   `json:"max_depth"`
+}
+
+// PaginationConfiguration reports DefaultPageLimit and MaxPageLimit.
+type PaginationConfiguration struct {
+	DefaultLimit int `json:"default_limit"`
+	MaxLimit     int `json:"max_limit"`
+}
+
+// ResponseConfiguration reports the settings applied when sending documents.
+type ResponseConfiguration struct {
+	EmptyRelationships string                    `json:"empty_relationships"`
+	FullLinkage        string                    `json:"full_linkage"`
+	ETags              bool                      `json:"etags"`
+	PrettyJSON         bool                      `json:"pretty_json"`
+	Compression        *Compression              `json:"compression"`
+	CachePolicy        *CachePolicyConfiguration `json:"cache_policy"`
+	ErrorRequestEcho   bool                      `json:"error_request_echo"`
+	CORS               *CORSPolicy               `json:"cors"`
+	Extensions         []string                  `json:"extensions"`
+	Profiles           []string                  `json:"profiles"`
+	Errors             map[string]string         `json:"errors"`
+}
+
+// StreamConfiguration reports the DefaultStreamOptions.
+type StreamConfiguration struct {
+	WriteTimeout  string `json:"write_timeout"`
+	FlushInterval string `json:"flush_interval"`
+	Checksum      bool   `json:"checksum"`
+}
+
+// CachePolicyConfiguration reports a CachePolicy.
+type CachePolicyConfiguration struct {
+	Public  bool   `json:"public"`
+	MaxAge  string `json:"max_age"`
+	NoStore bool   `json:"no_store"`
+}
+
+// configuration reports the policy, nil if it is
+func (p *CachePolicy) configuration() *CachePolicyConfiguration {
+	if p == nil {
+		return nil
+	}
+
+	return &CachePolicyConfiguration{Public: p.Public, MaxAge: p.MaxAge.String(), NoStore: p.NoStore}
+}
+
+// HookConfiguration reports which hooks are set.
+type HookConfiguration struct {
+	Metrics      bool `json:"metrics"`
+	Logging      bool `json:"logging"`
+	IDTranslator bool `json:"id_translator"`
+	Quota        bool `json:"quota"`
+}
+
+// ResourceConfiguration reports the declaration of a registered resource.
+type ResourceConfiguration struct {
+	Type              string                                  `json:"type"`
+	RelationshipLinks bool                                    `json:"relationship_links"`
+	MetaOnly          bool                                    `json:"meta_only"`
+	Computed          []string                                `json:"computed"`
+	Deprecated        []string                                `json:"deprecated"`
+	Relationships     map[string]string                       `json:"relationships"`
+	Includes          []string                                `json:"includes"`
+	Limits            *AttributeLimits                        `json:"limits"`
+	CachePolicies     map[Operation]*CachePolicyConfiguration `json:"cache_policies"`
+	IDTranslator      bool                                    `json:"id_translator"`
+	Stats             *ResourceStats                          `json:"stats"`
+	Quota             bool                                    `json:"quota"`
+	Policy            bool                                    `json:"policy"`
+	ServerManaged     []string                                `json:"server_managed"`
+	Defaults          []string                                `json:"defaults"`
+}
+
+var clientIDModeNames = map[ClientIDMode]string{
+	AllowClientIDs:   "allow",
+	RequireClientIDs: "require",
+	ForbidClientIDs:  "forbid",
+}
+
+var memberNameModeNames = map[MemberNameMode]string{
+	IgnoreMemberNames:  "ignore",
+	LenientMemberNames: "lenient",
+	StrictMemberNames:  "strict",
+}
+
+var emptyRelationshipModeNames = map[EmptyRelationshipMode]string{
+	OmitEmptyToManyLinkage:     "omit_to_many",
+	EmitEmptyLinkage:           "emit",
+	OmitEmptyRelationships:     "omit",
+	EmptyRelationshipLinksOnly: "links_only",
+}
+
+var fullLinkageModeNames = map[FullLinkageMode]string{
+	IgnoreOrphanedIncludes: "ignore",
+	WarnOrphanedIncludes:   "warn",
+	RejectOrphanedIncludes: "reject",
+}
+
+// CurrentConfiguration takes a snapshot of the configuration jsh is enforcing.
+func CurrentConfiguration() *Configuration {
+	options := DefaultParseOptions
+
+	config := &Configuration{
+		Version: JSONAPIVersion,
+		Codec:   JSON.Name(),
+		BaseURL: BaseURL,
+		Parsing: ParsingConfiguration{
+			ClientIDs:            clientIDModeNames[options.ClientIDs],
+			ValidateID:           options.ValidateID != nil,
+			GenerateID:           options.GenerateID != nil,
+			MemberNames:          memberNameModeNames[options.MemberNames],
+			LenientContentType:   options.LenientContentType,
+			ForbidUnknownMembers: options.ForbidUnknownMembers,
+			ForbidDataAndErrors:  options.ForbidDataAndErrors,
+			RequireDataOrMeta:    options.RequireDataOrMeta,
+			ForbidDuplicates:     options.ForbidDuplicates,
+			Deduplicate:          options.Deduplicate,
+			PreserveUnknown:      options.PreserveUnknownMembers,
+			MaxBodySize:          options.maxBodySize(),
+			MaxDepth:             options.maxDepth(),
+		},
+		Strict: options.MemberNames == StrictParseOptions.MemberNames &&
+			options.ForbidUnknownMembers && options.ForbidDataAndErrors &&
+			options.RequireDataOrMeta && options.ForbidDuplicates,
+		Pagination: PaginationConfiguration{
+			DefaultLimit: DefaultPageLimit,
+			MaxLimit:     MaxPageLimit,
+		},
+		Responses: ResponseConfiguration{
+			EmptyRelationships: emptyRelationshipModeNames[EmptyRelationships],
+			FullLinkage:        fullLinkageModeNames[FullLinkage],
+			ETags:              EmitETags,
+			PrettyJSON:         PrettyJSON,
+			Compression:        ResponseCompression,
+			CachePolicy:        DefaultCachePolicy.configuration(),
+			ErrorRequestEcho:   ErrorRequestEcho != nil,
+			CORS:               CORS,
+			Extensions:         sortedNames(supportedExtensions),
+			Profiles:           append([]string{}, Profiles...),
+			Errors: map[string]string{
+				"title":  DefaultErrorTitle,
+				"detail": DefaultErrorDetail,
+			},
+		},
+		Streaming: StreamConfiguration{
+			WriteTimeout:  DefaultStreamOptions.WriteTimeout.String(),
+			FlushInterval: DefaultStreamOptions.FlushInterval.String(),
+			Checksum:      DefaultStreamOptions.Checksum,
+		},
+		Hooks: HookConfiguration{
+			Metrics:      Metrics != nil,
+			Logging:      Logging != nil,
+			IDTranslator: DefaultIDTranslator != nil,
+			Quota:        DefaultQuotaProvider != nil,
+		},
+		Resources: []ResourceConfiguration{},
+	}
+
+	for _, resource := range registeredResources() {
+		config.Resources = append(config.Resources, resource.configuration())
+	}
+
+	return config
+}
+
+// configuration reports the resource's declaration
+func (r *Resource) configuration() ResourceConfiguration {
+	config := ResourceConfiguration{
+		Type:              r.Type,
+		RelationshipLinks: r.RelationshipLinks,
+		MetaOnly:          r.MetaOnly,
+		Computed:          []string{},
+		Deprecated:        []string{},
+		Relationships:     map[string]string{},
+		Includes:          []string{},
+		Limits:            r.Limits,
+		CachePolicies:     map[Operation]*CachePolicyConfiguration{},
+		IDTranslator:      r.IDTranslator != nil,
+		Stats:             r.Stats,
+		Quota:             r.Quota != nil,
+		Policy:            r.Policy != nil,
+		ServerManaged:     []string{},
+		Defaults:          []string{},
+	}
+
+	if r.ServerManaged != nil {
+		config.ServerManaged = append(config.ServerManaged, r.ServerManaged.Attributes...)
+	}
+
+	for name := range r.Defaults {
+		config.Defaults = append(config.Defaults, name)
+	}
+	sort.Strings(config.Defaults)
+
+	for name := range r.Computed {
+		config.Computed = append(config.Computed, name)
+	}
+	sort.Strings(config.Computed)
+
+	for name := range r.Deprecated {
+		config.Deprecated = append(config.Deprecated, name)
+	}
+	sort.Strings(config.Deprecated)
+
+	for name := range r.Includes {
+		config.Includes = append(config.Includes, name)
+	}
+	sort.Strings(config.Includes)
+
+	for operation, policy := range r.CachePolicies {
+		config.CachePolicies[operation] = policy.configuration()
+	}
+
+	for name, declaration := range r.Relationships {
+		config.Relationships[name] = strings.Join(declaration.relatedTypes(), ",")
+	}
+
+	return config
+}
+
+// sortedNames returns the names of a set in order
+func sortedNames(set map[string]bool) []string {
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+/*
+AdminHandler serves the CurrentConfiguration as meta.configuration of a meta
+document, so operators can verify what a deployed service actually enforces:
+
+	mux.Handle("/admin/jsh", requireOperator(jsh.AdminHandler()))
+
+The handler is opt-in and exposes internals such as the registered resource
+declarations, so mount it behind authentication.
+*/
+func AdminHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			Send(w, r, SpecificationError("The configuration can only be fetched with GET"))
+			return
+		}
+
+		document := New()
+		document.Status = http.StatusOK
+		document.Meta = map[string]interface{}{"configuration": CurrentConfiguration()}
+
+		SendDocument(w, r, document)
+	})
+}
@@ -0,0 +1,87 @@
+package jsh
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAdmin(t *testing.T) {
+
+	Convey("Admin Tests", t, func() {
+
+		Register(&Resource{
+			Type: "articles",
+			Computed: map[string]ComputedAttribute{
+				"can_edit": func(r *http.Request, object *Object) (interface{}, *Error) { return true, nil },
+			},
+			Relationships: map[string]RelationshipDeclaration{
+				"author": {Type: "users", Cardinality: ToOne},
+			},
+			Limits:        &AttributeLimits{MaxAttributes: 10},
+			CachePolicies: map[Operation]*CachePolicy{FetchOperation: {MaxAge: time.Minute}},
+		})
+		Reset(func() { Unregister("articles") })
+
+		Convey("->CurrentConfiguration()", func() {
+
+			Convey("should report the default options", func() {
+				config := CurrentConfiguration()
+				So(config.Version, ShouldEqual, JSONAPIVersion)
+				So(config.Codec, ShouldEqual, Codec)
+				So(config.Strict, ShouldBeFalse)
+				So(config.Parsing.ClientIDs, ShouldEqual, "allow")
+				So(config.Pagination.MaxLimit, ShouldEqual, MaxPageLimit)
+				So(config.Responses.Extensions, ShouldResemble, []string{AttributePatchExtension, BulkExtension})
+			})
+
+			Convey("should report strict parsing", func() {
+				defaults := DefaultParseOptions
+				DefaultParseOptions = StrictParseOptions
+				Reset(func() { DefaultParseOptions = defaults })
+
+				config := CurrentConfiguration()
+				So(config.Strict, ShouldBeTrue)
+				So(config.Parsing.MemberNames, ShouldEqual, "strict")
+			})
+
+			Convey("should report registered resources", func() {
+				config := CurrentConfiguration()
+				So(len(config.Resources), ShouldEqual, 1)
+
+				resource := config.Resources[0]
+				So(resource.Type, ShouldEqual, "articles")
+				So(resource.Computed, ShouldResemble, []string{"can_edit"})
+				So(resource.Relationships["author"], ShouldEqual, "users")
+				So(resource.Limits.MaxAttributes, ShouldEqual, 10)
+				So(resource.CachePolicies[FetchOperation].MaxAge, ShouldEqual, "1m0s")
+			})
+		})
+
+		Convey("->AdminHandler()", func() {
+			writer := httptest.NewRecorder()
+
+			Convey("should serve the configuration as meta", func() {
+				AdminHandler().ServeHTTP(writer, httptest.NewRequest("GET", "/admin/jsh", nil))
+				So(writer.Code, ShouldEqual, http.StatusOK)
+
+				doc := struct {
+					Meta struct {
+						Configuration Configuration `json:"configuration"`
+					} `json:"meta"`
+				}{}
+				So(json.Unmarshal(writer.Body.Bytes(), &doc), ShouldBeNil)
+				So(doc.Meta.Configuration.Resources[0].Type, ShouldEqual, "articles")
+			})
+
+			Convey("should only serve GETs", func() {
+				AdminHandler().ServeHTTP(writer, httptest.NewRequest("DELETE", "/admin/jsh", nil))
+				So(writer.Code, ShouldEqual, http.StatusNotAcceptable)
+			})
+		})
+	})
+}
@@ -0,0 +1,145 @@
+package jsh
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+/*
+Attribute unmarshals a single attribute into target without requiring a struct
+for the full set of attributes. If the attribute is missing, or cannot be
+unmarshaled into target, a 422 error pointing at the attribute is returned.
+*/
+func (o *Object) Attribute(key string, target interface{}) *Error {
+	attributes, err := o.attributeMap()
+	if err != nil {
+		return err
+	}
+
+	raw, exists := attributes[key]
+	if !exists {
+		return InputError("Missing attribute", key)
+	}
+
+	jsonErr := json.Unmarshal(raw, target)
+	if jsonErr != nil {
+		return InputError(fmt.Sprintf("Unexpected value: %s", raw), key)
+	}
+
+	return nil
+}
+
+/*
+HasAttributes returns false for meta-only objects, which carry no attributes at
+all (or attributes set to null), such as permission descriptors.
+*/
+func (o *Object) HasAttributes() bool {
+	return hasAttributes(o.Attributes)
+}
+
+// HasAttribute returns true if the attribute is present, even if set to null.
+func (o *Object) HasAttribute(key string) bool {
+	attributes, err := o.attributeMap()
+	if err != nil {
+		return false
+	}
+
+	_, exists := attributes[key]
+	return exists
+}
+
+// AttributeString returns the value of a string attribute.
+func (o *Object) AttributeString(key string) (string, *Error) {
+	var value string
+	err := o.Attribute(key, &value)
+	return value, err
+}
+
+// AttributeInt returns the value of an integer attribute.
+func (o *Object) AttributeInt(key string) (int, *Error) {
+	var value int
+	err := o.Attribute(key, &value)
+	return value, err
+}
+
+// AttributeFloat returns the value of a numeric attribute.
+func (o *Object) AttributeFloat(key string) (float64, *Error) {
+	var value float64
+	err := o.Attribute(key, &value)
+	return value, err
+}
+
+// AttributeBool returns the value of a boolean attribute.
+func (o *Object) AttributeBool(key string) (bool, *Error) {
+	var value bool
+	err := o.Attribute(key, &value)
+	return value, err
+}
+
+// AttributeTime returns the value of an RFC 3339 formatted timestamp attribute.
+func (o *Object) AttributeTime(key string) (time.Time, *Error) {
+	var value time.Time
+	err := o.Attribute(key, &value)
+	return value, err
+}
+
+/*
+SetAttribute marshals value and sets it as a single attribute, leaving all other
+attributes untouched.
+*/
+func (o *Object) SetAttribute(key string, value interface{}) *Error {
+	attributes, err := o.attributeMap()
+	if err != nil {
+		return err
+	}
+
+	if attributes == nil {
+		attributes = map[string]json.RawMessage{}
+	}
+
+	raw, jsonErr := json.Marshal(value)
+	if jsonErr != nil {
+		return ISE(fmt.Sprintf("Error marshaling attribute '%s': %s", key, jsonErr.Error()))
+	}
+
+	attributes[key] = raw
+	return o.marshal(attributes, nil)
+}
+
+// RemoveAttribute deletes a single attribute if it is present.
+func (o *Object) RemoveAttribute(key string) *Error {
+	attributes, err := o.attributeMap()
+	if err != nil {
+		return err
+	}
+
+	if _, exists := attributes[key]; !exists {
+		return nil
+	}
+
+	delete(attributes, key)
+	return o.marshal(attributes, nil)
+}
+
+// attributeMap decodes the top level of the object's attributes
+func (o *Object) attributeMap() (map[string]json.RawMessage, *Error) {
+	attributes := map[string]json.RawMessage{}
+	if !o.HasAttributes() {
+		return attributes, nil
+	}
+
+	err := json.Unmarshal(o.Attributes, &attributes)
+	if err != nil {
+		return nil, ISE(fmt.Sprintf("Unable to decode attributes for '%s': %s", o.Type, err.Error()))
+	}
+
+	return attributes, nil
+}
+
+// hasAttributes returns true if raw holds attributes other than null
+func hasAttributes(raw json.RawMessage) bool {
+	trimmed := bytes.TrimSpace(raw)
+	return len(trimmed) > 0 && !bytes.Equal(trimmed, []byte("null"))
+}
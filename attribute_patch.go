@@ -0,0 +1,463 @@
+package jsh
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// AttributePatchExtension is the name of the extension allowing PATCH
+	// requests to update a resource's attributes with RFC 6902 JSON Patch
+	// operations, rather than sending the attributes in full.
+	AttributePatchExtension = "attribute-patch"
+	// AttributePatchContentType is the Content-Type negotiated for the
+	// attribute patch extension
+	AttributePatchContentType = ContentType + "; ext=" + AttributePatchExtension
+)
+
+// PatchOp is the operation of a JSON Patch operation.
+type PatchOp string
+
+// The operations of RFC 6902
+const (
+	AddOp     PatchOp = "add"
+	RemoveOp  PatchOp = "remove"
+	ReplaceOp PatchOp = "replace"
+	MoveOp    PatchOp = "move"
+	CopyOp    PatchOp = "copy"
+	TestOp    PatchOp = "test"
+)
+
+// PatchOperation is a single JSON Patch operation. Paths are JSON pointers
+// relative to the resource object, and must address its attributes, such as
+// /attributes/address/city.
+type PatchOperation struct {
+	Op   PatchOp `json:"op"`
+	Path string  `json:"path"`
+	// From is the source of move and copy operations
+	From string `json:"from,omitempty"`
+	// Value is the value of add, replace, and test operations
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+/*
+AttributePatch is a JSON Patch document scoped to a resource's attributes, for
+clients updating large nested attributes without sending them in full:
+
+	PATCH /articles/1
+	Content-Type: application/vnd.api+json; ext=attribute-patch
+
+	[
+		{"op": "replace", "path": "/attributes/body/sections/3/title", "value": "Summary"},
+		{"op": "remove", "path": "/attributes/draft"}
+	]
+*/
+type AttributePatch []PatchOperation
+
+/*
+IsAttributePatch returns true if the request negotiated the attribute patch
+extension via its Content-Type header.
+*/
+func IsAttributePatch(r *http.Request) bool {
+	extensions, _ := contentTypeExtensions(r.Header.Get("Content-Type"))
+	for _, extension := range extensions {
+		if extension == AttributePatchExtension {
+			return true
+		}
+	}
+
+	return false
+}
+
+/*
+ParseAttributePatch parses the operations of an attribute patch request, which
+can then be applied to the stored resource:
+
+	patch, err := jsh.ParseAttributePatch(r)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	article := storage.Article(id)
+	err = patch.Apply(article)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+A 415 error is returned if the request didn't negotiate the extension, and only
+PATCH requests may use it. Invalid operations are 422 errors pointing at the
+offending member of the patch document.
+*/
+func ParseAttributePatch(r *http.Request) (AttributePatch, *Error) {
+	if !IsAttributePatch(r) {
+		return nil, &Error{
+			Title:  "Unsupported Media Type",
+			Detail: fmt.Sprintf("Attribute patch requests must use the Content-Type: %s", AttributePatchContentType),
+			Status: http.StatusUnsupportedMediaType,
+		}
+	}
+
+	if r.Method != "PATCH" {
+		return nil, SpecificationError(fmt.Sprintf("The attribute patch extension does not support '%s' requests", r.Method))
+	}
+
+	return NewParser(r).AttributePatch(r.Body)
+}
+
+// AttributePatch parses and validates a JSON Patch document scoped to a
+// resource's attributes.
+func (p *Parser) AttributePatch(payload io.ReadCloser) (AttributePatch, *Error) {
+	defer closeReader(payload)
+
+	start := time.Now()
+	patch, err := p.attributePatch(payload)
+	p.logParse(start, ObjectMode, nil, err)
+
+	return patch, err
+}
+
+// attributePatch reads and validates the patch payload
+func (p *Parser) attributePatch(payload io.Reader) (AttributePatch, *Error) {
+	body, err := p.readDocument(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	patch := AttributePatch{}
+	decodeErr := json.Unmarshal(body, &patch)
+	if decodeErr != nil {
+		return nil, &Error{
+			Title:  "Invalid Patch Document",
+			Detail: fmt.Sprintf("Patch documents must be an array of JSON Patch operations: %s", decodeErr.Error()),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	for i, operation := range patch {
+		err := operation.validate(i)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return patch, nil
+}
+
+// validate checks an operation's members, i being its index in the patch
+func (o PatchOperation) validate(i int) *Error {
+	switch o.Op {
+	case AddOp, ReplaceOp, TestOp:
+		if o.Value == nil {
+			return patchError(fmt.Sprintf("'%s' operations require a value", o.Op), i, "value")
+		}
+	case MoveOp, CopyOp:
+		if !isAttributePath(o.From) {
+			return patchError("Operations can only move or copy attributes, from must start with /attributes/", i, "from")
+		}
+		if o.Op == MoveOp && o.Path != o.From && strings.HasPrefix(o.Path, o.From+"/") {
+			return patchError("Attributes cannot be moved into themselves", i, "path")
+		}
+	case RemoveOp:
+	default:
+		return patchError(fmt.Sprintf("Unsupported patch operation '%s'", o.Op), i, "op")
+	}
+
+	if !isAttributePath(o.Path) {
+		return patchError("Operations can only change attributes, paths must start with /attributes/", i, "path")
+	}
+
+	return nil
+}
+
+// isAttributePath returns true if a JSON pointer addresses a member of the
+// attributes
+func isAttributePath(path string) bool {
+	return strings.HasPrefix(path, "/attributes/")
+}
+
+// patchError creates a 422 error for a member of a patch operation
+func patchError(detail string, i int, member string) *Error {
+	err := &Error{
+		Title:  "Invalid Patch Operation",
+		Detail: detail,
+		Status: 422,
+	}
+	err.Source.Pointer = fmt.Sprintf("/%d/%s", i, member)
+
+	return err
+}
+
+/*
+Apply applies the patch to an object's attributes. Operations are applied in
+order, and the object is left unchanged if any of them fails. Failed test
+operations are a 409 Conflict, other failures, such as removing a missing
+attribute, are 422 errors pointing at the offending operation. The patched
+object is checked against its resource declaration, like a parsed object.
+*/
+func (p AttributePatch) Apply(object *Object) *Error {
+	attributes := interface{}(map[string]interface{}{})
+	if object.HasAttributes() {
+		jsonErr := json.Unmarshal(object.Attributes, &attributes)
+		if jsonErr != nil {
+			return ISE(fmt.Sprintf("Unable to decode attributes to patch: %s", jsonErr.Error()))
+		}
+	}
+
+	document := map[string]interface{}{"attributes": attributes}
+	for i, operation := range p {
+		err := operation.apply(document, i)
+		if err != nil {
+			return err
+		}
+	}
+
+	raw, jsonErr := json.Marshal(document["attributes"])
+	if jsonErr != nil {
+		return ISE(fmt.Sprintf("Unable to encode patched attributes: %s", jsonErr.Error()))
+	}
+
+	patched := *object
+	patched.Attributes = raw
+
+	// the patched object holds the stored attributes as well as those the
+	// client wrote, so leave policies to the parser
+	err := acceptObject(nil, "PATCH", &patched)
+	if err != nil {
+		return err
+	}
+
+	object.Attributes = raw
+	return nil
+}
+
+// apply applies an operation to the document, i being its index in the patch
+func (o PatchOperation) apply(document map[string]interface{}, i int) *Error {
+	path := pointerTokens(o.Path)
+
+	var value interface{}
+	if o.Value != nil {
+		jsonErr := json.Unmarshal(o.Value, &value)
+		if jsonErr != nil {
+			return patchError(fmt.Sprintf("Invalid value: %s", jsonErr.Error()), i, "value")
+		}
+	}
+
+	var err error
+	switch o.Op {
+	case AddOp:
+		_, err = pointerSet(document, path, value, true)
+	case RemoveOp:
+		_, _, err = pointerRemove(document, path)
+	case ReplaceOp:
+		_, err = pointerSet(document, path, value, false)
+	case MoveOp:
+		var moved interface{}
+		_, moved, err = pointerRemove(document, pointerTokens(o.From))
+		if err == nil {
+			_, err = pointerSet(document, path, moved, true)
+		}
+	case CopyOp:
+		var copied interface{}
+		copied, err = pointerGet(document, pointerTokens(o.From))
+		if err == nil {
+			_, err = pointerSet(document, path, deepCopy(copied), true)
+		}
+	case TestOp:
+		var current interface{}
+		current, err = pointerGet(document, path)
+		if err == nil && !reflect.DeepEqual(current, value) {
+			conflict := Conflict(fmt.Sprintf("Test of '%s' failed", o.Path))
+			conflict.Source.Pointer = fmt.Sprintf("/%d", i)
+			return conflict
+		}
+	}
+
+	if err != nil {
+		return patchError(err.Error(), i, "path")
+	}
+
+	return nil
+}
+
+// pointerTokens splits a JSON pointer into its unescaped reference tokens
+func pointerTokens(pointer string) []string {
+	tokens := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	for i, token := range tokens {
+		tokens[i] = strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+	}
+
+	return tokens
+}
+
+// pointerGet returns the value at the tokens of a JSON pointer
+func pointerGet(node interface{}, tokens []string) (interface{}, error) {
+	for _, token := range tokens {
+		switch container := node.(type) {
+		case map[string]interface{}:
+			child, exists := container[token]
+			if !exists {
+				return nil, fmt.Errorf("Member '%s' does not exist", token)
+			}
+			node = child
+		case []interface{}:
+			index, err := arrayIndex(token, len(container)-1)
+			if err != nil {
+				return nil, err
+			}
+			node = container[index]
+		default:
+			return nil, fmt.Errorf("Cannot address '%s' of a value that isn't an object or array", token)
+		}
+	}
+
+	return node, nil
+}
+
+// pointerSet adds or replaces the value at the tokens of a JSON pointer,
+// returning the updated node, as arrays may need to grow
+func pointerSet(node interface{}, tokens []string, value interface{}, insert bool) (interface{}, error) {
+	token := tokens[0]
+	last := len(tokens) == 1
+
+	switch container := node.(type) {
+	case map[string]interface{}:
+		child, exists := container[token]
+		if last {
+			if !exists && !insert {
+				return nil, fmt.Errorf("Member '%s' does not exist", token)
+			}
+			container[token] = value
+			return container, nil
+		}
+
+		if !exists {
+			return nil, fmt.Errorf("Member '%s' does not exist", token)
+		}
+
+		updated, err := pointerSet(child, tokens[1:], value, insert)
+		if err != nil {
+			return nil, err
+		}
+		container[token] = updated
+		return container, nil
+	case []interface{}:
+		if last && insert {
+			index := len(container)
+			if token != "-" {
+				var err error
+				index, err = arrayIndex(token, len(container))
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			container = append(container, nil)
+			copy(container[index+1:], container[index:])
+			container[index] = value
+			return container, nil
+		}
+
+		index, err := arrayIndex(token, len(container)-1)
+		if err != nil {
+			return nil, err
+		}
+
+		if last {
+			container[index] = value
+			return container, nil
+		}
+
+		updated, err := pointerSet(container[index], tokens[1:], value, insert)
+		if err != nil {
+			return nil, err
+		}
+		container[index] = updated
+		return container, nil
+	}
+
+	return nil, fmt.Errorf("Cannot address '%s' of a value that isn't an object or array", token)
+}
+
+// pointerRemove removes the value at the tokens of a JSON pointer, returning
+// the updated node and the removed value
+func pointerRemove(node interface{}, tokens []string) (interface{}, interface{}, error) {
+	token := tokens[0]
+	last := len(tokens) == 1
+
+	switch container := node.(type) {
+	case map[string]interface{}:
+		child, exists := container[token]
+		if !exists {
+			return nil, nil, fmt.Errorf("Member '%s' does not exist", token)
+		}
+
+		if last {
+			delete(container, token)
+			return container, child, nil
+		}
+
+		updated, removed, err := pointerRemove(child, tokens[1:])
+		if err != nil {
+			return nil, nil, err
+		}
+		container[token] = updated
+		return container, removed, nil
+	case []interface{}:
+		index, err := arrayIndex(token, len(container)-1)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if last {
+			removed := container[index]
+			return append(container[:index], container[index+1:]...), removed, nil
+		}
+
+		updated, removed, err := pointerRemove(container[index], tokens[1:])
+		if err != nil {
+			return nil, nil, err
+		}
+		container[index] = updated
+		return container, removed, nil
+	}
+
+	return nil, nil, fmt.Errorf("Cannot address '%s' of a value that isn't an object or array", token)
+}
+
+// arrayIndex parses an array index token, which must be at most max
+func arrayIndex(token string, max int) (int, error) {
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || index > max || (token != "0" && strings.HasPrefix(token, "0")) {
+		return 0, fmt.Errorf("Invalid array index '%s'", token)
+	}
+
+	return index, nil
+}
+
+// deepCopy copies a decoded JSON value
+func deepCopy(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(typed))
+		for key, member := range typed {
+			copied[key] = deepCopy(member)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(typed))
+		for i, member := range typed {
+			copied[i] = deepCopy(member)
+		}
+		return copied
+	}
+
+	return value
+}
@@ -0,0 +1,150 @@
+package jsh
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAttributePatch(t *testing.T) {
+
+	Convey("Attribute Patch Tests", t, func() {
+
+		parse := func(body string) (AttributePatch, *Error) {
+			req, reqErr := testRequest([]byte(body))
+			So(reqErr, ShouldBeNil)
+			req.Method = "PATCH"
+			req.Header.Set("Content-Type", AttributePatchContentType)
+
+			return ParseAttributePatch(req)
+		}
+
+		object, _ := NewObject("1", "articles", map[string]interface{}{
+			"title": "Draft",
+			"body": map[string]interface{}{
+				"sections": []string{"intro", "outro"},
+			},
+			"draft": true,
+		})
+
+		attributes := func() map[string]interface{} {
+			decoded := map[string]interface{}{}
+			So(json.Unmarshal(object.Attributes, &decoded), ShouldBeNil)
+			return decoded
+		}
+
+		Convey("->ParseAttributePatch()", func() {
+
+			Convey("should parse operations", func() {
+				patch, err := parse(`[
+					{"op": "replace", "path": "/attributes/title", "value": "Final"},
+					{"op": "remove", "path": "/attributes/draft"}
+				]`)
+				So(err, ShouldBeNil)
+				So(len(patch), ShouldEqual, 2)
+				So(patch[0].Op, ShouldEqual, ReplaceOp)
+				So(string(patch[0].Value), ShouldEqual, `"Final"`)
+			})
+
+			Convey("should require the extension to be negotiated", func() {
+				req, _ := testRequest([]byte(`[]`))
+				req.Method = "PATCH"
+
+				_, err := ParseAttributePatch(req)
+				So(err, ShouldNotBeNil)
+				So(err.Status, ShouldEqual, http.StatusUnsupportedMediaType)
+			})
+
+			Convey("should reject operations outside of the attributes", func() {
+				_, err := parse(`[{"op": "replace", "path": "/id", "value": "2"}]`)
+				So(err, ShouldNotBeNil)
+				So(err.Status, ShouldEqual, 422)
+				So(err.Source.Pointer, ShouldEqual, "/0/path")
+
+				_, err = parse(`[{"op": "copy", "from": "/type", "path": "/attributes/type"}]`)
+				So(err, ShouldNotBeNil)
+				So(err.Source.Pointer, ShouldEqual, "/0/from")
+			})
+
+			Convey("should validate operations", func() {
+				_, err := parse(`[{"op": "remove", "path": "/attributes/a"}, {"op": "add", "path": "/attributes/b"}]`)
+				So(err, ShouldNotBeNil)
+				So(err.Source.Pointer, ShouldEqual, "/1/value")
+
+				_, err = parse(`[{"op": "merge", "path": "/attributes/a"}]`)
+				So(err, ShouldNotBeNil)
+				So(err.Source.Pointer, ShouldEqual, "/0/op")
+
+				_, err = parse(`{"op": "remove"}`)
+				So(err, ShouldNotBeNil)
+				So(err.Status, ShouldEqual, http.StatusBadRequest)
+			})
+		})
+
+		Convey("->Apply()", func() {
+
+			Convey("should apply operations in order", func() {
+				patch, err := parse(`[
+					{"op": "test", "path": "/attributes/title", "value": "Draft"},
+					{"op": "replace", "path": "/attributes/title", "value": "Final"},
+					{"op": "add", "path": "/attributes/body/sections/1", "value": "middle"},
+					{"op": "add", "path": "/attributes/body/sections/-", "value": "appendix"},
+					{"op": "copy", "from": "/attributes/title", "path": "/attributes/heading"},
+					{"op": "move", "from": "/attributes/draft", "path": "/attributes/unpublished"}
+				]`)
+				So(err, ShouldBeNil)
+
+				err = patch.Apply(object)
+				So(err, ShouldBeNil)
+				So(attributes(), ShouldResemble, map[string]interface{}{
+					"title":       "Final",
+					"heading":     "Final",
+					"unpublished": true,
+					"body": map[string]interface{}{
+						"sections": []interface{}{"intro", "middle", "outro", "appendix"},
+					},
+				})
+			})
+
+			Convey("should leave the object unchanged on failure", func() {
+				patch, err := parse(`[
+					{"op": "replace", "path": "/attributes/title", "value": "Final"},
+					{"op": "remove", "path": "/attributes/body/sections/5"}
+				]`)
+				So(err, ShouldBeNil)
+
+				err = patch.Apply(object)
+				So(err, ShouldNotBeNil)
+				So(err.Status, ShouldEqual, 422)
+				So(err.Source.Pointer, ShouldEqual, "/1/path")
+				So(attributes()["title"], ShouldEqual, "Draft")
+			})
+
+			Convey("should conflict on failed tests", func() {
+				patch, _ := parse(`[{"op": "test", "path": "/attributes/draft", "value": false}]`)
+
+				err := patch.Apply(object)
+				So(err, ShouldNotBeNil)
+				So(err.Status, ShouldEqual, http.StatusConflict)
+			})
+
+			Convey("should check the patched object against its declaration", func() {
+				Register(&Resource{Type: "articles", Limits: &AttributeLimits{MaxStringLength: 5}})
+				Reset(func() { Unregister("articles") })
+
+				patch, _ := parse(`[{"op": "replace", "path": "/attributes/title", "value": "Much too long"}]`)
+
+				err := patch.Apply(object)
+				So(err, ShouldNotBeNil)
+				So(err.Status, ShouldEqual, 422)
+				So(attributes()["title"], ShouldEqual, "Draft")
+			})
+		})
+
+		Convey("->pointerTokens()", func() {
+			So(pointerTokens("/attributes/a~1b/c~0d"), ShouldResemble, []string{"attributes", "a/b", "c~d"})
+		})
+	})
+}
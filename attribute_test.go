@@ -0,0 +1,90 @@
+package jsh
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAttribute(t *testing.T) {
+
+	Convey("Attribute Tests", t, func() {
+
+		testObject := &Object{
+			ID:         "ID123",
+			Type:       "user",
+			Attributes: json.RawMessage(`{"name":"bob","age":42,"admin":true,"created":"2016-01-02T15:04:05Z"}`),
+		}
+
+		Convey("->AttributeString()", func() {
+			name, err := testObject.AttributeString("name")
+			So(err, ShouldBeNil)
+			So(name, ShouldEqual, "bob")
+		})
+
+		Convey("->AttributeInt()", func() {
+			age, err := testObject.AttributeInt("age")
+			So(err, ShouldBeNil)
+			So(age, ShouldEqual, 42)
+
+			Convey("should return a 422 for a mismatched type", func() {
+				_, err := testObject.AttributeInt("name")
+				So(err, ShouldNotBeNil)
+				So(err.Status, ShouldEqual, 422)
+				So(err.Source.Pointer, ShouldEqual, "/data/attributes/name")
+			})
+		})
+
+		Convey("->AttributeBool()", func() {
+			admin, err := testObject.AttributeBool("admin")
+			So(err, ShouldBeNil)
+			So(admin, ShouldBeTrue)
+		})
+
+		Convey("->AttributeTime()", func() {
+			created, err := testObject.AttributeTime("created")
+			So(err, ShouldBeNil)
+			So(created.Equal(time.Date(2016, 1, 2, 15, 4, 5, 0, time.UTC)), ShouldBeTrue)
+		})
+
+		Convey("should return a 422 for a missing attribute", func() {
+			_, err := testObject.AttributeString("missing")
+			So(err, ShouldNotBeNil)
+			So(err.Source.Pointer, ShouldEqual, "/data/attributes/missing")
+			So(testObject.HasAttribute("missing"), ShouldBeFalse)
+		})
+
+		Convey("->SetAttribute()", func() {
+
+			Convey("should add an attribute without touching the others", func() {
+				err := testObject.SetAttribute("email", "bob@example.com")
+				So(err, ShouldBeNil)
+
+				email, err := testObject.AttributeString("email")
+				So(err, ShouldBeNil)
+				So(email, ShouldEqual, "bob@example.com")
+
+				age, err := testObject.AttributeInt("age")
+				So(err, ShouldBeNil)
+				So(age, ShouldEqual, 42)
+			})
+
+			Convey("should work on an object without attributes", func() {
+				object, objErr := NewObject("1", "user", nil)
+				So(objErr, ShouldBeNil)
+
+				err := object.SetAttribute("name", "alice")
+				So(err, ShouldBeNil)
+				So(object.HasAttribute("name"), ShouldBeTrue)
+			})
+		})
+
+		Convey("->RemoveAttribute()", func() {
+			err := testObject.RemoveAttribute("name")
+			So(err, ShouldBeNil)
+			So(testObject.HasAttribute("name"), ShouldBeFalse)
+		})
+	})
+}
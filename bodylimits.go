@@ -0,0 +1,83 @@
+package jsh
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// DefaultMaxBodySize is the size in bytes documents are capped at when
+// ParseOptions.MaxBodySize is zero.
+const DefaultMaxBodySize int64 = 10 << 20
+
+// DefaultMaxDepth is how deeply documents may nest when ParseOptions.MaxDepth
+// is zero. A resource object's attributes start at a depth of 4 in a list.
+const DefaultMaxDepth = 64
+
+// maxBodySize returns the body size cap, zero if there is none
+func (o *ParseOptions) maxBodySize() int64 {
+	switch {
+	case o.MaxBodySize == 0:
+		return DefaultMaxBodySize
+	case o.MaxBodySize < 0:
+		return 0
+	}
+
+	return o.MaxBodySize
+}
+
+// maxDepth returns the nesting cap, zero if there is none
+func (o *ParseOptions) maxDepth() int {
+	switch {
+	case o.MaxDepth == 0:
+		return DefaultMaxDepth
+	case o.MaxDepth < 0:
+		return 0
+	}
+
+	return o.MaxDepth
+}
+
+/*
+checkDepth rejects bodies nesting objects and arrays deeper than the cap,
+before they are decoded, since decoding recurses once per level. Brackets
+within strings are skipped, malformed JSON is left for the parser to report.
+*/
+func (o *ParseOptions) checkDepth(body []byte) *Error {
+	limit := o.maxDepth()
+	if limit == 0 {
+		return nil
+	}
+
+	depth := 0
+	inString := false
+	escaped := false
+	for _, char := range body {
+		switch {
+		case escaped:
+			escaped = false
+		case inString && char == '\\':
+			escaped = true
+		case char == '"':
+			inString = !inString
+		case inString:
+		case char == '{' || char == '[':
+			depth++
+			if depth > limit {
+				return documentError(fmt.Sprintf("JSON Document is nested more than %d levels deep", limit), "")
+			}
+		case char == '}' || char == ']':
+			depth--
+		}
+	}
+
+	return nil
+}
+
+// payloadTooLarge creates a 413 error for a document over the size cap
+func payloadTooLarge(limit int64) *Error {
+	return &Error{
+		Title:  "Payload Too Large",
+		Detail: fmt.Sprintf("JSON Document exceeds the maximum size of %d bytes", limit),
+		Status: http.StatusRequestEntityTooLarge,
+	}
+}
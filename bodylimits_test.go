@@ -0,0 +1,75 @@
+package jsh
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBodyLimits(t *testing.T) {
+
+	Convey("Body Limit Tests", t, func() {
+
+		parse := func(body string, options *ParseOptions) (*Document, *Error) {
+			req, reqErr := testRequest([]byte(body))
+			So(reqErr, ShouldBeNil)
+
+			parser := NewParser(req)
+			parser.Options = options
+			return parser.Document(req.Body, ObjectMode)
+		}
+
+		object := `{"data": {"type": "bars", "id": "1", "attributes": {"nested": %s}}}`
+		nested := func(levels int) string {
+			return strings.Replace(object, "%s", strings.Repeat("[", levels)+strings.Repeat("]", levels), 1)
+		}
+
+		Convey("MaxBodySize", func() {
+			body := nested(1)
+
+			_, err := parse(body, &ParseOptions{MaxBodySize: int64(len(body))})
+			So(err, ShouldBeNil)
+
+			_, err = parse(body, &ParseOptions{MaxBodySize: int64(len(body) - 1)})
+			So(err, ShouldNotBeNil)
+			So(err.Status, ShouldEqual, http.StatusRequestEntityTooLarge)
+
+			Convey("should default to DefaultMaxBodySize", func() {
+				huge := strings.Replace(object, "%s", `"`+strings.Repeat("a", int(DefaultMaxBodySize))+`"`, 1)
+
+				_, err := parse(huge, nil)
+				So(err, ShouldNotBeNil)
+				So(err.Status, ShouldEqual, http.StatusRequestEntityTooLarge)
+
+				_, err = parse(huge, &ParseOptions{MaxBodySize: -1})
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("MaxDepth", func() {
+			// the document, data, and attributes objects take 3 levels
+			_, err := parse(nested(2), &ParseOptions{MaxDepth: 5})
+			So(err, ShouldBeNil)
+
+			_, err = parse(nested(3), &ParseOptions{MaxDepth: 5})
+			So(err, ShouldNotBeNil)
+			So(err.Status, ShouldEqual, http.StatusBadRequest)
+
+			Convey("should ignore brackets within strings", func() {
+				body := strings.Replace(object, "%s", `"[[[[\"[[[["`, 1)
+				_, err := parse(body, &ParseOptions{MaxDepth: 3})
+				So(err, ShouldBeNil)
+			})
+
+			Convey("should default to DefaultMaxDepth", func() {
+				_, err := parse(nested(DefaultMaxDepth), nil)
+				So(err, ShouldNotBeNil)
+
+				_, err = parse(nested(DefaultMaxDepth), &ParseOptions{MaxDepth: -1})
+				So(err, ShouldBeNil)
+			})
+		})
+	})
+}
@@ -0,0 +1,147 @@
+package jsh
+
+import (
+	"net/http"
+	"sync"
+)
+
+/*
+DocumentBuilder assembles a document from objects, included resources, and
+errors added by many goroutines, such as those fetching the parts of a large
+compound document from several backends:
+
+	builder := jsh.NewDocumentBuilder(jsh.ListMode)
+
+	var wg sync.WaitGroup
+	for _, shard := range shards {
+		wg.Add(1)
+		go func(shard Shard) {
+			defer wg.Done()
+
+			users, authors, err := shard.UsersWithAuthors(ctx)
+			if err != nil {
+				builder.AddError(err)
+				return
+			}
+			builder.AddObject(users...)
+			builder.AddIncluded(authors...)
+		}(shard)
+	}
+	wg.Wait()
+
+	jsh.SendDocument(w, r, builder.Document())
+
+Resources are kept in the order they were added, which concurrent goroutines
+don't guarantee, so sort the document's data afterwards if the order matters.
+The zero value isn't usable, create builders with NewDocumentBuilder. A
+DocumentBuilder is safe for concurrent use.
+*/
+type DocumentBuilder struct {
+	mode     DocumentMode
+	mu       sync.Mutex
+	data     List
+	included List
+	errors   ErrorList
+}
+
+// NewDocumentBuilder creates a builder for a document of ObjectMode or
+// ListMode.
+func NewDocumentBuilder(mode DocumentMode) *DocumentBuilder {
+	return &DocumentBuilder{mode: mode}
+}
+
+// AddObject adds objects to the document's primary data.
+func (b *DocumentBuilder) AddObject(objects ...*Object) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.data = append(b.data, objects...)
+}
+
+// AddIncluded adds objects to the document's included resources.
+func (b *DocumentBuilder) AddIncluded(objects ...*Object) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.included = append(b.included, objects...)
+}
+
+// AddError adds errors to the document, which makes it an error document once
+// built.
+func (b *DocumentBuilder) AddError(errs ...*Error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.errors = append(b.errors, errs...)
+}
+
+// AddDocument adds the primary data, included resources, and errors of a
+// document, such as one a backend responded with.
+func (b *DocumentBuilder) AddDocument(document *Document) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.data = append(b.data, document.Data...)
+	b.included = append(b.included, document.Included...)
+	b.errors = append(b.errors, document.Errors...)
+}
+
+/*
+Document builds a document from what has been added so far, removing repeated
+resources, see Document.Deduplicate. If any errors were added, it's an error
+document holding only the errors, with the status they share, or the most
+general one, 400 Bad Request or 500 Internal Server Error, if they differ.
+
+An ObjectMode document is sent with its object's Status, 200 OK if it has
+none, and is a 500 error if more than one object was added.
+*/
+func (b *DocumentBuilder) Document() *Document {
+	b.mu.Lock()
+	data := append(List{}, b.data...)
+	included := append(List{}, b.included...)
+	errors := append(ErrorList{}, b.errors...)
+	b.mu.Unlock()
+
+	if len(errors) > 0 {
+		document := Build(errors)
+		document.Status = generalStatus(errors)
+		return document
+	}
+
+	document := New()
+	document.Mode = b.mode
+	document.Status = http.StatusOK
+	document.Data = data
+	if len(included) > 0 {
+		document.Included = included
+	}
+	document.Deduplicate()
+
+	if b.mode == ObjectMode {
+		if len(document.Data) > 1 {
+			return Build(ISE("Single 'data' object response is expected, more than one object was added"))
+		}
+		if object := document.First(); object != nil && object.Status != 0 {
+			document.Status = object.Status
+		}
+	}
+
+	return document
+}
+
+// generalStatus returns the status the errors share, or the most generally
+// applicable one if they differ
+func generalStatus(errors ErrorList) int {
+	status := errors[0].Status
+	for _, err := range errors[1:] {
+		switch {
+		case err.Status == status:
+		case err.Status >= http.StatusInternalServerError || status >= http.StatusInternalServerError:
+			return http.StatusInternalServerError
+		default:
+			status = http.StatusBadRequest
+		}
+	}
+
+	return status
+}
@@ -0,0 +1,116 @@
+package jsh
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDocumentBuilder(t *testing.T) {
+
+	Convey("DocumentBuilder Tests", t, func() {
+
+		user := func(id string) *Object {
+			object, err := NewObject(id, "users", map[string]string{"name": "user " + id})
+			So(err, ShouldBeNil)
+			return object
+		}
+
+		Convey("should assemble objects added concurrently", func() {
+			builder := NewDocumentBuilder(ListMode)
+
+			var wg sync.WaitGroup
+			for i := 0; i < 20; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					object, _ := NewObject(fmt.Sprint(i), "users", map[string]string{"name": "bob"})
+					author, _ := NewObject(fmt.Sprint(i%5), "authors", nil)
+					builder.AddObject(object)
+					builder.AddIncluded(author)
+				}(i)
+			}
+			wg.Wait()
+
+			document := builder.Document()
+			So(document.Mode, ShouldEqual, ListMode)
+			So(document.Status, ShouldEqual, http.StatusOK)
+			So(len(document.Data), ShouldEqual, 20)
+			So(len(document.Included), ShouldEqual, 5)
+		})
+
+		Convey("should deduplicate resources", func() {
+			builder := NewDocumentBuilder(ListMode)
+			builder.AddObject(user("1"), user("2"), user("1"))
+			builder.AddIncluded(user("2"), user("3"))
+
+			document := builder.Document()
+			So(len(document.Data), ShouldEqual, 2)
+			So(len(document.Included), ShouldEqual, 1)
+			So(document.Included[0].ID, ShouldEqual, "3")
+		})
+
+		Convey("should build empty lists", func() {
+			document := NewDocumentBuilder(ListMode).Document()
+			So(document.Data, ShouldNotBeNil)
+			So(document.Included, ShouldBeNil)
+			So(document.Validate(&http.Request{Method: "GET"}, true), ShouldBeNil)
+		})
+
+		Convey("should add documents", func() {
+			builder := NewDocumentBuilder(ListMode)
+			builder.AddDocument(&Document{Data: List{user("1")}, Included: List{user("2")}})
+
+			document := builder.Document()
+			So(len(document.Data), ShouldEqual, 1)
+			So(len(document.Included), ShouldEqual, 1)
+		})
+
+		Convey("in ObjectMode", func() {
+			builder := NewDocumentBuilder(ObjectMode)
+
+			Convey("should send the object's status", func() {
+				object := user("1")
+				object.Status = http.StatusCreated
+				builder.AddObject(object, object)
+
+				document := builder.Document()
+				So(document.First().ID, ShouldEqual, "1")
+				So(document.Status, ShouldEqual, http.StatusCreated)
+			})
+
+			Convey("should reject more than one object", func() {
+				builder.AddObject(user("1"), user("2"))
+
+				document := builder.Document()
+				So(document.Mode, ShouldEqual, ErrorMode)
+				So(document.Status, ShouldEqual, http.StatusInternalServerError)
+			})
+		})
+
+		Convey("with errors", func() {
+			builder := NewDocumentBuilder(ListMode)
+			builder.AddObject(user("1"))
+			builder.AddError(NotFound("users", "2"))
+
+			Convey("should build an error document", func() {
+				document := builder.Document()
+				So(document.Mode, ShouldEqual, ErrorMode)
+				So(document.HasData(), ShouldBeFalse)
+				So(document.Status, ShouldEqual, http.StatusNotFound)
+			})
+
+			Convey("should use the most general status", func() {
+				builder.AddError(InputError("Invalid name", "name"))
+				So(builder.Document().Status, ShouldEqual, http.StatusBadRequest)
+
+				builder.AddError(ISE("backend unavailable"))
+				So(builder.Document().Status, ShouldEqual, http.StatusInternalServerError)
+				So(len(builder.Document().Errors), ShouldEqual, 3)
+			})
+		})
+	})
+}
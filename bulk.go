@@ -0,0 +1,156 @@
+package jsh
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+const (
+	// BulkExtension is the name of the JSON API bulk extension, which allows
+	// multiple resources to be created, updated, or deleted in one request.
+	BulkExtension = "bulk"
+	// BulkContentType is the Content-Type negotiated for the bulk extension
+	BulkContentType = ContentType + "; ext=" + BulkExtension
+)
+
+// supportedExtensions lists the extensions accepted in a Content-Type header
+var supportedExtensions = map[string]bool{
+	BulkExtension:           true,
+	AttributePatchExtension: true,
+}
+
+/*
+IsBulk returns true if the request negotiated the bulk extension via its
+Content-Type header:
+
+	Content-Type: application/vnd.api+json; ext=bulk
+*/
+func IsBulk(r *http.Request) bool {
+	return isBulk(r.Header)
+}
+
+// isBulk returns true if the headers negotiate the bulk extension
+func isBulk(headers http.Header) bool {
+	extensions, _ := contentTypeExtensions(headers.Get("Content-Type"))
+	for _, extension := range extensions {
+		if extension == BulkExtension {
+			return true
+		}
+	}
+
+	return false
+}
+
+/*
+contentTypeExtensions returns the extensions negotiated by a JSON API
+Content-Type. The Content-Type is only valid if it has no media type parameters
+other than "ext" and "profile", and each of the extensions is supported.
+*/
+func contentTypeExtensions(contentType string) ([]string, bool) {
+	if contentType == ContentType {
+		return nil, true
+	}
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType != ContentType {
+		return nil, false
+	}
+
+	// profiles don't affect how the document is parsed
+	delete(params, "profile")
+	if len(params) == 0 {
+		return nil, true
+	}
+
+	ext, hasExt := params["ext"]
+	if !hasExt || len(params) > 1 {
+		return nil, false
+	}
+
+	extensions := strings.Split(ext, ",")
+	for _, extension := range extensions {
+		if !supportedExtensions[extension] {
+			return nil, false
+		}
+	}
+
+	return extensions, true
+}
+
+/*
+ParseBulk parses a bulk extension request, one that creates (POST), updates
+(PATCH), or deletes (DELETE) several resources at once:
+
+	objects, err := jsh.ParseBulk(r)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	for _, object := range objects {
+		...
+	}
+
+	jsh.SendBulk(w, r, objects)
+
+A 415 error is returned if the request didn't negotiate the bulk extension, and
+every resource must have an ID unless it is being created.
+*/
+func ParseBulk(r *http.Request) (List, *Error) {
+	if !IsBulk(r) {
+		return nil, &Error{
+			Title:  "Unsupported Media Type",
+			Detail: fmt.Sprintf("Bulk requests must use the Content-Type: %s", BulkContentType),
+			Status: http.StatusUnsupportedMediaType,
+		}
+	}
+
+	switch r.Method {
+	case "POST", "PATCH", "DELETE":
+	default:
+		return nil, SpecificationError(fmt.Sprintf("The bulk extension does not support '%s' requests", r.Method))
+	}
+
+	list, err := ParseList(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.Method != "POST" {
+		for i, object := range list {
+			if object.ID == "" {
+				return nil, indexPointer(InputError("Missing mandatory object attribute", "id"), i)
+			}
+		}
+	}
+
+	return list, nil
+}
+
+/*
+SendBulk sends the result of a bulk extension request. Created resources are
+sent with a 201 and updated resources with a 200, while deletions send a 204
+without a body.
+*/
+func SendBulk(w http.ResponseWriter, r *http.Request, list List) *Error {
+	if r.Method == "DELETE" {
+		w.Header().Set("Content-Type", BulkContentType)
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+
+	validationErr := list.Validate(r, true)
+	if validationErr != nil {
+		Send(w, r, validationErr)
+		return validationErr
+	}
+
+	document := Build(list)
+	if r.Method == "POST" {
+		document.Status = http.StatusCreated
+	}
+
+	return sendDocument(w, r, document, BulkContentType)
+}
@@ -0,0 +1,113 @@
+package jsh
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBulk(t *testing.T) {
+
+	Convey("Bulk Extension Tests", t, func() {
+
+		listJSON := `{"data": [
+			{"type": "users", "attributes": {"name": "a"}},
+			{"type": "users", "attributes": {"name": "b"}}
+		]}`
+
+		req, reqErr := testRequest([]byte(listJSON))
+		So(reqErr, ShouldBeNil)
+		req.Method = "POST"
+		req.Header.Set("Content-Type", BulkContentType)
+
+		Convey("->contentTypeExtensions()", func() {
+
+			Convey("should accept the plain content type", func() {
+				extensions, valid := contentTypeExtensions(ContentType)
+				So(valid, ShouldBeTrue)
+				So(extensions, ShouldBeEmpty)
+			})
+
+			Convey("should accept supported extensions", func() {
+				extensions, valid := contentTypeExtensions(`application/vnd.api+json; ext="bulk"`)
+				So(valid, ShouldBeTrue)
+				So(extensions, ShouldResemble, []string{"bulk"})
+			})
+
+			Convey("should reject unsupported extensions and parameters", func() {
+				_, valid := contentTypeExtensions("application/vnd.api+json; ext=jsonpatch")
+				So(valid, ShouldBeFalse)
+
+				_, valid = contentTypeExtensions("application/vnd.api+json; charset=utf-8")
+				So(valid, ShouldBeFalse)
+			})
+		})
+
+		Convey("->ParseBulk()", func() {
+
+			Convey("should parse resources to create without IDs", func() {
+				list, err := ParseBulk(req)
+				So(err, ShouldBeNil)
+				So(len(list), ShouldEqual, 2)
+			})
+
+			Convey("should require the extension to be negotiated", func() {
+				req.Header.Set("Content-Type", ContentType)
+
+				_, err := ParseBulk(req)
+				So(err, ShouldNotBeNil)
+				So(err.Status, ShouldEqual, http.StatusUnsupportedMediaType)
+			})
+
+			Convey("should require IDs for updates", func() {
+				req.Method = "PATCH"
+
+				_, err := ParseBulk(req)
+				So(err, ShouldNotBeNil)
+				So(err.Source.Pointer, ShouldEqual, "/data/0/attributes/id")
+			})
+
+			Convey("should reject GET requests", func() {
+				req.Method = "GET"
+
+				_, err := ParseBulk(req)
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("->SendBulk()", func() {
+			list, err := ParseBulk(req)
+			So(err, ShouldBeNil)
+			list[0].ID = "1"
+			list[1].ID = "2"
+
+			writer := httptest.NewRecorder()
+
+			Convey("should send created resources with a 201", func() {
+				err := SendBulk(writer, req, list)
+				So(err, ShouldBeNil)
+				So(writer.Code, ShouldEqual, http.StatusCreated)
+				So(writer.Header().Get("Content-Type"), ShouldEqual, BulkContentType)
+			})
+
+			Convey("should send updated resources with a 200", func() {
+				req.Method = "PATCH"
+
+				err := SendBulk(writer, req, list)
+				So(err, ShouldBeNil)
+				So(writer.Code, ShouldEqual, http.StatusOK)
+			})
+
+			Convey("should send deletions with a 204 and no body", func() {
+				req.Method = "DELETE"
+
+				err := SendBulk(writer, req, list)
+				So(err, ShouldBeNil)
+				So(writer.Code, ShouldEqual, http.StatusNoContent)
+				So(writer.Body.Len(), ShouldEqual, 0)
+			})
+		})
+	})
+}
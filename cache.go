@@ -0,0 +1,120 @@
+package jsh
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Operation identifies what a request does to a resource type.
+type Operation string
+
+const (
+	// FetchOperation is a GET of a single resource
+	FetchOperation Operation = "fetch"
+	// ListOperation is a GET of a collection of resources
+	ListOperation Operation = "list"
+	// CreateOperation is a POST
+	CreateOperation Operation = "create"
+	// UpdateOperation is a PATCH
+	UpdateOperation Operation = "update"
+	// DeleteOperation is a DELETE
+	DeleteOperation Operation = "delete"
+)
+
+/*
+CachePolicy sets the Cache-Control, Expires, and Pragma headers of successful
+responses. Declare policies per resource type and operation when registering a
+resource, rather than setting headers in each handler:
+
+	jsh.Register(&jsh.Resource{
+		Type: "articles",
+		CachePolicies: map[jsh.Operation]*jsh.CachePolicy{
+			jsh.FetchOperation: {Public: true, MaxAge: 5 * time.Minute},
+			jsh.ListOperation:  {MaxAge: 30 * time.Second},
+		},
+	})
+
+Handlers that set a Cache-Control header themselves take precedence.
+*/
+type CachePolicy struct {
+	// Public allows shared caches such as CDNs to store the response, otherwise
+	// it is private to the client
+	Public bool
+	// MaxAge is how long the response may be cached for
+	MaxAge time.Duration
+	// NoStore forbids caching the response at all, overriding the other fields
+	NoStore bool
+}
+
+// DefaultCachePolicy applies to responses without a declared policy. Leave nil
+// to not set caching headers.
+var DefaultCachePolicy *CachePolicy
+
+// apply sets the policy's headers
+func (p *CachePolicy) apply(header http.Header, now time.Time) {
+	if p.NoStore {
+		header.Set("Cache-Control", "no-store")
+		header.Set("Pragma", "no-cache")
+		header.Set("Expires", "0")
+		return
+	}
+
+	visibility := "private"
+	if p.Public {
+		visibility = "public"
+	}
+
+	header.Set("Cache-Control", fmt.Sprintf("%s, max-age=%d", visibility, int(p.MaxAge.Seconds())))
+	header.Set("Expires", now.Add(p.MaxAge).UTC().Format(http.TimeFormat))
+}
+
+// requestOperation determines the operation of a request sending document
+func requestOperation(r *http.Request, document *Document) Operation {
+	return methodOperation(r.Method, document.Mode)
+}
+
+// methodOperation determines the operation of a request method, the mode
+// distinguishing fetches from lists
+func methodOperation(method string, mode DocumentMode) Operation {
+	switch method {
+	case "POST":
+		return CreateOperation
+	case "PATCH":
+		return UpdateOperation
+	case "DELETE":
+		return DeleteOperation
+	}
+
+	if mode == ListMode {
+		return ListOperation
+	}
+
+	return FetchOperation
+}
+
+// applyCachePolicy sets the caching headers for a successful response
+func applyCachePolicy(w http.ResponseWriter, r *http.Request, document *Document) {
+	if document.HasErrors() || document.Status >= http.StatusBadRequest {
+		return
+	}
+
+	if w.Header().Get("Cache-Control") != "" {
+		return
+	}
+
+	policy := DefaultCachePolicy
+	if document.HasData() {
+		resource := Registered(document.First().Type)
+		if resource != nil {
+			declared, exists := resource.CachePolicies[requestOperation(r, document)]
+			if exists {
+				policy = declared
+			}
+		}
+	}
+
+	if policy != nil {
+		policy.apply(w.Header(), time.Now())
+	}
+}
@@ -0,0 +1,71 @@
+package jsh
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCachePolicy(t *testing.T) {
+
+	Convey("Cache Policy Tests", t, func() {
+
+		Register(&Resource{
+			Type: "articles",
+			CachePolicies: map[Operation]*CachePolicy{
+				FetchOperation: {Public: true, MaxAge: 5 * time.Minute},
+				ListOperation:  {NoStore: true},
+			},
+		})
+		Reset(func() {
+			Unregister("articles")
+			DefaultCachePolicy = nil
+		})
+
+		object, objErr := NewObject("1", "articles", map[string]string{"title": "Caching"})
+		So(objErr, ShouldBeNil)
+
+		writer := httptest.NewRecorder()
+		request := &http.Request{Method: "GET"}
+
+		Convey("should apply the policy for fetches", func() {
+			err := Send(writer, request, object)
+			So(err, ShouldBeNil)
+			So(writer.Header().Get("Cache-Control"), ShouldEqual, "public, max-age=300")
+			So(writer.Header().Get("Expires"), ShouldNotBeEmpty)
+		})
+
+		Convey("should apply the policy for lists", func() {
+			err := Send(writer, request, List{object})
+			So(err, ShouldBeNil)
+			So(writer.Header().Get("Cache-Control"), ShouldEqual, "no-store")
+			So(writer.Header().Get("Pragma"), ShouldEqual, "no-cache")
+		})
+
+		Convey("should fall back to the default policy", func() {
+			DefaultCachePolicy = &CachePolicy{MaxAge: time.Minute}
+
+			err := Send(writer, &http.Request{Method: "PATCH"}, object)
+			So(err, ShouldBeNil)
+			So(writer.Header().Get("Cache-Control"), ShouldEqual, "private, max-age=60")
+		})
+
+		Convey("should not override headers set by the handler", func() {
+			writer.Header().Set("Cache-Control", "no-cache")
+
+			err := Send(writer, request, object)
+			So(err, ShouldBeNil)
+			So(writer.Header().Get("Cache-Control"), ShouldEqual, "no-cache")
+		})
+
+		Convey("should not apply to errors", func() {
+			DefaultCachePolicy = &CachePolicy{MaxAge: time.Minute}
+
+			Send(writer, request, NotFound("articles", "2"))
+			So(writer.Header().Get("Cache-Control"), ShouldBeEmpty)
+		})
+	})
+}
@@ -0,0 +1,61 @@
+package jsc
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/derekdowling/go-json-spec-handler"
+)
+
+/*
+Aggregate sends each of the requests concurrently and merges the documents
+returned into one with jsh.Merge, for services composing the responses of
+several upstream APIs:
+
+	users, _ := jsc.ListRequest(usersURL, "users")
+	teams, _ := jsc.ListRequest(teamsURL, "teams")
+
+	merged, err := client.Aggregate(&jsh.MergeOptions{BaseURL: publicURL}, users, teams)
+
+Upstream error documents are merged into a single error document, which is
+returned along with its jsh.ErrorList as the error. Any other failure to fetch
+or parse a response is returned as is.
+*/
+func (c *Client) Aggregate(options *jsh.MergeOptions, requests ...*http.Request) (*jsh.Document, error) {
+	documents := make([]*jsh.Document, len(requests))
+	errs := make([]error, len(requests))
+
+	var wg sync.WaitGroup
+	for i, request := range requests {
+		wg.Add(1)
+		go func(i int, request *http.Request) {
+			defer wg.Done()
+
+			document, _, err := c.Do(request, jsh.ListMode)
+			if _, isErrorList := err.(jsh.ErrorList); isErrorList {
+				err = nil
+			}
+
+			documents[i] = document
+			errs[i] = err
+		}(i, request)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	merged, err := jsh.Merge(options, documents...)
+	if err != nil {
+		return nil, err
+	}
+
+	if merged.HasErrors() {
+		return merged, merged.Errors
+	}
+
+	return merged, nil
+}
@@ -0,0 +1,49 @@
+package jsc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/derekdowling/go-json-spec-handler"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAggregate(t *testing.T) {
+
+	Convey("Aggregate Tests", t, func() {
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/missing" {
+				jsh.Send(w, r, jsh.NotFound("missing", "1"))
+				return
+			}
+
+			object, _ := jsh.NewObject("1", r.URL.Path[1:], map[string]string{"foo": "bar"})
+			jsh.Send(w, r, jsh.List{object})
+		}))
+		Reset(func() {
+			server.Close()
+		})
+
+		client := &Client{}
+		users, _ := ListRequest(server.URL, "users")
+		teams, _ := ListRequest(server.URL, "teams")
+
+		Convey("should merge upstream documents", func() {
+			merged, err := client.Aggregate(nil, users, teams)
+			So(err, ShouldBeNil)
+			So(len(merged.Data), ShouldEqual, 2)
+			So(merged.Data[0].Type, ShouldEqual, "users")
+			So(merged.Data[1].Type, ShouldEqual, "teams")
+		})
+
+		Convey("should merge upstream errors", func() {
+			missing, _ := ListRequest(server.URL, "missing")
+
+			merged, err := client.Aggregate(nil, users, missing)
+			So(err, ShouldNotBeNil)
+			So(merged.HasErrors(), ShouldBeTrue)
+		})
+	})
+}
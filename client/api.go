@@ -0,0 +1,263 @@
+package jsc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/derekdowling/go-json-spec-handler"
+)
+
+/*
+Model is implemented by the structs API.Create and API.Update send, to supply
+their resource type and ID. Models that also implement IDSetter are given their
+ID when they are fetched or created.
+*/
+type Model interface {
+	JSONAPIType() string
+	JSONAPIID() string
+}
+
+// IDSetter can be implemented by a model to be given the ID of the object it
+// was unmarshaled from.
+type IDSetter interface {
+	SetJSONAPIID(id string)
+}
+
+/*
+API sends requests to the resources of a JSON API, marshaling and unmarshaling
+Go structs so callers don't handle Documents at all:
+
+	api := jsc.NewAPI("https://api.example.com")
+
+	user := &User{}
+	err := api.Fetch(ctx, "users", "1", user)
+
+	user.Name = "Bob"
+	err = api.Update(ctx, user)
+
+Error documents sent by the server are returned as jsh.ErrorLists, so their
+status and details can be inspected, see IsNotFound.
+*/
+type API struct {
+	// BaseURL is the API's root, such as https://api.example.com/v1
+	BaseURL string
+	// Client sends the requests, DefaultClient if nil
+	Client *Client
+	// IDs generates the IDs of models created without one, for APIs that
+	// expect client generated IDs. nil leaves them to the server.
+	IDs jsh.IDGenerator
+}
+
+// NewAPI returns an API for the base URL, sending requests with DefaultClient.
+func NewAPI(baseURL string) *API {
+	return &API{BaseURL: baseURL}
+}
+
+// API returns an API for the service, sending requests with its client.
+func (s *ServiceClient) API() *API {
+	return &API{BaseURL: s.BaseURL, Client: s.Client}
+}
+
+// Fetch fetches the resource with the type and ID, unmarshaling its attributes
+// into target.
+func (a *API) Fetch(ctx context.Context, resourceType string, id string, target interface{}) error {
+	request, err := FetchRequest(a.BaseURL, resourceType, id)
+	if err != nil {
+		return err
+	}
+
+	document, err := a.do(ctx, request, jsh.ObjectMode)
+	if err != nil {
+		return err
+	}
+
+	return unmarshalModel(document.First(), resourceType, target)
+}
+
+/*
+List fetches the collection of a resource type, appending an element for each
+of its objects to the slice target points to, which may hold structs or
+pointers to them:
+
+	users := []*User{}
+	err := api.List(ctx, "users", &users)
+*/
+func (a *API) List(ctx context.Context, resourceType string, target interface{}) error {
+	slice := reflect.ValueOf(target)
+	if slice.Kind() != reflect.Ptr || slice.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("List target must be a pointer to a slice, not %T", target)
+	}
+	slice = slice.Elem()
+
+	request, err := ListRequest(a.BaseURL, resourceType)
+	if err != nil {
+		return err
+	}
+
+	document, err := a.do(ctx, request, jsh.ListMode)
+	if err != nil {
+		return err
+	}
+
+	element := slice.Type().Elem()
+	for _, object := range document.Data {
+		var model reflect.Value
+		if element.Kind() == reflect.Ptr {
+			model = reflect.New(element.Elem())
+		} else {
+			model = reflect.New(element)
+		}
+
+		err = unmarshalModel(object, resourceType, model.Interface())
+		if err != nil {
+			return err
+		}
+
+		if element.Kind() != reflect.Ptr {
+			model = model.Elem()
+		}
+		slice.Set(reflect.Append(slice, model))
+	}
+
+	return nil
+}
+
+// Create POSTs the model as a new resource, and unmarshals the created
+// resource the server sends back into it, including its ID if it is an
+// IDSetter. Models without an ID are given one by the API's IDs, if set.
+func (a *API) Create(ctx context.Context, model Model) error {
+	object, err := modelObject(model)
+	if err != nil {
+		return err
+	}
+
+	if object.ID == "" && a.IDs != nil {
+		object.ID, err = a.IDs.GenerateID()
+		if err != nil {
+			return fmt.Errorf("Error generating ID: %s", err)
+		}
+		if setter, ok := model.(IDSetter); ok {
+			setter.SetJSONAPIID(object.ID)
+		}
+	}
+
+	request, err := PostRequest(a.BaseURL, object)
+	if err != nil {
+		return err
+	}
+
+	return a.send(ctx, request, model)
+}
+
+// Update PATCHes the resource with the model's attributes, and unmarshals the
+// updated resource into the model if the server sends it back.
+func (a *API) Update(ctx context.Context, model Model) error {
+	object, err := modelObject(model)
+	if err != nil {
+		return err
+	}
+
+	request, err := PatchRequest(a.BaseURL, object)
+	if err != nil {
+		return err
+	}
+
+	return a.send(ctx, request, model)
+}
+
+// Delete deletes the resource with the type and ID.
+func (a *API) Delete(ctx context.Context, resourceType string, id string) error {
+	request, err := DeleteRequest(a.BaseURL, resourceType, id)
+	if err != nil {
+		return err
+	}
+
+	_, err = a.do(ctx, request, jsh.ObjectMode)
+	return err
+}
+
+/*
+IsNotFound returns true if err is an error document sent with a 404, such as
+returned by API.Fetch for a resource that doesn't exist:
+
+	err := api.Fetch(ctx, "users", id, user)
+	if jsc.IsNotFound(err) {
+		...
+	}
+*/
+func IsNotFound(err error) bool {
+	errorList, isErrorList := err.(jsh.ErrorList)
+	if !isErrorList {
+		return false
+	}
+
+	for _, documentErr := range errorList {
+		if documentErr.Status == http.StatusNotFound {
+			return true
+		}
+	}
+
+	return false
+}
+
+// send sends a request writing the model, then unmarshals the object the
+// server sends back, if any, into it
+func (a *API) send(ctx context.Context, request *http.Request, model Model) error {
+	document, err := a.do(ctx, request, jsh.ObjectMode)
+	if err != nil {
+		return err
+	}
+
+	object := document.First()
+	if object == nil {
+		return nil
+	}
+
+	return unmarshalModel(object, model.JSONAPIType(), model)
+}
+
+// do sends the request with the context, returning error documents as errors
+func (a *API) do(ctx context.Context, request *http.Request, mode jsh.DocumentMode) (*jsh.Document, error) {
+	client := a.Client
+	if client == nil {
+		client = DefaultClient
+	}
+
+	document, _, err := client.Do(request.WithContext(ctx), mode)
+	if err != nil {
+		return nil, err
+	}
+
+	return document, nil
+}
+
+// modelObject marshals a model into an object
+func modelObject(model Model) (*jsh.Object, error) {
+	object, err := jsh.NewObject(model.JSONAPIID(), model.JSONAPIType(), model)
+	if err != nil {
+		return nil, err
+	}
+
+	return object, nil
+}
+
+// unmarshalModel unmarshals an object into target, setting its ID if it is an
+// IDSetter
+func unmarshalModel(object *jsh.Object, resourceType string, target interface{}) error {
+	if object == nil {
+		return fmt.Errorf("Response has no %s resource", resourceType)
+	}
+
+	errs := object.Unmarshal(resourceType, target)
+	if errs != nil {
+		return errs
+	}
+
+	if setter, isSetter := target.(IDSetter); isSetter {
+		setter.SetJSONAPIID(object.ID)
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package jsc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/derekdowling/go-json-spec-handler"
+	"github.com/derekdowling/go-json-spec-handler/jshmem"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type apiUser struct {
+	ID   string `json:"-"`
+	Name string `json:"name"`
+}
+
+func (u *apiUser) JSONAPIType() string    { return "users" }
+func (u *apiUser) JSONAPIID() string      { return u.ID }
+func (u *apiUser) SetJSONAPIID(id string) { u.ID = id }
+
+// storeHandler routes the conventional collection and member paths to a store
+func storeHandler(store *jshmem.Store) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/"+store.Type), "/")
+		params := jsh.RouteParams{ID: id}
+
+		switch {
+		case id == "" && r.Method == "GET":
+			store.List(w, r, params)
+		case id == "" && r.Method == "POST":
+			store.Create(w, r, params)
+		case r.Method == "GET":
+			store.Fetch(w, r, params)
+		case r.Method == "PATCH":
+			store.Update(w, r, params)
+		case r.Method == "DELETE":
+			store.Delete(w, r, params)
+		}
+	})
+}
+
+func TestAPI(t *testing.T) {
+
+	Convey("API Tests", t, func() {
+
+		bob, _ := jsh.NewObject("1", "users", map[string]string{"name": "Bob"})
+		store := jshmem.NewStore("users", bob)
+
+		server := httptest.NewServer(storeHandler(store))
+		Reset(server.Close)
+
+		api := NewAPI(server.URL)
+		ctx := context.Background()
+
+		Convey("->Fetch()", func() {
+			user := &apiUser{}
+			So(api.Fetch(ctx, "users", "1", user), ShouldBeNil)
+			So(user, ShouldResemble, &apiUser{ID: "1", Name: "Bob"})
+
+			Convey("should return error documents", func() {
+				err := api.Fetch(ctx, "users", "2", user)
+				So(err, ShouldNotBeNil)
+				So(IsNotFound(err), ShouldBeTrue)
+			})
+		})
+
+		Convey("->List()", func() {
+			store.Put(&jsh.Object{Type: "users", ID: "2", Attributes: []byte(`{"name": "Alice"}`)})
+
+			users := []*apiUser{}
+			So(api.List(ctx, "users", &users), ShouldBeNil)
+			So(users, ShouldResemble, []*apiUser{{ID: "1", Name: "Bob"}, {ID: "2", Name: "Alice"}})
+
+			values := []apiUser{}
+			So(api.List(ctx, "users", &values), ShouldBeNil)
+			So(len(values), ShouldEqual, 2)
+
+			So(api.List(ctx, "users", values), ShouldNotBeNil)
+		})
+
+		Convey("->Create()", func() {
+			user := &apiUser{Name: "Carol"}
+			So(api.Create(ctx, user), ShouldBeNil)
+			So(user.ID, ShouldNotBeEmpty)
+			So(store.Get(user.ID), ShouldNotBeNil)
+		})
+
+		Convey("->Create() with IDs", func() {
+			api.IDs = jsh.IDGeneratorFunc(func() (string, error) {
+				return "carol", nil
+			})
+
+			user := &apiUser{Name: "Carol"}
+			So(api.Create(ctx, user), ShouldBeNil)
+			So(user.ID, ShouldEqual, "carol")
+			So(store.Get("carol"), ShouldNotBeNil)
+		})
+
+		Convey("->Update()", func() {
+			user := &apiUser{ID: "1", Name: "Robert"}
+			So(api.Update(ctx, user), ShouldBeNil)
+
+			name, _ := store.Get("1").AttributeString("name")
+			So(name, ShouldEqual, "Robert")
+		})
+
+		Convey("->Delete()", func() {
+			So(api.Delete(ctx, "users", "1"), ShouldBeNil)
+			So(store.Get("1"), ShouldBeNil)
+
+			So(IsNotFound(api.Delete(ctx, "users", "1")), ShouldBeTrue)
+		})
+
+		Convey("should send requests with the context", func() {
+			canceled, cancel := context.WithCancel(ctx)
+			cancel()
+
+			So(api.Fetch(canceled, "users", "1", &apiUser{}), ShouldNotBeNil)
+		})
+	})
+}
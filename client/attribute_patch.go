@@ -0,0 +1,44 @@
+package jsc
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/derekdowling/go-json-spec-handler"
+)
+
+/*
+NewAttributePatchRequest builds a "PATCH /resources/:id" request updating the
+resource's attributes with JSON Patch operations, using the attribute patch
+extension, see jsh.AttributePatch:
+
+	request, err := jsc.NewAttributePatchRequest(baseURL, "articles", "1", jsh.AttributePatch{
+		{Op: jsh.ReplaceOp, Path: "/attributes/title", Value: json.RawMessage(`"Summary"`)},
+	})
+*/
+func NewAttributePatchRequest(baseURL string, resourceType string, id string, patch jsh.AttributePatch) (*http.Request, error) {
+	if resourceType == "" || id == "" {
+		return nil, jsh.SpecificationError("Type and ID cannot be empty for a PATCH request")
+	}
+
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing URL: %s", err.Error())
+	}
+
+	setIDPath(u, resourceType, id)
+
+	request, err := NewRequest("PATCH", u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("Error creating PATCH request: %s", err.Error())
+	}
+	request.Header.Set("Content-Type", jsh.AttributePatchContentType)
+
+	err = setBody(request, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
@@ -0,0 +1,59 @@
+package jsc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/derekdowling/go-json-spec-handler"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAttributePatch(t *testing.T) {
+
+	Convey("Attribute Patch Tests", t, func() {
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			patch, err := jsh.ParseAttributePatch(r)
+			if err != nil {
+				jsh.Send(w, r, err)
+				return
+			}
+
+			object, _ := jsh.NewObject("1", "articles", map[string]string{"title": "Draft"})
+			err = patch.Apply(object)
+			if err != nil {
+				jsh.Send(w, r, err)
+				return
+			}
+
+			jsh.Send(w, r, object)
+		}))
+		Reset(func() {
+			server.Close()
+		})
+
+		Convey("->NewAttributePatchRequest()", func() {
+			request, err := NewAttributePatchRequest(server.URL, "articles", "1", jsh.AttributePatch{
+				{Op: jsh.ReplaceOp, Path: "/attributes/title", Value: json.RawMessage(`"Final"`)},
+			})
+			So(err, ShouldBeNil)
+			So(request.URL.Path, ShouldEqual, "/articles/1")
+			So(request.Header.Get("Content-Type"), ShouldEqual, jsh.AttributePatchContentType)
+
+			doc, response, err := Do(request, jsh.ObjectMode)
+			So(err, ShouldBeNil)
+			So(response.StatusCode, ShouldEqual, http.StatusOK)
+
+			title, attrErr := doc.First().AttributeString("title")
+			So(attrErr, ShouldBeNil)
+			So(title, ShouldEqual, "Final")
+
+			Convey("should require a type and ID", func() {
+				_, err := NewAttributePatchRequest(server.URL, "articles", "", nil)
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
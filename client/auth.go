@@ -0,0 +1,200 @@
+package jsc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+/*
+Authenticator adds credentials to the requests a Client sends, such as an
+Authorization header:
+
+	client := &jsc.Client{Auth: jsc.BearerToken(apiToken)}
+
+Authenticators that also implement Refresher get the chance to renew their
+credentials when the server rejects them.
+*/
+type Authenticator interface {
+	Authenticate(request *http.Request) error
+}
+
+/*
+Refresher can be implemented by an Authenticator whose credentials expire, such
+as OAuth2 access tokens. When a request is answered with a 401 Unauthorized,
+Refresh is called with the rejected request, and if it succeeds the request is
+authenticated and sent again, once.
+*/
+type Refresher interface {
+	Refresh(rejected *http.Request) error
+}
+
+// AuthenticatorFunc adapts a function to an Authenticator.
+type AuthenticatorFunc func(request *http.Request) error
+
+// Authenticate calls f(request).
+func (f AuthenticatorFunc) Authenticate(request *http.Request) error {
+	return f(request)
+}
+
+// BearerToken authenticates requests with a static bearer token.
+func BearerToken(token string) Authenticator {
+	return HeaderAuth("Authorization", "Bearer "+token)
+}
+
+// BasicAuth authenticates requests with HTTP Basic authentication.
+func BasicAuth(username string, password string) Authenticator {
+	return AuthenticatorFunc(func(request *http.Request) error {
+		request.SetBasicAuth(username, password)
+		return nil
+	})
+}
+
+// HeaderAuth authenticates requests with a custom header, such as an
+// X-API-Key.
+func HeaderAuth(name string, value string) Authenticator {
+	return AuthenticatorFunc(func(request *http.Request) error {
+		request.Header.Set(name, value)
+		return nil
+	})
+}
+
+/*
+TokenAuth authenticates requests with bearer tokens it obtains from Fetch,
+caching each until it expires or the server rejects it:
+
+	client := &jsc.Client{Auth: &jsc.TokenAuth{
+		Fetch: func(ctx context.Context) (string, time.Time, error) {
+			token, err := oauthConfig.TokenSource(ctx, refreshToken).Token()
+			if err != nil {
+				return "", time.Time{}, err
+			}
+			return token.AccessToken, token.Expiry, nil
+		},
+	}}
+
+A TokenAuth is safe for concurrent use, and fetches a single new token when
+several requests are rejected at once.
+*/
+type TokenAuth struct {
+	// Fetch obtains a new access token, along with when it expires, or the
+	// zero time if it is valid until rejected
+	Fetch func(ctx context.Context) (token string, expires time.Time, err error)
+	// ExpiryMargin is how long before their expiry tokens are replaced, to
+	// allow for clock skew and latency. Defaults to 10 seconds.
+	ExpiryMargin time.Duration
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// Authenticate sets the request's Authorization header to the current token,
+// fetching one if there is none or it is about to expire.
+func (a *TokenAuth) Authenticate(request *http.Request) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token == "" || a.expiring() {
+		err := a.fetch(request.Context())
+		if err != nil {
+			return err
+		}
+	}
+
+	request.Header.Set("Authorization", "Bearer "+a.token)
+	return nil
+}
+
+// Refresh fetches a new token, unless the rejected request was sent with an
+// older token than the current one.
+func (a *TokenAuth) Refresh(rejected *http.Request) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token != "" && rejected.Header.Get("Authorization") != "Bearer "+a.token {
+		// another request already refreshed the token
+		return nil
+	}
+
+	return a.fetch(rejected.Context())
+}
+
+// expiring returns true if the current token is within the margin of its
+// expiry
+func (a *TokenAuth) expiring() bool {
+	if a.expires.IsZero() {
+		return false
+	}
+
+	margin := a.ExpiryMargin
+	if margin == 0 {
+		margin = 10 * time.Second
+	}
+
+	return time.Now().Add(margin).After(a.expires)
+}
+
+// fetch replaces the current token, the TokenAuth must be locked
+func (a *TokenAuth) fetch(ctx context.Context) error {
+	token, expires, err := a.Fetch(ctx)
+	if err != nil {
+		return fmt.Errorf("Error fetching access token: %s", err.Error())
+	}
+
+	a.token = token
+	a.expires = expires
+	return nil
+}
+
+// authenticate adds the client's credentials to the request
+func (c *Client) authenticate(request *http.Request) error {
+	if c.Auth == nil {
+		return nil
+	}
+
+	err := c.Auth.Authenticate(request)
+	if err != nil {
+		return fmt.Errorf("Error authenticating request: %s", err.Error())
+	}
+
+	return nil
+}
+
+// reauthenticate refreshes rejected credentials if the client's Authenticator
+// can, and prepares the request to be sent again, returning false if it can't
+func (c *Client) reauthenticate(request *http.Request, response *http.Response) bool {
+	refresher, canRefresh := c.Auth.(Refresher)
+	if !canRefresh || response.StatusCode != http.StatusUnauthorized {
+		return false
+	}
+	if request.Body != nil && request.GetBody == nil {
+		return false
+	}
+
+	if refresher.Refresh(request) != nil {
+		return false
+	}
+
+	if request.GetBody != nil {
+		body, err := request.GetBody()
+		if err != nil {
+			return false
+		}
+		request.Body = body
+	}
+
+	if c.authenticate(request) != nil {
+		return false
+	}
+
+	// drain so the connection can be reused
+	io.Copy(ioutil.Discard, response.Body)
+	response.Body.Close()
+
+	return true
+}
@@ -0,0 +1,125 @@
+package jsc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/derekdowling/go-json-spec-handler"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAuth(t *testing.T) {
+
+	Convey("Auth Tests", t, func() {
+
+		accepted := "Bearer fresh"
+		received := []string{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = append(received, r.Header.Get("Authorization")+" "+r.Header.Get("X-API-Key"))
+			if r.Header.Get("Authorization") != accepted && r.Header.Get("X-API-Key") == "" {
+				jsh.Send(w, r, &jsh.Error{Title: "Unauthorized", Status: http.StatusUnauthorized})
+				return
+			}
+
+			object, _ := jsh.NewObject("1", "tests", map[string]string{"name": "test"})
+			jsh.Send(w, r, object)
+		}))
+		Reset(server.Close)
+
+		fetch := func(client *Client) (*http.Response, error) {
+			request, err := FetchRequest(server.URL, "tests", "1")
+			So(err, ShouldBeNil)
+
+			_, response, err := client.Do(request, jsh.ObjectMode)
+			return response, err
+		}
+
+		Convey("should authenticate with static credentials", func() {
+			accepted = "Bearer static"
+			response, err := fetch(&Client{Auth: BearerToken("static")})
+			So(err, ShouldBeNil)
+			So(response.StatusCode, ShouldEqual, http.StatusOK)
+
+			accepted = "Basic dXNlcjpwYXNz"
+			response, err = fetch(&Client{Auth: BasicAuth("user", "pass")})
+			So(err, ShouldBeNil)
+			So(response.StatusCode, ShouldEqual, http.StatusOK)
+
+			response, err = fetch(&Client{Auth: HeaderAuth("X-API-Key", "key")})
+			So(err, ShouldBeNil)
+			So(received[len(received)-1], ShouldEqual, " key")
+		})
+
+		Convey("should fail requests that can't be authenticated", func() {
+			auth := AuthenticatorFunc(func(request *http.Request) error {
+				return errors.New("no credentials")
+			})
+
+			_, err := fetch(&Client{Auth: auth})
+			So(err, ShouldNotBeNil)
+			So(len(received), ShouldEqual, 0)
+		})
+
+		Convey("TokenAuth", func() {
+			var fetches int32
+			tokens := []string{"stale", "fresh"}
+			auth := &TokenAuth{Fetch: func(ctx context.Context) (string, time.Time, error) {
+				fetched := atomic.AddInt32(&fetches, 1)
+				return tokens[fetched-1], time.Time{}, nil
+			}}
+			client := &Client{Auth: auth}
+
+			Convey("should refresh rejected tokens and resend the request once", func() {
+				response, err := fetch(client)
+				So(err, ShouldBeNil)
+				So(response.StatusCode, ShouldEqual, http.StatusOK)
+				So(received, ShouldResemble, []string{"Bearer stale ", "Bearer fresh "})
+
+				// the refreshed token is reused
+				fetch(client)
+				So(atomic.LoadInt32(&fetches), ShouldEqual, 2)
+			})
+
+			Convey("should give up when the refreshed token is rejected too", func() {
+				accepted = "Bearer other"
+				_, err := fetch(client)
+				So(err, ShouldNotBeNil)
+				So(len(received), ShouldEqual, 2)
+			})
+
+			Convey("should not refresh tokens another request already refreshed", func() {
+				request, _ := http.NewRequest("GET", server.URL, nil)
+				So(auth.Authenticate(request), ShouldBeNil)
+				So(auth.Refresh(request), ShouldBeNil)
+				So(auth.Refresh(request), ShouldBeNil)
+				So(atomic.LoadInt32(&fetches), ShouldEqual, 2)
+			})
+
+			Convey("should replace tokens about to expire", func() {
+				auth.Fetch = func(ctx context.Context) (string, time.Time, error) {
+					atomic.AddInt32(&fetches, 1)
+					return "fresh", time.Now().Add(5 * time.Second), nil
+				}
+
+				fetch(client)
+				fetch(client)
+				So(atomic.LoadInt32(&fetches), ShouldEqual, 2)
+			})
+		})
+
+		Convey("should authenticate requests to pool services", func() {
+			pool := NewClientPool()
+			So(pool.Add("tests", &ServiceConfig{BaseURL: server.URL, Auth: BearerToken("fresh")}), ShouldBeNil)
+
+			service, _ := pool.Client("tests")
+			response, err := fetch(service.Client)
+			So(err, ShouldBeNil)
+			So(response.StatusCode, ShouldEqual, http.StatusOK)
+		})
+	})
+}
@@ -0,0 +1,121 @@
+package jsc
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+/*
+ResponseCache enables conditional GETs for a Client. Responses carrying an ETag
+or Last-Modified header are kept, later requests for the same URL are sent with
+If-None-Match and If-Modified-Since, and a 304 Not Modified from the server is
+answered with the cached body:
+
+	client := &jsc.Client{Cache: &jsc.ResponseCache{}}
+
+The zero value is ready to use. Entries are keyed by URL alone, so a cache
+shouldn't be shared by clients sending different credentials.
+*/
+type ResponseCache struct {
+	mu      sync.Mutex
+	entries map[string]*cachedResponse
+}
+
+// cachedResponse is a stored response that can be revalidated
+type cachedResponse struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+// send performs a GET request through the cache
+func (c *ResponseCache) send(request *http.Request, send func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	key := request.URL.String()
+	cached := c.get(key)
+
+	if cached != nil {
+		if etag := cached.header.Get("ETag"); etag != "" && request.Header.Get("If-None-Match") == "" {
+			request.Header.Set("If-None-Match", etag)
+		}
+
+		if modified := cached.header.Get("Last-Modified"); modified != "" && request.Header.Get("If-Modified-Since") == "" {
+			request.Header.Set("If-Modified-Since", modified)
+		}
+	}
+
+	response, err := send(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode == http.StatusNotModified && cached != nil {
+		io.Copy(ioutil.Discard, response.Body)
+		response.Body.Close()
+
+		return cached.response(request, response), nil
+	}
+
+	if response.StatusCode == http.StatusOK && (response.Header.Get("ETag") != "" || response.Header.Get("Last-Modified") != "") {
+		body, err := ioutil.ReadAll(response.Body)
+		response.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		c.set(key, &cachedResponse{
+			status: response.StatusCode,
+			header: response.Header,
+			body:   body,
+		})
+		response.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	return response, nil
+}
+
+func (c *ResponseCache) get(key string) *cachedResponse {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.entries[key]
+}
+
+func (c *ResponseCache) set(key string, cached *cachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries == nil {
+		c.entries = map[string]*cachedResponse{}
+	}
+	c.entries[key] = cached
+}
+
+/*
+response rebuilds the cached response for a request answered with a 304. The
+headers sent with the 304, such as a refreshed Cache-Control, take precedence
+over the cached ones.
+*/
+func (c *cachedResponse) response(request *http.Request, notModified *http.Response) *http.Response {
+	header := http.Header{}
+	for name, values := range c.header {
+		header[name] = values
+	}
+	for name, values := range notModified.Header {
+		header[name] = values
+	}
+
+	return &http.Response{
+		Status:        http.StatusText(c.status),
+		StatusCode:    c.status,
+		Proto:         notModified.Proto,
+		ProtoMajor:    notModified.ProtoMajor,
+		ProtoMinor:    notModified.ProtoMinor,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(c.body)),
+		ContentLength: int64(len(c.body)),
+		Request:       request,
+	}
+}
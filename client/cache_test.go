@@ -0,0 +1,61 @@
+package jsc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/derekdowling/go-json-spec-handler"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestResponseCache(t *testing.T) {
+
+	Convey("Response Cache Tests", t, func() {
+
+		renders := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			object, _ := jsh.NewObject("1", "tests", map[string]string{"foo": "bar"})
+			w.Header().Set("ETag", jsh.ETag(object))
+
+			if jsh.IsNotModified(r, w.Header()) {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+
+			renders++
+			jsh.Send(w, r, object)
+		}))
+		Reset(func() {
+			server.Close()
+		})
+
+		client := &Client{Cache: &ResponseCache{}}
+
+		fetch := func() *jsh.Document {
+			request, err := FetchRequest(server.URL, "tests", "1")
+			So(err, ShouldBeNil)
+
+			doc, resp, err := client.Do(request, jsh.ObjectMode)
+			So(err, ShouldBeNil)
+			So(resp.StatusCode, ShouldEqual, http.StatusOK)
+			return doc
+		}
+
+		Convey("should reuse cached bodies for unmodified responses", func() {
+			first := fetch()
+			second := fetch()
+
+			So(renders, ShouldEqual, 1)
+			So(second.First().ID, ShouldEqual, first.First().ID)
+		})
+
+		Convey("should not revalidate without a cache", func() {
+			client.Cache = nil
+
+			fetch()
+			fetch()
+			So(renders, ShouldEqual, 2)
+		})
+	})
+}
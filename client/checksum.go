@@ -0,0 +1,62 @@
+package jsc
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/derekdowling/go-json-spec-handler"
+)
+
+// checksumBody hashes a response body as it is read, for verification against
+// the jsh.ChecksumTrailer of streamed responses
+type checksumBody struct {
+	io.ReadCloser
+	hash hash.Hash
+}
+
+func (c *checksumBody) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.hash.Write(p[:n])
+	return n, err
+}
+
+// watchChecksum wraps the response body for checksum verification if the
+// server declared the checksum trailer, returning nil otherwise
+func watchChecksum(response *http.Response) *checksumBody {
+	if _, declared := response.Trailer[jsh.ChecksumTrailer]; !declared {
+		return nil
+	}
+
+	body := &checksumBody{ReadCloser: response.Body, hash: sha256.New()}
+	response.Body = body
+	return body
+}
+
+/*
+verify checks a fully read response against the checksum and resource
+count trailers sent by jsh.StreamList, returning an error if the transfer was
+corrupted or truncated.
+*/
+func (c *checksumBody) verify(response *http.Response, document *jsh.Document) *jsh.Error {
+	expected := response.Trailer.Get(jsh.ChecksumTrailer)
+	if expected == "" {
+		return jsh.ISE("Response declared a checksum trailer but did not send it")
+	}
+
+	actual := hex.EncodeToString(c.hash.Sum(nil))
+	if actual != expected {
+		return jsh.ISE(fmt.Sprintf("Response checksum mismatch, expected %s, got %s", expected, actual))
+	}
+
+	count := response.Trailer.Get(jsh.CountTrailer)
+	if count != "" && count != strconv.Itoa(len(document.Data)) {
+		return jsh.ISE(fmt.Sprintf("Response resource count mismatch, expected %s, got %d", count, len(document.Data)))
+	}
+
+	return nil
+}
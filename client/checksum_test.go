@@ -0,0 +1,51 @@
+package jsc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/derekdowling/go-json-spec-handler"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestChecksum(t *testing.T) {
+
+	Convey("Checksum Tests", t, func() {
+
+		corrupt := false
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			sent := 0
+			jsh.StreamList(w, r, func() (*jsh.Object, *jsh.Error) {
+				if sent == 3 {
+					return nil, nil
+				}
+				sent++
+				return jsh.NewObject(strconv.Itoa(sent), "tests", map[string]int{"n": sent})
+			}, &jsh.StreamOptions{Checksum: true})
+
+			// trailers can still be changed once the handler's body is written
+			if corrupt {
+				w.Header().Set(jsh.ChecksumTrailer, "corrupt")
+			}
+		}))
+		Reset(func() {
+			server.Close()
+		})
+
+		Convey("should verify streamed responses", func() {
+			doc, resp, err := List(server.URL, "tests")
+			So(err, ShouldBeNil)
+			So(len(doc.Data), ShouldEqual, 3)
+			So(resp.Trailer.Get(jsh.CountTrailer), ShouldEqual, "3")
+		})
+
+		Convey("should reject responses that fail verification", func() {
+			corrupt = true
+
+			_, _, err := List(server.URL, "tests")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -1,7 +1,6 @@
 package jsc
 
 import (
-	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -15,14 +14,24 @@ import (
 
 /*
 Document validates the HTTP response and attempts to parse a JSON API compatible
-Document from the response body before closing it.
+Document from the response body before closing it. Responses streamed with
+jsh.StreamOptions.Checksum are verified against their checksum trailers.
 */
 func Document(response *http.Response, mode jsh.DocumentMode) (*jsh.Document, *jsh.Error) {
+	checksum := watchChecksum(response)
+
 	document, err := buildParser(response).Document(response.Body, mode)
 	if err != nil {
 		return nil, err
 	}
 
+	if checksum != nil {
+		err = checksum.verify(response, document)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	document.Status = response.StatusCode
 	return document, nil
 }
@@ -41,15 +50,28 @@ func DumpBody(response *http.Response) (string, *jsh.Error) {
 	return string(byteData), nil
 }
 
+// buildParser creates a parser for a response document. Responses aren't
+// capped at a maximum size, since lists from trusted servers can grow large.
 func buildParser(response *http.Response) *jsh.Parser {
-	return &jsh.Parser{
+	options := jsh.DefaultParseOptions
+	options.MaxBodySize = -1
+
+	parser := &jsh.Parser{
 		Method:  "",
 		Headers: response.Header,
+		Options: &options,
+	}
+
+	if response.Request != nil {
+		parser.Context = response.Request.Context()
 	}
+
+	return parser
 }
 
 /*
-setPath builds a JSON url.Path for a given resource type.
+setPath builds a JSON url.Path for a given resource type, using the path
+registered with jsh.RegisterPath if there is one.
 */
 func setPath(url *url.URL, resource string) {
 
@@ -59,7 +81,7 @@ func setPath(url *url.URL, resource string) {
 	}
 
 	// don't pluralize resource automagically, JSON API spec is agnostic
-	url.Path = fmt.Sprintf("%s%s", url.Path, resource)
+	url.Path = fmt.Sprintf("%s%s", url.Path, jsh.ResourcePath(resource))
 }
 
 /*
@@ -75,6 +97,15 @@ func setIDPath(url *url.URL, resource string, id string) {
 	}
 }
 
+/*
+setRelationshipPath creates a JSON url.Path for a relationship endpoint, such as
+/articles/1/relationships/tags.
+*/
+func setRelationshipPath(url *url.URL, resource string, id string, relationship string) {
+	setIDPath(url, resource, id)
+	url.Path = strings.Join([]string{url.Path, "relationships", relationship}, "/")
+}
+
 /*
 prepareBody first prepares/validates the object to ensure it is JSON
 spec compatible, and then marshals it to JSON, sets the request body and
@@ -87,19 +118,60 @@ func prepareBody(request *http.Request, object *jsh.Object) error {
 		return fmt.Errorf("Error preparing object: %s", err.Error())
 	}
 
-	doc := jsh.Build(object)
+	return setBody(request, jsh.Build(object))
+}
 
-	jsonContent, jsonErr := json.MarshalIndent(doc, "", " ")
+// setBody marshals the payload to JSON and sets it as the request body
+func setBody(request *http.Request, payload interface{}) error {
+	jsonContent, jsonErr := jsh.MarshalJSON(payload)
 	if jsonErr != nil {
 		return fmt.Errorf("Unable to prepare JSON content: %s", jsonErr.Error())
 	}
 
 	request.Body = jsh.CreateReadCloser(jsonContent)
 	request.ContentLength = int64(len(jsonContent))
+	request.GetBody = func() (io.ReadCloser, error) {
+		return jsh.CreateReadCloser(jsonContent), nil
+	}
 
 	return nil
 }
 
+/*
+Client sends requests to a JSON API compatible endpoint. The zero value is ready
+to use and behaves like the package level Do, set Retry to automatically retry
+requests that fail transiently:
+
+	client := &jsc.Client{Retry: jsc.DefaultRetryPolicy}
+	doc, resp, err := client.Do(request, jsh.ObjectMode)
+*/
+type Client struct {
+	// HTTPClient is used to send requests, http.DefaultClient if nil
+	HTTPClient *http.Client
+	// Retry enables retries according to the policy, nil disables retries
+	Retry *RetryPolicy
+	// Cache enables conditional GETs, reusing cached bodies for responses
+	// that haven't been modified. nil disables caching.
+	Cache *ResponseCache
+	// Consistency enables read-your-writes consistency tokens, nil disables
+	// them.
+	Consistency *ConsistencySession
+	// Profiles are requested in the Accept header of requests without one.
+	// The profiles applied by the server are listed in the JSONAPI.Profile of
+	// response documents.
+	Profiles []string
+	// Auth adds credentials to every request, nil sends them as they are, see
+	// Authenticator.
+	Auth Authenticator
+	// Throttle delays requests to stay under the rate limit the server
+	// advertises, nil sends them regardless, see Throttle.
+	Throttle *Throttle
+}
+
+// DefaultClient is the Client used by Do and the method helpers such as Fetch
+// and Post.
+var DefaultClient = &Client{}
+
 /*
 Do sends a the specified request to a JSON API compatible endpoint and
 returns the resulting JSON Document if possible along with the response,
@@ -111,9 +183,14 @@ for times when you want to send a request to a custom endpoint, but would still
 like a JSONAPI response.
 */
 func Do(request *http.Request, mode jsh.DocumentMode) (*jsh.Document, *http.Response, error) {
+	return DefaultClient.Do(request, mode)
+}
 
-	client := &http.Client{}
-	response, clientErr := client.Do(request)
+// Do sends the request using the client's configuration, see the package level
+// Do for details.
+func (c *Client) Do(request *http.Request, mode jsh.DocumentMode) (*jsh.Document, *http.Response, error) {
+
+	response, clientErr := c.send(request)
 
 	if clientErr != nil {
 		return nil, nil, fmt.Errorf(
@@ -123,6 +200,11 @@ func Do(request *http.Request, mode jsh.DocumentMode) (*jsh.Document, *http.Resp
 
 	doc, parseErr := ParseResponse(response, mode)
 	if parseErr != nil {
+		// error documents are passed through as is so they can be inspected
+		if errorList, isErrorList := parseErr.(jsh.ErrorList); isErrorList {
+			return doc, response, errorList
+		}
+
 		return nil, response, fmt.Errorf("Error parsing response: %s", parseErr.Error())
 	}
 
@@ -131,19 +213,18 @@ func Do(request *http.Request, mode jsh.DocumentMode) (*jsh.Document, *http.Resp
 
 /*
 ParseResponse handles parsing an HTTP response into a JSON Document if
-possible.
+possible. If the server responded with an error document, the document is
+returned along with its jsh.ErrorList as the error.
 */
 func ParseResponse(response *http.Response, mode jsh.DocumentMode) (*jsh.Document, error) {
 
-	skipCodes := []int{
-		http.StatusNoContent,
-		http.StatusNotFound,
+	if response.StatusCode == http.StatusNoContent {
+		return nil, nil
 	}
 
-	for _, code := range skipCodes {
-		if code == response.StatusCode {
-			return nil, nil
-		}
+	// only expect a document for a 404 if the server indicated it sent one
+	if response.StatusCode == http.StatusNotFound && response.Header.Get("Content-Type") != jsh.ContentType {
+		return nil, nil
 	}
 
 	document, err := Document(response, mode)
@@ -151,6 +232,10 @@ func ParseResponse(response *http.Response, mode jsh.DocumentMode) (*jsh.Documen
 		return nil, err
 	}
 
+	if document.HasErrors() {
+		return document, document.Errors
+	}
+
 	return document, nil
 }
 
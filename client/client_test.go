@@ -1,8 +1,10 @@
 package jsc
 
 import (
+	"io/ioutil"
 	"log"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"testing"
 
@@ -32,6 +34,14 @@ func TestClientRequest(t *testing.T) {
 				setPath(url, "test")
 				So(url.String(), ShouldEqual, "//test/admin/test")
 			})
+
+			Convey("should use registered paths", func() {
+				jsh.RegisterPath("userProfile", "user-profiles")
+				Reset(func() { jsh.RegisterPath("userProfile", "") })
+
+				setPath(url, "userProfile")
+				So(url.String(), ShouldEqual, "//test/user-profiles")
+			})
 		})
 
 		Convey("->setIDPath()", func() {
@@ -43,6 +53,29 @@ func TestClientRequest(t *testing.T) {
 			})
 		})
 
+		Convey("->setBody()", func() {
+			request, _ := http.NewRequest("POST", "//test/tests", nil)
+			payload := map[string]string{"foo": "bar"}
+
+			Convey("should send compact JSON", func() {
+				So(setBody(request, payload), ShouldBeNil)
+
+				body, _ := ioutil.ReadAll(request.Body)
+				So(string(body), ShouldEqual, `{"foo":"bar"}`)
+				So(request.ContentLength, ShouldEqual, len(body))
+			})
+
+			Convey("should indent JSON if PrettyJSON is on", func() {
+				jsh.SetPrettyJSON(true)
+				Reset(func() { jsh.SetPrettyJSON(false) })
+
+				So(setBody(request, payload), ShouldBeNil)
+
+				body, _ := ioutil.ReadAll(request.Body)
+				So(string(body), ShouldEqual, "{\n \"foo\": \"bar\"\n}")
+			})
+		})
+
 	})
 }
 
@@ -104,6 +137,31 @@ func TestResponseParsing(t *testing.T) {
 	})
 }
 
+func TestClientProfiles(t *testing.T) {
+
+	Convey("Client Profile Tests", t, func() {
+
+		profile := "https://example.com/profiles/timestamps"
+		jsh.Profiles = []string{profile}
+		Reset(func() { jsh.Profiles = nil })
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			object, _ := jsh.NewObject("1", "tests", map[string]string{"foo": "bar"})
+			jsh.Send(w, r, object)
+		}))
+		Reset(func() { server.Close() })
+
+		Convey("should request profiles and receive those applied", func() {
+			client := &Client{Profiles: []string{profile}}
+
+			request, _ := FetchRequest(server.URL, "tests", "1")
+			doc, _, err := client.Do(request, jsh.ObjectMode)
+			So(err, ShouldBeNil)
+			So(doc.JSONAPI.Profile, ShouldResemble, []string{profile})
+		})
+	})
+}
+
 // not a great for this, would much rather have it in test_util, but it causes an
 // import cycle wit jsh-api
 func testAPI() *jshapi.API {
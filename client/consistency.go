@@ -0,0 +1,56 @@
+package jsc
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/derekdowling/go-json-spec-handler"
+)
+
+/*
+ConsistencySession gives a Client read-your-writes consistency against servers
+returning consistency tokens, see jsh.SetConsistencyToken. The latest token
+received is echoed on subsequent requests, so reads reflect the session's
+earlier writes even when served by an eventually consistent backend:
+
+	client := &jsc.Client{Consistency: &jsc.ConsistencySession{}}
+
+The zero value is ready to use. Share a session only between requests made on
+behalf of the same user or workflow.
+*/
+type ConsistencySession struct {
+	mu    sync.RWMutex
+	token string
+}
+
+// Token returns the latest consistency token received by the session.
+func (s *ConsistencySession) Token() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.token
+}
+
+// SetToken replaces the session's consistency token, such as to resume a
+// session from a token stored elsewhere.
+func (s *ConsistencySession) SetToken(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.token = token
+}
+
+// prepare echoes the session's token on a request that doesn't carry one
+func (s *ConsistencySession) prepare(request *http.Request) {
+	token := s.Token()
+	if token != "" && request.Header.Get(jsh.ConsistencyHeader) == "" {
+		request.Header.Set(jsh.ConsistencyHeader, token)
+	}
+}
+
+// record keeps the token of a response, if it has one
+func (s *ConsistencySession) record(response *http.Response) {
+	if token := response.Header.Get(jsh.ConsistencyHeader); token != "" {
+		s.SetToken(token)
+	}
+}
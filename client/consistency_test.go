@@ -0,0 +1,55 @@
+package jsc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/derekdowling/go-json-spec-handler"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestConsistencySession(t *testing.T) {
+
+	Convey("Consistency Session Tests", t, func() {
+
+		echoed := ""
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			echoed = jsh.ConsistencyToken(r)
+
+			object, _ := jsh.NewObject("1", "tests", map[string]string{"foo": "bar"})
+			if r.Method == "PATCH" {
+				jsh.SetConsistencyToken(w, "7")
+			}
+			jsh.Send(w, r, object)
+		}))
+		Reset(func() {
+			server.Close()
+		})
+
+		session := &ConsistencySession{}
+		client := &Client{Consistency: session}
+
+		Convey("should echo the token of earlier writes", func() {
+			object, _ := jsh.NewObject("1", "tests", map[string]string{"foo": "baz"})
+			patch, err := PatchRequest(server.URL, object)
+			So(err, ShouldBeNil)
+
+			_, _, err = client.Do(patch, jsh.ObjectMode)
+			So(err, ShouldBeNil)
+			So(session.Token(), ShouldEqual, "7")
+
+			fetch, _ := FetchRequest(server.URL, "tests", "1")
+			_, _, err = client.Do(fetch, jsh.ObjectMode)
+			So(err, ShouldBeNil)
+			So(echoed, ShouldEqual, "7")
+		})
+
+		Convey("should not send a token before one is received", func() {
+			fetch, _ := FetchRequest(server.URL, "tests", "1")
+			_, _, err := client.Do(fetch, jsh.ObjectMode)
+			So(err, ShouldBeNil)
+			So(echoed, ShouldEqual, "")
+		})
+	})
+}
@@ -33,6 +33,18 @@ This is useful for if you need to set custom headers on the request. Otherwise
 just use "jsc.Delete".
 */
 func DeleteRequest(urlStr string, resourceType string, id string) (*http.Request, error) {
+	return NewDeleteRequest(urlStr, resourceType, id)
+}
+
+/*
+NewDeleteRequest builds a "DELETE /resource/:id" request. Deleting a resource
+only requires its type and ID, so no object or body is needed.
+*/
+func NewDeleteRequest(urlStr string, resourceType string, id string) (*http.Request, error) {
+	if resourceType == "" || id == "" {
+		return nil, jsh.SpecificationError("Type and ID cannot be empty for a DELETE request")
+	}
+
 	u, err := url.Parse(urlStr)
 	if err != nil {
 		return nil, jsh.ISE(fmt.Sprintf("Error parsing URL: %s", err.Error()))
@@ -47,3 +59,122 @@ func DeleteRequest(urlStr string, resourceType string, id string) (*http.Request
 
 	return request, nil
 }
+
+/*
+DeleteRelationship removes members from a to-many relationship with an outbound
+"DELETE /resource/:id/relationships/:relationship" request, which carries the
+members to remove in its body.
+
+	tag := &jsh.ResourceIdentifier{Type: "tags", ID: "2"}
+	resp, err := jsc.DeleteRelationship("http://apiserver", "articles", "1", "tags", tag)
+*/
+func DeleteRelationship(urlStr string, resourceType string, id string, relationship string, identifiers ...*jsh.ResourceIdentifier) (*http.Response, error) {
+	request, err := DeleteRelationshipRequest(urlStr, resourceType, id, relationship, identifiers...)
+	if err != nil {
+		return nil, err
+	}
+
+	_, response, err := Do(request, jsh.ObjectMode)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+/*
+DeleteRelationshipRequest returns a fully formatted request for removing members
+from a to-many relationship. This is useful for if you need to set custom
+headers on the request. Otherwise just use "jsc.DeleteRelationship".
+*/
+func DeleteRelationshipRequest(urlStr string, resourceType string, id string, relationship string, identifiers ...*jsh.ResourceIdentifier) (*http.Request, error) {
+	if id == "" || relationship == "" {
+		return nil, jsh.SpecificationError("ID and relationship cannot be empty for a relationship DELETE request")
+	}
+
+	if len(identifiers) == 0 {
+		return nil, jsh.SpecificationError("At least one relationship member must be provided to DELETE")
+	}
+
+	u, err := url.Parse(urlStr)
+	if err != nil {
+		return nil, jsh.ISE(fmt.Sprintf("Error parsing URL: %s", err.Error()))
+	}
+
+	setRelationshipPath(u, resourceType, id, relationship)
+
+	request, err := NewRequest("DELETE", u.String(), nil)
+	if err != nil {
+		return nil, jsh.ISE(fmt.Sprintf("Error creating DELETE request: %s", err.Error()))
+	}
+
+	err = setBody(request, &jsh.Relationship{
+		Data:        identifiers,
+		Cardinality: jsh.ToMany,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+/*
+ClearRelationship empties a relationship with an outbound
+"PATCH /resource/:id/relationships/:relationship" request. To-one relationships
+can't be deleted, instead their linkage is set to null, while to-many
+relationships are set to an empty array.
+
+	resp, err := jsc.ClearRelationship("http://apiserver", "articles", "1", "author", jsh.ToOne)
+*/
+func ClearRelationship(urlStr string, resourceType string, id string, relationship string, cardinality jsh.Cardinality) (*http.Response, error) {
+	request, err := ClearRelationshipRequest(urlStr, resourceType, id, relationship, cardinality)
+	if err != nil {
+		return nil, err
+	}
+
+	_, response, err := Do(request, jsh.ObjectMode)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+/*
+ClearRelationshipRequest returns a fully formatted request for emptying a
+relationship. This is useful for if you need to set custom headers on the
+request. Otherwise just use "jsc.ClearRelationship".
+*/
+func ClearRelationshipRequest(urlStr string, resourceType string, id string, relationship string, cardinality jsh.Cardinality) (*http.Request, error) {
+	if id == "" || relationship == "" {
+		return nil, jsh.SpecificationError("ID and relationship cannot be empty for a relationship PATCH request")
+	}
+
+	u, err := url.Parse(urlStr)
+	if err != nil {
+		return nil, jsh.ISE(fmt.Sprintf("Error parsing URL: %s", err.Error()))
+	}
+
+	setRelationshipPath(u, resourceType, id, relationship)
+
+	request, err := NewRequest("PATCH", u.String(), nil)
+	if err != nil {
+		return nil, jsh.ISE(fmt.Sprintf("Error creating PATCH request: %s", err.Error()))
+	}
+
+	// empty linkage is always sent explicitly, regardless of jsh.EmptyRelationships
+	linkage := struct {
+		Data interface{} `json:"data"`
+	}{Data: jsh.ResourceLinkage{}}
+	if cardinality == jsh.ToOne {
+		linkage.Data = nil
+	}
+
+	err = setBody(request, linkage)
+	if err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
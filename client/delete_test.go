@@ -5,6 +5,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/derekdowling/go-json-spec-handler"
 	. "github.com/smartystreets/goconvey/convey"
 )
 
@@ -24,5 +25,74 @@ func TestDelete(t *testing.T) {
 			So(err, ShouldBeNil)
 			So(resp.StatusCode, ShouldEqual, http.StatusNoContent)
 		})
+
+		Convey("->NewDeleteRequest()", func() {
+			request, err := NewDeleteRequest(baseURL, "tests", "1")
+			So(err, ShouldBeNil)
+			So(request.Method, ShouldEqual, "DELETE")
+			So(request.URL.Path, ShouldEqual, "/tests/1")
+			So(request.Body, ShouldBeNil)
+
+			Convey("should require an ID", func() {
+				_, err := NewDeleteRequest(baseURL, "tests", "")
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("->ClearRelationshipRequest()", func() {
+
+			Convey("should send null for to-one relationships", func() {
+				request, err := ClearRelationshipRequest(baseURL, "articles", "1", "author", jsh.ToOne)
+				So(err, ShouldBeNil)
+				So(request.Method, ShouldEqual, "PATCH")
+				So(request.URL.Path, ShouldEqual, "/articles/1/relationships/author")
+
+				relationship, parseErr := jsh.NewParser(request).Relationship(request.Body)
+				So(parseErr, ShouldBeNil)
+				So(relationship.Cardinality, ShouldEqual, jsh.ToOne)
+				So(relationship.Data, ShouldBeEmpty)
+			})
+
+			Convey("should send an empty array for to-many relationships", func() {
+				request, err := ClearRelationshipRequest(baseURL, "articles", "1", "tags", jsh.ToMany)
+				So(err, ShouldBeNil)
+
+				relationship, parseErr := jsh.NewParser(request).Relationship(request.Body)
+				So(parseErr, ShouldBeNil)
+				So(relationship.Cardinality, ShouldEqual, jsh.ToMany)
+			})
+		})
+
+		Convey("->DeleteRelationship()", func() {
+			var removed jsh.ResourceLinkage
+			var path string
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				path = r.URL.Path
+
+				relationship, err := jsh.ParseRelationship(r)
+				if err != nil {
+					jsh.Send(w, r, err)
+					return
+				}
+
+				removed = relationship.Data
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer server.Close()
+
+			tag := &jsh.ResourceIdentifier{Type: "tags", ID: "2"}
+			resp, err := DeleteRelationship(server.URL, "articles", "1", "tags", tag)
+
+			So(err, ShouldBeNil)
+			So(resp.StatusCode, ShouldEqual, http.StatusNoContent)
+			So(path, ShouldEqual, "/articles/1/relationships/tags")
+			So(removed, ShouldResemble, jsh.ResourceLinkage{tag})
+
+			Convey("should require members to remove", func() {
+				_, err := DeleteRelationshipRequest(server.URL, "articles", "1", "tags")
+				So(err, ShouldNotBeNil)
+			})
+		})
 	})
 }
@@ -0,0 +1,122 @@
+package jsc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+
+	"github.com/derekdowling/go-json-spec-handler"
+)
+
+/*
+Inline fetches the attributes a server externalized from an object, see
+jsh.Externalization, and puts them back in place:
+
+	doc, _, err := jsc.Fetch("http://api.example.com", "builds", "42")
+	...
+	build := doc.First()
+	err = jsc.Inline(build, "log")
+
+Only the named attributes are fetched, or every externalized attribute if none
+are named. Each value is checked against its digest before the object's link
+and meta entry are replaced by the attribute.
+*/
+func Inline(object *jsh.Object, attributes ...string) error {
+	return DefaultClient.Inline(object, attributes...)
+}
+
+// Inline fetches externalized attributes using the client's configuration, see
+// the package level Inline for details.
+func (c *Client) Inline(object *jsh.Object, attributes ...string) error {
+	externalized, err := externalizedAttributes(object)
+	if err != nil {
+		return err
+	}
+
+	if len(attributes) == 0 {
+		for name := range externalized {
+			attributes = append(attributes, name)
+		}
+		sort.Strings(attributes)
+	}
+
+	for _, name := range attributes {
+		described, exists := externalized[name]
+		link := object.Links[name]
+		if !exists || link == nil {
+			return fmt.Errorf("Attribute '%s' of %s is not externalized", name, object.String())
+		}
+
+		value, err := c.fetchAttribute(link.HREF)
+		if err != nil {
+			return fmt.Errorf("Error fetching attribute '%s': %s", name, err.Error())
+		}
+
+		if digest := jsh.AttributeDigest(value); digest != described.Digest {
+			return fmt.Errorf("Attribute '%s' digest mismatch, expected %s, got %s", name, described.Digest, digest)
+		}
+
+		if setErr := object.SetAttribute(name, value); setErr != nil {
+			return setErr
+		}
+
+		delete(object.Links, name)
+		delete(externalized, name)
+	}
+
+	if len(externalized) == 0 {
+		delete(object.Meta, jsh.ExternalizedMeta)
+	} else {
+		object.Meta[jsh.ExternalizedMeta] = externalized
+	}
+
+	return nil
+}
+
+// externalizedAttributes decodes the meta.externalized entries of an object
+func externalizedAttributes(object *jsh.Object) (map[string]*jsh.ExternalizedAttribute, error) {
+	externalized := map[string]*jsh.ExternalizedAttribute{}
+
+	raw, exists := object.Meta[jsh.ExternalizedMeta]
+	if !exists {
+		return externalized, nil
+	}
+
+	// parsed documents hold generic JSON, sent ones the typed entries
+	encoded, err := json.Marshal(raw)
+	if err == nil {
+		err = json.Unmarshal(encoded, &externalized)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Invalid %s meta: %s", jsh.ExternalizedMeta, err.Error())
+	}
+
+	return externalized, nil
+}
+
+// fetchAttribute fetches the encoded value of an externalized attribute
+func (c *Client) fetchAttribute(href string) (json.RawMessage, error) {
+	request, err := http.NewRequest("GET", href, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.send(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", response.StatusCode)
+	}
+
+	value, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.RawMessage(value), nil
+}
@@ -0,0 +1,64 @@
+package jsc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/derekdowling/go-json-spec-handler"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestInline(t *testing.T) {
+
+	Convey("Inline Tests", t, func() {
+
+		log := json.RawMessage(`"a very long build log"`)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/blobs/log":
+				w.Write(log)
+			case "/blobs/corrupt":
+				w.Write([]byte(`"something else"`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		Reset(func() {
+			server.Close()
+		})
+
+		build, _ := jsh.NewObject("1", "builds", map[string]string{"status": "passed"})
+		build.Links["log"] = &jsh.Link{HREF: server.URL + "/blobs/log"}
+		build.Meta = map[string]interface{}{
+			jsh.ExternalizedMeta: map[string]interface{}{
+				"log": map[string]interface{}{"size": float64(len(log)), "digest": jsh.AttributeDigest(log)},
+			},
+		}
+
+		Convey("should fetch and verify externalized attributes", func() {
+			So(Inline(build), ShouldBeNil)
+
+			value, err := build.AttributeString("log")
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, "a very long build log")
+			So(build.Links["log"], ShouldBeNil)
+			So(build.Meta[jsh.ExternalizedMeta], ShouldBeNil)
+		})
+
+		Convey("should reject values that don't match their digest", func() {
+			build.Links["log"].HREF = server.URL + "/blobs/corrupt"
+
+			err := Inline(build, "log")
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "digest mismatch")
+			So(build.HasAttribute("log"), ShouldBeFalse)
+		})
+
+		Convey("should reject attributes that aren't externalized", func() {
+			err := Inline(build, "status")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,114 @@
+package jsc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/derekdowling/go-json-spec-handler"
+)
+
+// DefaultPollInterval is how long WaitForCompletion waits between polls of
+// jobs sent without a Retry-After header.
+var DefaultPollInterval = time.Second
+
+/*
+WaitForCompletion polls the status resource of a long-running job, such as one
+a 202 Accepted pointed to, see JobURL, until it is done, and returns the final
+document:
+
+	doc, response, err := jsc.Post(baseURL, object)
+	...
+	if response.StatusCode == http.StatusAccepted {
+		doc, err = jsc.WaitForCompletion(ctx, jsc.JobURL(response))
+	}
+
+Polls wait the Retry-After sent with the job, or DefaultPollInterval. Once the
+job completes, servers following jsh.SendJob redirect to its result, so the
+document is that of the resource the job produced, otherwise it's the completed
+job. A failed job is returned along with an error holding its jsh.Job.Error.
+*/
+func WaitForCompletion(ctx context.Context, jobURL string) (*jsh.Document, error) {
+	return DefaultClient.WaitForCompletion(ctx, jobURL)
+}
+
+// WaitForCompletion polls the job using the client's configuration, see the
+// package level WaitForCompletion for details.
+func (c *Client) WaitForCompletion(ctx context.Context, jobURL string) (*jsh.Document, error) {
+	for {
+		request, err := NewRequest("GET", jobURL, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		document, response, err := c.Do(request.WithContext(ctx), jsh.ObjectMode)
+		if err != nil {
+			return document, err
+		}
+
+		job, jobErr := documentJob(document)
+		if jobErr != nil {
+			return document, jobErr
+		}
+		if job == nil || job.Status == jsh.JobCompleted {
+			return document, nil
+		}
+		if job.Status == jsh.JobFailed {
+			return document, fmt.Errorf("Job %s failed: %s", job.ID, job.Error)
+		}
+
+		delay, ok := retryAfter(response)
+		if !ok {
+			delay = DefaultPollInterval
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// JobURL returns the URL of the job status resource a 202 Accepted response
+// points to, from its Content-Location or Location header, or an empty string
+// if it has neither.
+func JobURL(response *http.Response) string {
+	location := response.Header.Get("Content-Location")
+	if location == "" {
+		location = response.Header.Get("Location")
+	}
+	if location == "" || response.Request == nil {
+		return location
+	}
+
+	resolved, err := response.Request.URL.Parse(location)
+	if err != nil {
+		return location
+	}
+
+	return resolved.String()
+}
+
+// documentJob returns the job a document holds, or nil if its primary data
+// isn't one
+func documentJob(document *jsh.Document) (*jsh.Job, error) {
+	if document == nil {
+		return nil, nil
+	}
+
+	object := document.First()
+	if object == nil || object.Type != jsh.JobType {
+		return nil, nil
+	}
+
+	job, err := jsh.JobFromObject(object)
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
@@ -0,0 +1,88 @@
+package jsc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/derekdowling/go-json-spec-handler"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestJobs(t *testing.T) {
+
+	Convey("Job Tests", t, func() {
+
+		polls := 0
+		retry := "0"
+		job := &jsh.Job{ID: "1", Status: jsh.JobPending}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/widgets/9" {
+				object, _ := jsh.NewObject("9", "widgets", map[string]string{"name": "gear"})
+				jsh.Send(w, r, object)
+				return
+			}
+
+			polls++
+			if polls == 3 && job.Status == jsh.JobPending {
+				job.Status = jsh.JobCompleted
+				job.Result = &jsh.ResourceIdentifier{Type: "widgets", ID: "9"}
+			}
+
+			w.Header().Set("Retry-After", retry)
+			jsh.SendJob(w, r, job)
+		}))
+		Reset(server.Close)
+
+		jobURL := server.URL + "/jobs/1"
+
+		Convey("->WaitForCompletion()", func() {
+
+			Convey("should poll until the job completes and follow it to its result", func() {
+				doc, err := WaitForCompletion(context.Background(), jobURL)
+				So(err, ShouldBeNil)
+				So(polls, ShouldEqual, 3)
+				So(doc.First().Type, ShouldEqual, "widgets")
+				So(doc.First().ID, ShouldEqual, "9")
+			})
+
+			Convey("should return failed jobs with an error", func() {
+				job.Status = jsh.JobFailed
+				job.Error = "out of gears"
+
+				doc, err := WaitForCompletion(context.Background(), jobURL)
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "out of gears")
+				So(doc.First().Type, ShouldEqual, jsh.JobType)
+			})
+
+			Convey("should stop once the context ends", func() {
+				job.Status = jsh.JobRunning
+				retry = ""
+
+				ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+				defer cancel()
+
+				_, err := WaitForCompletion(ctx, jobURL)
+				So(err == context.DeadlineExceeded, ShouldBeTrue)
+			})
+		})
+
+		Convey("->JobURL()", func() {
+
+			Convey("should resolve the job's location against the request", func() {
+				request, _ := http.NewRequest("POST", server.URL+"/widgets", nil)
+				response := &http.Response{Header: http.Header{}, Request: request}
+				So(JobURL(response), ShouldBeEmpty)
+
+				response.Header.Set("Location", "/jobs/2")
+				So(JobURL(response), ShouldEqual, server.URL+"/jobs/2")
+
+				response.Header.Set("Content-Location", "/jobs/1")
+				So(JobURL(response), ShouldEqual, jobURL)
+			})
+		})
+	})
+}
@@ -0,0 +1,103 @@
+package jsc
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/derekdowling/go-json-spec-handler"
+)
+
+/*
+Send sends the request like Do, but returns the result as a Response. As with
+NewResponse, if the server responded with an error document the Response is
+returned along with its jsh.ErrorList.
+*/
+func (c *Client) Send(request *http.Request, mode jsh.DocumentMode) (*Response, error) {
+	document, response, err := c.Do(request, mode)
+	if response == nil {
+		return nil, err
+	}
+
+	return &Response{Response: response, Document: document}, err
+}
+
+/*
+NextPage builds a request for the page referenced by the document's top-level
+"next" link, resolved relative to the request that produced the response. The
+headers of the original request are preserved. If there is no next page, nil is
+returned.
+*/
+func (r *Response) NextPage() (*http.Request, error) {
+	if r.Document == nil || r.Document.Links == nil || r.Document.Links.Next == nil {
+		return nil, nil
+	}
+
+	href := r.Document.Links.Next.HREF
+	if href == "" {
+		return nil, nil
+	}
+
+	if r.Request == nil {
+		return NewRequest("GET", href, nil)
+	}
+
+	next, err := r.Request.URL.Parse(href)
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing next page link: %s", err.Error())
+	}
+
+	request, err := NewRequest("GET", next.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, values := range r.Request.Header {
+		request.Header[key] = values
+	}
+
+	return request.WithContext(r.Request.Context()), nil
+}
+
+/*
+GetAll fetches every page of a list, following the "next" link of each response
+until there are no more, and returns the objects of all of the pages. If a page
+fails, the objects fetched so far are returned along with the error:
+
+	request, _ := jsc.ListRequest("http://apiserver", "users")
+	users, err := client.GetAll(request)
+*/
+func (c *Client) GetAll(request *http.Request) (jsh.List, error) {
+	list := jsh.List{}
+	visited := map[string]bool{}
+
+	for request != nil {
+		if visited[request.URL.String()] {
+			return list, fmt.Errorf("Pagination loop detected at %s", request.URL.String())
+		}
+		visited[request.URL.String()] = true
+
+		response, err := c.Send(request, jsh.ListMode)
+		if err != nil {
+			return list, err
+		}
+
+		if response.Document == nil {
+			return list, nil
+		}
+
+		list = append(list, response.Document.Data...)
+
+		request, err = response.NextPage()
+		if err != nil {
+			return list, err
+		}
+	}
+
+	return list, nil
+}
+
+// GetAll fetches every page of a list using the DefaultClient, see
+// Client.GetAll.
+func GetAll(request *http.Request) (jsh.List, error) {
+	return DefaultClient.GetAll(request)
+}
@@ -0,0 +1,99 @@
+package jsc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/derekdowling/go-json-spec-handler"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// testPagedServer serves a list of total "tests" objects in pages of pageSize
+func testPagedServer(total int, pageSize int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test" {
+			jsh.Send(w, r, jsh.ParameterError("missing authorization", "Authorization"))
+			return
+		}
+
+		page, err := jsh.ParseCursorPage(r, nil)
+		if err != nil {
+			jsh.Send(w, r, err)
+			return
+		}
+
+		start, _ := strconv.Atoi(page.Position)
+		list := jsh.List{}
+		for i := start; i < total && i < start+pageSize; i++ {
+			object, _ := jsh.NewObject(strconv.Itoa(i), "tests", map[string]int{"index": i})
+			list = append(list, object)
+		}
+
+		next := ""
+		if start+pageSize < total {
+			next = strconv.Itoa(start + pageSize)
+		}
+
+		doc := jsh.Build(list)
+		doc.Links, err = page.Links(next, "")
+		if err != nil {
+			jsh.Send(w, r, err)
+			return
+		}
+
+		jsh.SendDocument(w, r, doc)
+	}))
+}
+
+func TestPagination(t *testing.T) {
+
+	Convey("Pagination Tests", t, func() {
+
+		server := testPagedServer(5, 2)
+		Reset(func() {
+			server.Close()
+		})
+
+		request, err := ListRequest(server.URL, "tests")
+		So(err, ShouldBeNil)
+		request.Header.Set("Authorization", "Bearer test")
+
+		client := &Client{}
+
+		Convey("->NextPage()", func() {
+			response, err := client.Send(request, jsh.ListMode)
+			So(err, ShouldBeNil)
+			So(len(response.Document.Data), ShouldEqual, 2)
+
+			next, err := response.NextPage()
+			So(err, ShouldBeNil)
+			So(next, ShouldNotBeNil)
+			So(next.URL.Host, ShouldEqual, request.URL.Host)
+			So(next.Header.Get("Authorization"), ShouldEqual, "Bearer test")
+
+			Convey("should be nil on the last page", func() {
+				response.Document.Links.Next = nil
+
+				next, err := response.NextPage()
+				So(err, ShouldBeNil)
+				So(next, ShouldBeNil)
+			})
+		})
+
+		Convey("->GetAll()", func() {
+			list, err := client.GetAll(request)
+			So(err, ShouldBeNil)
+			So(list.IDs(), ShouldResemble, []string{"0", "1", "2", "3", "4"})
+		})
+
+		Convey("->GetAll() should return errors from the server", func() {
+			request.Header.Del("Authorization")
+
+			list, err := client.GetAll(request)
+			So(err, ShouldNotBeNil)
+			So(len(list), ShouldEqual, 0)
+		})
+	})
+}
@@ -29,6 +29,19 @@ func Patch(baseURL string, object *jsh.Object) (*jsh.Document, *http.Response, e
 // a JSONAPI PATCH. This is useful for if you need to set custom headers on the
 // request. Otherwise just use "jsc.Patch".
 func PatchRequest(baseURL string, object *jsh.Object) (*http.Request, error) {
+	return NewPatchRequest(baseURL, object)
+}
+
+/*
+NewPatchRequest builds a "PATCH /resources/:id" request to update object. The
+object must have both a type and an ID, only the attributes and relationships
+it contains are updated by the server.
+*/
+func NewPatchRequest(baseURL string, object *jsh.Object) (*http.Request, error) {
+	if object == nil || object.Type == "" || object.ID == "" {
+		return nil, jsh.SpecificationError("Type and ID cannot be empty for a PATCH request")
+	}
+
 	u, err := url.Parse(baseURL)
 	if err != nil {
 		return nil, fmt.Errorf("Error parsing URL: %s", err.Error())
@@ -30,5 +30,22 @@ func TestPatch(t *testing.T) {
 			So(json.HasErrors(), ShouldBeFalse)
 			So(json.HasData(), ShouldBeTrue)
 		})
+
+		Convey("->NewPatchRequest()", func() {
+			object, err := jsh.NewObject("2", "tests", map[string]string{"foo": "bar"})
+			So(err, ShouldBeNil)
+
+			request, reqErr := NewPatchRequest(baseURL, object)
+			So(reqErr, ShouldBeNil)
+			So(request.Method, ShouldEqual, "PATCH")
+			So(request.URL.Path, ShouldEqual, "/tests/2")
+
+			Convey("should require an ID", func() {
+				object.ID = ""
+
+				_, reqErr := NewPatchRequest(baseURL, object)
+				So(reqErr, ShouldNotBeNil)
+			})
+		})
 	})
 }
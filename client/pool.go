@@ -0,0 +1,234 @@
+package jsc
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*
+ServiceConfig configures a Client for one upstream JSON API service of a
+ClientPool.
+*/
+type ServiceConfig struct {
+	// BaseURL is the service's root, such as https://users.example.com/v1
+	BaseURL string
+	// Header is added to every request sent to the service, such as an
+	// Authorization header holding a static API key
+	Header http.Header
+	// Authorize is called with every request before it is sent, to add
+	// credentials that change over time, such as refreshed OAuth tokens
+	Authorize func(request *http.Request) error
+	// Auth authenticates requests to the service, refreshing credentials the
+	// service rejects if it can, see Client.Auth
+	Auth Authenticator
+	// Timeout bounds each request to the service, including reading the
+	// response. Zero means no timeout.
+	Timeout time.Duration
+	// MaxConcurrent caps the number of requests in flight to the service,
+	// further requests wait for one to finish. Zero means no cap.
+	MaxConcurrent int
+	// Retry is the service's retry policy, nil disables retries
+	Retry *RetryPolicy
+	// Cache enables conditional GETs against the service, nil disables caching
+	Cache *ResponseCache
+	// Throttle keeps requests to the service under its rate limit, see
+	// Client.Throttle
+	Throttle *Throttle
+	// Profiles are requested from the service, see Client.Profiles
+	Profiles []string
+	// Transport sends the requests, http.DefaultTransport if nil
+	Transport http.RoundTripper
+}
+
+/*
+ServiceClient is a Client configured for a named service of a ClientPool,
+along with the service's BaseURL for building requests:
+
+	users, err := pool.Client("users")
+	if err != nil {
+		return err
+	}
+
+	request, err := jsc.FetchRequest(users.BaseURL, "users", id)
+	...
+	doc, response, err := users.Do(request, jsh.ObjectMode)
+*/
+type ServiceClient struct {
+	*Client
+	Name    string
+	BaseURL string
+}
+
+/*
+ClientPool manages the upstream JSON API services an application talks to,
+handing out clients configured for each of them:
+
+	pool := jsc.NewClientPool()
+	pool.Add("users", &jsc.ServiceConfig{
+		BaseURL:       "https://users.example.com/v1",
+		Header:        http.Header{"Authorization": {"Bearer " + usersKey}},
+		MaxConcurrent: 16,
+		Retry:         jsc.DefaultRetryPolicy,
+	})
+	pool.Add("billing", &jsc.ServiceConfig{
+		BaseURL: "https://billing.example.com",
+		Timeout: 2 * time.Second,
+	})
+
+Each service has a single Client, shared by everyone the pool hands it to, so
+that limits such as MaxConcurrent apply to all of an application's requests to
+the service. A ClientPool is safe for concurrent use.
+*/
+type ClientPool struct {
+	mu       sync.RWMutex
+	services map[string]*ServiceClient
+}
+
+// NewClientPool returns an empty pool.
+func NewClientPool() *ClientPool {
+	return &ClientPool{services: map[string]*ServiceClient{}}
+}
+
+// Add configures the named service, replacing any existing configuration of
+// the same name. The config's BaseURL must be an absolute URL.
+func (p *ClientPool) Add(name string, config *ServiceConfig) error {
+	base, err := url.Parse(config.BaseURL)
+	if err != nil || !base.IsAbs() {
+		return fmt.Errorf("Invalid base URL '%s' for service '%s'", config.BaseURL, name)
+	}
+
+	transport := config.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	service := &serviceTransport{
+		transport: transport,
+		header:    config.Header,
+		authorize: config.Authorize,
+	}
+	if config.MaxConcurrent > 0 {
+		service.slots = make(chan struct{}, config.MaxConcurrent)
+	}
+
+	client := &ServiceClient{
+		Client: &Client{
+			HTTPClient: &http.Client{Transport: service, Timeout: config.Timeout},
+			Retry:      config.Retry,
+			Cache:      config.Cache,
+			Profiles:   config.Profiles,
+			Auth:       config.Auth,
+			Throttle:   config.Throttle,
+		},
+		Name:    name,
+		BaseURL: strings.TrimSuffix(config.BaseURL, "/"),
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.services[name] = client
+
+	return nil
+}
+
+// Remove removes the named service from the pool. Clients already handed out
+// keep working.
+func (p *ClientPool) Remove(name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.services, name)
+}
+
+// Client returns the client configured for the named service, or an error if
+// the pool has no such service.
+func (p *ClientPool) Client(name string) (*ServiceClient, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	client, exists := p.services[name]
+	if !exists {
+		return nil, fmt.Errorf("No service named '%s' in the client pool", name)
+	}
+
+	return client, nil
+}
+
+// Services returns the names of the pool's services, sorted.
+func (p *ClientPool) Services() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	names := []string{}
+	for name := range p.services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// serviceTransport applies a service's credentials and concurrency limit to
+// the requests sent to it
+type serviceTransport struct {
+	transport http.RoundTripper
+	header    http.Header
+	authorize func(request *http.Request) error
+	// slots holds a token for each request in flight, nil if unlimited
+	slots chan struct{}
+}
+
+func (t *serviceTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	// round trippers must not modify the request they're given
+	request = request.Clone(request.Context())
+	for key, values := range t.header {
+		if request.Header.Get(key) == "" {
+			request.Header[key] = append([]string{}, values...)
+		}
+	}
+
+	if t.authorize != nil {
+		err := t.authorize(request)
+		if err != nil {
+			return nil, fmt.Errorf("Error authorizing request: %s", err.Error())
+		}
+	}
+
+	if t.slots == nil {
+		return t.transport.RoundTrip(request)
+	}
+
+	select {
+	case t.slots <- struct{}{}:
+	case <-request.Context().Done():
+		return nil, request.Context().Err()
+	}
+
+	response, err := t.transport.RoundTrip(request)
+	if err != nil {
+		<-t.slots
+		return nil, err
+	}
+
+	// the request is in flight until its body has been read
+	response.Body = &releasingBody{ReadCloser: response.Body, release: func() { <-t.slots }}
+	return response, nil
+}
+
+// releasingBody frees a concurrency slot when the response body is closed
+type releasingBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
@@ -0,0 +1,111 @@
+package jsc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/derekdowling/go-json-spec-handler"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestClientPool(t *testing.T) {
+
+	Convey("Client Pool Tests", t, func() {
+
+		var inFlight, maxInFlight int32
+		var mu sync.Mutex
+		headers := []http.Header{}
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			current := atomic.AddInt32(&inFlight, 1)
+			defer atomic.AddInt32(&inFlight, -1)
+			for {
+				max := atomic.LoadInt32(&maxInFlight)
+				if current <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, current) {
+					break
+				}
+			}
+
+			mu.Lock()
+			headers = append(headers, r.Header)
+			mu.Unlock()
+
+			object, _ := jsh.NewObject("1", "users", map[string]string{"name": "bob"})
+			jsh.Send(w, r, object)
+		}))
+		Reset(func() {
+			server.Close()
+		})
+
+		pool := NewClientPool()
+		err := pool.Add("users", &ServiceConfig{
+			BaseURL: server.URL + "/",
+			Header:  http.Header{"Authorization": {"Bearer static"}},
+			Authorize: func(request *http.Request) error {
+				request.Header.Set("X-Tenant", "acme")
+				return nil
+			},
+			MaxConcurrent: 1,
+		})
+		So(err, ShouldBeNil)
+		So(pool.Add("billing", &ServiceConfig{BaseURL: "https://billing.example.com"}), ShouldBeNil)
+
+		Convey("->Client()", func() {
+			users, err := pool.Client("users")
+			So(err, ShouldBeNil)
+			So(users.Name, ShouldEqual, "users")
+			So(users.BaseURL, ShouldEqual, server.URL)
+
+			Convey("should apply the service's headers and authorization", func() {
+				request, err := FetchRequest(users.BaseURL, "users", "1")
+				So(err, ShouldBeNil)
+
+				doc, _, err := users.Do(request, jsh.ObjectMode)
+				So(err, ShouldBeNil)
+				So(doc.First().ID, ShouldEqual, "1")
+
+				So(len(headers), ShouldEqual, 1)
+				So(headers[0].Get("Authorization"), ShouldEqual, "Bearer static")
+				So(headers[0].Get("X-Tenant"), ShouldEqual, "acme")
+				// the caller's request is left untouched
+				So(request.Header.Get("Authorization"), ShouldEqual, "")
+			})
+
+			Convey("should limit concurrent requests", func() {
+				var wg sync.WaitGroup
+				for i := 0; i < 4; i++ {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						request, _ := FetchRequest(users.BaseURL, "users", "1")
+						users.Do(request, jsh.ObjectMode)
+					}()
+				}
+				wg.Wait()
+
+				So(len(headers), ShouldEqual, 4)
+				So(atomic.LoadInt32(&maxInFlight), ShouldEqual, 1)
+			})
+
+			Convey("should hand out the same client for a service", func() {
+				again, _ := pool.Client("users")
+				So(again == users, ShouldBeTrue)
+			})
+		})
+
+		Convey("should list, remove, and reject unknown services", func() {
+			So(pool.Services(), ShouldResemble, []string{"billing", "users"})
+
+			pool.Remove("billing")
+			_, err := pool.Client("billing")
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("should reject relative base URLs", func() {
+			So(pool.Add("bad", &ServiceConfig{BaseURL: "/relative"}), ShouldNotBeNil)
+		})
+	})
+}
@@ -26,6 +26,19 @@ func Post(baseURL string, object *jsh.Object) (*jsh.Document, *http.Response, er
 // a JSONAPI POST. This is useful for if you need to set custom headers on the
 // request. Otherwise just use "jsc.Post".
 func PostRequest(baseURL string, object *jsh.Object) (*http.Request, error) {
+	return NewPostRequest(baseURL, object)
+}
+
+/*
+NewPostRequest builds a "POST /resources" request to create object. The object
+must have a type, while its ID is optional since the server usually assigns
+one, a client generated ID is sent if set.
+*/
+func NewPostRequest(baseURL string, object *jsh.Object) (*http.Request, error) {
+	if object == nil || object.Type == "" {
+		return nil, jsh.SpecificationError("Type cannot be empty for a POST request")
+	}
+
 	u, err := url.Parse(baseURL)
 	if err != nil {
 		return nil, fmt.Errorf("Error parsing URL: %s", err.Error())
@@ -28,5 +28,31 @@ func TestPost(t *testing.T) {
 		_, resp, postErr := Post(baseURL, testObject)
 		So(postErr, ShouldBeNil)
 		So(resp.StatusCode, ShouldEqual, http.StatusCreated)
+
+		Convey("->NewPostRequest()", func() {
+			request, err := NewPostRequest(baseURL, testObject)
+			So(err, ShouldBeNil)
+			So(request.Method, ShouldEqual, "POST")
+			So(request.URL.Path, ShouldEqual, "/tests")
+			So(request.ContentLength, ShouldBeGreaterThan, 0)
+
+			Convey("should send a client generated ID", func() {
+				testObject.ID = "abc"
+
+				request, err := NewPostRequest(baseURL, testObject)
+				So(err, ShouldBeNil)
+
+				object, parseErr := jsh.ParseObject(request)
+				So(parseErr, ShouldBeNil)
+				So(object.ID, ShouldEqual, "abc")
+			})
+
+			Convey("should require a type", func() {
+				testObject.Type = ""
+
+				_, err := NewPostRequest(baseURL, testObject)
+				So(err, ShouldNotBeNil)
+			})
+		})
 	})
 }
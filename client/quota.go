@@ -0,0 +1,136 @@
+package jsc
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/derekdowling/go-json-spec-handler"
+)
+
+// Headers carrying quota information under the names that predate the IETF
+// RateLimit headers, as sent by many APIs
+const (
+	legacyLimitHeader     = "X-RateLimit-Limit"
+	legacyRemainingHeader = "X-RateLimit-Remaining"
+	legacyResetHeader     = "X-RateLimit-Reset"
+)
+
+// resetEpoch is the smallest reset value taken to be a Unix time rather than a
+// number of seconds, since no quota window lasts 30 years. Servers sending
+// either are told apart by it, see ParseQuota.
+const resetEpoch = 1000000000
+
+/*
+ParseQuota reads the quota information a server sent with a response, see
+jsh.QuotaProvider, returning nil if there isn't any. Clients can use it to slow
+down before exhausting their allowance:
+
+	quota := jsc.ParseQuota(response)
+	if quota != nil && quota.Remaining == 0 {
+		time.Sleep(time.Until(quota.Reset))
+	}
+
+Both the IETF RateLimit headers and the older X-RateLimit headers are read. The
+reset may be a number of seconds, relative to the response's Date header or the
+current time if it has none, or a Unix time. Resets of 1000000000 or more,
+September 2001 as a Unix time, are taken to be Unix times.
+*/
+func ParseQuota(response *http.Response) *jsh.Quota {
+	quota := parseQuotaHeaders(response, jsh.QuotaLimitHeader, jsh.QuotaRemainingHeader, jsh.QuotaResetHeader)
+	if quota == nil {
+		quota = parseQuotaHeaders(response, legacyLimitHeader, legacyRemainingHeader, legacyResetHeader)
+	}
+
+	return quota
+}
+
+// parseQuotaHeaders reads quota information from the named headers
+func parseQuotaHeaders(response *http.Response, limitHeader string, remainingHeader string, resetHeader string) *jsh.Quota {
+	limit, limitErr := strconv.Atoi(response.Header.Get(limitHeader))
+	remaining, remainingErr := strconv.Atoi(response.Header.Get(remainingHeader))
+	if limitErr != nil || remainingErr != nil {
+		return nil
+	}
+
+	reset, _ := strconv.ParseInt(response.Header.Get(resetHeader), 10, 64)
+
+	return &jsh.Quota{
+		Limit:     limit,
+		Remaining: remaining,
+		Reset:     resetTime(response, reset),
+	}
+}
+
+// resetTime converts a quota reset value to a time
+func resetTime(response *http.Response, reset int64) time.Time {
+	if reset >= resetEpoch {
+		return time.Unix(reset, 0)
+	}
+
+	return responseTime(response).Add(time.Duration(reset) * time.Second)
+}
+
+/*
+RateLimit returns the rate limit the server advertised with the response's
+RateLimit-* or X-RateLimit-* headers, or nil if it sent none, see ParseQuota:
+
+	response, err := client.Send(request, jsh.ObjectMode)
+	if limit := response.RateLimit(); limit != nil && limit.Remaining == 0 {
+		time.Sleep(time.Until(limit.Reset))
+	}
+
+Use Quota to also read quota information from the document's meta, or a
+Throttle to wait automatically.
+*/
+func (r *Response) RateLimit() *jsh.Quota {
+	return ParseQuota(r.Response)
+}
+
+// Quota returns the quota information sent with the response, from its headers
+// or otherwise the "quota" member of the document's meta, or nil if there isn't
+// any.
+func (r *Response) Quota() *jsh.Quota {
+	if quota := ParseQuota(r.Response); quota != nil {
+		return quota
+	}
+
+	if r.Document == nil {
+		return nil
+	}
+
+	meta, isMap := r.Document.Meta.(map[string]interface{})
+	if !isMap {
+		return nil
+	}
+
+	members, isMap := meta["quota"].(map[string]interface{})
+	if !isMap {
+		return nil
+	}
+
+	limit, hasLimit := members["limit"].(float64)
+	remaining, hasRemaining := members["remaining"].(float64)
+	if !hasLimit || !hasRemaining {
+		return nil
+	}
+
+	reset, _ := members["reset"].(float64)
+
+	return &jsh.Quota{
+		Limit:     int(limit),
+		Remaining: int(remaining),
+		Reset:     responseTime(r.Response).Add(time.Duration(reset) * time.Second),
+	}
+}
+
+// responseTime returns when the response was sent according to its Date
+// header, falling back to the current time
+func responseTime(response *http.Response) time.Time {
+	date, err := http.ParseTime(response.Header.Get("Date"))
+	if err != nil {
+		return time.Now()
+	}
+
+	return date
+}
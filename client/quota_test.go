@@ -0,0 +1,87 @@
+package jsc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/derekdowling/go-json-spec-handler"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestQuota(t *testing.T) {
+
+	Convey("Quota Tests", t, func() {
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			jsh.SetQuota(w, &jsh.Quota{Limit: 100, Remaining: 7, Reset: time.Now().Add(time.Minute)})
+
+			object, _ := jsh.NewObject("1", "tests", map[string]string{"foo": "bar"})
+			jsh.Send(w, r, object)
+		}))
+		Reset(func() {
+			server.Close()
+		})
+
+		request, err := FetchRequest(server.URL, "tests", "1")
+		So(err, ShouldBeNil)
+
+		response, err := (&Client{}).Send(request, jsh.ObjectMode)
+		So(err, ShouldBeNil)
+
+		Convey("->ParseQuota()", func() {
+			quota := ParseQuota(response.Response)
+			So(quota, ShouldNotBeNil)
+			So(quota.Limit, ShouldEqual, 100)
+			So(quota.Remaining, ShouldEqual, 7)
+			So(quota.Reset, ShouldHappenWithin, 2*time.Second, time.Now().Add(time.Minute))
+
+			Convey("should read X-RateLimit headers", func() {
+				response.Header.Del(jsh.QuotaLimitHeader)
+				response.Header.Set("X-RateLimit-Limit", "60")
+				response.Header.Set("X-RateLimit-Remaining", "59")
+				response.Header.Set("X-RateLimit-Reset", "30")
+
+				quota := ParseQuota(response.Response)
+				So(quota, ShouldNotBeNil)
+				So(quota.Limit, ShouldEqual, 60)
+				So(quota.Remaining, ShouldEqual, 59)
+				So(quota.Reset, ShouldHappenWithin, 2*time.Second, time.Now().Add(30*time.Second))
+
+				Convey("with Unix reset times", func() {
+					response.Header.Set("X-RateLimit-Reset", "1500000000")
+					So(ParseQuota(response.Response).Reset, ShouldResemble, time.Unix(1500000000, 0))
+				})
+			})
+
+			Convey("should be nil without quota headers", func() {
+				response.Header.Del(jsh.QuotaLimitHeader)
+				So(ParseQuota(response.Response), ShouldBeNil)
+			})
+		})
+
+		Convey("->RateLimit()", func() {
+			limit := response.RateLimit()
+			So(limit, ShouldNotBeNil)
+			So(limit.Limit, ShouldEqual, 100)
+			So(limit.Remaining, ShouldEqual, 7)
+
+			Convey("should ignore the document's meta", func() {
+				response.Header.Del(jsh.QuotaLimitHeader)
+				So(response.RateLimit(), ShouldBeNil)
+			})
+		})
+
+		Convey("->Quota()", func() {
+
+			Convey("should fall back to the document's meta", func() {
+				response.Header.Del(jsh.QuotaLimitHeader)
+
+				quota := response.Quota()
+				So(quota, ShouldNotBeNil)
+				So(quota.Remaining, ShouldEqual, 7)
+			})
+		})
+	})
+}
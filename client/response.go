@@ -0,0 +1,73 @@
+package jsc
+
+import (
+	"net/http"
+
+	"github.com/derekdowling/go-json-spec-handler"
+)
+
+/*
+Response pairs an HTTP response with the JSON API Document parsed from its body.
+*/
+type Response struct {
+	*http.Response
+	// Document is nil for responses without a body, such as a 204
+	Document *jsh.Document
+}
+
+/*
+NewResponse parses an HTTP response into a Response. As with ParseResponse, an
+error document results in a jsh.ErrorList error, but the Response is still
+returned so it can be inspected.
+*/
+func NewResponse(response *http.Response, mode jsh.DocumentMode) (*Response, error) {
+	document, err := ParseResponse(response, mode)
+	if err != nil {
+		if _, isErrorList := err.(jsh.ErrorList); !isErrorList {
+			return nil, err
+		}
+	}
+
+	return &Response{Response: response, Document: document}, err
+}
+
+// Errors returns the top-level errors sent by the server, or nil if there are
+// none.
+func (r *Response) Errors() jsh.ErrorList {
+	if r.Document == nil || !r.Document.HasErrors() {
+		return nil
+	}
+
+	return r.Document.Errors
+}
+
+/*
+GetObject returns the primary data object of the response. If the server sent
+an error document its jsh.ErrorList is returned, which can be type asserted to
+inspect each error's status, code, and detail.
+*/
+func (r *Response) GetObject() (*jsh.Object, error) {
+	if errors := r.Errors(); errors != nil {
+		return nil, errors
+	}
+
+	if r.Document == nil {
+		return nil, nil
+	}
+
+	return r.Document.First(), nil
+}
+
+// GetList returns the primary data of the response, or its jsh.ErrorList if the
+// server sent an error document.
+func (r *Response) GetList() (jsh.List, error) {
+	if errors := r.Errors(); errors != nil {
+		return nil, errors
+	}
+
+	if r.Document == nil {
+		return nil, nil
+	}
+
+	return r.Document.Data, nil
+}
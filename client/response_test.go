@@ -0,0 +1,55 @@
+package jsc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/derekdowling/go-json-spec-handler"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestResponse(t *testing.T) {
+
+	Convey("Response Tests", t, func() {
+
+		Convey("error documents", func() {
+			recorder := httptest.NewRecorder()
+			jsh.Send(recorder, &http.Request{Method: "GET"}, jsh.NotFound("tests", "1"))
+
+			response, err := NewResponse(recorderToResponse(recorder), jsh.ObjectMode)
+			So(err, ShouldNotBeNil)
+			So(response, ShouldNotBeNil)
+
+			errorList, isErrorList := err.(jsh.ErrorList)
+			So(isErrorList, ShouldBeTrue)
+			So(errorList.StatusCode(), ShouldEqual, http.StatusNotFound)
+
+			Convey("->Errors()", func() {
+				So(response.Errors(), ShouldResemble, errorList)
+			})
+
+			Convey("->GetObject()", func() {
+				object, objErr := response.GetObject()
+				So(object, ShouldBeNil)
+				So(objErr, ShouldResemble, errorList)
+			})
+		})
+
+		Convey("data documents", func() {
+			object, objErr := jsh.NewObject("1", "tests", nil)
+			So(objErr, ShouldBeNil)
+
+			mocked, mockErr := mockObjectResponse(object)
+			So(mockErr, ShouldBeNil)
+
+			response, err := NewResponse(mocked, jsh.ObjectMode)
+			So(err, ShouldBeNil)
+			So(response.Errors(), ShouldBeNil)
+
+			parsed, parseErr := response.GetObject()
+			So(parseErr, ShouldBeNil)
+			So(parsed.ID, ShouldEqual, "1")
+		})
+	})
+}
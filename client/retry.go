@@ -0,0 +1,222 @@
+package jsc
+
+import (
+	"io"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/derekdowling/go-json-spec-handler"
+)
+
+/*
+RetryPolicy controls how a Client retries requests that fail transiently, either
+because the server responded with one of the retryable statuses or because the
+request couldn't be sent at all. Delays grow exponentially from BaseDelay up to
+MaxDelay, unless the server specifies one with a Retry-After header.
+
+Requests that aren't idempotent, POST and PATCH, are only retried if
+RetryNonIdempotent is set, since the server may have already acted on them.
+*/
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry, doubling with each attempt
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between attempts, including those requested via
+	// Retry-After. Zero disables the cap.
+	MaxDelay time.Duration
+	// Jitter randomizes each delay by up to the given fraction, between 0 and 1,
+	// so that many clients don't retry in lockstep
+	Jitter float64
+	// RetryNonIdempotent allows POST and PATCH requests to be retried
+	RetryNonIdempotent bool
+	// Statuses are the response status codes that trigger a retry
+	Statuses []int
+}
+
+// DefaultRetryPolicy retries idempotent requests up to 3 times.
+var DefaultRetryPolicy = &RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   100 * time.Millisecond,
+	MaxDelay:    5 * time.Second,
+	Jitter:      0.2,
+	Statuses: []int{
+		http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	},
+}
+
+// send performs the request, through the client's cache for GETs
+func (c *Client) send(request *http.Request) (*http.Response, error) {
+	if c.Consistency != nil {
+		c.Consistency.prepare(request)
+	}
+
+	if len(c.Profiles) > 0 && request.Header.Get("Accept") == "" {
+		request.Header.Set("Accept", jsh.ProfileContentType(c.Profiles...))
+	}
+
+	err := c.authenticate(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Throttle != nil {
+		err = c.Throttle.wait(request.Context())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	response, err := c.dispatch(request)
+	if err == nil && c.Auth != nil && c.reauthenticate(request, response) {
+		response, err = c.dispatch(request)
+	}
+
+	if err == nil && c.Consistency != nil {
+		c.Consistency.record(response)
+	}
+
+	if err == nil && c.Throttle != nil {
+		c.Throttle.record(response)
+	}
+
+	return response, err
+}
+
+// dispatch performs the request, through the client's cache if it has one
+func (c *Client) dispatch(request *http.Request) (*http.Response, error) {
+	if c.Cache != nil && request.Method == "GET" {
+		return c.Cache.send(request, c.attempt)
+	}
+
+	return c.attempt(request)
+}
+
+// attempt performs the request, retrying according to the client's policy
+func (c *Client) attempt(request *http.Request) (*http.Response, error) {
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	policy := c.Retry
+	if policy == nil || !policy.allows(request) {
+		return httpClient.Do(request)
+	}
+
+	for attempt := 1; ; attempt++ {
+		response, err := httpClient.Do(request)
+		if attempt >= policy.MaxAttempts || !policy.shouldRetry(response, err) {
+			return response, err
+		}
+
+		delay := policy.delay(attempt, response)
+		if response != nil {
+			// drain so the connection can be reused
+			io.Copy(ioutil.Discard, response.Body)
+			response.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-request.Context().Done():
+			timer.Stop()
+			return nil, request.Context().Err()
+		case <-timer.C:
+		}
+
+		if request.GetBody != nil {
+			request.Body, err = request.GetBody()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// allows returns true if the request can safely be sent more than once
+func (p *RetryPolicy) allows(request *http.Request) bool {
+	if p.MaxAttempts < 2 {
+		return false
+	}
+
+	// a body that can't be replayed would be sent empty on the next attempt
+	if request.Body != nil && request.Body != http.NoBody && request.GetBody == nil {
+		return false
+	}
+
+	switch request.Method {
+	case "POST", "PATCH":
+		return p.RetryNonIdempotent
+	default:
+		return true
+	}
+}
+
+// shouldRetry returns true if the attempt failed in a way a retry might fix
+func (p *RetryPolicy) shouldRetry(response *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+
+	for _, status := range p.Statuses {
+		if response.StatusCode == status {
+			return true
+		}
+	}
+
+	return false
+}
+
+// delay calculates how long to wait before the next attempt
+func (p *RetryPolicy) delay(attempt int, response *http.Response) time.Duration {
+	delay, ok := retryAfter(response)
+	if !ok {
+		delay = time.Duration(float64(p.BaseDelay) * math.Pow(2, float64(attempt-1)))
+		if p.Jitter > 0 {
+			delay += time.Duration(float64(delay) * p.Jitter * (2*rand.Float64() - 1))
+		}
+	}
+
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	if delay < 0 {
+		delay = 0
+	}
+
+	return delay
+}
+
+// retryAfter parses the Retry-After header, which is either a number of
+// seconds or an HTTP date
+func retryAfter(response *http.Response) (time.Duration, bool) {
+	if response == nil {
+		return 0, false
+	}
+
+	header := response.Header.Get("Retry-After")
+	if header == "" {
+		return 0, false
+	}
+
+	seconds, err := strconv.Atoi(header)
+	if err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	date, err := http.ParseTime(header)
+	if err == nil {
+		return time.Until(date), true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,168 @@
+package jsc
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/derekdowling/go-json-spec-handler"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRetry(t *testing.T) {
+
+	Convey("Retry Tests", t, func() {
+
+		var attempts int32
+		var bodies []string
+		failures := int32(2)
+		status := http.StatusServiceUnavailable
+		retryAfterHeader := ""
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			bodies = append(bodies, string(body))
+
+			if atomic.AddInt32(&attempts, 1) <= failures {
+				if retryAfterHeader != "" {
+					w.Header().Set("Retry-After", retryAfterHeader)
+				}
+				w.WriteHeader(status)
+				return
+			}
+
+			object, _ := jsh.NewObject("1", "tests", map[string]string{"foo": "bar"})
+			jsh.Send(w, r, object)
+		}))
+
+		Reset(func() {
+			server.Close()
+		})
+
+		policy := &RetryPolicy{
+			MaxAttempts: 3,
+			BaseDelay:   time.Millisecond,
+			Statuses:    DefaultRetryPolicy.Statuses,
+		}
+		client := &Client{Retry: policy}
+
+		Convey("should retry idempotent requests until they succeed", func() {
+			request, err := FetchRequest(server.URL, "tests", "1")
+			So(err, ShouldBeNil)
+
+			doc, response, err := client.Do(request, jsh.ObjectMode)
+			So(err, ShouldBeNil)
+			So(response.StatusCode, ShouldEqual, http.StatusOK)
+			So(doc.First().ID, ShouldEqual, "1")
+			So(attempts, ShouldEqual, 3)
+		})
+
+		Convey("should give up after MaxAttempts", func() {
+			failures = 5
+
+			request, err := FetchRequest(server.URL, "tests", "1")
+			So(err, ShouldBeNil)
+
+			_, response, _ := client.Do(request, jsh.ObjectMode)
+			So(response.StatusCode, ShouldEqual, http.StatusServiceUnavailable)
+			So(attempts, ShouldEqual, 3)
+		})
+
+		Convey("should not retry statuses outside the policy", func() {
+			status = http.StatusInternalServerError
+
+			request, err := FetchRequest(server.URL, "tests", "1")
+			So(err, ShouldBeNil)
+
+			_, response, _ := client.Do(request, jsh.ObjectMode)
+			So(response.StatusCode, ShouldEqual, http.StatusInternalServerError)
+			So(attempts, ShouldEqual, 1)
+		})
+
+		Convey("should not retry POST requests by default", func() {
+			object, _ := jsh.NewObject("", "tests", map[string]string{"foo": "bar"})
+			request, err := PostRequest(server.URL, object)
+			So(err, ShouldBeNil)
+
+			_, response, _ := client.Do(request, jsh.ObjectMode)
+			So(response.StatusCode, ShouldEqual, http.StatusServiceUnavailable)
+			So(attempts, ShouldEqual, 1)
+		})
+
+		Convey("should replay the body of POST requests when opted in", func() {
+			policy.RetryNonIdempotent = true
+
+			object, _ := jsh.NewObject("", "tests", map[string]string{"foo": "bar"})
+			request, err := PostRequest(server.URL, object)
+			So(err, ShouldBeNil)
+
+			_, response, _ := client.Do(request, jsh.ObjectMode)
+			So(response.StatusCode, ShouldEqual, http.StatusCreated)
+			So(attempts, ShouldEqual, 3)
+			So(bodies[2], ShouldNotBeEmpty)
+			So(bodies[2], ShouldEqual, bodies[0])
+		})
+
+		Convey("should honor Retry-After", func() {
+			failures = 1
+			status = http.StatusTooManyRequests
+			retryAfterHeader = "1"
+
+			request, err := FetchRequest(server.URL, "tests", "1")
+			So(err, ShouldBeNil)
+
+			start := time.Now()
+			_, response, _ := client.Do(request, jsh.ObjectMode)
+			So(response.StatusCode, ShouldEqual, http.StatusOK)
+			So(time.Since(start), ShouldBeGreaterThanOrEqualTo, time.Second)
+		})
+
+		Convey("->delay()", func() {
+
+			Convey("should back off exponentially", func() {
+				So(policy.delay(1, nil), ShouldEqual, time.Millisecond)
+				So(policy.delay(3, nil), ShouldEqual, 4*time.Millisecond)
+			})
+
+			Convey("should cap delays at MaxDelay", func() {
+				policy.MaxDelay = 2 * time.Millisecond
+				So(policy.delay(5, nil), ShouldEqual, 2*time.Millisecond)
+
+				response := &http.Response{Header: http.Header{"Retry-After": []string{"120"}}}
+				So(policy.delay(1, response), ShouldEqual, 2*time.Millisecond)
+			})
+
+			Convey("should apply jitter within bounds", func() {
+				policy.BaseDelay = 100 * time.Millisecond
+				policy.Jitter = 0.5
+
+				for i := 0; i < 20; i++ {
+					delay := policy.delay(1, nil)
+					So(delay, ShouldBeBetweenOrEqual, 50*time.Millisecond, 150*time.Millisecond)
+				}
+			})
+		})
+
+		Convey("->retryAfter()", func() {
+
+			Convey("should parse HTTP dates", func() {
+				date := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+				response := &http.Response{Header: http.Header{"Retry-After": []string{date}}}
+
+				delay, ok := retryAfter(response)
+				So(ok, ShouldBeTrue)
+				So(delay, ShouldBeBetween, 58*time.Second, 61*time.Second)
+			})
+
+			Convey("should ignore invalid values", func() {
+				response := &http.Response{Header: http.Header{"Retry-After": []string{"soon"}}}
+
+				_, ok := retryAfter(response)
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}
@@ -0,0 +1,120 @@
+package jsc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/derekdowling/go-json-spec-handler"
+)
+
+/*
+Throttle keeps a Client under the rate limit its server advertises, see
+ParseQuota. The quota of each response is recorded, and once few enough
+requests remain, further requests wait for the quota to reset rather than being
+rejected:
+
+	client := &jsc.Client{Throttle: &jsc.Throttle{Reserve: 5}}
+
+Requests sent before the next response arrives are counted against the last
+quota recorded, so concurrent requests don't overshoot it. A 429 Too Many
+Requests with a Retry-After header exhausts the quota until then.
+
+The zero value is ready to use. A Throttle is safe for concurrent use, and can
+be shared by the clients of one service.
+*/
+type Throttle struct {
+	// Reserve is how many requests of the quota are kept back, requests wait
+	// once no more than Reserve remain. Zero waits only once it is exhausted.
+	Reserve int
+	// MaxWait caps how long a request waits for the quota to reset, requests
+	// that would wait longer fail instead. Zero means no cap.
+	MaxWait time.Duration
+
+	mu    sync.Mutex
+	quota *jsh.Quota
+}
+
+// Quota returns the last quota recorded by the throttle, less the requests
+// sent since, or nil if there is none.
+func (t *Throttle) Quota() *jsh.Quota {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.quota == nil {
+		return nil
+	}
+
+	quota := *t.quota
+	return &quota
+}
+
+// wait blocks until the quota allows another request, counting the request
+// against it, or returns an error if the context ends or it would wait too
+// long
+func (t *Throttle) wait(ctx context.Context) error {
+	for {
+		delay := t.take()
+		if delay <= 0 {
+			return nil
+		}
+
+		if t.MaxWait > 0 && delay > t.MaxWait {
+			return fmt.Errorf("Rate limit exhausted for another %s", delay)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// take counts a request against the quota, or returns how long until it
+// resets if the request has to wait
+func (t *Throttle) take() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.quota == nil {
+		return 0
+	}
+
+	delay := t.quota.Reset.Sub(time.Now())
+	if delay <= 0 {
+		// the quota has reset, the next response brings the new one
+		t.quota = nil
+		return 0
+	}
+
+	if t.quota.Remaining <= t.Reserve {
+		return delay
+	}
+
+	t.quota.Remaining--
+	return 0
+}
+
+// record keeps the quota of a response, if it has one
+func (t *Throttle) record(response *http.Response) {
+	quota := ParseQuota(response)
+	if quota == nil && response.StatusCode == http.StatusTooManyRequests {
+		if delay, hasDelay := retryAfter(response); hasDelay {
+			quota = &jsh.Quota{Reset: time.Now().Add(delay)}
+		}
+	}
+
+	if quota == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.quota = quota
+}
@@ -0,0 +1,101 @@
+package jsc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/derekdowling/go-json-spec-handler"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestThrottle(t *testing.T) {
+
+	Convey("Throttle Tests", t, func() {
+
+		remaining := 2
+		reset := time.Second
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if remaining < 0 {
+				w.Header().Set("Retry-After", "1")
+				jsh.Send(w, r, &jsh.Error{Title: "Too Many Requests", Status: http.StatusTooManyRequests})
+				return
+			}
+
+			w.Header().Set("X-RateLimit-Limit", "10")
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+			w.Header().Set("X-RateLimit-Reset", strconv.Itoa(int(reset/time.Second)))
+
+			object, _ := jsh.NewObject("1", "tests", map[string]string{"foo": "bar"})
+			jsh.Send(w, r, object)
+		}))
+		Reset(server.Close)
+
+		throttle := &Throttle{}
+		client := &Client{Throttle: throttle}
+
+		fetch := func(ctx context.Context) error {
+			request, err := FetchRequest(server.URL, "tests", "1")
+			So(err, ShouldBeNil)
+
+			_, err = client.Send(request.WithContext(ctx), jsh.ObjectMode)
+			return err
+		}
+
+		Convey("should record quotas and count requests against them", func() {
+			So(throttle.Quota(), ShouldBeNil)
+
+			So(fetch(context.Background()), ShouldBeNil)
+			So(throttle.Quota().Remaining, ShouldEqual, 2)
+
+			So(throttle.wait(context.Background()), ShouldBeNil)
+			So(throttle.Quota().Remaining, ShouldEqual, 1)
+		})
+
+		Convey("should wait for exhausted quotas to reset", func() {
+			remaining = 0
+			reset = 2 * time.Second
+			So(fetch(context.Background()), ShouldBeNil)
+
+			started := time.Now()
+			So(fetch(context.Background()), ShouldBeNil)
+			So(time.Now(), ShouldHappenOnOrAfter, started.Add(500*time.Millisecond))
+			So(requests, ShouldEqual, 2)
+		})
+
+		Convey("should keep the reserve", func() {
+			throttle.Reserve = 2
+			So(fetch(context.Background()), ShouldBeNil)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			So(fetch(ctx), ShouldNotBeNil)
+			So(requests, ShouldEqual, 1)
+		})
+
+		Convey("should fail requests that would wait longer than MaxWait", func() {
+			remaining = 0
+			reset = time.Minute
+			throttle.MaxWait = time.Second
+			So(fetch(context.Background()), ShouldBeNil)
+
+			So(fetch(context.Background()), ShouldNotBeNil)
+			So(requests, ShouldEqual, 1)
+		})
+
+		Convey("should wait out Too Many Requests responses", func() {
+			remaining = -1
+			fetch(context.Background())
+
+			quota := throttle.Quota()
+			So(quota, ShouldNotBeNil)
+			So(quota.Remaining, ShouldEqual, 0)
+			So(quota.Reset, ShouldHappenWithin, 2*time.Second, time.Now().Add(time.Second))
+		})
+	})
+}
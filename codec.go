@@ -0,0 +1,84 @@
+package jsh
+
+import (
+	"encoding/json"
+	"io"
+)
+
+/*
+JSONCodec is a JSON implementation jsh encodes and decodes documents with. It
+follows the API of encoding/json, which drop-in replacements such as
+json-iterator and segmentio/encoding already implement, so faster engines can
+be used for large payloads, see JSON.
+
+Codecs must honor the json.Marshaler and json.Unmarshaler methods of jsh's
+types, and should return the encoding/json error types, which jsh relies on to
+point 422 errors at mistyped attributes.
+*/
+type JSONCodec interface {
+	// Name identifies the implementation, such as "encoding/json"
+	Name() string
+	Marshal(v interface{}) ([]byte, error)
+	MarshalIndent(v interface{}, prefix string, indent string) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+	NewEncoder(w io.Writer) JSONEncoder
+	NewDecoder(r io.Reader) JSONDecoder
+}
+
+// JSONEncoder writes JSON values to an output stream, like a json.Encoder.
+type JSONEncoder interface {
+	Encode(v interface{}) error
+	SetIndent(prefix string, indent string)
+}
+
+// JSONDecoder reads JSON values from an input stream, like a json.Decoder.
+type JSONDecoder interface {
+	Decode(v interface{}) error
+}
+
+/*
+JSON is the codec documents are parsed and sent with, encoding/json by default.
+Replace it at init, before any requests are handled, to use another engine,
+such as those the jshjsoniter and jshsegmentio packages enable:
+
+	func init() {
+		jshsegmentio.Enable()
+	}
+
+Builds with GOEXPERIMENT=jsonv2 get the encoding/json v2 engine without
+replacing it.
+*/
+var JSON JSONCodec = StandardCodec{}
+
+// StandardCodec is the JSONCodec of encoding/json.
+type StandardCodec struct{}
+
+// Name returns Codec.
+func (StandardCodec) Name() string {
+	return Codec
+}
+
+// Marshal calls json.Marshal.
+func (StandardCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// MarshalIndent calls json.MarshalIndent.
+func (StandardCodec) MarshalIndent(v interface{}, prefix string, indent string) ([]byte, error) {
+	return json.MarshalIndent(v, prefix, indent)
+}
+
+// Unmarshal calls json.Unmarshal.
+func (StandardCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// NewEncoder returns a json.Encoder.
+func (StandardCodec) NewEncoder(w io.Writer) JSONEncoder {
+	return json.NewEncoder(w)
+}
+
+// NewDecoder returns a json.Decoder.
+func (StandardCodec) NewDecoder(r io.Reader) JSONDecoder {
+	return json.NewDecoder(r)
+}
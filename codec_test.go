@@ -0,0 +1,72 @@
+package jsh
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// countingCodec counts the values encoded and decoded with encoding/json
+type countingCodec struct {
+	StandardCodec
+	marshaled   int
+	unmarshaled int
+	encoded     int
+}
+
+func (c *countingCodec) Name() string {
+	return "counting"
+}
+
+func (c *countingCodec) Marshal(v interface{}) ([]byte, error) {
+	c.marshaled++
+	return c.StandardCodec.Marshal(v)
+}
+
+func (c *countingCodec) Unmarshal(data []byte, v interface{}) error {
+	c.unmarshaled++
+	return c.StandardCodec.Unmarshal(data, v)
+}
+
+func (c *countingCodec) NewEncoder(w io.Writer) JSONEncoder {
+	c.encoded++
+	return c.StandardCodec.NewEncoder(w)
+}
+
+func TestCodec(t *testing.T) {
+
+	Convey("Codec Tests", t, func() {
+
+		codec := &countingCodec{}
+		JSON = codec
+		Reset(func() { JSON = StandardCodec{} })
+
+		Convey("should parse documents with the codec", func() {
+			req, err := testRequest([]byte(`{"data": {"type": "user", "id": "1", "attributes": {"name": "Bob"}}}`))
+			So(err, ShouldBeNil)
+
+			object, parseErr := ParseObject(req)
+			So(parseErr, ShouldBeNil)
+			So(object.ID, ShouldEqual, "1")
+			So(codec.unmarshaled, ShouldBeGreaterThan, 0)
+		})
+
+		Convey("should send documents with the codec", func() {
+			object, err := NewObject("1", "user", map[string]string{"name": "Bob"})
+			So(err, ShouldBeNil)
+
+			writer := httptest.NewRecorder()
+			So(Send(writer, &http.Request{Method: "GET"}, object), ShouldBeNil)
+			So(writer.Body.String(), ShouldContainSubstring, `"name":"Bob"`)
+			So(codec.encoded, ShouldEqual, 1)
+			So(codec.marshaled, ShouldBeGreaterThan, 0)
+		})
+
+		Convey("should report the codec's name", func() {
+			So(CurrentConfiguration().Codec, ShouldEqual, "counting")
+		})
+	})
+}
@@ -0,0 +1,172 @@
+package jsh
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+/*
+ResponseCompression, when set, gzips response bodies of at least MinSize bytes
+for clients that accept it via Accept-Encoding:
+
+	jsh.ResponseCompression = &jsh.Compression{MinSize: 1024}
+
+Leave nil to send responses uncompressed. Requests compressed with gzip or
+deflate are always decompressed when parsed, regardless of this setting.
+*/
+var ResponseCompression *Compression
+
+// Compression configures the gzip compression of responses.
+type Compression struct {
+	// MinSize is the smallest body, in bytes, that is compressed. Small bodies
+	// often grow when compressed.
+	MinSize int `json:"min_size"`
+	// Level is the gzip compression level, gzip.DefaultCompression if 0
+	Level int `json:"level"`
+}
+
+// compress gzips content if the policy and request allow it, returning the
+// content to send and whether it was compressed
+func (c *Compression) compress(r *http.Request, content []byte) ([]byte, bool) {
+	if c == nil || len(content) < c.MinSize || !acceptsGzip(r) {
+		return content, false
+	}
+
+	level := c.Level
+	if level == 0 {
+		level = gzip.DefaultCompression
+	}
+
+	compressed := &bytes.Buffer{}
+	writer, err := gzip.NewWriterLevel(compressed, level)
+	if err != nil {
+		return content, false
+	}
+
+	writer.Write(content)
+	if writer.Close() != nil {
+		return content, false
+	}
+
+	return compressed.Bytes(), true
+}
+
+// acceptsGzip returns true if the request's Accept-Encoding allows gzip
+func acceptsGzip(r *http.Request) bool {
+	if r == nil {
+		return false
+	}
+
+	accepted := false
+	for _, header := range r.Header["Accept-Encoding"] {
+		for _, encoding := range strings.Split(header, ",") {
+			name, quality := encodingQuality(encoding)
+			switch name {
+			case "gzip":
+				// an explicit gzip quality overrides a wildcard
+				return quality > 0
+			case "*":
+				accepted = quality > 0
+			}
+		}
+	}
+
+	return accepted
+}
+
+// encodingQuality splits an Accept-Encoding entry such as "gzip;q=0.5" into
+// its name and quality
+func encodingQuality(encoding string) (string, float64) {
+	parts := strings.Split(encoding, ";")
+	name := strings.ToLower(strings.TrimSpace(parts[0]))
+	quality := 1.0
+
+	for _, param := range parts[1:] {
+		param = strings.TrimSpace(param)
+		if strings.HasPrefix(param, "q=") {
+			parsed, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64)
+			if err == nil {
+				quality = parsed
+			}
+		}
+	}
+
+	return name, quality
+}
+
+// decodeBody wraps a request body to decompress it according to its
+// Content-Encoding
+func decodeBody(headers http.Header, body io.Reader) (io.Reader, *Error) {
+	encoding := strings.ToLower(strings.TrimSpace(headers.Get("Content-Encoding")))
+
+	var decoded io.Reader
+	var err error
+
+	switch encoding {
+	case "", "identity":
+		return body, nil
+	case "gzip", "x-gzip":
+		decoded, err = gzip.NewReader(body)
+	case "deflate":
+		decoded, err = zlib.NewReader(body)
+	default:
+		return nil, &Error{
+			Title:  "Unsupported Media Type",
+			Detail: fmt.Sprintf("Unsupported Content-Encoding: %s", encoding),
+			Status: http.StatusUnsupportedMediaType,
+		}
+	}
+
+	if err != nil {
+		return nil, headerError(fmt.Sprintf("Request body is not valid %s: %s", encoding, err.Error()))
+	}
+
+	return &decodingReader{reader: decoded, encoding: encoding}, nil
+}
+
+// decodingReader reads a decompressed body, turning corruption found partway
+// through it into a decodingError
+type decodingReader struct {
+	reader   io.Reader
+	encoding string
+}
+
+func (d *decodingReader) Read(p []byte) (int, error) {
+	n, err := d.reader.Read(p)
+	if err != nil && corrupt(err) {
+		err = &decodingError{encoding: d.encoding, err: err}
+	}
+
+	return n, err
+}
+
+// decodingError is a compressed body turning out to be corrupt or truncated
+type decodingError struct {
+	encoding string
+	err      error
+}
+
+func (e *decodingError) Error() string {
+	return fmt.Sprintf("Request body is not valid %s: %s", e.encoding, e.err.Error())
+}
+
+// corrupt returns true for the decompression errors of malformed input, as
+// opposed to those of reading the underlying body
+func corrupt(err error) bool {
+	var corruptInput flate.CorruptInputError
+
+	return errors.As(err, &corruptInput) ||
+		errors.Is(err, gzip.ErrChecksum) ||
+		errors.Is(err, gzip.ErrHeader) ||
+		errors.Is(err, zlib.ErrChecksum) ||
+		errors.Is(err, zlib.ErrHeader) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
@@ -0,0 +1,129 @@
+package jsh
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCompression(t *testing.T) {
+
+	Convey("Compression Tests", t, func() {
+
+		Convey("->acceptsGzip()", func() {
+			accepts := func(header string) bool {
+				r := &http.Request{Header: http.Header{}}
+				r.Header.Set("Accept-Encoding", header)
+				return acceptsGzip(r)
+			}
+
+			So(accepts("gzip, deflate"), ShouldBeTrue)
+			So(accepts("*"), ShouldBeTrue)
+			So(accepts("deflate"), ShouldBeFalse)
+			So(accepts("gzip;q=0"), ShouldBeFalse)
+			So(accepts("*, gzip;q=0"), ShouldBeFalse)
+			So(accepts(""), ShouldBeFalse)
+		})
+
+		Convey("request decompression", func() {
+			body := &bytes.Buffer{}
+			writer := gzip.NewWriter(body)
+			writer.Write([]byte(`{"data": {"type": "users", "attributes": {"name": "bob"}}}`))
+			writer.Close()
+
+			req, reqErr := testRequest(body.Bytes())
+			So(reqErr, ShouldBeNil)
+			req.Method = "POST"
+			req.Header.Set("Content-Encoding", "gzip")
+
+			Convey("should parse gzipped bodies", func() {
+				object, err := ParseObject(req)
+				So(err, ShouldBeNil)
+				So(object.Type, ShouldEqual, "users")
+			})
+
+			Convey("should reject unsupported encodings", func() {
+				req.Header.Set("Content-Encoding", "br")
+
+				_, err := ParseObject(req)
+				So(err, ShouldNotBeNil)
+				So(err.Status, ShouldEqual, http.StatusUnsupportedMediaType)
+			})
+
+			Convey("should reject corrupt bodies", func() {
+				req.Body = ioutil.NopCloser(bytes.NewReader([]byte("not gzip")))
+
+				_, err := ParseObject(req)
+				So(err, ShouldNotBeNil)
+				So(err.Status, ShouldEqual, http.StatusBadRequest)
+			})
+
+			Convey("should reject bodies corrupted partway through", func() {
+				corrupted := body.Bytes()
+				// flip a byte of the CRC-32 in the gzip trailer
+				corrupted[len(corrupted)-8] ^= 0xff
+				req.Body = ioutil.NopCloser(bytes.NewReader(corrupted))
+
+				_, err := ParseObject(req)
+				So(err, ShouldNotBeNil)
+				So(err.Status, ShouldEqual, http.StatusBadRequest)
+			})
+
+			Convey("should reject truncated bodies", func() {
+				req.Body = ioutil.NopCloser(bytes.NewReader(body.Bytes()[:body.Len()/2]))
+
+				_, err := ParseObject(req)
+				So(err, ShouldNotBeNil)
+				So(err.Status, ShouldEqual, http.StatusBadRequest)
+			})
+		})
+
+		Convey("response compression", func() {
+			ResponseCompression = &Compression{MinSize: 100}
+			Reset(func() {
+				ResponseCompression = nil
+			})
+
+			request := &http.Request{Method: "GET", Header: http.Header{}}
+			request.Header.Set("Accept-Encoding", "gzip")
+
+			object, objErr := NewObject("1", "users", map[string]string{"bio": string(bytes.Repeat([]byte("a"), 200))})
+			So(objErr, ShouldBeNil)
+
+			Convey("should gzip large responses", func() {
+				writer := httptest.NewRecorder()
+				err := Send(writer, request, object)
+				So(err, ShouldBeNil)
+				So(writer.Header().Get("Content-Encoding"), ShouldEqual, "gzip")
+				So(writer.Header().Get("Vary"), ShouldEqual, "Accept-Encoding")
+
+				reader, gzipErr := gzip.NewReader(writer.Body)
+				So(gzipErr, ShouldBeNil)
+				content, readErr := ioutil.ReadAll(reader)
+				So(readErr, ShouldBeNil)
+				So(string(content), ShouldContainSubstring, `"users"`)
+			})
+
+			Convey("should not gzip small responses", func() {
+				ResponseCompression.MinSize = 100000
+
+				writer := httptest.NewRecorder()
+				Send(writer, request, object)
+				So(writer.Header().Get("Content-Encoding"), ShouldEqual, "")
+			})
+
+			Convey("should not gzip for clients that don't accept it", func() {
+				request.Header.Del("Accept-Encoding")
+
+				writer := httptest.NewRecorder()
+				Send(writer, request, object)
+				So(writer.Header().Get("Content-Encoding"), ShouldEqual, "")
+			})
+		})
+	})
+}
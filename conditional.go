@@ -0,0 +1,78 @@
+package jsh
+
+import (
+	"net/http"
+	"time"
+)
+
+/*
+SetLastModified sets the Last-Modified header of a response, so that Send
+answers conditional requests sent with If-Modified-Since:
+
+	jsh.SetLastModified(w, article.UpdatedAt)
+	jsh.Send(w, r, object)
+
+Combine with a CachePolicy to control how long clients may reuse the response
+before revalidating it.
+*/
+func SetLastModified(w http.ResponseWriter, modified time.Time) {
+	w.Header().Set("Last-Modified", modified.UTC().Format(http.TimeFormat))
+}
+
+/*
+IsNotModified returns true if a GET or HEAD request's If-None-Match or
+If-Modified-Since headers match the ETag or Last-Modified of the response
+headers, meaning the client's copy is current. Send checks this itself and
+responds with a 304 Not Modified, handlers can call it to skip rendering
+expensive responses:
+
+	jsh.SetLastModified(w, article.UpdatedAt)
+	if jsh.IsNotModified(r, w.Header()) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+As the HTTP specification requires, If-Modified-Since is ignored when
+If-None-Match is present.
+*/
+func IsNotModified(r *http.Request, header http.Header) bool {
+	if r == nil || (r.Method != "GET" && r.Method != "HEAD") {
+		return false
+	}
+
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		etag := header.Get("ETag")
+		return etag != "" && matchesETag(ifNoneMatch, etag, true)
+	}
+
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+
+	modified, err := http.ParseTime(header.Get("Last-Modified"))
+	if err != nil {
+		return false
+	}
+
+	return !modified.After(since)
+}
+
+// sendNotModified responds with a 304 if the document is a successful response
+// the client already has a current copy of
+func sendNotModified(w http.ResponseWriter, r *http.Request, document *Document) bool {
+	if document.Status != http.StatusOK || !IsNotModified(r, w.Header()) {
+		return false
+	}
+
+	w.WriteHeader(http.StatusNotModified)
+
+	observe(&MetricEvent{
+		Kind:   SendEvent,
+		Method: r.Method,
+		Path:   requestPath(r),
+		Status: http.StatusNotModified,
+	})
+
+	return true
+}
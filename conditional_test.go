@@ -0,0 +1,80 @@
+package jsh
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestConditional(t *testing.T) {
+
+	Convey("Conditional Request Tests", t, func() {
+
+		modified := time.Date(2016, 1, 2, 3, 4, 5, 0, time.UTC)
+		request := &http.Request{Method: "GET", Header: http.Header{}}
+
+		object, objErr := NewObject("1", "articles", map[string]string{"title": "a"})
+		So(objErr, ShouldBeNil)
+
+		Convey("->IsNotModified()", func() {
+			header := http.Header{}
+			header.Set("ETag", `"abc"`)
+			header.Set("Last-Modified", modified.Format(http.TimeFormat))
+
+			Convey("should match If-None-Match", func() {
+				request.Header.Set("If-None-Match", `W/"abc"`)
+				So(IsNotModified(request, header), ShouldBeTrue)
+
+				request.Header.Set("If-None-Match", `"other"`)
+				So(IsNotModified(request, header), ShouldBeFalse)
+			})
+
+			Convey("should compare If-Modified-Since", func() {
+				request.Header.Set("If-Modified-Since", modified.Format(http.TimeFormat))
+				So(IsNotModified(request, header), ShouldBeTrue)
+
+				request.Header.Set("If-Modified-Since", modified.Add(-time.Hour).Format(http.TimeFormat))
+				So(IsNotModified(request, header), ShouldBeFalse)
+			})
+
+			Convey("should ignore If-Modified-Since when If-None-Match is present", func() {
+				request.Header.Set("If-None-Match", `"other"`)
+				request.Header.Set("If-Modified-Since", modified.Format(http.TimeFormat))
+				So(IsNotModified(request, header), ShouldBeFalse)
+			})
+
+			Convey("should only apply to GET and HEAD requests", func() {
+				request.Method = "PATCH"
+				request.Header.Set("If-None-Match", `"abc"`)
+				So(IsNotModified(request, header), ShouldBeFalse)
+			})
+		})
+
+		Convey("->Send()", func() {
+			writer := httptest.NewRecorder()
+			SetLastModified(writer, modified)
+
+			Convey("should respond with a 304 for current copies", func() {
+				request.Header.Set("If-Modified-Since", modified.Format(http.TimeFormat))
+
+				err := Send(writer, request, object)
+				So(err, ShouldBeNil)
+				So(writer.Code, ShouldEqual, http.StatusNotModified)
+				So(writer.Body.Len(), ShouldEqual, 0)
+				So(writer.Header().Get("Last-Modified"), ShouldEqual, modified.Format(http.TimeFormat))
+			})
+
+			Convey("should send the document for stale copies", func() {
+				request.Header.Set("If-Modified-Since", modified.Add(-time.Hour).Format(http.TimeFormat))
+
+				err := Send(writer, request, object)
+				So(err, ShouldBeNil)
+				So(writer.Code, ShouldEqual, http.StatusOK)
+				So(writer.Body.Len(), ShouldBeGreaterThan, 0)
+			})
+		})
+	})
+}
@@ -0,0 +1,50 @@
+package jsh
+
+import "net/http"
+
+// ConsistencyHeader carries read-your-writes consistency tokens between servers
+// and clients.
+const ConsistencyHeader = "Jsh-Consistency-Token"
+
+/*
+SetConsistencyToken returns a consistency token with a response, typically one
+identifying the write just made, such as a database log position:
+
+	jsh.SetConsistencyToken(w, strconv.FormatInt(commit.LSN, 10))
+	jsh.Send(w, r, object)
+
+The token is sent in the ConsistencyHeader and in the "consistency" member of
+the document's top-level meta. Clients with a jsc.ConsistencySession echo the
+latest token on subsequent requests, available to handlers via
+ConsistencyToken, so that reads can wait for or be routed to a replica that has
+caught up with the session's writes.
+*/
+func SetConsistencyToken(w http.ResponseWriter, token string) {
+	w.Header().Set(ConsistencyHeader, token)
+}
+
+// ConsistencyToken returns the consistency token echoed by a client, if any.
+func ConsistencyToken(r *http.Request) string {
+	return r.Header.Get(ConsistencyHeader)
+}
+
+// addConsistencyMeta copies the response's consistency token into the
+// document's meta
+func addConsistencyMeta(w http.ResponseWriter, document *Document) {
+	token := w.Header().Get(ConsistencyHeader)
+	if token == "" || document.HasErrors() {
+		return
+	}
+
+	meta := metaMap(document.Meta)
+	if meta == nil {
+		if document.Meta != nil {
+			// meta that isn't an object can't carry the token
+			return
+		}
+		meta = map[string]interface{}{}
+	}
+
+	meta["consistency"] = token
+	document.Meta = meta
+}
@@ -0,0 +1,45 @@
+package jsh
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestConsistency(t *testing.T) {
+
+	Convey("Consistency Token Tests", t, func() {
+
+		request := &http.Request{Method: "POST", Header: http.Header{}}
+		object, _ := NewObject("1", "users", map[string]string{"name": "bob"})
+
+		Convey("should send the token in the header and meta", func() {
+			writer := httptest.NewRecorder()
+			SetConsistencyToken(writer, "42")
+
+			err := Send(writer, request, object)
+			So(err, ShouldBeNil)
+			So(writer.Header().Get(ConsistencyHeader), ShouldEqual, "42")
+
+			doc := struct {
+				Meta map[string]string `json:"meta"`
+			}{}
+			So(json.Unmarshal(writer.Body.Bytes(), &doc), ShouldBeNil)
+			So(doc.Meta["consistency"], ShouldEqual, "42")
+		})
+
+		Convey("should not add meta without a token", func() {
+			writer := httptest.NewRecorder()
+			Send(writer, request, object)
+			So(writer.Body.String(), ShouldNotContainSubstring, "consistency")
+		})
+
+		Convey("->ConsistencyToken()", func() {
+			request.Header.Set(ConsistencyHeader, "41")
+			So(ConsistencyToken(request), ShouldEqual, "41")
+		})
+	})
+}
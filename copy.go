@@ -0,0 +1,196 @@
+package jsh
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+/*
+Copy returns a deep copy of the object, so that it can be changed without
+affecting the original, such as to keep the state of a resource before applying
+a PATCH:
+
+	before := existing.Copy()
+	err := existing.Merge(patch)
+	...
+	changed, err := before.DiffAttributes(existing)
+
+Meta values are copied if they are decoded JSON, maps and slices of them, and
+shared otherwise.
+*/
+func (o *Object) Copy() *Object {
+	copied := *o
+
+	if o.Attributes != nil {
+		copied.Attributes = append(json.RawMessage{}, o.Attributes...)
+	}
+
+	if o.Links != nil {
+		copied.Links = make(map[string]*Link, len(o.Links))
+		for name, link := range o.Links {
+			copied.Links[name] = link.copy()
+		}
+	}
+
+	if o.Relationships != nil {
+		copied.Relationships = make(map[string]*Relationship, len(o.Relationships))
+		for name, relationship := range o.Relationships {
+			copied.Relationships[name] = relationship.copy()
+		}
+	}
+
+	copied.Meta = copyMeta(o.Meta)
+	copied.Extra = copyExtra(o.Extra)
+
+	return &copied
+}
+
+/*
+Equal returns true if the other object represents the same resource state:
+the same type, ID, attributes, relationships, links, meta, and extra members. Members are
+compared by their JSON values, so attributes differing only in key order or
+whitespace are equal. Status isn't compared.
+*/
+func (o *Object) Equal(other *Object) bool {
+	if o == nil || other == nil {
+		return o == other
+	}
+
+	if o.Type != other.Type || o.ID != other.ID {
+		return false
+	}
+
+	changed, err := o.DiffAttributes(other)
+	if err != nil {
+		if !bytes.Equal(o.Attributes, other.Attributes) {
+			return false
+		}
+	} else if len(changed) > 0 {
+		return false
+	}
+
+	// nil and empty maps marshal differently but are both left out of objects
+	return (len(o.Relationships) == 0 && len(other.Relationships) == 0 || jsonEqual(o.Relationships, other.Relationships)) &&
+		(len(o.Links) == 0 && len(other.Links) == 0 || jsonEqual(o.Links, other.Links)) &&
+		(len(o.Meta) == 0 && len(other.Meta) == 0 || jsonEqual(o.Meta, other.Meta)) &&
+		(len(o.Extra) == 0 && len(other.Extra) == 0 || jsonEqual(o.Extra, other.Extra))
+}
+
+/*
+DiffAttributes returns the sorted names of the attributes whose values differ
+between the object and the other object, including those only one of them has,
+such as to audit which attributes a PATCH changed.
+*/
+func (o *Object) DiffAttributes(other *Object) ([]string, *Error) {
+	attributes, err := o.attributeMap()
+	if err != nil {
+		return nil, err
+	}
+
+	otherAttributes, err := other.attributeMap()
+	if err != nil {
+		return nil, err
+	}
+
+	changed := []string{}
+	for name, value := range attributes {
+		otherValue, exists := otherAttributes[name]
+		if !exists || !jsonEqual(value, otherValue) {
+			changed = append(changed, name)
+		}
+	}
+
+	for name := range otherAttributes {
+		if _, exists := attributes[name]; !exists {
+			changed = append(changed, name)
+		}
+	}
+
+	sort.Strings(changed)
+	return changed, nil
+}
+
+// copy returns a deep copy of the link
+func (l *Link) copy() *Link {
+	if l == nil {
+		return nil
+	}
+
+	return &Link{HREF: l.HREF, Meta: copyMeta(l.Meta)}
+}
+
+// copy returns a deep copy of the relationship
+func (r *Relationship) copy() *Relationship {
+	if r == nil {
+		return nil
+	}
+
+	copied := *r
+	copied.Meta = copyMeta(r.Meta)
+	copied.Extra = copyExtra(r.Extra)
+
+	if r.Links != nil {
+		copied.Links = &Links{
+			Self:    r.Links.Self.copy(),
+			Related: r.Links.Related.copy(),
+			First:   r.Links.First.copy(),
+			Last:    r.Links.Last.copy(),
+			Prev:    r.Links.Prev.copy(),
+			Next:    r.Links.Next.copy(),
+		}
+	}
+
+	if r.Data != nil {
+		copied.Data = make(ResourceLinkage, len(r.Data))
+		for i, identifier := range r.Data {
+			if identifier != nil {
+				copiedIdentifier := *identifier
+				copied.Data[i] = &copiedIdentifier
+			}
+		}
+	}
+
+	return &copied
+}
+
+// copyMeta returns a deep copy of a meta map
+func copyMeta(meta map[string]interface{}) map[string]interface{} {
+	if meta == nil {
+		return nil
+	}
+
+	return deepCopy(meta).(map[string]interface{})
+}
+
+// copyExtra returns a copy of a map of extra members
+func copyExtra(extra map[string]json.RawMessage) map[string]json.RawMessage {
+	if extra == nil {
+		return nil
+	}
+
+	copied := make(map[string]json.RawMessage, len(extra))
+	for name, value := range extra {
+		copied[name] = append(json.RawMessage{}, value...)
+	}
+
+	return copied
+}
+
+// jsonEqual returns true if two values marshal to the same JSON value
+func jsonEqual(a interface{}, b interface{}) bool {
+	var decodedA, decodedB interface{}
+
+	rawA, errA := json.Marshal(a)
+	rawB, errB := json.Marshal(b)
+	if errA != nil || errB != nil {
+		return reflect.DeepEqual(a, b)
+	}
+
+	if json.Unmarshal(rawA, &decodedA) != nil || json.Unmarshal(rawB, &decodedB) != nil {
+		return bytes.Equal(rawA, rawB)
+	}
+
+	return reflect.DeepEqual(decodedA, decodedB)
+}
@@ -0,0 +1,80 @@
+package jsh
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCopy(t *testing.T) {
+
+	Convey("Copy Tests", t, func() {
+
+		object, err := NewObject("1", "users", map[string]interface{}{"name": "Bob", "tags": []string{"a"}})
+		So(err, ShouldBeNil)
+		object.Links["self"] = &Link{HREF: "/users/1"}
+		object.Relationships["team"] = &Relationship{
+			Data:  ResourceLinkage{{Type: "teams", ID: "1"}},
+			Links: &Links{Related: &Link{HREF: "/users/1/team"}},
+		}
+		object.Meta = map[string]interface{}{"nested": map[string]interface{}{"count": 1.0}}
+
+		Convey("->Copy()", func() {
+			copied := object.Copy()
+			So(copied, ShouldResemble, object)
+			So(copied.Equal(object), ShouldBeTrue)
+
+			copied.Attributes[2] = 'X'
+			copied.Links["self"].HREF = "/other"
+			copied.Relationships["team"].Data[0].ID = "2"
+			copied.Relationships["team"].Links.Related.HREF = "/other"
+			copied.Meta["nested"].(map[string]interface{})["count"] = 2.0
+
+			So(string(object.Attributes), ShouldContainSubstring, `"name"`)
+			So(object.Links["self"].HREF, ShouldEqual, "/users/1")
+			So(object.Relationships["team"].Data[0].ID, ShouldEqual, "1")
+			So(object.Relationships["team"].Links.Related.HREF, ShouldEqual, "/users/1/team")
+			So(object.Meta["nested"].(map[string]interface{})["count"], ShouldEqual, 1.0)
+		})
+
+		Convey("->Equal()", func() {
+			parsed := &Object{}
+			raw, _ := json.Marshal(object)
+			So(json.Unmarshal(raw, parsed), ShouldBeNil)
+			So(parsed.Equal(object), ShouldBeTrue)
+
+			reordered := object.Copy()
+			reordered.Attributes = []byte(`{ "tags": ["a"], "name": "Bob" }`)
+			So(reordered.Equal(object), ShouldBeTrue)
+
+			changed := object.Copy()
+			changed.Relationships["team"].Data[0].ID = "2"
+			So(changed.Equal(object), ShouldBeFalse)
+
+			So(object.Equal(nil), ShouldBeFalse)
+			So(object.Equal(&Object{Type: "users", ID: "2"}), ShouldBeFalse)
+
+			Convey("should treat nil and empty members alike", func() {
+				bare, _ := NewObject("2", "users", map[string]string{})
+				So(bare.Equal(&Object{Type: "users", ID: "2", Attributes: []byte(`{}`)}), ShouldBeTrue)
+			})
+		})
+
+		Convey("->DiffAttributes()", func() {
+			patched := object.Copy()
+			So(patched.Merge(&Object{Type: "users", ID: "1", Attributes: []byte(`{"name": "Robert", "age": 30}`)}), ShouldBeNil)
+
+			changed, err := object.DiffAttributes(patched)
+			So(err, ShouldBeNil)
+			So(changed, ShouldResemble, []string{"age", "name"})
+
+			changed, err = object.DiffAttributes(object.Copy())
+			So(err, ShouldBeNil)
+			So(len(changed), ShouldEqual, 0)
+
+			_, err = object.DiffAttributes(&Object{Type: "users", Attributes: []byte(`[]`)})
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
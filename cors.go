@@ -0,0 +1,138 @@
+package jsh
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+CORS, when set, has Middleware answer cross-origin requests from the allowed
+origins, so browser applications can consume the API without a separate CORS
+layer:
+
+	jsh.CORS = &jsh.CORSPolicy{AllowedOrigins: []string{"https://app.example.com"}}
+
+Since the JSON API media type isn't one browsers send without asking, every
+write is preceded by a preflight OPTIONS request, which Middleware answers
+before checking the Content-Type and Accept headers. Leave nil to leave CORS to
+the handlers.
+*/
+var CORS *CORSPolicy
+
+// DefaultCORSHeaders are the request headers allowed when
+// CORSPolicy.AllowedHeaders is empty.
+var DefaultCORSHeaders = []string{"Accept", "Authorization", "Content-Type", "If-Match", "If-None-Match", ConsistencyHeader}
+
+// DefaultCORSExposedHeaders are the response headers exposed to scripts when
+// CORSPolicy.ExposedHeaders is empty, those jsh sends besides the safelisted
+// ones.
+var DefaultCORSExposedHeaders = []string{
+	"Location", "Content-Location", "Link", "ETag", "Retry-After", "Sunset", DeprecationHeader, ConsistencyHeader,
+	QuotaLimitHeader, QuotaRemainingHeader, QuotaResetHeader,
+}
+
+// CORSPolicy configures the cross-origin requests Middleware allows.
+type CORSPolicy struct {
+	// AllowedOrigins are the origins allowed, such as https://app.example.com,
+	// or "*" for any
+	AllowedOrigins []string `json:"allowed_origins"`
+	// AllowedMethods are the methods allowed, those of the requested route, see
+	// AllowedMethods, if empty
+	AllowedMethods []string `json:"allowed_methods"`
+	// AllowedHeaders are the request headers allowed, DefaultCORSHeaders if
+	// empty
+	AllowedHeaders []string `json:"allowed_headers"`
+	// ExposedHeaders are the response headers scripts may read,
+	// DefaultCORSExposedHeaders if empty
+	ExposedHeaders []string `json:"exposed_headers"`
+	// AllowCredentials allows requests with cookies or HTTP authentication.
+	// The request's origin is then sent back rather than "*".
+	AllowCredentials bool `json:"allow_credentials"`
+	// MaxAge is how long browsers may cache preflight results, zero leaves it
+	// to the browser
+	MaxAge time.Duration `json:"max_age"`
+}
+
+// allows returns true if the policy allows requests from the origin
+func (c *CORSPolicy) allows(origin string) bool {
+	for _, allowed := range c.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+
+	return false
+}
+
+/*
+apply adds the CORS headers for a request from an allowed origin, returning
+true if the request was a preflight and has been answered. Requests without an
+Origin, or from origins that aren't allowed, are left alone.
+*/
+func (c *CORSPolicy) apply(w http.ResponseWriter, r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if c == nil || origin == "" {
+		return false
+	}
+
+	header := w.Header()
+	header.Add("Vary", "Origin")
+	if !c.allows(origin) {
+		return false
+	}
+
+	if c.AllowCredentials || !c.allowsAny() {
+		header.Set("Access-Control-Allow-Origin", origin)
+	} else {
+		header.Set("Access-Control-Allow-Origin", "*")
+	}
+	if c.AllowCredentials {
+		header.Set("Access-Control-Allow-Credentials", "true")
+	}
+
+	if r.Method != "OPTIONS" || r.Header.Get("Access-Control-Request-Method") == "" {
+		header.Set("Access-Control-Expose-Headers", strings.Join(orDefault(c.ExposedHeaders, DefaultCORSExposedHeaders), ", "))
+		return false
+	}
+
+	methods := c.AllowedMethods
+	if len(methods) == 0 {
+		methods = AllowedMethods(r.URL.Path)
+	}
+	if len(methods) == 0 {
+		methods = []string{"GET", "HEAD", "POST", "PATCH", "DELETE"}
+	}
+
+	header.Add("Vary", "Access-Control-Request-Method")
+	header.Add("Vary", "Access-Control-Request-Headers")
+	header.Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+	header.Set("Access-Control-Allow-Headers", strings.Join(orDefault(c.AllowedHeaders, DefaultCORSHeaders), ", "))
+	if c.MaxAge > 0 {
+		header.Set("Access-Control-Max-Age", strconv.Itoa(int(c.MaxAge/time.Second)))
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return true
+}
+
+// allowsAny returns true if the policy allows any origin
+func (c *CORSPolicy) allowsAny() bool {
+	for _, allowed := range c.AllowedOrigins {
+		if allowed == "*" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// orDefault returns values, or the defaults if there are none
+func orDefault(values []string, defaults []string) []string {
+	if len(values) == 0 {
+		return defaults
+	}
+
+	return values
+}
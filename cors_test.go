@@ -0,0 +1,98 @@
+package jsh
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCORS(t *testing.T) {
+
+	Convey("CORS Tests", t, func() {
+
+		Register(&Resource{Type: "widgets"})
+		CORS = &CORSPolicy{AllowedOrigins: []string{"https://app.example.com"}, MaxAge: time.Hour}
+		Reset(func() {
+			Unregister("widgets")
+			CORS = nil
+		})
+
+		handled := false
+		handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			handled = true
+			w.Header().Set("Location", "/widgets/1")
+			w.WriteHeader(http.StatusCreated)
+		}))
+
+		serve := func(method string, origin string) *httptest.ResponseRecorder {
+			request, _ := http.NewRequest(method, "/widgets", nil)
+			if origin != "" {
+				request.Header.Set("Origin", origin)
+			}
+			if method == "OPTIONS" {
+				request.Header.Set("Access-Control-Request-Method", "POST")
+			}
+
+			writer := httptest.NewRecorder()
+			handler.ServeHTTP(writer, request)
+			return writer
+		}
+
+		Convey("should answer preflights from allowed origins", func() {
+			writer := serve("OPTIONS", "https://app.example.com")
+			So(handled, ShouldBeFalse)
+			So(writer.Code, ShouldEqual, http.StatusNoContent)
+			So(writer.Header().Get("Access-Control-Allow-Origin"), ShouldEqual, "https://app.example.com")
+			So(writer.Header().Get("Access-Control-Allow-Methods"), ShouldEqual, "GET, HEAD, OPTIONS, POST")
+			So(writer.Header().Get("Access-Control-Allow-Headers"), ShouldContainSubstring, "Content-Type")
+			So(writer.Header().Get("Access-Control-Max-Age"), ShouldEqual, "3600")
+
+			Convey("even with a JSON API Accept header the checks would refuse", func() {
+				request, _ := http.NewRequest("OPTIONS", "/widgets", nil)
+				request.Header.Set("Origin", "https://app.example.com")
+				request.Header.Set("Access-Control-Request-Method", "POST")
+				request.Header.Set("Accept", ContentType+"; charset=utf-8")
+
+				writer := httptest.NewRecorder()
+				handler.ServeHTTP(writer, request)
+				So(writer.Code, ShouldEqual, http.StatusNoContent)
+			})
+		})
+
+		Convey("should expose headers to allowed origins", func() {
+			request, _ := http.NewRequest("POST", "/widgets", bytes.NewReader([]byte(`{}`)))
+			request.Header.Set("Content-Type", ContentType)
+			request.Header.Set("Origin", "https://app.example.com")
+
+			writer := httptest.NewRecorder()
+			handler.ServeHTTP(writer, request)
+			So(handled, ShouldBeTrue)
+			So(writer.Code, ShouldEqual, http.StatusCreated)
+			So(writer.Header().Get("Access-Control-Allow-Origin"), ShouldEqual, "https://app.example.com")
+			So(writer.Header().Get("Access-Control-Expose-Headers"), ShouldContainSubstring, "Location")
+			So(writer.Header().Get("Vary"), ShouldEqual, "Origin")
+		})
+
+		Convey("should leave other origins alone", func() {
+			writer := serve("GET", "https://evil.example.com")
+			So(writer.Header().Get("Access-Control-Allow-Origin"), ShouldBeEmpty)
+
+			writer = serve("GET", "")
+			So(writer.Header().Get("Vary"), ShouldBeEmpty)
+		})
+
+		Convey("should allow any origin with *", func() {
+			CORS.AllowedOrigins = []string{"*"}
+			So(serve("GET", "https://other.example.com").Header().Get("Access-Control-Allow-Origin"), ShouldEqual, "*")
+
+			CORS.AllowCredentials = true
+			writer := serve("GET", "https://other.example.com")
+			So(writer.Header().Get("Access-Control-Allow-Origin"), ShouldEqual, "https://other.example.com")
+			So(writer.Header().Get("Access-Control-Allow-Credentials"), ShouldEqual, "true")
+		})
+	})
+}
@@ -0,0 +1,123 @@
+package jsh
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+)
+
+// DataShape identifies the shape of a document's primary data.
+type DataShape int
+
+const (
+	// NullData is a "data" member that is null or missing
+	NullData DataShape = iota
+	// ObjectData is a "data" member holding a single resource object
+	ObjectData
+	// ListData is a "data" member holding an array of resource objects
+	ListData
+)
+
+/*
+PrimaryData is the primary data of a parsed document, either a single Object or
+a List depending on Shape.
+*/
+type PrimaryData struct {
+	Shape DataShape
+	// Object is set for ObjectData
+	Object *Object
+	// List is set for ListData, and may be empty
+	List List
+}
+
+/*
+ParseData parses a request whose "data" may either be a single resource object
+or an array of them, for endpoints that legitimately accept both:
+
+	data, err := jsh.ParseData(r)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	switch data.Shape {
+	case jsh.ObjectData:
+		...
+	case jsh.ListData:
+		...
+	}
+
+The body is read once, and validated like ParseObject or ParseList would based
+on its shape.
+*/
+func ParseData(r *http.Request) (*PrimaryData, *Error) {
+	return NewParser(r).Data(r.Body)
+}
+
+// Data parses a document whose primary data may be an object or a list, see
+// ParseData.
+func (p *Parser) Data(payload io.ReadCloser) (*PrimaryData, *Error) {
+	defer closeReader(payload)
+
+	start := time.Now()
+	shape := NullData
+	mode := ObjectMode
+	var document *Document
+
+	body, err := p.readDocument(payload)
+	if err == nil {
+		shape = dataShape(body)
+		if shape == ListData {
+			mode = ListMode
+		}
+
+		document, err = p.parse(body, mode)
+	}
+	p.logParse(start, mode, document, err)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data := &PrimaryData{Shape: shape}
+	switch shape {
+	case ObjectData:
+		data.Object = document.First()
+		if data.Object == nil {
+			data.Shape = NullData
+		} else if p.Method != "POST" && data.Object.ID == "" {
+			return nil, InputError("Missing mandatory object attribute", "id")
+		}
+	case ListData:
+		data.List = document.Data
+		if data.List == nil {
+			data.List = List{}
+		}
+	}
+
+	return data, nil
+}
+
+// dataShape determines the shape of the "data" member of a document body,
+// leaving invalid bodies to the full parse to report
+func dataShape(body []byte) DataShape {
+	document := struct {
+		Data json.RawMessage `json:"data"`
+	}{}
+
+	if json.Unmarshal(body, &document) != nil {
+		return ObjectData
+	}
+
+	data := bytes.TrimSpace(document.Data)
+	switch {
+	case len(data) == 0 || bytes.Equal(data, []byte("null")):
+		return NullData
+	case data[0] == '[':
+		return ListData
+	}
+
+	return ObjectData
+}
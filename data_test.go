@@ -0,0 +1,81 @@
+package jsh
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestData(t *testing.T) {
+
+	Convey("Primary Data Tests", t, func() {
+
+		Convey("->ParseData()", func() {
+
+			Convey("should parse single objects", func() {
+				req, reqErr := testRequest([]byte(`{"data": {"type": "user", "id": "1", "attributes": {"name": "Bob"}}}`))
+				So(reqErr, ShouldBeNil)
+
+				data, err := ParseData(req)
+				So(err, ShouldBeNil)
+				So(data.Shape, ShouldEqual, ObjectData)
+				So(data.Object.ID, ShouldEqual, "1")
+				So(data.List, ShouldBeNil)
+			})
+
+			Convey("should parse lists", func() {
+				req, reqErr := testRequest([]byte(`{"data": [
+					{"type": "user", "id": "1", "attributes": {"name": "Bob"}},
+					{"type": "user", "id": "2", "attributes": {"name": "Ann"}}
+				]}`))
+				So(reqErr, ShouldBeNil)
+
+				data, err := ParseData(req)
+				So(err, ShouldBeNil)
+				So(data.Shape, ShouldEqual, ListData)
+				So(data.List.IDs(), ShouldResemble, []string{"1", "2"})
+				So(data.Object, ShouldBeNil)
+			})
+
+			Convey("should parse empty lists", func() {
+				req, reqErr := testRequest([]byte(`{"data": []}`))
+				So(reqErr, ShouldBeNil)
+
+				data, err := ParseData(req)
+				So(err, ShouldBeNil)
+				So(data.Shape, ShouldEqual, ListData)
+				So(len(data.List), ShouldEqual, 0)
+			})
+
+			Convey("should parse null data", func() {
+				req, reqErr := testRequest([]byte(`{"data": null}`))
+				So(reqErr, ShouldBeNil)
+
+				data, err := ParseData(req)
+				So(err, ShouldBeNil)
+				So(data.Shape, ShouldEqual, NullData)
+			})
+
+			Convey("should validate according to the shape", func() {
+				req, reqErr := testRequest([]byte(`{"data": [
+					{"type": "user", "id": "1", "attributes": {"name": "Bob"}},
+					{"id": "2", "attributes": {"name": "Ann"}}
+				]}`))
+				So(reqErr, ShouldBeNil)
+
+				_, err := ParseData(req)
+				So(err, ShouldNotBeNil)
+				So(err.Source.Pointer, ShouldStartWith, "/data/1")
+			})
+
+			Convey("should require object IDs outside of POSTs", func() {
+				req, reqErr := testRequest([]byte(`{"data": {"type": "user", "attributes": {"name": "Bob"}}}`))
+				So(reqErr, ShouldBeNil)
+				req.Method = "PATCH"
+
+				_, err := ParseData(req)
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
@@ -0,0 +1,68 @@
+package jsh
+
+import (
+	"fmt"
+	"sort"
+)
+
+/*
+DefaultFunc computes the default value of an attribute for an object being
+created, such as a timestamp or a generated UUID. Use it as a value of
+Resource.Defaults:
+
+	jsh.Register(&jsh.Resource{
+		Type: "posts",
+		Defaults: map[string]interface{}{
+			"status":     "draft",
+			"created_at": jsh.DefaultFunc(func(object *jsh.Object) (interface{}, *jsh.Error) {
+				return time.Now().UTC(), nil
+			}),
+		},
+	})
+
+Defaults are applied to POSTed objects that leave the attribute out, before
+they are checked against the resource's Schema, so that Required attributes
+with a default may be omitted, and after its write Policy, so that callers who
+may not write an attribute still get its default. Declare the attribute
+ServerManaged as well to keep clients from overriding its default.
+*/
+type DefaultFunc func(object *Object) (interface{}, *Error)
+
+// applyDefaults sets the default values of the attributes an object being
+// created leaves out
+func applyDefaults(defaults map[string]interface{}, object *Object) *Error {
+	attributes, err := object.attributeMap()
+	if err != nil {
+		return err
+	}
+
+	names := []string{}
+	for name := range defaults {
+		if _, exists := attributes[name]; !exists {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := defaults[name]
+		if compute, ok := value.(DefaultFunc); ok {
+			computed, err := compute(object)
+			if err != nil {
+				return err
+			}
+			value = computed
+		}
+
+		raw, jsonErr := JSON.Marshal(value)
+		if jsonErr != nil {
+			return ISE(fmt.Sprintf("Error marshaling default value of attribute '%s': %s", name, jsonErr))
+		}
+		attributes[name] = raw
+	}
+
+	return object.marshal(attributes, nil)
+}
@@ -0,0 +1,91 @@
+package jsh
+
+import (
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDefaults(t *testing.T) {
+
+	Convey("Defaults Tests", t, func() {
+
+		Register(&Resource{
+			Type: "posts",
+			Defaults: map[string]interface{}{
+				"status": "draft",
+				"slug": DefaultFunc(func(object *Object) (interface{}, *Error) {
+					return "post-" + object.ID, nil
+				}),
+			},
+			Schema: &Schema{
+				Attributes: map[string]*AttributeSchema{
+					"title":  {Type: StringAttribute, Required: true},
+					"status": {Type: StringAttribute, Required: true},
+					"slug":   {Type: StringAttribute},
+				},
+			},
+		})
+		Reset(func() { Unregister("posts") })
+
+		parse := func(method string, body string) (*Object, *Error) {
+			req, reqErr := testRequest([]byte(body))
+			So(reqErr, ShouldBeNil)
+			req.Method = method
+
+			return ParseObject(req)
+		}
+
+		attribute := func(object *Object, name string) string {
+			attributes, err := object.attributeMap()
+			So(err, ShouldBeNil)
+			return string(attributes[name])
+		}
+
+		Convey("should be applied to created objects leaving them out", func() {
+			object, err := parse("POST", `{"data": {"type": "posts", "id": "1", "attributes": {"title": "Hi"}}}`)
+			So(err, ShouldBeNil)
+			So(attribute(object, "status"), ShouldEqual, `"draft"`)
+			So(attribute(object, "slug"), ShouldEqual, `"post-1"`)
+			So(attribute(object, "title"), ShouldEqual, `"Hi"`)
+		})
+
+		Convey("should not override values clients send", func() {
+			object, err := parse("POST", `{"data": {"type": "posts", "attributes": {"title": "Hi", "status": "published"}}}`)
+			So(err, ShouldBeNil)
+			So(attribute(object, "status"), ShouldEqual, `"published"`)
+		})
+
+		Convey("should not be applied to updates", func() {
+			object, err := parse("PATCH", `{"data": {"type": "posts", "id": "1", "attributes": {"title": "Hi"}}}`)
+			So(err, ShouldBeNil)
+			So(object.HasAttribute("status"), ShouldBeFalse)
+		})
+
+		Convey("should not be checked against write policies", func() {
+			Registered("posts").Policy = &FieldPolicy{
+				Writable: map[string][]string{AnyRole: {"title"}},
+			}
+
+			object, err := parse("POST", `{"data": {"type": "posts", "id": "1", "attributes": {"title": "Hi"}}}`)
+			So(err, ShouldBeNil)
+			So(attribute(object, "status"), ShouldEqual, `"draft"`)
+
+			_, err = parse("POST", `{"data": {"type": "posts", "attributes": {"title": "Hi", "status": "published"}}}`)
+			So(err, ShouldNotBeNil)
+			So(err.Status, ShouldEqual, http.StatusForbidden)
+			So(err.Source.Pointer, ShouldEqual, "/data/attributes/status")
+		})
+
+		Convey("should return the errors of DefaultFuncs", func() {
+			Registered("posts").Defaults["slug"] = DefaultFunc(func(object *Object) (interface{}, *Error) {
+				return nil, ISE("no slugs left")
+			})
+
+			_, err := parse("POST", `{"data": {"type": "posts", "attributes": {"title": "Hi"}}}`)
+			So(err, ShouldNotBeNil)
+			So(err.Status, ShouldEqual, 500)
+		})
+	})
+}
@@ -0,0 +1,138 @@
+package jsh
+
+import (
+	"net/http"
+	"time"
+)
+
+/*
+TypeDeprecation declares a whole resource type deprecated. The type is served
+as usual, but responses tell clients it is going away and usage is reported,
+so its removal can be planned with data:
+
+	jsh.Register(&jsh.Resource{
+		Type: "users-v1",
+		Deprecation: &jsh.TypeDeprecation{
+			Message:     "Use people instead",
+			Replacement: "people",
+			Sunset:      time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+	})
+
+Responses with primary data of the type are sent with the Deprecation header,
+a Sunset header if a date is set, and Link headers to the replacement and
+documentation. The "deprecation" member of the document's top-level meta
+describes each deprecated type present in primary or included data. A
+DeprecationEvent is reported to Metrics for each such response.
+*/
+type TypeDeprecation struct {
+	// Message explains the deprecation to clients, such as what to use instead
+	Message string
+	// Replacement is the resource type replacing the deprecated one, if any
+	Replacement string
+	// Sunset is when the type will be removed, if known
+	Sunset time.Time
+	// Documentation is the URL of a migration guide or other documentation
+	Documentation string
+}
+
+// DeprecationHeader marks responses of deprecated resource types.
+const DeprecationHeader = "Deprecation"
+
+// meta describes the deprecation in the document's meta
+func (d *TypeDeprecation) meta() map[string]interface{} {
+	meta := map[string]interface{}{"message": d.Message}
+	if d.Replacement != "" {
+		meta["replacement"] = d.Replacement
+	}
+	if !d.Sunset.IsZero() {
+		meta["sunset"] = d.Sunset.UTC().Format(time.RFC3339)
+	}
+	if d.Documentation != "" {
+		meta["documentation"] = d.Documentation
+	}
+
+	return meta
+}
+
+// deprecationFor returns the deprecation of a resource type, or nil
+func deprecationFor(resourceType string) *TypeDeprecation {
+	resource := Registered(resourceType)
+	if resource == nil {
+		return nil
+	}
+
+	return resource.Deprecation
+}
+
+// applyTypeDeprecation adds the headers and meta of any deprecated types
+// present in the document, and reports usage of a deprecated primary type
+func applyTypeDeprecation(w http.ResponseWriter, r *http.Request, document *Document) {
+	deprecated := map[string]interface{}{}
+	for _, objects := range [][]*Object{document.Data, document.Included} {
+		for _, object := range objects {
+			if _, seen := deprecated[object.Type]; seen {
+				continue
+			}
+
+			if deprecation := deprecationFor(object.Type); deprecation != nil {
+				deprecated[object.Type] = deprecation.meta()
+			}
+		}
+	}
+
+	if len(deprecated) == 0 {
+		return
+	}
+
+	if primary := document.First(); primary != nil {
+		if deprecation := deprecationFor(primary.Type); deprecation != nil {
+			setDeprecationHeaders(w, deprecation)
+
+			observe(&MetricEvent{
+				Kind:   DeprecationEvent,
+				Method: r.Method,
+				Path:   requestPath(r),
+				Type:   primary.Type,
+			})
+		}
+	}
+
+	meta := metaMap(document.Meta)
+	if meta == nil {
+		if document.Meta != nil {
+			// meta that isn't an object can't carry the deprecation
+			return
+		}
+		meta = map[string]interface{}{}
+	}
+
+	meta["deprecation"] = deprecated
+	document.Meta = meta
+}
+
+// setDeprecationHeaders sends the headers describing a deprecation
+func setDeprecationHeaders(w http.ResponseWriter, deprecation *TypeDeprecation) {
+	w.Header().Set(DeprecationHeader, "true")
+	if !deprecation.Sunset.IsZero() {
+		w.Header().Set("Sunset", deprecation.Sunset.UTC().Format(http.TimeFormat))
+	}
+	if deprecation.Replacement != "" {
+		w.Header().Add("Link", "<"+urlBuilder().Collection(deprecation.Replacement)+`>; rel="successor-version"`)
+	}
+	if deprecation.Documentation != "" {
+		w.Header().Add("Link", "<"+deprecation.Documentation+`>; rel="deprecation"`)
+	}
+}
+
+// DeprecatedTypes lists the registered resource types that are deprecated.
+func DeprecatedTypes() []string {
+	types := []string{}
+	for _, resource := range registeredResources() {
+		if resource.Deprecation != nil {
+			types = append(types, resource.Type)
+		}
+	}
+
+	return types
+}
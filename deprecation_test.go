@@ -0,0 +1,97 @@
+package jsh
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTypeDeprecation(t *testing.T) {
+
+	Convey("Type Deprecation Tests", t, func() {
+
+		Register(&Resource{
+			Type: "users-v1",
+			Deprecation: &TypeDeprecation{
+				Message:       "Use people instead",
+				Replacement:   "people",
+				Sunset:        time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+				Documentation: "https://example.com/migrating",
+			},
+		})
+		Register(&Resource{Type: "people"})
+		Reset(func() {
+			Unregister("users-v1")
+			Unregister("people")
+		})
+
+		events := &recordingHook{}
+		Metrics = events
+		Reset(func() { Metrics = nil })
+
+		request := &http.Request{Method: "GET", Header: http.Header{}}
+		deprecationMeta := func(writer *httptest.ResponseRecorder) map[string]map[string]string {
+			doc := struct {
+				Meta map[string]map[string]map[string]string `json:"meta"`
+			}{}
+			So(json.Unmarshal(writer.Body.Bytes(), &doc), ShouldBeNil)
+			return doc.Meta["deprecation"]
+		}
+
+		Convey("should serve deprecated types with headers and meta", func() {
+			user, _ := NewObject("1", "users-v1", map[string]string{"name": "bob"})
+
+			writer := httptest.NewRecorder()
+			err := Send(writer, request, user)
+			So(err, ShouldBeNil)
+			So(writer.Code, ShouldEqual, http.StatusOK)
+			So(writer.Header().Get(DeprecationHeader), ShouldEqual, "true")
+			So(writer.Header().Get("Sunset"), ShouldEqual, "Fri, 01 Jan 2027 00:00:00 GMT")
+			So(writer.Header()["Link"], ShouldResemble, []string{
+				`</people>; rel="successor-version"`,
+				`<https://example.com/migrating>; rel="deprecation"`,
+			})
+			So(deprecationMeta(writer)["users-v1"], ShouldResemble, map[string]string{
+				"message":       "Use people instead",
+				"replacement":   "people",
+				"sunset":        "2027-01-01T00:00:00Z",
+				"documentation": "https://example.com/migrating",
+			})
+
+			So(len(events.events), ShouldEqual, 2)
+			So(events.events[0].Kind, ShouldEqual, DeprecationEvent)
+			So(events.events[0].Type, ShouldEqual, "users-v1")
+		})
+
+		Convey("should describe deprecated included types in meta only", func() {
+			person, _ := NewObject("1", "people", map[string]string{"name": "bob"})
+			user, _ := NewObject("1", "users-v1", map[string]string{"name": "bob"})
+			doc := Build(person)
+			doc.Included = append(doc.Included, user)
+			doc.Status = http.StatusOK
+
+			writer := httptest.NewRecorder()
+			SendDocument(writer, request, doc)
+			So(writer.Header().Get(DeprecationHeader), ShouldBeEmpty)
+			So(deprecationMeta(writer)["users-v1"], ShouldNotBeNil)
+			So(len(events.events), ShouldEqual, 1)
+		})
+
+		Convey("should leave other types alone", func() {
+			person, _ := NewObject("1", "people", map[string]string{"name": "bob"})
+
+			writer := httptest.NewRecorder()
+			Send(writer, request, person)
+			So(writer.Header().Get(DeprecationHeader), ShouldBeEmpty)
+			So(writer.Body.String(), ShouldNotContainSubstring, "deprecation")
+		})
+
+		Convey("->DeprecatedTypes()", func() {
+			So(DeprecatedTypes(), ShouldResemble, []string{"users-v1"})
+		})
+	})
+}
@@ -0,0 +1,74 @@
+package jsh
+
+import "encoding/json"
+
+/*
+Diff returns the minimal object updating a resource from its before state to
+its after state, as the body of a PATCH request, holding only the attributes and
+relationships that changed. It returns nil if nothing did:
+
+	before := user.Copy()
+	user.Merge(edits)
+
+	patch := jsh.Diff(before, user)
+	if patch != nil {
+		_, _, err := jsc.Patch(baseURL, patch)
+		...
+	}
+
+Attributes after lacks are sent as null. Relationships after lacks, or whose
+linkage is absent, are left unchanged, since objects often carry only the
+relationships that were loaded, so clear one with empty linkage. Changed relationships are sent with their
+linkage alone, without links or meta.
+
+If either object's attributes can't be decoded, all of after's are sent.
+*/
+func Diff(before *Object, after *Object) *Object {
+	patch := &Object{Type: after.Type, ID: after.ID}
+
+	changed, diffErr := before.DiffAttributes(after)
+	afterAttributes, err := after.attributeMap()
+	switch {
+	case diffErr != nil || err != nil:
+		if after.HasAttributes() {
+			patch.Attributes = append(json.RawMessage{}, after.Attributes...)
+		}
+	case len(changed) > 0:
+		attributes := map[string]json.RawMessage{}
+		for _, name := range changed {
+			value, exists := afterAttributes[name]
+			if !exists {
+				value = json.RawMessage("null")
+			}
+			attributes[name] = value
+		}
+
+		// raw messages decoded from valid JSON always marshal
+		patch.Attributes, _ = json.Marshal(attributes)
+	}
+
+	for name, relationship := range after.Relationships {
+		if relationship.State() == LinkageAbsent {
+			continue
+		}
+
+		previous, existed := before.Relationships[name]
+		if existed && previous != nil && previous.Cardinality == relationship.Cardinality && jsonEqual(previous.Data, relationship.Data) {
+			continue
+		}
+
+		if patch.Relationships == nil {
+			patch.Relationships = map[string]*Relationship{}
+		}
+		linkage := relationship.copy()
+		linkage.Links = nil
+		linkage.Meta = nil
+		patch.Relationships[name] = linkage
+	}
+
+	if patch.Attributes == nil && patch.Relationships == nil {
+		return nil
+	}
+
+	return patch
+}
@@ -0,0 +1,60 @@
+package jsh
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDiff(t *testing.T) {
+
+	Convey("Diff Tests", t, func() {
+
+		before, err := NewObject("1", "users", map[string]interface{}{"name": "Bob", "age": 30, "nickname": "B"})
+		So(err, ShouldBeNil)
+		before.Relationships["team"] = &Relationship{Data: ResourceLinkage{{Type: "teams", ID: "1"}}, Cardinality: ToOne}
+		before.Relationships["tags"] = &Relationship{Data: ResourceLinkage{{Type: "tags", ID: "1"}}}
+
+		after := before.Copy()
+
+		Convey("should be nil without changes", func() {
+			So(Diff(before, after), ShouldBeNil)
+		})
+
+		Convey("should hold only changed attributes", func() {
+			after.Attributes = []byte(`{"name": "Robert", "age": 30}`)
+
+			patch := Diff(before, after)
+			So(patch, ShouldNotBeNil)
+			So(patch.Type, ShouldEqual, "users")
+			So(patch.ID, ShouldEqual, "1")
+			So(string(patch.Attributes), ShouldEqual, `{"name":"Robert","nickname":null}`)
+			So(patch.Relationships, ShouldBeNil)
+		})
+
+		Convey("should hold only changed relationships", func() {
+			after.Relationships["team"] = &Relationship{Cardinality: ToOne, Data: ResourceLinkage{}, Links: &Links{Self: &Link{HREF: "/self"}}}
+			after.Relationships["tags"] = &Relationship{Links: &Links{Related: &Link{HREF: "/tags"}}}
+
+			patch := Diff(before, after)
+			So(patch, ShouldNotBeNil)
+			So(patch.Attributes, ShouldBeNil)
+			So(len(patch.Relationships), ShouldEqual, 1)
+			So(patch.Relationships["team"].Data, ShouldBeEmpty)
+			So(patch.Relationships["team"].Links, ShouldBeNil)
+
+			Convey("including new ones", func() {
+				after.Relationships["manager"] = &Relationship{Data: ResourceLinkage{{Type: "users", ID: "2"}}}
+				So(len(Diff(before, after).Relationships), ShouldEqual, 2)
+			})
+		})
+
+		Convey("should send all attributes if they can't be compared", func() {
+			before.Attributes = []byte(`[]`)
+
+			patch := Diff(before, after)
+			So(patch, ShouldNotBeNil)
+			So(string(patch.Attributes), ShouldEqual, string(after.Attributes))
+		})
+	})
+}
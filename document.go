@@ -28,17 +28,24 @@ of each attribute: http://jsonapi.org/format/#document-structure
 type Document struct {
 	Data List `json:"data"`
 	// Object   *Object     `json:"-"`
-	Errors   ErrorList   `json:"errors,omitempty"`
-	Links    *Link       `json:"links,omitempty"`
+	Errors ErrorList `json:"errors,omitempty"`
+	// Links holds the document's self, related, and pagination links. It was
+	// a *Link before pagination was added, set Links.Self instead.
+	Links    *Links      `json:"links,omitempty"`
 	Included []*Object   `json:"included,omitempty"`
 	Meta     interface{} `json:"meta,omitempty"`
 	JSONAPI  struct {
 		Version string `json:"version"`
+		// Profile lists the profiles applied to the document, see Profiles
+		Profile []string `json:"profile,omitempty"`
 	} `json:"jsonapi"`
 	// Status is an HTTP Status Code
 	Status int `json:"-"`
 	// DataMode to enforce for the document
 	Mode DocumentMode `json:"-"`
+	// Extra holds unrecognized top-level members, kept when parsing with
+	// ParseOptions.PreserveUnknownMembers and sent along with the document
+	Extra map[string]json.RawMessage `json:"-"`
 	// empty is used to signify that the response shouldn't contain a json payload
 	// in the case that we only want to return an HTTP Status Code in order to bypass
 	// validation steps.
@@ -245,9 +252,19 @@ func (d *Document) Error() string {
 /*
 MarshalJSON handles the custom serialization case caused by case where the "data"
 element of a document might be either a single resource object, or a collection of
-them.
+them. Extra members are added to the result.
 */
 func (d *Document) MarshalJSON() ([]byte, error) {
+	raw, err := d.marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	return addMembers(raw, d.Extra, topLevelMembers)
+}
+
+// marshal marshals the document's own members
+func (d *Document) marshal() ([]byte, error) {
 	// we use the MarshalDoc type to avoid recursively calling this function below
 	// when we marshal
 	type MarshalDoc Document
@@ -267,7 +284,7 @@ func (d *Document) MarshalJSON() ([]byte, error) {
 			Data *Object `json:"data"`
 		}
 
-		return json.Marshal(MarshalObject{
+		return JSON.Marshal(MarshalObject{
 			MarshalDoc: doc,
 			Data:       data,
 		})
@@ -281,12 +298,12 @@ func (d *Document) MarshalJSON() ([]byte, error) {
 			Data *Object `json:"data,omitempty"`
 		}
 
-		return json.Marshal(MarshalError{
+		return JSON.Marshal(MarshalError{
 			MarshalDoc: doc,
 		})
 
 	case ListMode:
-		return json.Marshal(doc)
+		return JSON.Marshal(doc)
 	default:
 		return nil, ISE(fmt.Sprintf("Unexpected DocumentMode value when marshaling: %d", d.Mode))
 	}
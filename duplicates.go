@@ -0,0 +1,98 @@
+package jsh
+
+import "fmt"
+
+/*
+Deduplicate removes the resources of the document's primary data and included
+resources that are already present, by type and ID, keeping the first of each,
+and returns how many were removed. Included resources that are also primary data
+are removed from included, as the specification forbids compound documents from
+repeating a resource:
+
+	document.Included = append(document.Included, authors...)
+	document.Deduplicate()
+
+Resources without an ID, such as those being created, are never duplicates.
+*/
+func (d *Document) Deduplicate() int {
+	seen := map[string]bool{}
+
+	data, removed := deduplicate(d.Data, seen)
+	included, removedIncluded := deduplicate(d.Included, seen)
+
+	d.Data = data
+	if d.Included != nil {
+		d.Included = included
+	}
+
+	return removed + removedIncluded
+}
+
+// deduplicate filters out objects already seen, preserving the list if none
+// are
+func deduplicate(objects List, seen map[string]bool) (List, int) {
+	unique := objects[:0:0]
+	for _, object := range objects {
+		key := resourceKey(object.Type, object.ID)
+		if object.ID != "" && seen[key] {
+			continue
+		}
+
+		seen[key] = true
+		unique = append(unique, object)
+	}
+
+	if len(unique) == len(objects) {
+		return objects, 0
+	}
+
+	return unique, len(objects) - len(unique)
+}
+
+// duplicate returns the pointer to the first resource of the document's
+// primary data or included resources already present, and describes it
+func (d *Document) duplicate() (string, string) {
+	seen := map[string]bool{}
+
+	check := func(objects List, member string) (string, string) {
+		for i, object := range objects {
+			key := resourceKey(object.Type, object.ID)
+			if object.ID == "" {
+				continue
+			}
+			if seen[key] {
+				return fmt.Sprintf("/%s/%d", member, i),
+					fmt.Sprintf("Resource '%s' of type '%s' is present more than once", object.ID, object.Type)
+			}
+			seen[key] = true
+		}
+
+		return "", ""
+	}
+
+	if pointer, detail := check(d.Data, "data"); pointer != "" {
+		return pointer, detail
+	}
+
+	return check(d.Included, "included")
+}
+
+// checkDuplicates removes or rejects resources repeated within the document's
+// primary data and included resources, as configured
+func (o *ParseOptions) checkDuplicates(document *Document) *Error {
+	if o.Deduplicate {
+		document.Deduplicate()
+		return nil
+	}
+
+	if !o.ForbidDuplicates {
+		return nil
+	}
+
+	pointer, detail := document.duplicate()
+	if pointer != "" {
+		return documentError(detail, pointer)
+	}
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package jsh
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDuplicates(t *testing.T) {
+
+	Convey("Duplicate Tests", t, func() {
+
+		user := func(id string) *Object {
+			return &Object{Type: "users", ID: id, Attributes: []byte(`{}`)}
+		}
+
+		document := Build(List{user("1"), user("2"), user("1"), user("")})
+		document.Included = List{user("2"), user("3"), user("3"), {Type: "teams", ID: "1", Attributes: []byte(`{}`)}}
+
+		Convey("->Deduplicate()", func() {
+			So(document.Deduplicate(), ShouldEqual, 3)
+
+			ids := []string{}
+			for _, object := range document.Data {
+				ids = append(ids, object.ID)
+			}
+			So(ids, ShouldResemble, []string{"1", "2", ""})
+			So(len(document.Included), ShouldEqual, 2)
+			So(document.Included[0].ID, ShouldEqual, "3")
+			So(document.Included[1].Type, ShouldEqual, "teams")
+
+			So(document.Deduplicate(), ShouldEqual, 0)
+		})
+
+	})
+}
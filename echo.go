@@ -0,0 +1,77 @@
+package jsh
+
+import "net/http"
+
+/*
+ErrorRequestEcho, when set, adds sanitized information about the request to the
+meta of every error sent, so that error documents remain meaningful once they
+end up in logs or support tickets far from the request that caused them:
+
+	jsh.ErrorRequestEcho = &jsh.RequestEcho{Params: []string{"include", "page[size]"}}
+
+Each error then carries:
+
+	"meta": {
+		"request": {
+			"method": "GET",
+			"path": "/articles/1",
+			"params": {"include": "author"}
+		}
+	}
+
+Leave nil to not echo requests.
+*/
+var ErrorRequestEcho *RequestEcho
+
+// RequestEcho configures which request information is echoed in error meta.
+type RequestEcho struct {
+	// Params lists the query parameters to echo. Other parameters are never
+	// included, as they may carry credentials or personal information.
+	Params []string
+}
+
+// echoRequest adds the request echo to each of the document's errors
+func echoRequest(r *http.Request, document *Document) {
+	if ErrorRequestEcho == nil || r == nil || len(document.Errors) == 0 {
+		return
+	}
+
+	request := ErrorRequestEcho.describe(r)
+	for i, err := range document.Errors {
+		// copy the error so that errors shared between responses aren't altered
+		echoed := *err
+		echoed.Meta = map[string]interface{}{}
+		for key, value := range err.Meta {
+			echoed.Meta[key] = value
+		}
+		echoed.Meta["request"] = request
+
+		document.Errors[i] = &echoed
+	}
+}
+
+// describe builds the sanitized request information
+func (e *RequestEcho) describe(r *http.Request) map[string]interface{} {
+	request := map[string]interface{}{
+		"method": r.Method,
+		"path":   requestPath(r),
+	}
+
+	if r.URL == nil {
+		return request
+	}
+
+	query := r.URL.Query()
+	params := map[string]string{}
+	for _, param := range e.Params {
+		if value := query.Get(param); value != "" {
+			params[param] = value
+		}
+	}
+
+	if len(params) > 0 {
+		request["params"] = params
+	}
+
+	return request
+}
@@ -0,0 +1,69 @@
+package jsh
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRequestEcho(t *testing.T) {
+
+	Convey("Request Echo Tests", t, func() {
+
+		ErrorRequestEcho = &RequestEcho{Params: []string{"include"}}
+		Reset(func() {
+			ErrorRequestEcho = nil
+		})
+
+		request, reqErr := http.NewRequest("GET", "http://localhost/articles/1?include=author&token=secret", nil)
+		So(reqErr, ShouldBeNil)
+
+		writer := httptest.NewRecorder()
+		sent := NotFound("articles", "1")
+
+		err := Send(writer, request, sent)
+		So(err, ShouldBeNil)
+
+		doc := struct {
+			Errors []struct {
+				Meta struct {
+					Request struct {
+						Method string            `json:"method"`
+						Path   string            `json:"path"`
+						Params map[string]string `json:"params"`
+					} `json:"request"`
+				} `json:"meta"`
+			} `json:"errors"`
+		}{}
+
+		jsonErr := json.Unmarshal(writer.Body.Bytes(), &doc)
+		So(jsonErr, ShouldBeNil)
+		So(len(doc.Errors), ShouldEqual, 1)
+
+		echoed := doc.Errors[0].Meta.Request
+
+		Convey("should echo the method and path", func() {
+			So(echoed.Method, ShouldEqual, "GET")
+			So(echoed.Path, ShouldEqual, "/articles/1")
+		})
+
+		Convey("should only echo selected params", func() {
+			So(echoed.Params, ShouldResemble, map[string]string{"include": "author"})
+		})
+
+		Convey("should not modify the sent error", func() {
+			So(sent.Meta, ShouldBeNil)
+		})
+
+		Convey("should not echo by default", func() {
+			ErrorRequestEcho = nil
+			writer := httptest.NewRecorder()
+
+			Send(writer, request, NotFound("articles", "1"))
+			So(writer.Body.String(), ShouldNotContainSubstring, "request")
+		})
+	})
+}
@@ -0,0 +1,48 @@
+package jsh
+
+import "bytes"
+
+/*
+PrettyJSON indents the JSON of documents jsh sends, and of request bodies jsc
+sends, which is easier to read while developing. It's off by default, sending
+compact JSON, which saves bandwidth and skips the indentation pass over every
+body:
+
+	jsh.SetPrettyJSON(os.Getenv("ENV") == "development")
+*/
+var PrettyJSON = false
+
+// SetPrettyJSON turns indentation of sent JSON on or off, see PrettyJSON.
+func SetPrettyJSON(pretty bool) {
+	PrettyJSON = pretty
+}
+
+// MarshalJSON marshals the value, indented if PrettyJSON is on.
+func MarshalJSON(v interface{}) ([]byte, error) {
+	if PrettyJSON {
+		return JSON.MarshalIndent(v, "", " ")
+	}
+
+	return JSON.Marshal(v)
+}
+
+// encodeDocument encodes the document into a pooled buffer, which the caller
+// returns with releaseBuffer once the body has been written
+func encodeDocument(document *Document) (*bytes.Buffer, error) {
+	buffer := getBuffer()
+
+	encoder := JSON.NewEncoder(buffer)
+	if PrettyJSON {
+		encoder.SetIndent("", " ")
+	}
+
+	err := encoder.Encode(document)
+	if err != nil {
+		releaseBuffer(buffer)
+		return nil, err
+	}
+
+	// drop the newline the encoder terminates values with
+	buffer.Truncate(buffer.Len() - 1)
+	return buffer, nil
+}
@@ -0,0 +1,139 @@
+package jsh
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestEncode(t *testing.T) {
+
+	Convey("Encode Tests", t, func() {
+
+		object, err := NewObject("1", "widgets", map[string]string{"name": "gear"})
+		So(err, ShouldBeNil)
+		object.Status = http.StatusOK
+		document := Build(object)
+
+		Reset(func() {
+			PrettyJSON = false
+		})
+
+		Convey("->encodeDocument()", func() {
+
+			Convey("should indent documents like MarshalIndent if PrettyJSON is on", func() {
+				SetPrettyJSON(true)
+
+				buffer, err := encodeDocument(document)
+				So(err, ShouldBeNil)
+				defer releaseBuffer(buffer)
+
+				expected, err := json.MarshalIndent(document, "", " ")
+				So(err, ShouldBeNil)
+				So(buffer.String(), ShouldEqual, string(expected))
+			})
+
+			Convey("should send compact documents by default", func() {
+				buffer, err := encodeDocument(document)
+				So(err, ShouldBeNil)
+				defer releaseBuffer(buffer)
+
+				expected, err := json.Marshal(document)
+				So(err, ShouldBeNil)
+				So(buffer.String(), ShouldEqual, string(expected))
+			})
+		})
+
+		Convey("->MarshalJSON()", func() {
+
+			Convey("should only indent if PrettyJSON is on", func() {
+				raw, err := MarshalJSON(map[string]int{"teeth": 12})
+				So(err, ShouldBeNil)
+				So(string(raw), ShouldEqual, `{"teeth":12}`)
+
+				SetPrettyJSON(true)
+				raw, err = MarshalJSON(map[string]int{"teeth": 12})
+				So(err, ShouldBeNil)
+				So(string(raw), ShouldEqual, "{\n \"teeth\": 12\n}")
+			})
+		})
+
+		Convey("->SendDocument()", func() {
+
+			Convey("should send the encoded body", func() {
+				request, _ := http.NewRequest("GET", "/widgets/1", nil)
+				writer := httptest.NewRecorder()
+				So(SendDocument(writer, request, document), ShouldBeNil)
+				So(writer.Body.String(), ShouldContainSubstring, `"data":{"type":"widgets","id":"1"`)
+				So(writer.Body.String(), ShouldNotContainSubstring, "\n")
+				So(writer.Header().Get("Content-Length"), ShouldEqual, fmt.Sprint(writer.Body.Len()))
+			})
+		})
+	})
+}
+
+// benchmarkList builds a list document of count widgets
+func benchmarkList(b *testing.B, count int) *Document {
+	list := List{}
+	for i := 0; i < count; i++ {
+		object, err := NewObject(fmt.Sprint(i), "widgets", map[string]interface{}{
+			"name":  "gear",
+			"teeth": i,
+			"tags":  []string{"metal", "round"},
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+		list = append(list, object)
+	}
+
+	document := Build(list)
+	document.Status = http.StatusOK
+	return document
+}
+
+// benchmarkSend measures sending the document with and without indentation
+func benchmarkSend(b *testing.B, document *Document) {
+	request, _ := http.NewRequest("GET", "/widgets", nil)
+
+	for _, indent := range []bool{true, false} {
+		name := "compact"
+		if indent {
+			name = "indented"
+		}
+
+		b.Run(name, func(b *testing.B) {
+			PrettyJSON = indent
+			defer func() { PrettyJSON = false }()
+
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				SendDocument(httptest.NewRecorder(), request, document)
+			}
+		})
+	}
+}
+
+func BenchmarkSendObject(b *testing.B) {
+	benchmarkSend(b, benchmarkList(b, 1))
+}
+
+func BenchmarkSendList(b *testing.B) {
+	benchmarkSend(b, benchmarkList(b, 100))
+}
+
+func BenchmarkSendListWithExtra(b *testing.B) {
+	document := benchmarkList(b, 100)
+	for _, object := range document.Data {
+		object.Extra = map[string]json.RawMessage{"vendor:version": json.RawMessage(`3`)}
+		object.AddToOneRelationship("maker", "makers", "1")
+		object.Relationships["maker"].Extra = map[string]json.RawMessage{"vendor:hint": json.RawMessage(`"eager"`)}
+	}
+	document.Extra = map[string]json.RawMessage{"vendor:trace": json.RawMessage(`{"id":"abc"}`)}
+
+	benchmarkSend(b, document)
+}
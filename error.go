@@ -1,8 +1,10 @@
 package jsh
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 )
 
@@ -83,13 +85,18 @@ http://jsonapi.org/format/#error-objects
 	jsh.Send(w, r, error)
 */
 type Error struct {
+	// Code is an optional application specific error code
+	Code   string `json:"code,omitempty"`
 	Title  string `json:"title"`
 	Detail string `json:"detail"`
 	Status int    `json:"status,string"`
 	Source struct {
-		Pointer string `json:"pointer"`
+		Pointer   string `json:"pointer"`
+		Parameter string `json:"parameter,omitempty"`
 	} `json:"source"`
-	ISE string `json:"-"`
+	// Meta holds non-standard information about the error
+	Meta map[string]interface{} `json:"meta,omitempty"`
+	ISE  string                 `json:"-"`
 }
 
 /*
@@ -103,6 +110,10 @@ func (e *Error) Error() string {
 		msg += fmt.Sprintf("(Source.Pointer: %s)", e.Source.Pointer)
 	}
 
+	if e.Source.Parameter != "" {
+		msg += fmt.Sprintf("(Source.Parameter: %s)", e.Source.Parameter)
+	}
+
 	if e.ISE != "" {
 		msg += fmt.Sprintf("\nInternal Error: %s", e.ISE)
 	}
@@ -110,6 +121,36 @@ func (e *Error) Error() string {
 	return msg
 }
 
+/*
+UnmarshalJSON accepts the error "status" as either a string, as the JSON API
+Specification requires, or a number, as some servers send it.
+*/
+func (e *Error) UnmarshalJSON(data []byte) error {
+	type UnmarshalError Error
+
+	raw := struct {
+		*UnmarshalError
+		Status json.RawMessage `json:"status"`
+	}{UnmarshalError: (*UnmarshalError)(e)}
+
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return err
+	}
+
+	status := strings.Trim(string(raw.Status), `"`)
+	if status == "" || status == "null" {
+		return nil
+	}
+
+	e.Status, err = strconv.Atoi(status)
+	if err != nil {
+		return fmt.Errorf("Invalid error status: %s", raw.Status)
+	}
+
+	return nil
+}
+
 /*
 Validate ensures that the an error meets all JSON API criteria.
 */
@@ -166,6 +207,40 @@ func InputError(msg string, attribute string) *Error {
 	return err
 }
 
+/*
+ParameterError creates a properly formatted HTTP Status 400 error for a query
+parameter that could not be understood. The parameter name is set to
+err.Source.Parameter.
+*/
+func ParameterError(msg string, parameter string) *Error {
+	err := &Error{
+		Title:  "Invalid Query Parameter",
+		Detail: msg,
+		Status: http.StatusBadRequest,
+	}
+
+	err.Source.Parameter = parameter
+
+	return err
+}
+
+/*
+RelationshipError creates a properly formatted HTTP Status 422 error for an
+invalid relationship. err.Source.Pointer is set to
+"/data/relationships/<relationship>".
+*/
+func RelationshipError(msg string, relationship string) *Error {
+	err := &Error{
+		Title:  "Invalid Relationship",
+		Detail: msg,
+		Status: 422,
+	}
+
+	err.Source.Pointer = fmt.Sprintf("/data/relationships/%s", relationship)
+
+	return err
+}
+
 // SpecificationError is used whenever the Client violates the JSON API Spec
 func SpecificationError(detail string) *Error {
 	return &Error{
@@ -183,3 +258,15 @@ func NotFound(resourceType string, id string) *Error {
 		Status: http.StatusNotFound,
 	}
 }
+
+/*
+Conflict returns a 409 formatted error, used when a request conflicts with the
+current state of a resource, such as a type or ID mismatch.
+*/
+func Conflict(detail string) *Error {
+	return &Error{
+		Title:  "Conflict",
+		Detail: detail,
+		Status: http.StatusConflict,
+	}
+}
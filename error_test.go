@@ -1,6 +1,7 @@
 package jsh
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
@@ -52,6 +53,31 @@ func TestError(t *testing.T) {
 			})
 		})
 
+		Convey("->UnmarshalJSON()", func() {
+
+			Convey("should accept a string status", func() {
+				err := &Error{}
+				jsonErr := json.Unmarshal([]byte(`{"status": "404", "code": "missing", "title": "Not Found"}`), err)
+				So(jsonErr, ShouldBeNil)
+				So(err.Status, ShouldEqual, 404)
+				So(err.Code, ShouldEqual, "missing")
+			})
+
+			Convey("should accept a numeric status", func() {
+				err := &Error{}
+				jsonErr := json.Unmarshal([]byte(`{"status": 409, "source": {"pointer": "/data/id"}}`), err)
+				So(jsonErr, ShouldBeNil)
+				So(err.Status, ShouldEqual, 409)
+				So(err.Source.Pointer, ShouldEqual, "/data/id")
+			})
+
+			Convey("should reject an invalid status", func() {
+				err := &Error{}
+				jsonErr := json.Unmarshal([]byte(`{"status": "bad"}`), err)
+				So(jsonErr, ShouldNotBeNil)
+			})
+		})
+
 		Convey("->Send()", func() {
 
 			testError := &Error{
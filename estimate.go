@@ -0,0 +1,208 @@
+package jsh
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+/*
+ResourceStats describes the typical objects of a resource type, allowing the
+size of responses to be estimated before they are resolved. Declare them with
+Resource.Stats, from sampled responses or storage statistics.
+*/
+type ResourceStats struct {
+	// ObjectSize is the typical size in bytes of a serialized object
+	ObjectSize int `json:"object_size"`
+	// AttributeSizes are the typical sizes in bytes of individual attributes,
+	// subtracted from ObjectSize when a sparse fieldset leaves them out
+	AttributeSizes map[string]int `json:"attribute_sizes,omitempty"`
+	// Fanout is the average number of related resources per object for each
+	// relationship, 1 if not set
+	Fanout map[string]float64 `json:"fanout,omitempty"`
+}
+
+// DefaultResourceStats are used to estimate the objects of resource types
+// without Stats.
+var DefaultResourceStats = &ResourceStats{ObjectSize: 512}
+
+/*
+PayloadEstimate is the expected size of the response to a query, calculated
+from ResourceStats without resolving it. Included resources are counted per
+type, and are an upper bound since duplicates are only included once.
+*/
+type PayloadEstimate struct {
+	Resources int            `json:"resources"`
+	Included  map[string]int `json:"included,omitempty"`
+	Bytes     int            `json:"bytes"`
+}
+
+/*
+EstimatePayload estimates the response to a list request for resourceType, from
+its page[limit], include, and fields[TYPE] query parameters. Total is the number
+of resources in the collection, or -1 if unknown, in which case a full page is
+assumed. Serve estimates from a pre-flight endpoint so interactive clients can
+warn users before requesting huge pages:
+
+	total, _ := storage.CountArticles()
+
+	estimate, err := jsh.EstimatePayload(r, "articles", total)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	jsh.SendDocument(w, r, estimate.Document())
+
+Included relationships must be declared in the resource's Relationships, a 400
+is returned otherwise.
+*/
+func EstimatePayload(r *http.Request, resourceType string, total int) (*PayloadEstimate, *Error) {
+	page, err := ParseCursorPage(r, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	include, err := ParseInclude(r)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := parseFieldsets(r)
+
+	count := page.Limit
+	if total >= 0 && total < count {
+		count = total
+	}
+
+	estimate := &PayloadEstimate{
+		Resources: count,
+		Included:  map[string]int{},
+		Bytes:     int(float64(count) * estimatedSize(resourceType, fields)),
+	}
+
+	err = estimate.include(resourceType, float64(count), include, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return estimate, nil
+}
+
+// include adds the estimated resources of each relationship in tree, for count
+// objects of resourceType
+func (e *PayloadEstimate) include(resourceType string, count float64, tree IncludeTree, fields map[string][]string) *Error {
+	names := make([]string, 0, len(tree))
+	for name := range tree {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		related, declared := relatedType(resourceType, name)
+		if !declared {
+			return ParameterError(
+				fmt.Sprintf("Relationship '%s' of '%s' cannot be included", name, resourceType),
+				IncludeParam,
+			)
+		}
+
+		relatedCount := count * statsFor(resourceType).fanout(name)
+		e.Included[related] += int(relatedCount)
+		e.Bytes += int(relatedCount * estimatedSize(related, fields))
+
+		err := e.include(related, relatedCount, tree[name], fields)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Document builds a meta-only document carrying the estimate as meta.estimate.
+func (e *PayloadEstimate) Document() *Document {
+	document := New()
+	document.Status = http.StatusOK
+	document.Meta = map[string]interface{}{"estimate": e}
+
+	return document
+}
+
+// relatedType returns the resource type a declared relationship links to
+func relatedType(resourceType string, relationship string) (string, bool) {
+	resource := Registered(resourceType)
+	if resource == nil {
+		return "", false
+	}
+
+	declaration, declared := resource.Relationships[relationship]
+	return declaration.Type, declared
+}
+
+// statsFor returns the stats of a resource type, falling back to
+// DefaultResourceStats
+func statsFor(resourceType string) *ResourceStats {
+	resource := Registered(resourceType)
+	if resource == nil || resource.Stats == nil {
+		return DefaultResourceStats
+	}
+
+	return resource.Stats
+}
+
+// fanout returns the related resources per object of a relationship
+func (s *ResourceStats) fanout(relationship string) float64 {
+	fanout, exists := s.Fanout[relationship]
+	if !exists {
+		return 1
+	}
+
+	return fanout
+}
+
+// estimatedSize returns the size of an object of resourceType, less the
+// attributes left out by its sparse fieldset
+func estimatedSize(resourceType string, fields map[string][]string) float64 {
+	stats := statsFor(resourceType)
+	size := stats.ObjectSize
+
+	fieldset, sparse := fields[resourceType]
+	if !sparse {
+		return float64(size)
+	}
+
+	selected := map[string]bool{}
+	for _, field := range fieldset {
+		selected[field] = true
+	}
+
+	for attribute, attributeSize := range stats.AttributeSizes {
+		if !selected[attribute] {
+			size -= attributeSize
+		}
+	}
+
+	if size < 0 {
+		size = 0
+	}
+
+	return float64(size)
+}
+
+// parseFieldsets reads the fields[TYPE] query parameters of a request
+func parseFieldsets(r *http.Request) map[string][]string {
+	fields := map[string][]string{}
+
+	for key, values := range r.URL.Query() {
+		if !strings.HasPrefix(key, "fields[") || !strings.HasSuffix(key, "]") || len(values) == 0 {
+			continue
+		}
+
+		resourceType := key[len("fields[") : len(key)-1]
+		fields[resourceType] = strings.Split(values[0], ",")
+	}
+
+	return fields
+}
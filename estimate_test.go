@@ -0,0 +1,106 @@
+package jsh
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestEstimate(t *testing.T) {
+
+	Convey("Payload Estimate Tests", t, func() {
+
+		Register(&Resource{
+			Type: "articles",
+			Stats: &ResourceStats{
+				ObjectSize:     1000,
+				AttributeSizes: map[string]int{"body": 800},
+				Fanout:         map[string]float64{"comments": 3},
+			},
+			Relationships: map[string]RelationshipDeclaration{
+				"author":   {Type: "users", Cardinality: ToOne},
+				"comments": {Type: "comments", Cardinality: ToMany},
+			},
+		})
+		Register(&Resource{
+			Type:  "comments",
+			Stats: &ResourceStats{ObjectSize: 200},
+			Relationships: map[string]RelationshipDeclaration{
+				"author": {Type: "users", Cardinality: ToOne},
+			},
+		})
+		Reset(func() {
+			Unregister("articles")
+			Unregister("comments")
+		})
+
+		Convey("->EstimatePayload()", func() {
+
+			Convey("should estimate a page of resources", func() {
+				r := httptest.NewRequest("GET", "/articles/estimate?page[limit]=10", nil)
+
+				estimate, err := EstimatePayload(r, "articles", -1)
+				So(err, ShouldBeNil)
+				So(estimate.Resources, ShouldEqual, 10)
+				So(estimate.Bytes, ShouldEqual, 10000)
+			})
+
+			Convey("should not exceed the collection size", func() {
+				r := httptest.NewRequest("GET", "/articles/estimate?page[limit]=10", nil)
+
+				estimate, err := EstimatePayload(r, "articles", 4)
+				So(err, ShouldBeNil)
+				So(estimate.Resources, ShouldEqual, 4)
+			})
+
+			Convey("should count included resources", func() {
+				r := httptest.NewRequest("GET", "/articles/estimate?page[limit]=10&include=author,comments.author", nil)
+
+				estimate, err := EstimatePayload(r, "articles", -1)
+				So(err, ShouldBeNil)
+				So(estimate.Included["comments"], ShouldEqual, 30)
+				So(estimate.Included["users"], ShouldEqual, 40)
+				So(estimate.Bytes, ShouldEqual, 10000+30*200+40*DefaultResourceStats.ObjectSize)
+			})
+
+			Convey("should account for sparse fieldsets", func() {
+				r := httptest.NewRequest("GET", "/articles/estimate?page[limit]=10&fields[articles]=title", nil)
+
+				estimate, err := EstimatePayload(r, "articles", -1)
+				So(err, ShouldBeNil)
+				So(estimate.Bytes, ShouldEqual, 2000)
+			})
+
+			Convey("should reject undeclared relationships", func() {
+				r := httptest.NewRequest("GET", "/articles/estimate?include=tags", nil)
+
+				_, err := EstimatePayload(r, "articles", -1)
+				So(err, ShouldNotBeNil)
+				So(err.Status, ShouldEqual, http.StatusBadRequest)
+				So(err.Source.Parameter, ShouldEqual, IncludeParam)
+			})
+		})
+
+		Convey("->Document()", func() {
+			r := httptest.NewRequest("GET", "/articles/estimate?page[limit]=2", nil)
+			estimate, err := EstimatePayload(r, "articles", -1)
+			So(err, ShouldBeNil)
+
+			writer := httptest.NewRecorder()
+			err = SendDocument(writer, r, estimate.Document())
+			So(err, ShouldBeNil)
+
+			doc := struct {
+				Meta struct {
+					Estimate PayloadEstimate `json:"estimate"`
+				} `json:"meta"`
+			}{}
+			So(json.Unmarshal(writer.Body.Bytes(), &doc), ShouldBeNil)
+			So(doc.Meta.Estimate.Resources, ShouldEqual, 2)
+			So(doc.Meta.Estimate.Bytes, ShouldEqual, 2000)
+		})
+	})
+}
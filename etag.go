@@ -0,0 +1,126 @@
+package jsh
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+/*
+EmitETags sets an ETag header, computed by ETag, on successful responses
+containing a single resource object, unless the handler has set one itself.
+Clients can send it back via If-Match when updating or deleting the resource,
+which handlers check with CheckPreconditions.
+*/
+var EmitETags = false
+
+/*
+ETag computes a strong entity tag for an object from its type, ID, attributes,
+and the "version" member of its meta, if any. Attributes are canonicalized
+first, so formatting and member order don't affect the tag.
+*/
+func ETag(object *Object) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s\x00%s\x00", object.Type, object.ID)
+
+	var attributes interface{}
+	if json.Unmarshal(object.Attributes, &attributes) == nil {
+		canonical, _ := json.Marshal(attributes)
+		hash.Write(canonical)
+	}
+
+	if version, exists := object.Meta["version"]; exists {
+		raw, _ := json.Marshal(version)
+		hash.Write([]byte{0})
+		hash.Write(raw)
+	}
+
+	return `"` + hex.EncodeToString(hash.Sum(nil))[:32] + `"`
+}
+
+/*
+CheckPreconditions validates a request's If-Match and If-None-Match headers
+against the current state of the resource it targets, pass nil if the resource
+doesn't exist:
+
+	current, err := loadArticle(id)
+	...
+	preconditionErr := jsh.CheckPreconditions(r, current)
+	if preconditionErr != nil {
+		jsh.Send(w, r, preconditionErr)
+		return
+	}
+
+A 412 Precondition Failed error is returned if the client's view of the resource
+is out of date, preventing lost updates between concurrent writers.
+*/
+func CheckPreconditions(r *http.Request, current *Object) *Error {
+	etag := ""
+	if current != nil {
+		etag = ETag(current)
+	}
+
+	if ifMatch := r.Header.Get("If-Match"); ifMatch != "" {
+		if current == nil || !matchesETag(ifMatch, etag, false) {
+			return PreconditionFailed("The resource has been modified since it was last fetched")
+		}
+	}
+
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		if current != nil && matchesETag(ifNoneMatch, etag, true) {
+			return PreconditionFailed("The resource already exists in the state given by If-None-Match")
+		}
+	}
+
+	return nil
+}
+
+// PreconditionFailed returns a 412 formatted error
+func PreconditionFailed(detail string) *Error {
+	return &Error{
+		Title:  "Precondition Failed",
+		Detail: detail,
+		Status: http.StatusPreconditionFailed,
+	}
+}
+
+// matchesETag returns true if a list of entity tags from a conditional header
+// matches etag. Weak comparison ignores the W/ prefix of weak tags.
+func matchesETag(header string, etag string, weak bool) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+
+		if weak {
+			candidate = strings.TrimPrefix(candidate, "W/")
+		}
+
+		if candidate == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+/*
+documentETag returns the ETag of a successful single object response, or an
+empty string. It is computed before the document is prepared, so that it
+matches the ETag of the object as handlers load it for CheckPreconditions.
+*/
+func documentETag(r *http.Request, document *Document) string {
+	if !EmitETags || r.Method == "DELETE" || document.Mode != ObjectMode {
+		return ""
+	}
+
+	if document.HasErrors() || document.Status >= http.StatusBadRequest || len(document.Data) != 1 {
+		return ""
+	}
+
+	return ETag(document.First())
+}
@@ -0,0 +1,117 @@
+package jsh
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestETag(t *testing.T) {
+
+	Convey("ETag Tests", t, func() {
+
+		object, objErr := NewObject("1", "articles", map[string]string{"title": "a", "body": "b"})
+		So(objErr, ShouldBeNil)
+
+		Convey("->ETag()", func() {
+
+			Convey("should ignore attribute formatting and order", func() {
+				reordered := &Object{
+					Type:       "articles",
+					ID:         "1",
+					Attributes: json.RawMessage(`{"title":"a","body":"b"}`),
+				}
+				So(ETag(reordered), ShouldEqual, ETag(object))
+			})
+
+			Convey("should change with attributes and meta version", func() {
+				etag := ETag(object)
+
+				object.Meta = map[string]interface{}{"version": 2}
+				So(ETag(object), ShouldNotEqual, etag)
+
+				versioned := ETag(object)
+				object.Marshal(map[string]string{"title": "c"})
+				So(ETag(object), ShouldNotEqual, versioned)
+			})
+		})
+
+		Convey("->CheckPreconditions()", func() {
+			request := &http.Request{Method: "PATCH", Header: http.Header{}}
+
+			Convey("should pass without conditional headers", func() {
+				So(CheckPreconditions(request, object), ShouldBeNil)
+			})
+
+			Convey("should pass a matching If-Match", func() {
+				request.Header.Set("If-Match", `"other", `+ETag(object))
+				So(CheckPreconditions(request, object), ShouldBeNil)
+			})
+
+			Convey("should fail a stale If-Match", func() {
+				request.Header.Set("If-Match", `"stale"`)
+
+				err := CheckPreconditions(request, object)
+				So(err, ShouldNotBeNil)
+				So(err.Status, ShouldEqual, http.StatusPreconditionFailed)
+			})
+
+			Convey("should fail If-Match for missing resources", func() {
+				request.Header.Set("If-Match", "*")
+				So(CheckPreconditions(request, nil), ShouldNotBeNil)
+			})
+
+			Convey("should fail a matching If-None-Match", func() {
+				request.Header.Set("If-None-Match", "W/"+ETag(object))
+				So(CheckPreconditions(request, object), ShouldNotBeNil)
+
+				request.Header.Set("If-None-Match", "*")
+				So(CheckPreconditions(request, object), ShouldNotBeNil)
+				So(CheckPreconditions(request, nil), ShouldBeNil)
+			})
+		})
+
+		Convey("ETag emission", func() {
+			EmitETags = true
+			Reset(func() {
+				EmitETags = false
+			})
+
+			request := &http.Request{Method: "GET"}
+
+			Convey("should set the ETag of single objects", func() {
+				writer := httptest.NewRecorder()
+				Send(writer, request, object)
+				So(writer.Header().Get("ETag"), ShouldEqual, ETag(object))
+			})
+
+			Convey("should match the object before computed attributes are added", func() {
+				Register(&Resource{
+					Type: "articles",
+					Computed: map[string]ComputedAttribute{
+						"length": func(r *http.Request, object *Object) (interface{}, *Error) { return 2, nil },
+					},
+				})
+				Reset(func() { Unregister("articles") })
+
+				etag := ETag(object)
+				writer := httptest.NewRecorder()
+				Send(writer, request, object)
+				So(writer.Header().Get("ETag"), ShouldEqual, etag)
+			})
+
+			Convey("should not set an ETag for lists or errors", func() {
+				writer := httptest.NewRecorder()
+				Send(writer, request, List{object})
+				So(writer.Header().Get("ETag"), ShouldEqual, "")
+
+				writer = httptest.NewRecorder()
+				Send(writer, request, NotFound("articles", "1"))
+				So(writer.Header().Get("ETag"), ShouldEqual, "")
+			})
+		})
+	})
+}
@@ -0,0 +1,156 @@
+package jsh
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// InlineParam is the query parameter listing externalized attributes a client
+// wants sent inline, such as ?inline=body,log.
+const InlineParam = "inline"
+
+// ExternalizedMeta is the member of an object's meta describing its
+// externalized attributes.
+const ExternalizedMeta = "externalized"
+
+/*
+AttributeStore stores the value of an oversized attribute outside of the
+document being sent, returning the URL the encoded JSON value can be fetched
+from, such as a blob storage URL. Stores are called every time the attribute is
+sent, so they should be idempotent, for instance by keying values by digest.
+*/
+type AttributeStore interface {
+	Store(r *http.Request, object *Object, attribute string, value json.RawMessage) (string, *Error)
+}
+
+// AttributeStoreFunc adapts a function to an AttributeStore.
+type AttributeStoreFunc func(r *http.Request, object *Object, attribute string, value json.RawMessage) (string, *Error)
+
+// Store calls f(r, object, attribute, value).
+func (f AttributeStoreFunc) Store(r *http.Request, object *Object, attribute string, value json.RawMessage) (string, *Error) {
+	return f(r, object, attribute, value)
+}
+
+/*
+Externalization keeps documents small by moving large attributes, such as
+rendered HTML or logs, out of them when they are sent:
+
+	jsh.Register(&jsh.Resource{
+		Type: "builds",
+		Externalization: &jsh.Externalization{
+			Threshold:  64 * 1024,
+			Attributes: []string{"log"},
+			Store:      blobs,
+		},
+	})
+
+An attribute whose encoded value is larger than Threshold bytes is handed to the
+Store and removed from the object, which instead gets a link of the same name to
+the stored value, and an entry in meta.externalized with its size and SHA-256
+digest:
+
+	"links": {"log": {"href": "https://blobs.example.com/8f43..."}},
+	"meta": {"externalized": {"log": {"size": 1048576, "digest": "sha256:8f43..."}}}
+
+Clients can ask for attributes to be sent inline anyway with the inline query
+parameter, or fetch them afterwards with jsc.Inline.
+*/
+type Externalization struct {
+	// Threshold is the encoded size in bytes above which attributes are
+	// externalized
+	Threshold int
+	// Attributes lists the attributes that may be externalized, any attribute
+	// may be if empty
+	Attributes []string
+	Store      AttributeStore
+}
+
+// ExternalizedAttribute describes an attribute sent outside of its document.
+type ExternalizedAttribute struct {
+	// Size is the size of the encoded value in bytes
+	Size int `json:"size"`
+	// Digest is the SHA-256 digest of the encoded value, as "sha256:<hex>"
+	Digest string `json:"digest"`
+}
+
+// AttributeDigest formats the digest of an encoded attribute value as listed in
+// meta.externalized.
+func AttributeDigest(value json.RawMessage) string {
+	sum := sha256.Sum256(value)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// apply externalizes the object's oversized attributes, other than those the
+// request asked to be inlined
+func (e *Externalization) apply(r *http.Request, object *Object) *Error {
+	attributes, err := object.attributeMap()
+	if err != nil {
+		return err
+	}
+
+	inline := inlineAttributes(r)
+	names := []string{}
+	for name, value := range attributes {
+		if len(value) <= e.Threshold || contains(inline, name) {
+			continue
+		}
+
+		if len(e.Attributes) == 0 || contains(e.Attributes, name) {
+			names = append(names, name)
+		}
+	}
+
+	if len(names) == 0 {
+		return nil
+	}
+	sort.Strings(names)
+
+	externalized := map[string]*ExternalizedAttribute{}
+	for _, name := range names {
+		value := attributes[name]
+
+		href, err := e.Store.Store(r, object, name, value)
+		if err != nil {
+			return err
+		}
+
+		if object.Links == nil {
+			object.Links = map[string]*Link{}
+		}
+		object.Links[name] = &Link{HREF: href}
+
+		externalized[name] = &ExternalizedAttribute{Size: len(value), Digest: AttributeDigest(value)}
+		delete(attributes, name)
+	}
+
+	err = object.marshal(attributes, nil)
+	if err != nil {
+		return err
+	}
+
+	if object.Meta == nil {
+		object.Meta = map[string]interface{}{}
+	}
+	object.Meta[ExternalizedMeta] = externalized
+
+	return nil
+}
+
+// inlineAttributes returns the attributes listed by the request's inline query
+// parameter
+func inlineAttributes(r *http.Request) []string {
+	if r == nil {
+		return nil
+	}
+
+	inline := []string{}
+	for _, value := range r.URL.Query()[InlineParam] {
+		inline = append(inline, strings.Split(value, ",")...)
+	}
+
+	return inline
+}
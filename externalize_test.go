@@ -0,0 +1,89 @@
+package jsh
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestExternalization(t *testing.T) {
+
+	Convey("Externalization Tests", t, func() {
+
+		stored := map[string]json.RawMessage{}
+		Register(&Resource{
+			Type: "builds",
+			Externalization: &Externalization{
+				Threshold:  16,
+				Attributes: []string{"log", "notes"},
+				Store: AttributeStoreFunc(func(r *http.Request, object *Object, attribute string, value json.RawMessage) (string, *Error) {
+					href := "https://blobs.example.com/" + object.ID + "/" + attribute
+					stored[href] = value
+					return href, nil
+				}),
+			},
+		})
+		Reset(func() { Unregister("builds") })
+
+		log := strings.Repeat("x", 32)
+		send := func(query string) map[string]interface{} {
+			build, _ := NewObject("1", "builds", map[string]string{
+				"log":    log,
+				"status": "passed",
+				"title":  strings.Repeat("y", 32),
+			})
+
+			request := &http.Request{Method: "GET", Header: http.Header{}, URL: &url.URL{RawQuery: query}}
+			writer := httptest.NewRecorder()
+			So(Send(writer, request, build), ShouldBeNil)
+
+			doc := struct {
+				Data map[string]interface{} `json:"data"`
+			}{}
+			So(json.Unmarshal(writer.Body.Bytes(), &doc), ShouldBeNil)
+			return doc.Data
+		}
+
+		Convey("should replace oversized attributes with links and meta", func() {
+			data := send("")
+
+			attributes := data["attributes"].(map[string]interface{})
+			So(attributes["log"], ShouldBeNil)
+			So(attributes["status"], ShouldEqual, "passed")
+			// only the listed attributes are externalized
+			So(attributes["title"], ShouldNotBeNil)
+
+			links := data["links"].(map[string]interface{})
+			So(links["log"], ShouldResemble, map[string]interface{}{"href": "https://blobs.example.com/1/log"})
+
+			value := stored["https://blobs.example.com/1/log"]
+			So(string(value), ShouldEqual, `"`+log+`"`)
+
+			meta := data["meta"].(map[string]interface{})[ExternalizedMeta].(map[string]interface{})
+			So(meta["log"], ShouldResemble, map[string]interface{}{
+				"size":   float64(len(value)),
+				"digest": AttributeDigest(value),
+			})
+			So(meta["notes"], ShouldBeNil)
+		})
+
+		Convey("should send attributes inline when requested", func() {
+			data := send(InlineParam + "=log")
+
+			attributes := data["attributes"].(map[string]interface{})
+			So(attributes["log"], ShouldEqual, log)
+			So(data["meta"], ShouldBeNil)
+			So(stored, ShouldBeEmpty)
+		})
+
+		Convey("->AttributeDigest()", func() {
+			So(AttributeDigest(json.RawMessage(`"abc"`)), ShouldEqual,
+				"sha256:6cc43f858fbb763301637b5af970e2a46b46f461f27e5a0f41e009c59b827b25")
+		})
+	})
+}
@@ -0,0 +1,152 @@
+package jsh
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// objectMembers are the members of resource objects the specification defines
+var objectMembers = map[string]bool{
+	"type":          true,
+	"id":            true,
+	"attributes":    true,
+	"links":         true,
+	"relationships": true,
+	"meta":          true,
+}
+
+// relationshipMembers are the members of relationships the specification
+// defines
+var relationshipMembers = map[string]bool{
+	"links": true,
+	"data":  true,
+	"meta":  true,
+}
+
+// MarshalJSON marshals the object, adding its Extra members.
+func (o Object) MarshalJSON() ([]byte, error) {
+	type MarshalObject Object
+
+	raw, err := JSON.Marshal(MarshalObject(o))
+	if err != nil {
+		return nil, err
+	}
+
+	return addMembers(raw, o.Extra, objectMembers)
+}
+
+/*
+addMembers splices extra members into the end of a marshaled JSON object,
+leaving the members it already has as they are. The object only has members of
+the known set, which extra members may not replace, so they are skipped.
+*/
+func addMembers(raw []byte, extra map[string]json.RawMessage, known map[string]bool) ([]byte, error) {
+	names := make([]string, 0, len(extra))
+	for name := range extra {
+		if !known[name] {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return raw, nil
+	}
+	sort.Strings(names)
+
+	raw = bytes.TrimSpace(raw)
+	if len(raw) < 2 || raw[len(raw)-1] != '}' {
+		return nil, fmt.Errorf("extra members can only be added to objects, not %s", raw)
+	}
+
+	empty := len(bytes.TrimSpace(raw[1:len(raw)-1])) == 0
+
+	spliced := bytes.NewBuffer(make([]byte, 0, len(raw)+64*len(names)))
+	spliced.Write(raw[:len(raw)-1])
+	for i, name := range names {
+		if i > 0 || !empty {
+			spliced.WriteByte(',')
+		}
+
+		key, err := JSON.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		spliced.Write(key)
+		spliced.WriteByte(':')
+		spliced.Write(extra[name])
+	}
+	spliced.WriteByte('}')
+
+	return spliced.Bytes(), nil
+}
+
+/*
+preserveUnknownMembers keeps the members of a parsed document body that the
+specification doesn't define in the Extra of the document, its resource objects,
+and their relationships, so they are sent again unchanged. Bodies that can't be
+decoded are left for the parser to report.
+*/
+func preserveUnknownMembers(body []byte, document *Document) {
+	members := map[string]json.RawMessage{}
+	if json.Unmarshal(body, &members) != nil {
+		return
+	}
+	document.Extra = unknownMembers(members, topLevelMembers)
+
+	data := bytes.TrimSpace(members["data"])
+	objects := []json.RawMessage{}
+	if len(data) > 0 && data[0] == '{' {
+		objects = append(objects, data)
+	} else {
+		json.Unmarshal(data, &objects)
+	}
+	preserveObjectMembers(objects, document.Data)
+
+	included := []json.RawMessage{}
+	json.Unmarshal(members["included"], &included)
+	preserveObjectMembers(included, document.Included)
+}
+
+// preserveObjectMembers keeps the unknown members of each raw resource object
+// in the matching parsed object
+func preserveObjectMembers(raw []json.RawMessage, objects List) {
+	for i, object := range objects {
+		if i >= len(raw) {
+			return
+		}
+
+		members := map[string]json.RawMessage{}
+		if json.Unmarshal(raw[i], &members) != nil {
+			continue
+		}
+		object.Extra = unknownMembers(members, objectMembers)
+
+		relationships := map[string]json.RawMessage{}
+		json.Unmarshal(members["relationships"], &relationships)
+		for name, relationship := range object.Relationships {
+			decoded := map[string]json.RawMessage{}
+			if relationship != nil && json.Unmarshal(relationships[name], &decoded) == nil {
+				relationship.Extra = unknownMembers(decoded, relationshipMembers)
+			}
+		}
+	}
+}
+
+// unknownMembers returns the members that aren't known, or nil if there are
+// none
+func unknownMembers(members map[string]json.RawMessage, known map[string]bool) map[string]json.RawMessage {
+	var unknown map[string]json.RawMessage
+	for name, value := range members {
+		if known[name] {
+			continue
+		}
+
+		if unknown == nil {
+			unknown = map[string]json.RawMessage{}
+		}
+		unknown[name] = value
+	}
+
+	return unknown
+}
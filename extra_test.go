@@ -0,0 +1,125 @@
+package jsh
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestExtraMembers(t *testing.T) {
+
+	Convey("Extra Member Tests", t, func() {
+
+		body := `{
+			"data": [{
+				"type": "users",
+				"id": "1",
+				"attributes": {"name": "Bob"},
+				"relationships": {"team": {"data": {"type": "teams", "id": "1"}, "vendor:hint": "eager"}},
+				"vendor:version": 3
+			}],
+			"included": [{"type": "teams", "id": "1", "@context": "teams"}],
+			"vendor:trace": {"id": "abc"}
+		}`
+
+		parse := func(options *ParseOptions) *Document {
+			req, reqErr := testRequest([]byte(body))
+			So(reqErr, ShouldBeNil)
+
+			parser := NewParser(req)
+			parser.Options = options
+			document, err := parser.Document(req.Body, ListMode)
+			So(err, ShouldBeNil)
+			return document
+		}
+
+		Convey("should drop unknown members by default", func() {
+			document := parse(nil)
+			So(document.Extra, ShouldBeNil)
+			So(document.Data[0].Extra, ShouldBeNil)
+		})
+
+		Convey("should preserve unknown members with PreserveUnknownMembers", func() {
+			document := parse(&ParseOptions{PreserveUnknownMembers: true})
+
+			So(string(document.Extra["vendor:trace"]), ShouldEqual, `{"id": "abc"}`)
+			So(len(document.Extra), ShouldEqual, 1)
+			So(string(document.Data[0].Extra["vendor:version"]), ShouldEqual, "3")
+			So(string(document.Data[0].Relationships["team"].Extra["vendor:hint"]), ShouldEqual, `"eager"`)
+			So(string(document.Included[0].Extra["@context"]), ShouldEqual, `"teams"`)
+
+			Convey("and send them again", func() {
+				raw, err := json.Marshal(document)
+				So(err, ShouldBeNil)
+
+				resent := map[string]interface{}{}
+				So(json.Unmarshal(raw, &resent), ShouldBeNil)
+				So(resent["vendor:trace"], ShouldResemble, map[string]interface{}{"id": "abc"})
+
+				object := resent["data"].([]interface{})[0].(map[string]interface{})
+				So(object["vendor:version"], ShouldEqual, 3)
+				team := object["relationships"].(map[string]interface{})["team"].(map[string]interface{})
+				So(team["vendor:hint"], ShouldEqual, "eager")
+			})
+		})
+
+		Convey("should add extra members after the defined ones", func() {
+			object := &Object{Type: "users", ID: "1", Extra: map[string]json.RawMessage{
+				"vendor:version": json.RawMessage(`3`),
+				"@context":       json.RawMessage(`"users"`),
+			}}
+
+			raw, err := json.Marshal(object)
+			So(err, ShouldBeNil)
+			So(string(raw), ShouldEqual, `{"type":"users","id":"1","@context":"users","vendor:version":3}`)
+		})
+
+		Convey("should round-trip documents losslessly", func() {
+			body := `{"data": {
+				"type": "users",
+				"id": "1",
+				"attributes": {"name": "Bob"},
+				"relationships": {
+					"team": {"links": {"related": {"href": "/users/1/team"}}, "vendor:hint": "lazy"},
+					"manager": {"data": null},
+					"reports": {"data": [{"type": "users", "id": "2"}]}
+				},
+				"vendor:version": 3
+			}, "vendor:trace": {"id": "abc"}}`
+
+			parseBody := func(body []byte) *Document {
+				req, reqErr := testRequest(body)
+				So(reqErr, ShouldBeNil)
+
+				parser := NewParser(req)
+				parser.Options = &ParseOptions{PreserveUnknownMembers: true}
+				document, err := parser.Document(req.Body, ObjectMode)
+				So(err, ShouldBeNil)
+				return document
+			}
+
+			parsed := parseBody([]byte(body))
+			parsed.Status = http.StatusOK
+
+			writer := httptest.NewRecorder()
+			So(SendDocument(writer, &http.Request{Method: "GET"}, parsed), ShouldBeNil)
+
+			resent := parseBody(writer.Body.Bytes())
+			So(resent.First().Equal(parsed.First()), ShouldBeTrue)
+			So(resent.First().RelationshipState("team"), ShouldEqual, LinkageAbsent)
+			So(resent.First().RelationshipState("manager"), ShouldEqual, LinkageCleared)
+			So(string(resent.Extra["vendor:trace"]), ShouldEqual, `{"id":"abc"}`)
+		})
+
+		Convey("should not let extra members replace defined ones", func() {
+			object := &Object{Type: "users", ID: "1", Extra: map[string]json.RawMessage{"id": json.RawMessage(`"2"`)}}
+
+			raw, err := json.Marshal(object)
+			So(err, ShouldBeNil)
+			So(string(raw), ShouldContainSubstring, `"id":"1"`)
+		})
+	})
+}
@@ -0,0 +1,287 @@
+package jsh
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FilterParam is the family of query parameters filtering a collection, such
+// as filter[age][gte].
+const FilterParam = "filter"
+
+// FilterOperator compares an attribute against the values of a filter.
+type FilterOperator string
+
+const (
+	// Equal matches attributes equal to a value, the default operator
+	Equal FilterOperator = "eq"
+	// NotEqual matches attributes that differ from a value
+	NotEqual FilterOperator = "ne"
+	// GreaterThan matches attributes greater than a value
+	GreaterThan FilterOperator = "gt"
+	// GreaterOrEqual matches attributes greater than or equal to a value
+	GreaterOrEqual FilterOperator = "gte"
+	// LessThan matches attributes less than a value
+	LessThan FilterOperator = "lt"
+	// LessOrEqual matches attributes less than or equal to a value
+	LessOrEqual FilterOperator = "lte"
+	// Like matches string attributes containing a value, ignoring case
+	Like FilterOperator = "like"
+)
+
+// filterOperators lists the supported operators in their canonical order
+var filterOperators = []FilterOperator{Equal, NotEqual, GreaterThan, GreaterOrEqual, LessThan, LessOrEqual, Like}
+
+/*
+FilterCondition is a single filter query parameter. An attribute matches the
+condition if it matches any of the Values, so filter[status]=draft,review
+matches drafts and resources under review.
+*/
+type FilterCondition struct {
+	Attribute string
+	Operator  FilterOperator
+	Values    []string
+}
+
+// Parameter formats the condition's query parameter name, such as
+// filter[age][gte].
+func (c FilterCondition) Parameter() string {
+	return fmt.Sprintf("%s[%s][%s]", FilterParam, c.Attribute, c.Operator)
+}
+
+// Filter is a parsed filter, resources must match all of its conditions.
+type Filter []FilterCondition
+
+/*
+ParseFilter parses the filter query parameters of a request into conditions
+that handlers or storage adapters can translate into their own queries:
+
+	filter, err := jsh.ParseFilter(r, map[string][]jsh.FilterOperator{
+		"age":  {jsh.GreaterOrEqual, jsh.LessThan},
+		"name": {jsh.Equal, jsh.Like},
+	})
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	for _, condition := range filter {
+		query = query.Where(condition.Attribute, condition.Operator, condition.Values)
+	}
+
+Parameters take the form filter[ATTRIBUTE][OPERATOR]=VALUES, where the
+operator defaults to Equal and values are comma separated. If allowed is
+provided, filtering by any other attribute, or any other operator of an
+attribute listed with operators, is a 400 error whose source parameter is the
+offending query parameter. Conditions are sorted by attribute and operator.
+*/
+func ParseFilter(r *http.Request, allowed map[string][]FilterOperator) (Filter, *Error) {
+	filter := Filter{}
+
+	for key, values := range r.URL.Query() {
+		if key != FilterParam && !strings.HasPrefix(key, FilterParam+"[") {
+			continue
+		}
+
+		condition, err := parseFilterKey(key)
+		if err != nil {
+			return nil, err
+		}
+
+		if allowed != nil {
+			operators, attributeAllowed := allowed[condition.Attribute]
+			if !attributeAllowed {
+				return nil, ParameterError(fmt.Sprintf("Filtering by '%s' is not supported", condition.Attribute), key)
+			}
+
+			if len(operators) > 0 && !containsOperator(operators, condition.Operator) {
+				return nil, ParameterError(
+					fmt.Sprintf("Filtering '%s' with '%s' is not supported", condition.Attribute, condition.Operator),
+					key,
+				)
+			}
+		}
+
+		for _, value := range values {
+			condition.Values = append(condition.Values, strings.Split(value, ",")...)
+		}
+
+		filter = append(filter, condition)
+	}
+
+	sort.Sort(filterSorter(filter))
+
+	return filter, nil
+}
+
+// parseFilterKey parses the attribute and operator of a filter query parameter
+func parseFilterKey(key string) (FilterCondition, *Error) {
+	invalid := ParameterError("Filters must take the form filter[ATTRIBUTE] or filter[ATTRIBUTE][OPERATOR]", key)
+
+	members := []string{}
+	rest := strings.TrimPrefix(key, FilterParam)
+	for rest != "" {
+		end := strings.Index(rest, "]")
+		if rest[0] != '[' || end < 0 {
+			return FilterCondition{}, invalid
+		}
+
+		members = append(members, rest[1:end])
+		rest = rest[end+1:]
+	}
+
+	if len(members) == 0 || len(members) > 2 || members[0] == "" {
+		return FilterCondition{}, invalid
+	}
+
+	condition := FilterCondition{Attribute: members[0], Operator: Equal}
+	if len(members) == 2 {
+		condition.Operator = FilterOperator(members[1])
+		if !containsOperator(filterOperators, condition.Operator) {
+			return FilterCondition{}, ParameterError(fmt.Sprintf("Unsupported filter operator '%s'", members[1]), key)
+		}
+	}
+
+	return condition, nil
+}
+
+// containsOperator returns true if operators contains operator
+func containsOperator(operators []FilterOperator, operator FilterOperator) bool {
+	for _, candidate := range operators {
+		if candidate == operator {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Condition returns the condition on an attribute with the given operator, or
+// nil if the filter doesn't have one.
+func (f Filter) Condition(attribute string, operator FilterOperator) *FilterCondition {
+	for i := range f {
+		if f[i].Attribute == attribute && f[i].Operator == operator {
+			return &f[i]
+		}
+	}
+
+	return nil
+}
+
+/*
+Matches returns true if an object matches every condition of the filter, for
+filtering resources in memory:
+
+	articles = articles.Filter(filter.Matches)
+
+Values are converted to the type of the attribute they're compared with, and
+never match attributes of other types, such as missing attributes.
+*/
+func (f Filter) Matches(object *Object) bool {
+	for _, condition := range f {
+		value, err := sortValue(object, condition.Attribute)
+		if err != nil || !condition.matches(value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matches returns true if a decoded attribute value matches any of the
+// condition's values
+func (c FilterCondition) matches(value interface{}) bool {
+	for _, raw := range c.Values {
+		operand, converted := filterOperand(value, raw)
+		if !converted {
+			continue
+		}
+
+		if c.Operator == Like {
+			str, isString := value.(string)
+			if isString && strings.Contains(strings.ToLower(str), strings.ToLower(raw)) {
+				return true
+			}
+			continue
+		}
+
+		comparison := compareValues(value, operand, nil)
+		switch c.Operator {
+		case Equal:
+			if comparison == 0 {
+				return true
+			}
+		case NotEqual:
+			if comparison != 0 {
+				return true
+			}
+		case GreaterThan:
+			if comparison > 0 {
+				return true
+			}
+		case GreaterOrEqual:
+			if comparison >= 0 {
+				return true
+			}
+		case LessThan:
+			if comparison < 0 {
+				return true
+			}
+		case LessOrEqual:
+			if comparison <= 0 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// filterOperand converts a filter value to the type of an attribute value
+func filterOperand(value interface{}, raw string) (interface{}, bool) {
+	switch value.(type) {
+	case string:
+		return raw, true
+	case float64:
+		number, err := strconv.ParseFloat(raw, 64)
+		return number, err == nil
+	case bool:
+		boolean, err := strconv.ParseBool(raw)
+		return boolean, err == nil
+	}
+
+	return nil, false
+}
+
+// filterSorter orders conditions by attribute and then operator
+type filterSorter Filter
+
+func (s filterSorter) Len() int {
+	return len(s)
+}
+
+func (s filterSorter) Less(i, j int) bool {
+	if s[i].Attribute != s[j].Attribute {
+		return s[i].Attribute < s[j].Attribute
+	}
+
+	return operatorIndex(s[i].Operator) < operatorIndex(s[j].Operator)
+}
+
+func (s filterSorter) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// operatorIndex returns the canonical position of an operator
+func operatorIndex(operator FilterOperator) int {
+	for i, candidate := range filterOperators {
+		if candidate == operator {
+			return i
+		}
+	}
+
+	return len(filterOperators)
+}
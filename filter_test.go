@@ -0,0 +1,81 @@
+package jsh
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFilter(t *testing.T) {
+
+	Convey("Filter Tests", t, func() {
+
+		parse := func(query string, allowed map[string][]FilterOperator) (Filter, *Error) {
+			return ParseFilter(httptest.NewRequest("GET", "/users?"+query, nil), allowed)
+		}
+
+		Convey("->ParseFilter()", func() {
+
+			Convey("should parse operators and OR lists", func() {
+				filter, err := parse("filter[name][like]=bob&filter[age][gte]=21&filter[status]=active,invited&sort=age", nil)
+				So(err, ShouldBeNil)
+				So(filter, ShouldResemble, Filter{
+					{Attribute: "age", Operator: GreaterOrEqual, Values: []string{"21"}},
+					{Attribute: "name", Operator: Like, Values: []string{"bob"}},
+					{Attribute: "status", Operator: Equal, Values: []string{"active", "invited"}},
+				})
+				So(filter.Condition("age", GreaterOrEqual).Parameter(), ShouldEqual, "filter[age][gte]")
+				So(filter.Condition("age", LessThan), ShouldBeNil)
+			})
+
+			Convey("should reject malformed parameters", func() {
+				for _, query := range []string{"filter=bob", "filter[]=bob", "filter[name=bob", "filter[a][eq][b]=1"} {
+					_, err := parse(query, nil)
+					So(err, ShouldNotBeNil)
+					So(err.Status, ShouldEqual, 400)
+				}
+			})
+
+			Convey("should reject unknown operators", func() {
+				_, err := parse("filter[age][between]=1", nil)
+				So(err, ShouldNotBeNil)
+				So(err.Source.Parameter, ShouldEqual, "filter[age][between]")
+			})
+
+			Convey("should only allow the declared attributes and operators", func() {
+				allowed := map[string][]FilterOperator{"age": {GreaterOrEqual}, "name": nil}
+
+				_, err := parse("filter[name][like]=bob&filter[age][gte]=21", allowed)
+				So(err, ShouldBeNil)
+
+				_, err = parse("filter[email]=a", allowed)
+				So(err, ShouldNotBeNil)
+				So(err.Source.Parameter, ShouldEqual, "filter[email]")
+
+				_, err = parse("filter[age][lt]=30", allowed)
+				So(err, ShouldNotBeNil)
+				So(err.Source.Parameter, ShouldEqual, "filter[age][lt]")
+			})
+		})
+
+		Convey("->Matches()", func() {
+			bob, _ := NewObject("1", "users", map[string]interface{}{"name": "Bobby", "age": 30, "admin": true})
+			ann, _ := NewObject("2", "users", map[string]interface{}{"name": "Ann", "age": 19, "admin": false})
+			list := List{bob, ann}
+
+			match := func(query string) []string {
+				filter, err := parse(query, nil)
+				So(err, ShouldBeNil)
+				return list.Filter(filter.Matches).IDs()
+			}
+
+			So(match("filter[age][gte]=21"), ShouldResemble, []string{"1"})
+			So(match("filter[name][like]=BOB"), ShouldResemble, []string{"1"})
+			So(match("filter[name]=Ann,Bobby"), ShouldResemble, []string{"1", "2"})
+			So(match("filter[admin]=false"), ShouldResemble, []string{"2"})
+			So(match("filter[age][lt]=20&filter[name][ne]=Ann"), ShouldBeEmpty)
+			So(match("filter[missing]=1"), ShouldBeEmpty)
+		})
+	})
+}
@@ -0,0 +1,140 @@
+package jsh
+
+import (
+	"net/http"
+	"sync"
+)
+
+/*
+BeforeParseHook is called with the raw body of every document a Parser reads,
+after its headers are validated and before it is decoded, and returns the body
+to parse in its place. Use it to decrypt payloads or audit log them as sent. An
+error aborts parsing and is returned by the Parser.
+*/
+type BeforeParseHook func(p *Parser, body []byte) ([]byte, *Error)
+
+/*
+AfterParseHook is called with every document a Parser has decoded and
+validated, before it is returned, and may modify it, such as to decrypt fields
+or strip PII before handlers see them. An error aborts parsing and is returned
+by the Parser.
+*/
+type AfterParseHook func(p *Parser, document *Document) *Error
+
+/*
+BeforeSendHook is called with every response Document once the registered
+resource declarations have been applied, and before it is serialized, and may
+modify it, such as to attach standard meta. An error is sent in place of the
+document.
+*/
+type BeforeSendHook func(r *http.Request, document *Document) *Error
+
+// hooks holds the hooks registered with BeforeParse, AfterParse, and BeforeSend
+var hooks = struct {
+	sync.RWMutex
+	beforeParse []BeforeParseHook
+	afterParse  []AfterParseHook
+	beforeSend  []BeforeSendHook
+}{}
+
+/*
+BeforeParse registers a hook to run before every document is parsed, in the
+order registered:
+
+	jsh.BeforeParse(func(p *jsh.Parser, body []byte) ([]byte, *jsh.Error) {
+		audit.Printf("%s %s: %s", p.Method, p.Path, body)
+		return body, nil
+	})
+
+Hooks run for every Parser, including those jsc uses to parse responses, which
+have no Method. Register them at startup.
+*/
+func BeforeParse(hook BeforeParseHook) {
+	hooks.Lock()
+	defer hooks.Unlock()
+	hooks.beforeParse = append(hooks.beforeParse, hook)
+}
+
+// AfterParse registers a hook to run after every document is parsed, in the
+// order registered, see AfterParseHook.
+func AfterParse(hook AfterParseHook) {
+	hooks.Lock()
+	defer hooks.Unlock()
+	hooks.afterParse = append(hooks.afterParse, hook)
+}
+
+/*
+BeforeSend registers a hook to run before every document is sent, in the order
+registered:
+
+	jsh.BeforeSend(func(r *http.Request, document *jsh.Document) *jsh.Error {
+		if document.Meta == nil {
+			document.Meta = map[string]interface{}{"region": region}
+		}
+		return nil
+	})
+*/
+func BeforeSend(hook BeforeSendHook) {
+	hooks.Lock()
+	defer hooks.Unlock()
+	hooks.beforeSend = append(hooks.beforeSend, hook)
+}
+
+// ClearHooks removes every hook registered with BeforeParse, AfterParse, and
+// BeforeSend.
+func ClearHooks() {
+	hooks.Lock()
+	defer hooks.Unlock()
+	hooks.beforeParse = nil
+	hooks.afterParse = nil
+	hooks.beforeSend = nil
+}
+
+// runBeforeParse passes the body through the BeforeParse hooks
+func (p *Parser) runBeforeParse(body []byte) ([]byte, *Error) {
+	hooks.RLock()
+	registered := hooks.beforeParse
+	hooks.RUnlock()
+
+	for _, hook := range registered {
+		var err *Error
+		body, err = hook(p, body)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return body, nil
+}
+
+// runAfterParse passes the document through the AfterParse hooks
+func (p *Parser) runAfterParse(document *Document) *Error {
+	hooks.RLock()
+	registered := hooks.afterParse
+	hooks.RUnlock()
+
+	for _, hook := range registered {
+		err := hook(p, document)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// runBeforeSend passes the document through the BeforeSend hooks
+func runBeforeSend(r *http.Request, document *Document) *Error {
+	hooks.RLock()
+	registered := hooks.beforeSend
+	hooks.RUnlock()
+
+	for _, hook := range registered {
+		err := hook(r, document)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
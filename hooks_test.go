@@ -0,0 +1,124 @@
+package jsh
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestHooks(t *testing.T) {
+
+	Convey("Hook Tests", t, func() {
+
+		Reset(ClearHooks)
+
+		objectJSON := []byte(`{"data": {"type": "users", "id": "1", "attributes": {"name": "bob", "ssn": "123-45-6789"}}}`)
+
+		Convey("->BeforeParse()", func() {
+
+			Convey("should replace the parsed body", func() {
+				audited := []string{}
+				BeforeParse(func(p *Parser, body []byte) ([]byte, *Error) {
+					audited = append(audited, p.Method+" "+string(body))
+					return bytes.Replace(body, []byte("bob"), []byte("alice"), 1), nil
+				})
+
+				request, _ := testRequest(objectJSON)
+				object, err := ParseObject(request)
+				So(err, ShouldBeNil)
+				So(audited, ShouldResemble, []string{"GET " + string(objectJSON)})
+
+				attributes := map[string]string{}
+				So(object.Unmarshal("users", &attributes), ShouldBeNil)
+				So(attributes["name"], ShouldEqual, "alice")
+			})
+
+			Convey("should abort parsing on errors", func() {
+				BeforeParse(func(p *Parser, body []byte) ([]byte, *Error) {
+					return nil, InputError("Undecryptable payload", "")
+				})
+
+				request, _ := testRequest([]byte(`{"data": {"type": "tags", "id": "1"}}`))
+				_, err := ParseRelationship(request)
+				So(err, ShouldNotBeNil)
+				So(err.Detail, ShouldEqual, "Undecryptable payload")
+			})
+		})
+
+		Convey("->AfterParse()", func() {
+
+			Convey("should modify parsed documents", func() {
+				AfterParse(func(p *Parser, document *Document) *Error {
+					for _, object := range document.Data {
+						object.RemoveAttribute("ssn")
+					}
+					return nil
+				})
+
+				request, _ := testRequest(objectJSON)
+				object, err := ParseObject(request)
+				So(err, ShouldBeNil)
+				So(string(object.Attributes), ShouldNotContainSubstring, "ssn")
+			})
+
+			Convey("should run in order and stop at the first error", func() {
+				calls := 0
+				AfterParse(func(p *Parser, document *Document) *Error {
+					calls++
+					return Conflict("Rejected")
+				})
+				AfterParse(func(p *Parser, document *Document) *Error {
+					calls++
+					return nil
+				})
+
+				request, _ := testRequest(objectJSON)
+				_, err := ParseObject(request)
+				So(err, ShouldNotBeNil)
+				So(err.Status, ShouldEqual, http.StatusConflict)
+				So(calls, ShouldEqual, 1)
+			})
+		})
+
+		Convey("->BeforeSend()", func() {
+			request := &http.Request{Method: "GET", Header: http.Header{}}
+			object, _ := NewObject("1", "users", map[string]string{"name": "bob"})
+
+			Convey("should modify sent documents", func() {
+				BeforeSend(func(r *http.Request, document *Document) *Error {
+					document.Meta = map[string]interface{}{"region": "eu"}
+					return nil
+				})
+
+				writer := httptest.NewRecorder()
+				So(Send(writer, request, object), ShouldBeNil)
+				So(writer.Body.String(), ShouldContainSubstring, `"region":"eu"`)
+			})
+
+			Convey("should send errors in place of the document", func() {
+				BeforeSend(func(r *http.Request, document *Document) *Error {
+					return ISE("Hook failed")
+				})
+
+				writer := httptest.NewRecorder()
+				err := Send(writer, request, object)
+				So(err, ShouldNotBeNil)
+				So(writer.Code, ShouldEqual, http.StatusInternalServerError)
+			})
+		})
+
+		Convey("->ClearHooks()", func() {
+			BeforeSend(func(r *http.Request, document *Document) *Error {
+				return ISE("Hook failed")
+			})
+			ClearHooks()
+
+			writer := httptest.NewRecorder()
+			object, _ := NewObject("1", "users", nil)
+			So(Send(writer, &http.Request{Method: "GET", Header: http.Header{}}, object), ShouldBeNil)
+		})
+	})
+}
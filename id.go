@@ -0,0 +1,111 @@
+package jsh
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// ClientIDMode determines how client generated IDs are treated on POST.
+type ClientIDMode int
+
+const (
+	// AllowClientIDs accepts resources created with or without an ID
+	AllowClientIDs ClientIDMode = iota
+	// RequireClientIDs rejects resources created without an ID
+	RequireClientIDs
+	// ForbidClientIDs rejects resources created with an ID with a 403, as the
+	// specification requires for servers that don't support client generated IDs
+	ForbidClientIDs
+)
+
+/*
+IDValidator checks a client generated ID, returning an error to reject it.
+Return a 403 for IDs that are unacceptable and a 409 Conflict for IDs that are
+already in use:
+
+	parser.Options.ValidateID = func(id string) *jsh.Error {
+		if storage.Exists(id) {
+			return jsh.Conflict(fmt.Sprintf("ID '%s' already exists", id))
+		}
+		return jsh.UUIDValidator(id)
+	}
+*/
+type IDValidator func(id string) *Error
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// UUIDValidator accepts IDs in the canonical UUID format.
+func UUIDValidator(id string) *Error {
+	if !uuidPattern.MatchString(id) {
+		return forbiddenID(fmt.Sprintf("ID '%s' is not a valid UUID", id))
+	}
+
+	return nil
+}
+
+// PrefixValidator accepts IDs that start with prefix, such as "usr_".
+func PrefixValidator(prefix string) IDValidator {
+	return func(id string) *Error {
+		if !strings.HasPrefix(id, prefix) || len(id) == len(prefix) {
+			return forbiddenID(fmt.Sprintf("ID '%s' must start with '%s'", id, prefix))
+		}
+
+		return nil
+	}
+}
+
+// acceptID applies the parse options to the ID of an object being created
+func acceptID(options *ParseOptions, method string, object *Object) *Error {
+	if method != "POST" {
+		return nil
+	}
+
+	if object.ID == "" {
+		if options.ClientIDs == RequireClientIDs {
+			return forbiddenID("A client generated ID is required")
+		}
+
+		return nil
+	}
+
+	if options.ClientIDs == ForbidClientIDs {
+		return forbiddenID("Client generated IDs are not supported")
+	}
+
+	if options.ValidateID != nil {
+		return options.ValidateID(object.ID)
+	}
+
+	return nil
+}
+
+// generateID assigns an ID from the parse options' generator to an object being
+// created without one. Generated IDs are internal, so it runs after the IDs of
+// the object have been translated.
+func generateID(options *ParseOptions, method string, object *Object) *Error {
+	if method != "POST" || object.ID != "" || options.GenerateID == nil {
+		return nil
+	}
+
+	id, err := options.GenerateID.GenerateID()
+	if err != nil {
+		return ISE(fmt.Sprintf("Error generating ID: %s", err))
+	}
+	object.ID = id
+
+	return nil
+}
+
+// forbiddenID creates a 403 error pointing at the ID of the primary data
+func forbiddenID(detail string) *Error {
+	err := &Error{
+		Title:  "Forbidden",
+		Detail: detail,
+		Status: http.StatusForbidden,
+	}
+	err.Source.Pointer = "/data/id"
+
+	return err
+}
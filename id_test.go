@@ -0,0 +1,104 @@
+package jsh
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestClientIDs(t *testing.T) {
+
+	Convey("Client ID Tests", t, func() {
+
+		parse := func(body string, options *ParseOptions) (*Document, *Error) {
+			req, reqErr := testRequest([]byte(body))
+			So(reqErr, ShouldBeNil)
+			req.Method = "POST"
+
+			parser := NewParser(req)
+			parser.Options = options
+			return parser.Document(req.Body, ObjectMode)
+		}
+
+		withID := `{"data": {"type": "users", "id": "6ba7b810-9dad-11d1-80b4-00c04fd430c8"}}`
+		withoutID := `{"data": {"type": "users"}}`
+
+		Convey("should allow client IDs by default", func() {
+			_, err := parse(withID, nil)
+			So(err, ShouldBeNil)
+
+			_, err = parse(withoutID, nil)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("should forbid client IDs", func() {
+			_, err := parse(withID, &ParseOptions{ClientIDs: ForbidClientIDs})
+			So(err, ShouldNotBeNil)
+			So(err.Status, ShouldEqual, http.StatusForbidden)
+			So(err.Source.Pointer, ShouldEqual, "/data/id")
+		})
+
+		Convey("should require client IDs", func() {
+			_, err := parse(withoutID, &ParseOptions{ClientIDs: RequireClientIDs})
+			So(err, ShouldNotBeNil)
+			So(err.Status, ShouldEqual, http.StatusForbidden)
+		})
+
+		Convey("should validate client IDs", func() {
+			_, err := parse(withID, &ParseOptions{ValidateID: UUIDValidator})
+			So(err, ShouldBeNil)
+
+			_, err = parse(`{"data": {"type": "users", "id": "1"}}`, &ParseOptions{ValidateID: UUIDValidator})
+			So(err, ShouldNotBeNil)
+			So(err.Status, ShouldEqual, http.StatusForbidden)
+		})
+
+		Convey("should pass through validator conflicts", func() {
+			conflict := func(id string) *Error {
+				return Conflict("ID already exists")
+			}
+
+			_, err := parse(withID, &ParseOptions{ValidateID: conflict})
+			So(err, ShouldNotBeNil)
+			So(err.Status, ShouldEqual, http.StatusConflict)
+		})
+
+		Convey("should generate IDs for resources created without one", func() {
+			doc, err := parse(withoutID, &ParseOptions{GenerateID: UUIDv7Generator, ValidateID: PrefixValidator("usr_")})
+			So(err, ShouldBeNil)
+			So(UUIDValidator(doc.First().ID), ShouldBeNil)
+
+			doc, err = parse(withID, &ParseOptions{GenerateID: UUIDv7Generator})
+			So(err, ShouldBeNil)
+			So(doc.First().ID, ShouldEqual, "6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+		})
+
+		Convey("should not translate generated IDs", func() {
+			Register(&Resource{Type: "users", IDTranslator: prefixTranslator{}})
+			Reset(func() { Unregister("users") })
+
+			doc, err := parse(withoutID, &ParseOptions{GenerateID: UUIDv4Generator})
+			So(err, ShouldBeNil)
+			So(UUIDValidator(doc.First().ID), ShouldBeNil)
+		})
+
+		Convey("should reject resources when generating their ID fails", func() {
+			failing := IDGeneratorFunc(func() (string, error) {
+				return "", errors.New("entropy exhausted")
+			})
+
+			_, err := parse(withoutID, &ParseOptions{GenerateID: failing})
+			So(err, ShouldNotBeNil)
+			So(err.Status, ShouldEqual, http.StatusInternalServerError)
+		})
+
+		Convey("->PrefixValidator()", func() {
+			validate := PrefixValidator("usr_")
+			So(validate("usr_123"), ShouldBeNil)
+			So(validate("usr_"), ShouldNotBeNil)
+			So(validate("org_123"), ShouldNotBeNil)
+		})
+	})
+}
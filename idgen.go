@@ -0,0 +1,128 @@
+package jsh
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+/*
+IDGenerator generates the IDs of resources created without one. Set
+ParseOptions.GenerateID to assign IDs to resources POSTed without one as they
+are parsed, or the IDs of a jsc.API to generate them on the client:
+
+	jsh.DefaultParseOptions.GenerateID = jsh.UUIDv7Generator
+
+UUIDv4Generator, UUIDv7Generator, and ULIDGenerator are built in, and any
+function can be used as one with IDGeneratorFunc.
+*/
+type IDGenerator interface {
+	GenerateID() (string, error)
+}
+
+// IDGeneratorFunc is a function used as an IDGenerator.
+type IDGeneratorFunc func() (string, error)
+
+// GenerateID calls f.
+func (f IDGeneratorFunc) GenerateID() (string, error) {
+	return f()
+}
+
+// UUIDv4Generator generates random UUIDs.
+var UUIDv4Generator IDGenerator = IDGeneratorFunc(func() (string, error) {
+	return newUUIDv4()
+})
+
+// UUIDv7Generator generates UUIDs that start with the time they were generated
+// at, which sort by creation time and keep database indexes compact.
+var UUIDv7Generator IDGenerator = IDGeneratorFunc(func() (string, error) {
+	return newUUIDv7(time.Now())
+})
+
+// ULIDGenerator generates ULIDs, 26 character IDs that sort by creation time,
+// such as 01ARZ3NDEKTSV4RRFFQ69G5FAV.
+var ULIDGenerator IDGenerator = IDGeneratorFunc(func() (string, error) {
+	return newULID(time.Now())
+})
+
+// newUUIDv4 returns a version 4 UUID
+func newUUIDv4() (string, error) {
+	var uuid [16]byte
+	_, err := rand.Read(uuid[:])
+	if err != nil {
+		return "", err
+	}
+
+	return formatUUID(uuid, 4), nil
+}
+
+// newUUIDv7 returns a version 7 UUID for the time
+func newUUIDv7(now time.Time) (string, error) {
+	var uuid [16]byte
+	_, err := rand.Read(uuid[6:])
+	if err != nil {
+		return "", err
+	}
+	putMillis(uuid[:6], now)
+
+	return formatUUID(uuid, 7), nil
+}
+
+// formatUUID sets the version and variant bits of the UUID and formats it
+// canonically
+func formatUUID(uuid [16]byte, version byte) string {
+	uuid[6] = uuid[6]&0x0f | version<<4
+	uuid[8] = uuid[8]&0x3f | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10], uuid[10:])
+}
+
+// crockford is the Crockford base32 alphabet ULIDs are encoded with
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// newULID returns a ULID for the time
+func newULID(now time.Time) (string, error) {
+	var ulid [16]byte
+	_, err := rand.Read(ulid[6:])
+	if err != nil {
+		return "", err
+	}
+	putMillis(ulid[:6], now)
+
+	// 26 characters of 5 bits each, the first of which holds the 3 most
+	// significant bits of the 128
+	encoded := make([]byte, 26)
+	for i := range encoded {
+		var value byte
+		for bit := i*5 - 2; bit < i*5+3; bit++ {
+			value <<= 1
+			if bit >= 0 && ulid[bit/8]&(0x80>>uint(bit%8)) != 0 {
+				value |= 1
+			}
+		}
+		encoded[i] = crockford[value]
+	}
+
+	return string(encoded), nil
+}
+
+// putMillis writes the milliseconds since the Unix epoch as a 48 bit big-endian
+// integer
+func putMillis(b []byte, now time.Time) {
+	var millis [8]byte
+	binary.BigEndian.PutUint64(millis[:], uint64(now.UnixNano()/int64(time.Millisecond)))
+	copy(b, millis[2:])
+}
+
+var ulidPattern = regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`)
+
+// ULIDValidator accepts IDs in the canonical ULID format.
+func ULIDValidator(id string) *Error {
+	if !ulidPattern.MatchString(id) {
+		return forbiddenID(fmt.Sprintf("ID '%s' is not a valid ULID", id))
+	}
+
+	return nil
+}
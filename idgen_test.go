@@ -0,0 +1,70 @@
+package jsh
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestIDGenerators(t *testing.T) {
+
+	Convey("ID Generator Tests", t, func() {
+
+		// the timestamp of the ULID specification's example
+		created := time.Unix(0, 1469922850259*int64(time.Millisecond))
+
+		Convey("->UUIDv4Generator", func() {
+			id, err := UUIDv4Generator.GenerateID()
+			So(err, ShouldBeNil)
+			So(UUIDValidator(id), ShouldBeNil)
+			So(id[14:15], ShouldEqual, "4")
+			So(regexp.MustCompile(`^[89ab]$`).MatchString(id[19:20]), ShouldBeTrue)
+
+			other, _ := UUIDv4Generator.GenerateID()
+			So(other, ShouldNotEqual, id)
+		})
+
+		Convey("->UUIDv7Generator", func() {
+			id, err := UUIDv7Generator.GenerateID()
+			So(err, ShouldBeNil)
+			So(UUIDValidator(id), ShouldBeNil)
+			So(id[14:15], ShouldEqual, "7")
+
+			Convey("should start with the time", func() {
+				id, err := newUUIDv7(created)
+				So(err, ShouldBeNil)
+				So(id[:13], ShouldEqual, "01563e3a-b5d3")
+			})
+		})
+
+		Convey("->ULIDGenerator", func() {
+			id, err := ULIDGenerator.GenerateID()
+			So(err, ShouldBeNil)
+			So(len(id), ShouldEqual, 26)
+			So(ULIDValidator(id), ShouldBeNil)
+
+			Convey("should start with the time", func() {
+				id, err := newULID(created)
+				So(err, ShouldBeNil)
+				So(id[:10], ShouldEqual, "01ARZ3NDEK")
+			})
+
+			Convey("should sort by creation time", func() {
+				earlier, _ := newULID(created)
+				later, _ := newULID(created.Add(time.Millisecond))
+				So(earlier < later, ShouldBeTrue)
+			})
+		})
+
+		Convey("->ULIDValidator()", func() {
+			So(ULIDValidator("01ARZ3NDEKTSV4RRFFQ69G5FAV"), ShouldBeNil)
+
+			err := ULIDValidator("01ARZ3NDEKTSV4RRFFQ69G5FAI")
+			So(err, ShouldNotBeNil)
+			So(err.Source.Pointer, ShouldEqual, "/data/id")
+			So(ULIDValidator("81ARZ3NDEKTSV4RRFFQ69G5FAV"), ShouldNotBeNil)
+		})
+	})
+}
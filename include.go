@@ -0,0 +1,197 @@
+package jsh
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// IncludeParam is the query parameter used to request compound documents.
+const IncludeParam = "include"
+
+/*
+IncludeTree is a parsed include query parameter. Each key is a relationship name,
+and its value contains the relationships to include from the related resources
+in turn. For example "author.comments,tags" parses to:
+
+	IncludeTree{
+		"author": IncludeTree{"comments": IncludeTree{}},
+		"tags":   IncludeTree{},
+	}
+*/
+type IncludeTree map[string]IncludeTree
+
+/*
+IncludeResolver fetches the related resources for the ids found in a
+relationship's linkage. Resolvers are registered per relationship using
+Resource.Includes.
+*/
+type IncludeResolver func(ids []string) (List, *Error)
+
+/*
+ParseInclude parses the include query parameter of a request into an
+IncludeTree. An empty tree is returned if include isn't set.
+*/
+func ParseInclude(r *http.Request) (IncludeTree, *Error) {
+	tree := IncludeTree{}
+
+	include := r.URL.Query().Get(IncludeParam)
+	if include == "" {
+		return tree, nil
+	}
+
+	for _, path := range strings.Split(include, ",") {
+		node := tree
+
+		for _, name := range strings.Split(path, ".") {
+			if name == "" {
+				return nil, ParameterError(fmt.Sprintf("Invalid relationship path '%s'", path), IncludeParam)
+			}
+
+			child, exists := node[name]
+			if !exists {
+				child = IncludeTree{}
+				node[name] = child
+			}
+
+			node = child
+		}
+	}
+
+	return tree, nil
+}
+
+/*
+ResolveIncludes walks the include tree from the document's primary data, calling
+the registered IncludeResolver of each relationship on the path and adding the
+results to document.Included. Each resource is only fetched and included once:
+
+	document := jsh.Build(articles)
+
+	include, err := jsh.ParseInclude(r)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	err = jsh.ResolveIncludes(document, include)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	jsh.SendDocument(w, r, document)
+
+A 400 error is returned if a relationship on the path has no resolver.
+*/
+func ResolveIncludes(document *Document, include IncludeTree) *Error {
+	seen := map[string]*Object{}
+
+	for _, object := range document.Data {
+		seen[resourceKey(object.Type, object.ID)] = object
+	}
+
+	for _, object := range document.Included {
+		seen[resourceKey(object.Type, object.ID)] = object
+	}
+
+	return resolveLevel(document, document.Data, include, seen)
+}
+
+// resolveLevel includes each relationship in tree for the provided objects
+func resolveLevel(document *Document, objects List, tree IncludeTree, seen map[string]*Object) *Error {
+	// resolve in a stable order so that the included array is deterministic
+	names := make([]string, 0, len(tree))
+	for name := range tree {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		related := List{}
+		queued := map[string]bool{}
+
+		// ids to resolve, grouped by the type of the object that references them
+		pending := map[string][]string{}
+		resolvers := map[string]IncludeResolver{}
+
+		for _, object := range objects {
+			resolver := includeResolver(object.Type, name)
+			if resolver == nil {
+				return ParameterError(
+					fmt.Sprintf("Relationship '%s' of type '%s' cannot be included", name, object.Type),
+					IncludeParam,
+				)
+			}
+			resolvers[object.Type] = resolver
+
+			relationship, exists := object.Relationships[name]
+			if !exists {
+				continue
+			}
+
+			for _, identifier := range relationship.Data {
+				key := resourceKey(identifier.Type, identifier.ID)
+
+				if existing, exists := seen[key]; exists {
+					related = append(related, existing)
+					continue
+				}
+
+				if !queued[key] {
+					queued[key] = true
+					pending[object.Type] = append(pending[object.Type], identifier.ID)
+				}
+			}
+		}
+
+		types := make([]string, 0, len(pending))
+		for resourceType := range pending {
+			types = append(types, resourceType)
+		}
+		sort.Strings(types)
+
+		for _, resourceType := range types {
+			resolved, err := resolvers[resourceType](pending[resourceType])
+			if err != nil {
+				return err
+			}
+
+			for _, object := range resolved {
+				key := resourceKey(object.Type, object.ID)
+				if _, exists := seen[key]; exists {
+					continue
+				}
+
+				seen[key] = object
+				document.Included = append(document.Included, object)
+				related = append(related, object)
+			}
+		}
+
+		if len(tree[name]) > 0 {
+			err := resolveLevel(document, related, tree[name], seen)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// includeResolver finds the registered resolver for a type's relationship
+func includeResolver(resourceType string, relationship string) IncludeResolver {
+	resource := Registered(resourceType)
+	if resource == nil {
+		return nil
+	}
+
+	return resource.Includes[relationship]
+}
+
+// resourceKey uniquely identifies a resource by type and id
+func resourceKey(resourceType string, id string) string {
+	return resourceType + "/" + id
+}
@@ -0,0 +1,98 @@
+package jsh
+
+import (
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestInclude(t *testing.T) {
+
+	Convey("Include Tests", t, func() {
+
+		Convey("->ParseInclude()", func() {
+
+			Convey("should parse nested relationship paths", func() {
+				req, err := http.NewRequest("GET", "/articles?include=author.comments,author,tags", nil)
+				So(err, ShouldBeNil)
+
+				tree, parseErr := ParseInclude(req)
+				So(parseErr, ShouldBeNil)
+				So(tree, ShouldResemble, IncludeTree{
+					"author": IncludeTree{"comments": IncludeTree{}},
+					"tags":   IncludeTree{},
+				})
+			})
+
+			Convey("should reject empty path segments", func() {
+				req, err := http.NewRequest("GET", "/articles?include=author..comments", nil)
+				So(err, ShouldBeNil)
+
+				_, parseErr := ParseInclude(req)
+				So(parseErr, ShouldNotBeNil)
+				So(parseErr.Source.Parameter, ShouldEqual, IncludeParam)
+			})
+		})
+
+		Convey("->ResolveIncludes()", func() {
+			calls := map[string][]string{}
+
+			Register(&Resource{
+				Type: "articles",
+				Includes: map[string]IncludeResolver{
+					"author": func(ids []string) (List, *Error) {
+						calls["author"] = append(calls["author"], ids...)
+						list := List{}
+						for _, id := range ids {
+							user := &Object{ID: id, Type: "users"}
+							user.AddToManyRelationship("comments", &ResourceIdentifier{Type: "comments", ID: "c" + id})
+							list = append(list, user)
+						}
+						return list, nil
+					},
+				},
+			})
+			Register(&Resource{
+				Type: "users",
+				Includes: map[string]IncludeResolver{
+					"comments": func(ids []string) (List, *Error) {
+						calls["comments"] = append(calls["comments"], ids...)
+						list := List{}
+						for _, id := range ids {
+							list = append(list, &Object{ID: id, Type: "comments"})
+						}
+						return list, nil
+					},
+				},
+			})
+			Reset(func() {
+				Unregister("articles")
+				Unregister("users")
+			})
+
+			first := &Object{ID: "1", Type: "articles"}
+			first.AddToOneRelationship("author", "users", "9")
+			second := &Object{ID: "2", Type: "articles"}
+			second.AddToOneRelationship("author", "users", "9")
+
+			doc := Build(List{first, second})
+
+			Convey("should resolve a nested tree and deduplicate", func() {
+				err := ResolveIncludes(doc, IncludeTree{"author": IncludeTree{"comments": IncludeTree{}}})
+				So(err, ShouldBeNil)
+				So(calls["author"], ShouldResemble, []string{"9"})
+				So(calls["comments"], ShouldResemble, []string{"c9"})
+				So(len(doc.Included), ShouldEqual, 2)
+				So(doc.Included[0].Type, ShouldEqual, "users")
+				So(doc.Included[1].Type, ShouldEqual, "comments")
+			})
+
+			Convey("should reject relationships without a resolver", func() {
+				err := ResolveIncludes(doc, IncludeTree{"tags": IncludeTree{}})
+				So(err, ShouldNotBeNil)
+				So(err.Status, ShouldEqual, http.StatusBadRequest)
+			})
+		})
+	})
+}
@@ -0,0 +1,155 @@
+package jsh
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// JobStatus is the state of a long-running operation tracked by a Job.
+type JobStatus string
+
+const (
+	// JobPending jobs are waiting to start
+	JobPending JobStatus = "pending"
+	// JobRunning jobs have started but not finished
+	JobRunning JobStatus = "running"
+	// JobCompleted jobs have finished successfully
+	JobCompleted JobStatus = "completed"
+	// JobFailed jobs have finished unsuccessfully, see Job.Error
+	JobFailed JobStatus = "failed"
+)
+
+// JobType is the resource type of Job objects, and the one clients recognize
+// them by.
+var JobType = "jobs"
+
+/*
+Job is the status resource of a long-running operation, such as a POST that
+takes too long to answer with the created resource. The request is answered with
+a 202 Accepted pointing at the job, see SendAccepted, which clients poll until it
+completes, see SendJob:
+
+	job := &jsh.Job{ID: queue.Enqueue(object), Status: jsh.JobPending, RetryAfter: 5 * time.Second}
+	jsh.SendAccepted(w, r, job)
+
+Jobs are sent as resources of JobType, with their status, progress, and error
+as attributes, and the resource they produced as their "result" relationship.
+*/
+type Job struct {
+	// ID identifies the job, its URL is that of a resource of JobType
+	ID string `json:"-"`
+	// Status is the job's state
+	Status JobStatus `json:"status"`
+	// Progress is the completed fraction of the job, from 0 to 1, optional
+	Progress float64 `json:"progress,omitempty"`
+	// Error describes why a failed job failed
+	Error string `json:"error,omitempty"`
+	// Result identifies the resource a completed job produced, if any
+	Result *ResourceIdentifier `json:"-"`
+	// RetryAfter is sent as the Retry-After header of unfinished jobs, to
+	// tell clients how long to wait before polling again. Zero sends none.
+	RetryAfter time.Duration `json:"-"`
+}
+
+// Done returns true if the job has completed or failed.
+func (j *Job) Done() bool {
+	return j.Status == JobCompleted || j.Status == JobFailed
+}
+
+// Object returns the job as a resource of JobType, linking to itself and to
+// its result.
+func (j *Job) Object() (*Object, *Error) {
+	object, err := NewObject(j.ID, JobType, j)
+	if err != nil {
+		return nil, err
+	}
+
+	object.Links["self"] = &Link{HREF: j.url()}
+	if j.Result != nil {
+		err = object.AddToOneRelationship("result", j.Result.Type, j.Result.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return object, nil
+}
+
+// JobFromObject decodes a job sent by SendAccepted or SendJob, returning an
+// error if the object isn't of JobType.
+func JobFromObject(object *Object) (*Job, *Error) {
+	job := &Job{ID: object.ID}
+	if errs := object.Unmarshal(JobType, job); errs != nil {
+		return nil, errs[0]
+	}
+
+	if result := object.Relationships["result"]; result != nil && len(result.Data) > 0 {
+		job.Result = result.Data[0]
+	}
+
+	return job, nil
+}
+
+/*
+SendAccepted answers a request with a 202 Accepted and the job processing it,
+its URL in the Content-Location header, so that the client can poll it. Since
+the specification only allows a 202 for POST and PATCH requests, other requests
+get a 500 error.
+*/
+func SendAccepted(w http.ResponseWriter, r *http.Request, job *Job) *Error {
+	if r.Method != "POST" && r.Method != "PATCH" {
+		err := ISE(fmt.Sprintf("%s requests can't be answered with a 202 Accepted", r.Method))
+		Send(w, r, err)
+		return err
+	}
+
+	object, err := job.Object()
+	if err != nil {
+		return Send(w, r, err)
+	}
+	object.Status = http.StatusAccepted
+
+	w.Header().Set("Content-Location", job.url())
+	job.setRetryAfter(w)
+
+	return Send(w, r, object)
+}
+
+/*
+SendJob answers a poll of the job's status resource. Jobs that completed with a
+result are answered with a 303 See Other to the result's URL, which HTTP clients
+follow, so that pollers end up with the resource they were waiting for. Other
+jobs are sent as they are, with a Retry-After header until they are done.
+*/
+func SendJob(w http.ResponseWriter, r *http.Request, job *Job) *Error {
+	if job.Status == JobCompleted && job.Result != nil {
+		w.Header().Set("Location", urlBuilder().Resource(job.Result.Type, job.Result.ID))
+		w.WriteHeader(http.StatusSeeOther)
+		return nil
+	}
+
+	object, err := job.Object()
+	if err != nil {
+		return Send(w, r, err)
+	}
+
+	job.setRetryAfter(w)
+	return Send(w, r, object)
+}
+
+// url returns the URL of the job's status resource
+func (j *Job) url() string {
+	return urlBuilder().Resource(JobType, j.ID)
+}
+
+// setRetryAfter tells clients when to poll an unfinished job again
+func (j *Job) setRetryAfter(w http.ResponseWriter) {
+	if j.Done() || j.RetryAfter <= 0 {
+		return
+	}
+
+	seconds := int((j.RetryAfter + time.Second - 1) / time.Second)
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+}
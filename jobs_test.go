@@ -0,0 +1,125 @@
+package jsh
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestJobs(t *testing.T) {
+
+	Convey("Job Tests", t, func() {
+
+		job := &Job{ID: "1", Status: JobPending, RetryAfter: 1500 * time.Millisecond}
+
+		request := func(method string) *http.Request {
+			request, _ := http.NewRequest(method, "/widgets", bytes.NewReader(nil))
+			return request
+		}
+
+		sent := func(writer *httptest.ResponseRecorder) *Object {
+			document := &Document{}
+			So(json.Unmarshal(writer.Body.Bytes(), document), ShouldBeNil)
+			So(len(document.Data), ShouldEqual, 1)
+			return document.Data[0]
+		}
+
+		Convey("->SendAccepted()", func() {
+
+			Convey("should answer with a 202 pointing at the job", func() {
+				writer := httptest.NewRecorder()
+				So(SendAccepted(writer, request("POST"), job), ShouldBeNil)
+				So(writer.Code, ShouldEqual, http.StatusAccepted)
+				So(writer.Header().Get("Content-Location"), ShouldEqual, "/jobs/1")
+				So(writer.Header().Get("Retry-After"), ShouldEqual, "2")
+				So(writer.Header().Get("Location"), ShouldBeEmpty)
+
+				object := sent(writer)
+				So(object.Type, ShouldEqual, JobType)
+				So(object.Links["self"].HREF, ShouldEqual, "/jobs/1")
+
+				parsed, err := JobFromObject(object)
+				So(err, ShouldBeNil)
+				So(parsed.ID, ShouldEqual, "1")
+				So(parsed.Status, ShouldEqual, JobPending)
+			})
+
+			Convey("should reject methods that can't be accepted", func() {
+				writer := httptest.NewRecorder()
+				So(SendAccepted(writer, request("GET"), job), ShouldNotBeNil)
+				So(writer.Code, ShouldEqual, http.StatusInternalServerError)
+			})
+		})
+
+		Convey("->SendJob()", func() {
+
+			Convey("should send unfinished jobs with a Retry-After", func() {
+				job.Status = JobRunning
+				job.Progress = 0.5
+
+				writer := httptest.NewRecorder()
+				So(SendJob(writer, request("GET"), job), ShouldBeNil)
+				So(writer.Code, ShouldEqual, http.StatusOK)
+				So(writer.Header().Get("Retry-After"), ShouldEqual, "2")
+
+				parsed, err := JobFromObject(sent(writer))
+				So(err, ShouldBeNil)
+				So(parsed.Status, ShouldEqual, JobRunning)
+				So(parsed.Progress, ShouldEqual, 0.5)
+			})
+
+			Convey("should send failed jobs with their error", func() {
+				job.Status = JobFailed
+				job.Error = "out of gears"
+
+				writer := httptest.NewRecorder()
+				So(SendJob(writer, request("GET"), job), ShouldBeNil)
+				So(writer.Code, ShouldEqual, http.StatusOK)
+				So(writer.Header().Get("Retry-After"), ShouldBeEmpty)
+
+				parsed, err := JobFromObject(sent(writer))
+				So(err, ShouldBeNil)
+				So(parsed.Error, ShouldEqual, "out of gears")
+			})
+
+			Convey("should redirect completed jobs to their result", func() {
+				job.Status = JobCompleted
+				job.Result = &ResourceIdentifier{Type: "widgets", ID: "9"}
+
+				writer := httptest.NewRecorder()
+				So(SendJob(writer, request("GET"), job), ShouldBeNil)
+				So(writer.Code, ShouldEqual, http.StatusSeeOther)
+				So(writer.Header().Get("Location"), ShouldEqual, "/widgets/9")
+			})
+		})
+
+		Convey("->JobFromObject()", func() {
+
+			Convey("should decode the result relationship", func() {
+				job.Status = JobCompleted
+				job.Result = &ResourceIdentifier{Type: "widgets", ID: "9"}
+
+				object, err := job.Object()
+				So(err, ShouldBeNil)
+
+				parsed, err := JobFromObject(object)
+				So(err, ShouldBeNil)
+				So(parsed.Result.ID, ShouldEqual, "9")
+				So(parsed.Done(), ShouldBeTrue)
+			})
+
+			Convey("should reject other types", func() {
+				object, err := NewObject("1", "widgets", map[string]string{"name": "gear"})
+				So(err, ShouldBeNil)
+
+				_, err = JobFromObject(object)
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
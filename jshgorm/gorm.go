@@ -0,0 +1,800 @@
+//go:build gorm
+// +build gorm
+
+package jshgorm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/derekdowling/go-json-spec-handler"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+/*
+Resource serves a GORM model as a JSON API resource. Its handlers are
+jsh.ResourceHandlers, which Handle registers at the conventional routes, or
+which can be adapted to other routers with jshrouter.
+*/
+type Resource struct {
+	DB *gorm.DB
+	// Type is the resource type, the model's table name
+	Type string
+	// Filters lists the filter operators allowed for each attribute, see
+	// jsh.ParseFilter. Any attribute can be filtered with any operator if nil.
+	Filters map[string][]jsh.FilterOperator
+
+	model *model
+}
+
+// New reflects a model's GORM schema, such as &Article{}, into a Resource.
+func New(db *gorm.DB, value interface{}) (*Resource, error) {
+	statement := &gorm.Statement{DB: db}
+	err := statement.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if statement.Schema.PrioritizedPrimaryField == nil {
+		return nil, fmt.Errorf("jshgorm: %s has no primary key", statement.Schema.Name)
+	}
+
+	m := newModel(statement.Schema)
+	return &Resource{DB: db, Type: m.Type, model: m}, nil
+}
+
+// Declaration declares the resource's relationships and relationship links
+// with jsh.
+func (res *Resource) Declaration() *jsh.Resource {
+	return &jsh.Resource{
+		Type:              res.Type,
+		RelationshipLinks: true,
+		Relationships:     res.model.declaration(),
+	}
+}
+
+// Register registers the resource's Declaration.
+func (res *Resource) Register() {
+	jsh.Register(res.Declaration())
+}
+
+/*
+Handle registers the resource's handlers with mux, at /TYPE, /TYPE/{id},
+/TYPE/{id}/{relationship}, and /TYPE/{id}/relationships/{relationship}.
+*/
+func (res *Resource) Handle(mux *http.ServeMux) {
+	collection := "/" + jsh.ResourcePath(res.Type)
+	member := collection + "/{" + jsh.IDParam + "}"
+	related := member + "/{" + jsh.RelationshipParam + "}"
+	relationship := member + "/relationships/{" + jsh.RelationshipParam + "}"
+
+	mux.Handle("GET "+collection, jsh.ResourceHandler(res.List))
+	mux.Handle("POST "+collection, jsh.ResourceHandler(res.Create))
+	mux.Handle("GET "+member, jsh.ResourceHandler(res.Fetch))
+	mux.Handle("PATCH "+member, jsh.ResourceHandler(res.Update))
+	mux.Handle("DELETE "+member, jsh.ResourceHandler(res.Delete))
+	mux.Handle("GET "+related, jsh.ResourceHandler(res.Related))
+	mux.Handle("GET "+relationship, jsh.ResourceHandler(res.Relationship))
+	mux.Handle("PATCH "+relationship, jsh.ResourceHandler(res.UpdateRelationship))
+	mux.Handle("POST "+relationship, jsh.ResourceHandler(res.UpdateRelationship))
+	mux.Handle("DELETE "+relationship, jsh.ResourceHandler(res.UpdateRelationship))
+}
+
+/*
+List sends a page of models, sorted by the sort query parameter and filtered by
+the filter query parameters. Pages are paginated by offset cursors, and the
+associations named by the include query parameter are preloaded and included.
+*/
+func (res *Resource) List(w http.ResponseWriter, r *http.Request, params jsh.RouteParams) {
+	tx, include, err := res.preload(r)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	sort, err := jsh.ParseSort(r, res.model.attributeNames()...)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	for _, field := range sort {
+		column, _ := res.model.column(field.Attribute)
+		tx = tx.Order(clause.OrderByColumn{
+			Column: clause.Column{Name: column},
+			Desc:   field.Direction == jsh.Descending,
+		})
+	}
+
+	tx, err = res.filter(r, tx)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	page, err := jsh.ParseCursorPage(r, nil)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	offset := 0
+	if page.Position != "" {
+		offset, _ = strconv.Atoi(page.Position)
+	}
+
+	// one extra model is fetched to find out whether there's a next page
+	values := reflect.New(reflect.SliceOf(reflect.PtrTo(res.model.schema.ModelType)))
+	dbErr := tx.Offset(offset).Limit(page.Limit + 1).Find(values.Interface()).Error
+	if dbErr != nil {
+		jsh.Send(w, r, res.dbError(dbErr, ""))
+		return
+	}
+
+	models := values.Elem()
+	next := ""
+	if models.Len() > page.Limit {
+		models = models.Slice(0, page.Limit)
+		next = strconv.Itoa(offset + page.Limit)
+	}
+
+	list := []reflect.Value{}
+	for i := 0; i < models.Len(); i++ {
+		list = append(list, models.Index(i))
+	}
+
+	document, err := res.document(list, include, jsh.ListMode)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	document.Links, err = page.Links(next, "")
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	jsh.SendDocument(w, r, document)
+}
+
+// Fetch sends the model identified by the route's ID, or a 404, including the
+// associations named by the include query parameter.
+func (res *Resource) Fetch(w http.ResponseWriter, r *http.Request, params jsh.RouteParams) {
+	tx, include, err := res.preload(r)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	value, err := res.find(tx, params.ID)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	document, err := res.document([]reflect.Value{value}, include, jsh.ObjectMode)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	jsh.SendDocument(w, r, document)
+}
+
+/*
+Create creates a model from a POSTed object and sends it with a 201. The
+object's relationships set the model's associations. Models are given IDs by
+the database unless the client generated one, which the ParseOptions must
+allow.
+*/
+func (res *Resource) Create(w http.ResponseWriter, r *http.Request, params jsh.RouteParams) {
+	object, err := jsh.ParseObjectFor(r, res.Type, "")
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+	if object == nil {
+		jsh.Send(w, r, jsh.InputError("Missing primary data", "data"))
+		return
+	}
+
+	value := res.model.new()
+	if object.ID != "" {
+		id, err := res.model.parseID(object.ID)
+		if err != nil {
+			jsh.Send(w, r, jsh.InputError(fmt.Sprintf("Invalid ID '%s'", object.ID), "id"))
+			return
+		}
+		setField(value, res.model.schema.PrioritizedPrimaryField, id)
+	}
+
+	_, err = res.assign(value, object)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	err = res.transaction(r, func(tx *gorm.DB) *jsh.Error {
+		dbErr := tx.Omit(clause.Associations).Create(value.Interface()).Error
+		if dbErr != nil {
+			return res.dbError(dbErr, "")
+		}
+
+		return res.associate(tx, value, object)
+	})
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	created, err := res.find(res.DB.WithContext(r.Context()), res.model.id(value))
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	document, err := res.document([]reflect.Value{created}, nil, jsh.ObjectMode)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	document.Status = http.StatusCreated
+	jsh.SendDocument(w, r, document)
+}
+
+// Update writes the attributes and relationships of a PATCHed object to its
+// model, and sends the updated resource.
+func (res *Resource) Update(w http.ResponseWriter, r *http.Request, params jsh.RouteParams) {
+	object, err := jsh.ParseObjectFor(r, res.Type, params.ID)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+	if object == nil {
+		jsh.Send(w, r, jsh.InputError("Missing primary data", "data"))
+		return
+	}
+
+	err = res.transaction(r, func(tx *gorm.DB) *jsh.Error {
+		value, err := res.find(tx, object.ID)
+		if err != nil {
+			return err
+		}
+
+		columns, err := res.assign(value, object)
+		if err != nil {
+			return err
+		}
+
+		if len(columns) > 0 {
+			dbErr := tx.Model(value.Interface()).Select(columns).Updates(value.Interface()).Error
+			if dbErr != nil {
+				return res.dbError(dbErr, object.ID)
+			}
+		}
+
+		return res.associate(tx, value, object)
+	})
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	updated, err := res.find(res.DB.WithContext(r.Context()), object.ID)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	document, err := res.document([]reflect.Value{updated}, nil, jsh.ObjectMode)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	jsh.SendDocument(w, r, document)
+}
+
+// Delete deletes the model identified by the route's ID, sending a 204, or a
+// 404 if there is no such model.
+func (res *Resource) Delete(w http.ResponseWriter, r *http.Request, params jsh.RouteParams) {
+	id, err := res.model.parseID(params.ID)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	result := res.DB.WithContext(r.Context()).Delete(res.model.new().Interface(), id)
+	if result.Error != nil {
+		jsh.Send(w, r, res.dbError(result.Error, params.ID))
+		return
+	}
+
+	if result.RowsAffected == 0 {
+		jsh.Send(w, r, jsh.NotFound(res.Type, params.ID))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+/*
+Related sends the resources related to a model by one of its relationships, a
+single resource or null for to-one relationships, and a list for to-many
+relationships. The include query parameter applies to the related resources.
+*/
+func (res *Resource) Related(w http.ResponseWriter, r *http.Request, params jsh.RouteParams) {
+	relationship, err := res.relationship(params.Relationship)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+	related := newModel(relationship.FieldSchema)
+
+	include, err := jsh.ParseInclude(r)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	preloads, err := related.preloads(include, relationship.Name+".")
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	tx := res.DB.WithContext(r.Context()).Preload(relationship.Name)
+	for _, path := range preloads {
+		tx = tx.Preload(path)
+	}
+
+	value, err := res.find(tx, params.ID)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	loaded := fieldValue(value, relationship.Field)
+	values := []reflect.Value{}
+	document := jsh.New()
+	if loaded.Kind() == reflect.Slice {
+		document.Mode = jsh.ListMode
+		document.Data = jsh.List{}
+		for i := 0; i < loaded.Len(); i++ {
+			values = append(values, loaded.Index(i))
+		}
+	} else if loaded = reflect.Indirect(loaded); loaded.IsValid() && !loaded.IsZero() {
+		values = append(values, loaded)
+	}
+
+	objects := map[string]*jsh.Object{}
+	for _, relatedValue := range values {
+		object, err := related.object(relatedValue)
+		if err == nil {
+			err = document.AddObject(object)
+		}
+		if err == nil {
+			objects[object.Type+"/"+object.ID] = object
+			err = related.included(relatedValue, include, objects, &document.Included)
+		}
+		if err != nil {
+			jsh.Send(w, r, err)
+			return
+		}
+	}
+
+	document.Status = http.StatusOK
+	jsh.SendDocument(w, r, document)
+}
+
+// Relationship sends the resource linkage of one of a model's relationships.
+func (res *Resource) Relationship(w http.ResponseWriter, r *http.Request, params jsh.RouteParams) {
+	relationship, err := res.relationship(params.Relationship)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	res.sendLinkage(w, r, relationship, params)
+}
+
+/*
+UpdateRelationship updates one of a model's relationships from a resource
+linkage document, replacing it for PATCH requests, and adding or removing the
+listed members of to-many relationships for POST and DELETE requests. The
+updated linkage is sent in response.
+*/
+func (res *Resource) UpdateRelationship(w http.ResponseWriter, r *http.Request, params jsh.RouteParams) {
+	relationship, err := res.relationship(params.Relationship)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	toMany := relationship.Type == schema.HasMany || relationship.Type == schema.Many2Many
+	if r.Method != "PATCH" && !toMany {
+		jsh.Send(w, r, jsh.SpecificationError(
+			fmt.Sprintf("Only to-many relationships support '%s' requests", r.Method),
+		))
+		return
+	}
+
+	update, err := jsh.ParseRelationship(r)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	err = res.transaction(r, func(tx *gorm.DB) *jsh.Error {
+		value, err := res.find(tx, params.ID)
+		if err != nil {
+			return err
+		}
+
+		linked, err := res.linked(relationship, update.Data)
+		if err != nil {
+			return err
+		}
+
+		if relationship.Type == schema.BelongsTo {
+			columns := res.setForeignKeys(value, relationship, linked)
+			dbErr := tx.Model(value.Interface()).Select(columns).Updates(value.Interface()).Error
+			return res.dbError(dbErr, params.ID)
+		}
+
+		association := tx.Model(value.Interface()).Association(relationship.Name)
+		var dbErr error
+		switch {
+		case r.Method == "POST":
+			dbErr = association.Append(linked...)
+		case r.Method == "DELETE":
+			dbErr = association.Delete(linked...)
+		case len(linked) == 0:
+			dbErr = association.Clear()
+		default:
+			dbErr = association.Replace(linked...)
+		}
+
+		return res.dbError(dbErr, params.ID)
+	})
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	res.sendLinkage(w, r, relationship, params)
+}
+
+// sendLinkage sends the current linkage of a relationship
+func (res *Resource) sendLinkage(w http.ResponseWriter, r *http.Request, relationship *schema.Relationship, params jsh.RouteParams) {
+	value, err := res.find(res.DB.WithContext(r.Context()).Preload(relationship.Name), params.ID)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	object, err := res.model.object(value)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	body, jsonErr := json.Marshal(object.Relationships[params.Relationship])
+	if jsonErr != nil {
+		jsh.Send(w, r, jsh.ISE(jsonErr.Error()))
+		return
+	}
+
+	w.Header().Set("Content-Type", jsh.ContentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+// preload starts a query preloading the associations named by the request's
+// include query parameter
+func (res *Resource) preload(r *http.Request) (*gorm.DB, jsh.IncludeTree, *jsh.Error) {
+	include, err := jsh.ParseInclude(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	preloads, err := res.model.preloads(include, "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tx := res.DB.WithContext(r.Context())
+	for _, path := range preloads {
+		tx = tx.Preload(path)
+	}
+
+	return tx, include, nil
+}
+
+// filter adds the conditions of the request's filter query parameters to a
+// query
+func (res *Resource) filter(r *http.Request, tx *gorm.DB) (*gorm.DB, *jsh.Error) {
+	filters := res.Filters
+	if filters == nil {
+		filters = map[string][]jsh.FilterOperator{"id": nil}
+		for _, attribute := range res.model.attributeNames() {
+			filters[attribute] = nil
+		}
+	}
+
+	filter, err := jsh.ParseFilter(r, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, condition := range filter {
+		column, exists := res.model.column(condition.Attribute)
+		if !exists {
+			return nil, jsh.ParameterError(
+				fmt.Sprintf("Resources of type '%s' have no attribute '%s'", res.Type, condition.Attribute),
+				condition.Parameter(),
+			)
+		}
+		column = tx.Statement.Quote(column)
+
+		clauses := []string{}
+		args := []interface{}{}
+		for _, value := range condition.Values {
+			switch condition.Operator {
+			case jsh.Like:
+				clauses = append(clauses, "LOWER("+column+") LIKE ? ESCAPE '!'")
+				value = "%" + likeEscaper.Replace(strings.ToLower(value)) + "%"
+			case jsh.NotEqual:
+				clauses = append(clauses, column+" <> ?")
+			default:
+				clauses = append(clauses, column+" "+sqlOperators[condition.Operator]+" ?")
+			}
+			args = append(args, value)
+		}
+
+		// a resource must differ from every value, and match any other condition
+		joiner := " OR "
+		if condition.Operator == jsh.NotEqual {
+			joiner = " AND "
+		}
+		tx = tx.Where("("+strings.Join(clauses, joiner)+")", args...)
+	}
+
+	return tx, nil
+}
+
+// likeEscaper escapes the wildcards of LIKE patterns, and the escape character
+// itself, which is not a backslash since MySQL would need it escaped in turn
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// sqlOperators maps filter operators to their SQL comparison
+var sqlOperators = map[jsh.FilterOperator]string{
+	jsh.Equal:          "=",
+	jsh.GreaterThan:    ">",
+	jsh.GreaterOrEqual: ">=",
+	jsh.LessThan:       "<",
+	jsh.LessOrEqual:    "<=",
+}
+
+// find loads the model with the given ID
+func (res *Resource) find(tx *gorm.DB, id string) (reflect.Value, *jsh.Error) {
+	key, err := res.model.parseID(id)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	value := res.model.new()
+	dbErr := tx.First(value.Interface(), key).Error
+	if dbErr != nil {
+		return reflect.Value{}, res.dbError(dbErr, id)
+	}
+
+	return value, nil
+}
+
+/*
+assign writes an object's attributes and to-one relationships onto a model,
+returning the columns it set. Attributes the model doesn't have and
+relationships it doesn't declare are rejected.
+*/
+func (res *Resource) assign(value reflect.Value, object *jsh.Object) ([]string, *jsh.Error) {
+	columns := []string{}
+
+	if object.HasAttributes() {
+		raw := map[string]json.RawMessage{}
+		jsonErr := json.Unmarshal(object.Attributes, &raw)
+		if jsonErr != nil {
+			return nil, jsh.ISE(fmt.Sprintf("Unable to decode attributes: %s", jsonErr.Error()))
+		}
+
+		for attribute, encoded := range raw {
+			field, exists := res.model.attributes[attribute]
+			if !exists {
+				return nil, jsh.InputError(fmt.Sprintf("Resources of type '%s' have no attribute '%s'", res.Type, attribute), attribute)
+			}
+
+			jsonErr = json.Unmarshal(encoded, fieldValue(value, field).Addr().Interface())
+			if jsonErr != nil {
+				return nil, jsh.InputError(fmt.Sprintf("Invalid value for '%s': %s", attribute, jsonErr.Error()), attribute)
+			}
+			columns = append(columns, field.DBName)
+		}
+	}
+
+	for name, update := range object.Relationships {
+		relationship, err := res.relationship(name)
+		if err != nil {
+			return nil, jsh.RelationshipError(err.Detail, name)
+		}
+
+		if relationship.Type != schema.BelongsTo || update.State() == jsh.LinkageAbsent {
+			continue
+		}
+
+		linked, err := res.linked(relationship, update.Data)
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, res.setForeignKeys(value, relationship, linked)...)
+	}
+
+	return columns, nil
+}
+
+// associate writes an object's relationships, other than belongs-to, to the
+// associations of a stored model
+func (res *Resource) associate(tx *gorm.DB, value reflect.Value, object *jsh.Object) *jsh.Error {
+	for name, update := range object.Relationships {
+		relationship := res.model.relationships[name]
+		if relationship.Type == schema.BelongsTo || update.State() == jsh.LinkageAbsent {
+			continue
+		}
+
+		linked, err := res.linked(relationship, update.Data)
+		if err != nil {
+			return err
+		}
+
+		association := tx.Model(value.Interface()).Association(relationship.Name)
+		var dbErr error
+		if len(linked) == 0 {
+			dbErr = association.Clear()
+		} else {
+			dbErr = association.Replace(linked...)
+		}
+		if dbErr != nil {
+			return res.dbError(dbErr, "")
+		}
+	}
+
+	return nil
+}
+
+// setForeignKeys points a belongs-to association at the linked model, or
+// clears it, returning the foreign key columns
+func (res *Resource) setForeignKeys(value reflect.Value, relationship *schema.Relationship, linked []interface{}) []string {
+	columns := []string{}
+	for _, reference := range relationship.References {
+		var key interface{}
+		if len(linked) > 0 {
+			key = fieldValue(reflect.ValueOf(linked[0]), reference.PrimaryKey).Interface()
+		}
+
+		setField(value, reference.ForeignKey, key)
+		columns = append(columns, reference.ForeignKey.DBName)
+	}
+
+	return columns
+}
+
+// linked converts resource linkage to models of the related type carrying only
+// their primary keys, returning a 409 for linkage to other types
+func (res *Resource) linked(relationship *schema.Relationship, linkage jsh.ResourceLinkage) ([]interface{}, *jsh.Error) {
+	related := newModel(relationship.FieldSchema)
+
+	linked := []interface{}{}
+	for _, identifier := range linkage {
+		if identifier.Type != related.Type {
+			return nil, jsh.Conflict(fmt.Sprintf(
+				"Relationship '%s' links to '%s' resources, not '%s'",
+				memberName(relationship.Field), related.Type, identifier.Type,
+			))
+		}
+
+		id, err := related.parseID(identifier.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		value := related.new()
+		setField(value, related.schema.PrioritizedPrimaryField, id)
+		linked = append(linked, value.Interface())
+	}
+
+	return linked, nil
+}
+
+// relationship returns the association storing a relationship, or a 404
+func (res *Resource) relationship(name string) (*schema.Relationship, *jsh.Error) {
+	relationship, exists := res.model.relationships[name]
+	if !exists {
+		return nil, jsh.NotFound(res.Type+" relationship", name)
+	}
+
+	return relationship, nil
+}
+
+// document builds a document of loaded model values, including the
+// associations of the include tree
+func (res *Resource) document(values []reflect.Value, include jsh.IncludeTree, mode jsh.DocumentMode) (*jsh.Document, *jsh.Error) {
+	document := jsh.New()
+	document.Mode = mode
+	if mode == jsh.ListMode {
+		document.Data = jsh.List{}
+	}
+
+	objects := map[string]*jsh.Object{}
+	for _, value := range values {
+		object, err := res.model.object(value)
+		if err != nil {
+			return nil, err
+		}
+
+		err = document.AddObject(object)
+		if err != nil {
+			return nil, err
+		}
+		objects[object.Type+"/"+object.ID] = object
+	}
+
+	for _, value := range values {
+		err := res.model.included(value, include, objects, &document.Included)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	document.Status = http.StatusOK
+	return document, nil
+}
+
+// transaction runs fn in a database transaction, rolling back if it fails
+func (res *Resource) transaction(r *http.Request, fn func(tx *gorm.DB) *jsh.Error) *jsh.Error {
+	var failure *jsh.Error
+	dbErr := res.DB.WithContext(r.Context()).Transaction(func(tx *gorm.DB) error {
+		failure = fn(tx)
+		if failure != nil {
+			return failure
+		}
+
+		return nil
+	})
+
+	if failure != nil {
+		return failure
+	}
+
+	return res.dbError(dbErr, "")
+}
+
+// dbError converts a GORM error, a 404 for missing records
+func (res *Resource) dbError(err error, id string) *jsh.Error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return jsh.NotFound(res.Type, id)
+	}
+
+	return jsh.ISE(fmt.Sprintf("Unable to access %s: %s", res.model.schema.Table, err.Error()))
+}
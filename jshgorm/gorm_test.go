@@ -0,0 +1,285 @@
+//go:build gorm
+// +build gorm
+
+package jshgorm
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/derekdowling/go-json-spec-handler"
+	"github.com/glebarez/sqlite"
+	. "github.com/smartystreets/goconvey/convey"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type author struct {
+	ID       uint      `json:"-"`
+	Name     string    `json:"name"`
+	Articles []article `json:"articles"`
+}
+
+type article struct {
+	ID       uint   `json:"-"`
+	Title    string `json:"title"`
+	Views    int    `json:"views"`
+	AuthorID *uint
+	Author   *author `json:"author"`
+	Tags     []tag   `json:"tags" gorm:"many2many:article_tags"`
+}
+
+type tag struct {
+	ID   uint   `json:"-"`
+	Name string `json:"name"`
+}
+
+func TestResource(t *testing.T) {
+
+	Convey("GORM Resource Tests", t, func() {
+
+		db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+		So(err, ShouldBeNil)
+		sqlDB, err := db.DB()
+		So(err, ShouldBeNil)
+		// every connection would open its own in-memory database
+		sqlDB.SetMaxOpenConns(1)
+		Reset(func() { sqlDB.Close() })
+
+		So(db.AutoMigrate(&author{}, &article{}, &tag{}), ShouldBeNil)
+
+		mux := http.NewServeMux()
+		for _, value := range []interface{}{&author{}, &article{}, &tag{}} {
+			resource, err := New(db, value)
+			So(err, ShouldBeNil)
+			resource.Register()
+			resource.Handle(mux)
+		}
+		Reset(func() {
+			jsh.Unregister("authors")
+			jsh.Unregister("articles")
+			jsh.Unregister("tags")
+		})
+
+		alice := &author{Name: "Alice"}
+		So(db.Create(alice).Error, ShouldBeNil)
+		golang := &tag{Name: "go"}
+		So(db.Create(golang).Error, ShouldBeNil)
+		for i, title := range []string{"First", "Second", "Third"} {
+			So(db.Create(&article{Title: title, Views: (i + 1) * 10, AuthorID: &alice.ID}).Error, ShouldBeNil)
+		}
+
+		serve := func(method string, target string, body string) (*httptest.ResponseRecorder, *jsh.Document) {
+			var payload io.Reader
+			if body != "" {
+				payload = strings.NewReader(body)
+			}
+
+			r := httptest.NewRequest(method, target, payload)
+			r.Header.Set("Content-Type", jsh.ContentType)
+
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, r)
+
+			doc := &jsh.Document{}
+			if w.Body.Len() > 0 && !strings.Contains(target, "/relationships/") {
+				So(json.Unmarshal(w.Body.Bytes(), doc), ShouldBeNil)
+			}
+			return w, doc
+		}
+
+		Convey("->New()", func() {
+
+			Convey("should derive the resource type", func() {
+				articles, err := New(db, &article{})
+				So(err, ShouldBeNil)
+				So(articles.Type, ShouldEqual, "articles")
+
+				declaration := articles.Declaration()
+				So(declaration.Relationships["author"].Cardinality, ShouldEqual, jsh.ToOne)
+				So(declaration.Relationships["tags"].Cardinality, ShouldEqual, jsh.ToMany)
+			})
+
+			Convey("should not make foreign keys attributes", func() {
+				articles, _ := New(db, &article{})
+				So(articles.model.attributes, ShouldContainKey, "title")
+				So(articles.model.attributes, ShouldNotContainKey, "AuthorID")
+			})
+		})
+
+		Convey("->List()", func() {
+			w, doc := serve("GET", "/articles?sort=-views&page[limit]=2", "")
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(doc.Data.IDs(), ShouldResemble, []string{"3", "2"})
+			So(doc.Links.Next, ShouldNotBeNil)
+
+			Convey("should continue on the next page", func() {
+				w, doc := serve("GET", doc.Links.Next.HREF, "")
+				So(w.Code, ShouldEqual, http.StatusOK)
+				So(doc.Data.IDs(), ShouldResemble, []string{"1"})
+				So(doc.Links.Next, ShouldBeNil)
+			})
+
+			Convey("should filter", func() {
+				w, doc := serve("GET", "/articles?filter[views][gte]=20&filter[title][like]=ir", "")
+				So(w.Code, ShouldEqual, http.StatusOK)
+				So(doc.Data.IDs(), ShouldResemble, []string{"3"})
+			})
+
+			Convey("should escape LIKE wildcards", func() {
+				w, doc := serve("GET", "/articles?filter[title][like]=_", "")
+				So(w.Code, ShouldEqual, http.StatusOK)
+				So(doc.Data, ShouldBeEmpty)
+			})
+
+			Convey("should reject unknown sort attributes", func() {
+				w, _ := serve("GET", "/articles?sort=body", "")
+				So(w.Code, ShouldEqual, http.StatusBadRequest)
+			})
+		})
+
+		Convey("->Fetch()", func() {
+			w, doc := serve("GET", "/articles/1?include=author", "")
+			So(w.Code, ShouldEqual, http.StatusOK)
+
+			object := doc.First()
+			title, _ := object.AttributeString("title")
+			So(title, ShouldEqual, "First")
+			So(object.Relationships["author"].Data[0].ID, ShouldEqual, "1")
+			So(jsh.List(doc.Included).IDs(), ShouldResemble, []string{"1"})
+			So(doc.Included[0].Type, ShouldEqual, "authors")
+
+			Convey("should 404 for missing models", func() {
+				w, _ := serve("GET", "/articles/9", "")
+				So(w.Code, ShouldEqual, http.StatusNotFound)
+
+				w, _ = serve("GET", "/articles/nine", "")
+				So(w.Code, ShouldEqual, http.StatusNotFound)
+			})
+
+			Convey("should reject unknown includes", func() {
+				w, _ := serve("GET", "/articles/1?include=editor", "")
+				So(w.Code, ShouldEqual, http.StatusBadRequest)
+			})
+		})
+
+		Convey("->Create()", func() {
+			w, doc := serve("POST", "/articles", `{"data": {"type": "articles", "attributes": {"title": "Fourth"},
+				"relationships": {
+					"author": {"data": {"type": "authors", "id": "1"}},
+					"tags": {"data": [{"type": "tags", "id": "1"}]}
+				}}}`)
+			So(w.Code, ShouldEqual, http.StatusCreated)
+			So(doc.First().ID, ShouldEqual, "4")
+
+			created := &article{}
+			So(db.Preload("Tags").First(created, 4).Error, ShouldBeNil)
+			So(created.Title, ShouldEqual, "Fourth")
+			So(*created.AuthorID, ShouldEqual, alice.ID)
+			So(len(created.Tags), ShouldEqual, 1)
+
+			Convey("should reject unknown attributes", func() {
+				w, doc := serve("POST", "/articles", `{"data": {"type": "articles", "attributes": {"body": "Hi"}}}`)
+				So(w.Code, ShouldEqual, 422)
+				So(doc.Errors[0].Source.Pointer, ShouldEqual, "/data/attributes/body")
+			})
+
+			Convey("should reject linkage to other types", func() {
+				w, _ := serve("POST", "/articles", `{"data": {"type": "articles", "attributes": {"title": "Fifth"},
+					"relationships": {"author": {"data": {"type": "tags", "id": "1"}}}}}`)
+				So(w.Code, ShouldEqual, http.StatusConflict)
+			})
+		})
+
+		Convey("->Update()", func() {
+			w, doc := serve("PATCH", "/articles/1", `{"data": {"type": "articles", "id": "1", "attributes": {"views": 11},
+				"relationships": {"author": {"data": null}}}}`)
+			So(w.Code, ShouldEqual, http.StatusOK)
+
+			views, _ := doc.First().AttributeInt("views")
+			So(views, ShouldEqual, 11)
+
+			updated := &article{}
+			So(db.First(updated, 1).Error, ShouldBeNil)
+			So(updated.Title, ShouldEqual, "First")
+			So(updated.AuthorID, ShouldBeNil)
+
+			Convey("should 404 for missing models", func() {
+				w, _ := serve("PATCH", "/articles/9", `{"data": {"type": "articles", "id": "9", "attributes": {"views": 1}}}`)
+				So(w.Code, ShouldEqual, http.StatusNotFound)
+			})
+		})
+
+		Convey("->Delete()", func() {
+			w, _ := serve("DELETE", "/articles/3", "")
+			So(w.Code, ShouldEqual, http.StatusNoContent)
+
+			var count int64
+			db.Model(&article{}).Count(&count)
+			So(count, ShouldEqual, 2)
+
+			Convey("should 404 for missing models", func() {
+				w, _ := serve("DELETE", "/articles/3", "")
+				So(w.Code, ShouldEqual, http.StatusNotFound)
+			})
+		})
+
+		Convey("->Related()", func() {
+			w, doc := serve("GET", "/authors/1/articles", "")
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(doc.Data.IDs(), ShouldResemble, []string{"1", "2", "3"})
+
+			Convey("should send null for empty to-one relationships", func() {
+				So(db.Model(&article{}).Where("id = ?", 2).Update("author_id", nil).Error, ShouldBeNil)
+
+				w, _ := serve("GET", "/articles/2/author", "")
+				So(w.Code, ShouldEqual, http.StatusOK)
+				So(w.Body.String(), ShouldContainSubstring, `"data":null`)
+			})
+		})
+
+		Convey("->UpdateRelationship()", func() {
+			linkage := func(target string) jsh.ResourceLinkage {
+				w, _ := serve("GET", target, "")
+				So(w.Code, ShouldEqual, http.StatusOK)
+
+				relationship := &jsh.Relationship{}
+				So(json.Unmarshal(w.Body.Bytes(), relationship), ShouldBeNil)
+				return relationship.Data
+			}
+
+			w, _ := serve("POST", "/articles/1/relationships/tags", `{"data": [{"type": "tags", "id": "1"}]}`)
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(len(linkage("/articles/1/relationships/tags")), ShouldEqual, 1)
+
+			Convey("should remove members", func() {
+				w, _ := serve("DELETE", "/articles/1/relationships/tags", `{"data": [{"type": "tags", "id": "1"}]}`)
+				So(w.Code, ShouldEqual, http.StatusOK)
+				So(linkage("/articles/1/relationships/tags"), ShouldBeEmpty)
+			})
+
+			Convey("should replace to-one linkage", func() {
+				bob := &author{Name: "Bob"}
+				So(db.Create(bob).Error, ShouldBeNil)
+
+				w, _ := serve("PATCH", "/articles/1/relationships/author", `{"data": {"type": "authors", "id": "2"}}`)
+				So(w.Code, ShouldEqual, http.StatusOK)
+				So(linkage("/articles/1/relationships/author")[0].ID, ShouldEqual, "2")
+			})
+
+			Convey("should only POST to to-many relationships", func() {
+				w, _ := serve("POST", "/articles/1/relationships/author", `{"data": {"type": "authors", "id": "1"}}`)
+				So(w.Code, ShouldEqual, http.StatusNotAcceptable)
+			})
+
+			Convey("should 404 for unknown relationships", func() {
+				w, _ := serve("GET", "/articles/1/relationships/editor", "")
+				So(w.Code, ShouldEqual, http.StatusNotFound)
+			})
+		})
+	})
+}
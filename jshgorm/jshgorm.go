@@ -0,0 +1,27 @@
+/*
+Package jshgorm serves GORM models as JSON API resources. It is built with the
+gorm tag, once gorm.io/gorm is vendored, so services without GORM never pull in
+the dependency:
+
+	go build -tags gorm
+
+Its tests run against an in-memory SQLite database, and need
+github.com/glebarez/sqlite vendored as well.
+
+A Resource reflects a model's GORM schema to derive its type, from the table
+name, its attributes, from the fields' json tags, and its relationships, from
+its belongs-to, has-one, has-many, and many-to-many associations:
+
+	articles, err := jshgorm.New(db, &Article{})
+	if err != nil {
+		log.Fatal(err)
+	}
+	articles.Register()
+	articles.Handle(mux)
+
+The resource's handlers create, fetch, list, update, and delete models, serve
+related resources and relationship linkage, and update relationships. The
+include query parameter preloads associations, which are sent as included
+resources.
+*/
+package jshgorm
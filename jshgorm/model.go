@@ -0,0 +1,327 @@
+//go:build gorm
+// +build gorm
+
+package jshgorm
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/derekdowling/go-json-spec-handler"
+	"gorm.io/gorm/schema"
+)
+
+// model maps a GORM schema to a JSON API resource type
+type model struct {
+	Type   string
+	schema *schema.Schema
+	// attributes maps attribute names to the fields storing them
+	attributes map[string]*schema.Field
+	// relationships maps relationship names to the associations storing them
+	relationships map[string]*schema.Relationship
+}
+
+/*
+newModel derives a resource type from a schema. The type is the table name, and
+members are named by their fields' json tags, or their Go names. The primary key
+and the foreign keys of belongs-to associations aren't attributes, they make up
+the ID and the to-one linkage.
+*/
+func newModel(s *schema.Schema) *model {
+	m := &model{
+		Type:          s.Table,
+		schema:        s,
+		attributes:    map[string]*schema.Field{},
+		relationships: map[string]*schema.Relationship{},
+	}
+
+	foreignKeys := map[string]bool{}
+	for _, relationship := range s.Relationships.Relations {
+		// GORM also files associations under the schemas they point at, keyed
+		// "_Schema_Name", which aren't relationships of this model
+		if relationship.Schema != s {
+			continue
+		}
+
+		name := memberName(relationship.Field)
+		if name == "" {
+			continue
+		}
+		m.relationships[name] = relationship
+
+		if relationship.Type == schema.BelongsTo {
+			for _, reference := range relationship.References {
+				foreignKeys[reference.ForeignKey.Name] = true
+			}
+		}
+	}
+
+	for _, field := range s.Fields {
+		if field.DBName == "" || field.PrimaryKey || foreignKeys[field.Name] {
+			continue
+		}
+
+		name := memberName(field)
+		if name != "" {
+			m.attributes[name] = field
+		}
+	}
+
+	return m
+}
+
+// memberName returns the JSON name of a field, or "" for fields hidden with
+// json:"-"
+func memberName(field *schema.Field) string {
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	}
+
+	return name
+}
+
+// attributeNames returns the names of the model's attributes
+func (m *model) attributeNames() []string {
+	names := []string{}
+	for name := range m.attributes {
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// column returns the column storing an attribute, or the ID
+func (m *model) column(attribute string) (string, bool) {
+	if attribute == "id" {
+		return m.schema.PrioritizedPrimaryField.DBName, true
+	}
+
+	field, exists := m.attributes[attribute]
+	if !exists {
+		return "", false
+	}
+
+	return field.DBName, true
+}
+
+// new allocates a pointer to a zero value of the model
+func (m *model) new() reflect.Value {
+	return reflect.New(m.schema.ModelType)
+}
+
+// id formats the primary key of a model value
+func (m *model) id(value reflect.Value) string {
+	return fmt.Sprint(fieldValue(value, m.schema.PrioritizedPrimaryField).Interface())
+}
+
+// parseID converts a resource ID to the type of the primary key
+func (m *model) parseID(id string) (interface{}, *jsh.Error) {
+	parsed, err := convertID(m.schema.PrioritizedPrimaryField.IndirectFieldType, id)
+	if err != nil {
+		return nil, jsh.NotFound(m.Type, id)
+	}
+
+	return parsed, nil
+}
+
+/*
+object builds the resource object of a model value. To-one linkage comes from
+belongs-to foreign keys and is always sent, other associations are only linked
+when they were loaded.
+*/
+func (m *model) object(value reflect.Value) (*jsh.Object, *jsh.Error) {
+	attributes := map[string]interface{}{}
+	for name, field := range m.attributes {
+		attributes[name] = fieldValue(value, field).Interface()
+	}
+
+	object, err := jsh.NewObject(m.id(value), m.Type, attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, relationship := range m.relationships {
+		related := newModel(relationship.FieldSchema)
+
+		switch relationship.Type {
+		case schema.BelongsTo:
+			id := ""
+			foreignKey := reflect.Indirect(fieldValue(value, relationship.References[0].ForeignKey))
+			if foreignKey.IsValid() && !foreignKey.IsZero() {
+				id = fmt.Sprint(foreignKey.Interface())
+			}
+
+			err = object.AddToOneRelationship(name, related.Type, id)
+		case schema.HasOne:
+			loaded := reflect.Indirect(fieldValue(value, relationship.Field))
+			if !loaded.IsValid() || loaded.IsZero() {
+				continue
+			}
+
+			err = object.AddToOneRelationship(name, related.Type, related.id(loaded))
+		default:
+			loaded := fieldValue(value, relationship.Field)
+			if loaded.IsNil() {
+				continue
+			}
+
+			identifiers := []*jsh.ResourceIdentifier{}
+			for i := 0; i < loaded.Len(); i++ {
+				identifiers = append(identifiers, &jsh.ResourceIdentifier{
+					Type: related.Type,
+					ID:   related.id(reflect.Indirect(loaded.Index(i))),
+				})
+			}
+			err = object.AddToManyRelationship(name, identifiers...)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return object, nil
+}
+
+/*
+included collects the resource objects of the associations an include tree
+loaded, adding each once to objects, which is keyed by type and ID.
+*/
+func (m *model) included(value reflect.Value, include jsh.IncludeTree, objects map[string]*jsh.Object, list *[]*jsh.Object) *jsh.Error {
+	for name, children := range include {
+		relationship := m.relationships[name]
+		related := newModel(relationship.FieldSchema)
+
+		loaded := fieldValue(value, relationship.Field)
+		values := []reflect.Value{}
+		if loaded.Kind() == reflect.Slice {
+			for i := 0; i < loaded.Len(); i++ {
+				values = append(values, reflect.Indirect(loaded.Index(i)))
+			}
+		} else if loaded = reflect.Indirect(loaded); loaded.IsValid() && !loaded.IsZero() {
+			values = append(values, loaded)
+		}
+
+		for _, relatedValue := range values {
+			key := related.Type + "/" + related.id(relatedValue)
+			if _, exists := objects[key]; !exists {
+				object, err := related.object(relatedValue)
+				if err != nil {
+					return err
+				}
+
+				objects[key] = object
+				*list = append(*list, object)
+			}
+
+			err := related.included(relatedValue, children, objects, list)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+/*
+preloads converts an include tree to the association paths to preload, such as
+"Author.Profile", returning a 400 for relationships the models don't have.
+*/
+func (m *model) preloads(include jsh.IncludeTree, prefix string) ([]string, *jsh.Error) {
+	paths := []string{}
+	for name, children := range include {
+		relationship, exists := m.relationships[name]
+		if !exists {
+			return nil, jsh.ParameterError(
+				fmt.Sprintf("Resources of type '%s' have no relationship '%s'", m.Type, name),
+				jsh.IncludeParam,
+			)
+		}
+
+		path := prefix + relationship.Name
+		paths = append(paths, path)
+
+		nested, err := newModel(relationship.FieldSchema).preloads(children, path+".")
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, nested...)
+	}
+
+	return paths, nil
+}
+
+// declaration declares the model's relationships for jsh.Resource
+func (m *model) declaration() map[string]jsh.RelationshipDeclaration {
+	declarations := map[string]jsh.RelationshipDeclaration{}
+	for name, relationship := range m.relationships {
+		cardinality := jsh.ToOne
+		if relationship.Type == schema.HasMany || relationship.Type == schema.Many2Many {
+			cardinality = jsh.ToMany
+		}
+
+		declarations[name] = jsh.RelationshipDeclaration{
+			Type:        relationship.FieldSchema.Table,
+			Cardinality: cardinality,
+		}
+	}
+
+	return declarations
+}
+
+// fieldValue returns the value of a schema field on a model value, which may be
+// a pointer
+func fieldValue(value reflect.Value, field *schema.Field) reflect.Value {
+	return reflect.Indirect(value).FieldByName(field.Name)
+}
+
+// setField sets a field on a model value, to nil or the zero value if value is
+// nil
+func setField(value reflect.Value, field *schema.Field, set interface{}) {
+	target := fieldValue(value, field)
+	if set == nil {
+		target.Set(reflect.Zero(target.Type()))
+		return
+	}
+
+	converted := reflect.ValueOf(set)
+	if target.Kind() == reflect.Ptr {
+		pointer := reflect.New(target.Type().Elem())
+		pointer.Elem().Set(converted.Convert(target.Type().Elem()))
+		target.Set(pointer)
+		return
+	}
+
+	target.Set(converted.Convert(target.Type()))
+}
+
+// convertID parses an ID for a key of the given type
+func convertID(keyType reflect.Type, id string) (interface{}, error) {
+	switch keyType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		return reflect.ValueOf(parsed).Convert(keyType).Interface(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		return reflect.ValueOf(parsed).Convert(keyType).Interface(), nil
+	case reflect.String:
+		return reflect.ValueOf(id).Convert(keyType).Interface(), nil
+	}
+
+	return nil, fmt.Errorf("unsupported primary key type %s", keyType)
+}
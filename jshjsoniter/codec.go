@@ -0,0 +1,61 @@
+//go:build jsoniter
+// +build jsoniter
+
+package jshjsoniter
+
+import (
+	"io"
+
+	"github.com/derekdowling/go-json-spec-handler"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Codec is the jsh.JSONCodec of json-iterator's encoding/json compatible
+// configuration.
+var Codec = New(jsoniter.ConfigCompatibleWithStandardLibrary)
+
+// Enable sets Codec as jsh.JSON.
+func Enable() {
+	jsh.JSON = Codec
+}
+
+// New returns the jsh.JSONCodec of a json-iterator configuration, such as one
+// frozen from a jsoniter.Config.
+func New(api jsoniter.API) jsh.JSONCodec {
+	return &codec{api: api}
+}
+
+// codec adapts a jsoniter.API to jsh.JSONCodec
+type codec struct {
+	api jsoniter.API
+}
+
+// Name implements jsh.JSONCodec.
+func (c *codec) Name() string {
+	return "github.com/json-iterator/go"
+}
+
+// Marshal implements jsh.JSONCodec.
+func (c *codec) Marshal(v interface{}) ([]byte, error) {
+	return c.api.Marshal(v)
+}
+
+// MarshalIndent implements jsh.JSONCodec.
+func (c *codec) MarshalIndent(v interface{}, prefix string, indent string) ([]byte, error) {
+	return c.api.MarshalIndent(v, prefix, indent)
+}
+
+// Unmarshal implements jsh.JSONCodec.
+func (c *codec) Unmarshal(data []byte, v interface{}) error {
+	return c.api.Unmarshal(data, v)
+}
+
+// NewEncoder implements jsh.JSONCodec.
+func (c *codec) NewEncoder(w io.Writer) jsh.JSONEncoder {
+	return c.api.NewEncoder(w)
+}
+
+// NewDecoder implements jsh.JSONCodec.
+func (c *codec) NewDecoder(r io.Reader) jsh.JSONDecoder {
+	return c.api.NewDecoder(r)
+}
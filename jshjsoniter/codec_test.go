@@ -0,0 +1,55 @@
+//go:build jsoniter
+// +build jsoniter
+
+package jshjsoniter
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/derekdowling/go-json-spec-handler"
+	jsoniter "github.com/json-iterator/go"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCodec(t *testing.T) {
+
+	Convey("json-iterator Codec Tests", t, func() {
+
+		send := func() string {
+			object, err := jsh.NewObject("1", "users", map[string]interface{}{"name": "Bob", "tags": []string{"a", "b"}})
+			So(err, ShouldBeNil)
+
+			w := httptest.NewRecorder()
+			So(jsh.Send(w, httptest.NewRequest("GET", "/users/1", nil), object), ShouldBeNil)
+			return w.Body.String()
+		}
+		standard := send()
+
+		Enable()
+		Reset(func() { jsh.JSON = jsh.StandardCodec{} })
+
+		Convey("should send what encoding/json sends", func() {
+			So(jsh.JSON.Name(), ShouldEqual, "github.com/json-iterator/go")
+			So(send(), ShouldEqual, standard)
+		})
+
+		Convey("should parse documents", func() {
+			r := httptest.NewRequest("POST", "/users", strings.NewReader(`{"data": {"type": "users", "attributes": {"name": "Bob"}}}`))
+			r.Header.Set("Content-Type", jsh.ContentType)
+
+			object, err := jsh.ParseObject(r)
+			So(err, ShouldBeNil)
+			name, _ := object.AttributeString("name")
+			So(name, ShouldEqual, "Bob")
+		})
+
+		Convey("->New()", func() {
+			jsh.JSON = New(jsoniter.Config{SortMapKeys: true, EscapeHTML: false}.Froze())
+
+			object, _ := jsh.NewObject("1", "users", map[string]string{"bio": "<b>"})
+			So(string(object.Attributes), ShouldEqual, `{"bio":"<b>"}`)
+		})
+	})
+}
@@ -0,0 +1,19 @@
+/*
+Package jshjsoniter encodes and decodes jsh documents with json-iterator, which
+is faster than encoding/json for large payloads. It is built with the jsoniter
+tag, once github.com/json-iterator/go is vendored, so services sticking with
+encoding/json never pull in the dependency:
+
+	go build -tags jsoniter
+
+Enable it at init, before any requests are handled:
+
+	func init() {
+		jshjsoniter.Enable()
+	}
+
+The codec is configured to be compatible with encoding/json. Its decoding errors
+differ from encoding/json's though, so 422 errors for mistyped attributes don't
+point at the attribute.
+*/
+package jshjsoniter
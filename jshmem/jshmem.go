@@ -0,0 +1,15 @@
+/*
+Package jshmem serves JSON API resources from memory, for demos, tests, and
+local development. A Store holds the resources of one type and handles
+listing, fetching, creating, updating, and deleting them:
+
+	mux := http.NewServeMux()
+	jshmem.NewStore("articles").Handle(mux)
+	jshmem.NewStore("people", bob, alice).Handle(mux)
+	http.ListenAndServe(":8080", mux)
+
+Lists support keyset pagination sorted by any attribute, and filters. Stores are
+safe for concurrent use, and hand out copies of their resources so that callers
+can't change them behind the store's back. Nothing is persisted.
+*/
+package jshmem
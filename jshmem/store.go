@@ -0,0 +1,293 @@
+package jshmem
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/derekdowling/go-json-spec-handler"
+)
+
+/*
+Store holds the resources of one type in memory. Its List, Fetch, Create,
+Update, and Delete methods are jsh.ResourceHandlers, which Handle registers at
+the conventional routes, or which can be adapted to other routers with
+jshrouter.
+*/
+type Store struct {
+	Type string
+	// Filters lists the filter operators allowed for each attribute, see
+	// jsh.ParseFilter. Any attribute can be filtered with any operator if nil.
+	Filters map[string][]jsh.FilterOperator
+
+	mu      sync.RWMutex
+	objects map[string]*jsh.Object
+	nextID  int
+}
+
+// NewStore returns a store of the given type, holding copies of objects.
+func NewStore(resourceType string, objects ...*jsh.Object) *Store {
+	store := &Store{Type: resourceType, objects: map[string]*jsh.Object{}}
+	for _, object := range objects {
+		store.Put(object)
+	}
+
+	return store
+}
+
+// Handle registers the store's handlers with mux, at /TYPE and /TYPE/{id}.
+func (s *Store) Handle(mux *http.ServeMux) {
+	collection := "/" + jsh.ResourcePath(s.Type)
+	member := collection + "/{" + jsh.IDParam + "}"
+
+	mux.Handle("GET "+collection, jsh.ResourceHandler(s.List))
+	mux.Handle("POST "+collection, jsh.ResourceHandler(s.Create))
+	mux.Handle("GET "+member, jsh.ResourceHandler(s.Fetch))
+	mux.Handle("PATCH "+member, jsh.ResourceHandler(s.Update))
+	mux.Handle("DELETE "+member, jsh.ResourceHandler(s.Delete))
+}
+
+// Put stores a copy of an object, replacing any stored object with its ID. An
+// ID is generated for objects without one, and returned.
+func (s *Store) Put(object *jsh.Object) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := copyObject(object)
+	stored.Type = s.Type
+	if stored.ID == "" {
+		stored.ID = s.generateID()
+	}
+	s.objects[stored.ID] = stored
+
+	return stored.ID
+}
+
+// Get returns a copy of the object with the given ID, or nil.
+func (s *Store) Get(id string) *jsh.Object {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stored, exists := s.objects[id]
+	if !exists {
+		return nil
+	}
+
+	return copyObject(stored)
+}
+
+// All returns copies of every stored object, ordered by ID.
+func (s *Store) All() jsh.List {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := []string{}
+	for id := range s.objects {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	list := jsh.List{}
+	for _, id := range ids {
+		list = append(list, copyObject(s.objects[id]))
+	}
+
+	return list
+}
+
+// Remove deletes the object with the given ID, returning false if there was
+// none.
+func (s *Store) Remove(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, exists := s.objects[id]
+	delete(s.objects, id)
+
+	return exists
+}
+
+/*
+List sends a page of the stored objects matching the filter query parameters.
+The page is sorted by the sort query parameter, and paginated by keyset.
+*/
+func (s *Store) List(w http.ResponseWriter, r *http.Request, params jsh.RouteParams) {
+	keyset, err := jsh.ParseKeyset(r, nil)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	filter, err := jsh.ParseFilter(r, s.Filters)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	list := s.All().Filter(filter.Matches)
+
+	err = list.Sort(keyset.Sort)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	page := jsh.List{}
+	var last *jsh.Object
+	for _, object := range list {
+		after, err := keyset.After(object)
+		if err != nil {
+			jsh.Send(w, r, err)
+			return
+		}
+		if !after {
+			continue
+		}
+
+		if len(page) == keyset.Limit {
+			last = page[len(page)-1]
+			break
+		}
+		page = append(page, object)
+	}
+
+	document := jsh.Build(page)
+	document.Links, err = keyset.Links(last)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	jsh.SendDocument(w, r, document)
+}
+
+// Fetch sends the object identified by the route's ID, or a 404.
+func (s *Store) Fetch(w http.ResponseWriter, r *http.Request, params jsh.RouteParams) {
+	id, err := jsh.InternalID(s.Type, params.ID)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	object := s.Get(id)
+	if object == nil {
+		jsh.Send(w, r, jsh.NotFound(s.Type, params.ID))
+		return
+	}
+
+	jsh.Send(w, r, object)
+}
+
+/*
+Create stores a POSTed object and sends it with a 201. Objects are given IDs by
+the store unless the client generated one, which the ParseOptions must allow,
+and a 409 is sent if the ID is taken.
+*/
+func (s *Store) Create(w http.ResponseWriter, r *http.Request, params jsh.RouteParams) {
+	object, err := jsh.ParseObjectFor(r, s.Type, "")
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+	if object == nil {
+		jsh.Send(w, r, jsh.InputError("Missing primary data", "data"))
+		return
+	}
+
+	s.mu.Lock()
+	if _, exists := s.objects[object.ID]; exists && object.ID != "" {
+		s.mu.Unlock()
+		jsh.Send(w, r, jsh.Conflict(fmt.Sprintf("A resource of type '%s' with ID '%s' already exists", s.Type, object.ID)))
+		return
+	}
+
+	if object.ID == "" {
+		object.ID = s.generateID()
+	}
+	s.objects[object.ID] = copyObject(object)
+	s.mu.Unlock()
+
+	object.Status = http.StatusCreated
+	jsh.Send(w, r, object)
+}
+
+// Update merges a PATCHed object into the stored object, see jsh.Object.Merge,
+// and sends the result.
+func (s *Store) Update(w http.ResponseWriter, r *http.Request, params jsh.RouteParams) {
+	patch, err := jsh.ParseObjectFor(r, s.Type, params.ID)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+	if patch == nil {
+		jsh.Send(w, r, jsh.InputError("Missing primary data", "data"))
+		return
+	}
+
+	s.mu.Lock()
+	stored, exists := s.objects[patch.ID]
+	if !exists {
+		s.mu.Unlock()
+		jsh.Send(w, r, jsh.NotFound(s.Type, patch.ID))
+		return
+	}
+
+	updated := copyObject(stored)
+	err = updated.Merge(patch)
+	if err == nil {
+		s.objects[updated.ID] = copyObject(updated)
+	}
+	s.mu.Unlock()
+
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	jsh.Send(w, r, updated)
+}
+
+// Delete deletes the object identified by the route's ID, sending a 204, or a
+// 404 if there is no such object.
+func (s *Store) Delete(w http.ResponseWriter, r *http.Request, params jsh.RouteParams) {
+	id, err := jsh.InternalID(s.Type, params.ID)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	if !s.Remove(id) {
+		jsh.Send(w, r, jsh.NotFound(s.Type, params.ID))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// generateID returns the next unused numeric ID, the store must be locked
+func (s *Store) generateID() string {
+	for {
+		s.nextID++
+		id := strconv.Itoa(s.nextID)
+		if _, exists := s.objects[id]; !exists {
+			return id
+		}
+	}
+}
+
+// copyObject deep copies an object through its JSON encoding
+func copyObject(object *jsh.Object) *jsh.Object {
+	raw, err := json.Marshal(object)
+	if err != nil {
+		// objects that can't be encoded couldn't be sent either, keep them as is
+		copied := *object
+		return &copied
+	}
+
+	copied := &jsh.Object{}
+	json.Unmarshal(raw, copied)
+	return copied
+}
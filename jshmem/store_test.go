@@ -0,0 +1,160 @@
+package jshmem
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/derekdowling/go-json-spec-handler"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestStore(t *testing.T) {
+
+	Convey("Store Tests", t, func() {
+
+		article := func(id string, title string, views int) *jsh.Object {
+			object, _ := jsh.NewObject(id, "articles", map[string]interface{}{"title": title, "views": views})
+			return object
+		}
+
+		store := NewStore("articles",
+			article("1", "First", 10),
+			article("2", "Second", 30),
+			article("3", "Third", 20),
+		)
+
+		serve := func(handler jsh.ResourceHandler, method string, target string, body string, id string) (*httptest.ResponseRecorder, *jsh.Document) {
+			var payload io.Reader
+			if body != "" {
+				payload = strings.NewReader(body)
+			}
+
+			r := httptest.NewRequest(method, target, payload)
+			r.Header.Set("Content-Type", jsh.ContentType)
+
+			w := httptest.NewRecorder()
+			handler(w, r, jsh.RouteParams{ID: id})
+
+			doc := &jsh.Document{}
+			if w.Body.Len() > 0 {
+				So(json.Unmarshal(w.Body.Bytes(), doc), ShouldBeNil)
+			}
+			return w, doc
+		}
+
+		Convey("->List()", func() {
+			w, doc := serve(store.List, "GET", "/articles?sort=-views&page[limit]=2", "", "")
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(doc.Data.IDs(), ShouldResemble, []string{"2", "3"})
+			So(doc.Links.Next, ShouldNotBeNil)
+
+			Convey("should continue after the cursor", func() {
+				w, doc := serve(store.List, "GET", doc.Links.Next.HREF, "", "")
+				So(w.Code, ShouldEqual, http.StatusOK)
+				So(doc.Data.IDs(), ShouldResemble, []string{"1"})
+				So(doc.Links.Next, ShouldBeNil)
+			})
+
+			Convey("should filter", func() {
+				w, doc := serve(store.List, "GET", "/articles?filter[views][gte]=20&filter[title][like]=t", "", "")
+				So(w.Code, ShouldEqual, http.StatusOK)
+				So(doc.Data.IDs(), ShouldResemble, []string{"3"})
+			})
+
+			Convey("should reject filters that aren't allowed", func() {
+				store.Filters = map[string][]jsh.FilterOperator{"title": {jsh.Equal}}
+
+				w, _ := serve(store.List, "GET", "/articles?filter[views]=20", "", "")
+				So(w.Code, ShouldEqual, http.StatusBadRequest)
+			})
+		})
+
+		Convey("->Fetch()", func() {
+			w, doc := serve(store.Fetch, "GET", "/articles/2", "", "2")
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(doc.First().ID, ShouldEqual, "2")
+
+			Convey("should 404 for missing objects", func() {
+				w, _ := serve(store.Fetch, "GET", "/articles/9", "", "9")
+				So(w.Code, ShouldEqual, http.StatusNotFound)
+			})
+		})
+
+		Convey("->Create()", func() {
+			w, doc := serve(store.Create, "POST", "/articles", `{"data": {"type": "articles", "attributes": {"title": "Fourth"}}}`, "")
+			So(w.Code, ShouldEqual, http.StatusCreated)
+			So(doc.First().ID, ShouldEqual, "4")
+			So(store.Get("4"), ShouldNotBeNil)
+		})
+
+		Convey("->Update()", func() {
+			w, doc := serve(store.Update, "PATCH", "/articles/1", `{"data": {"type": "articles", "id": "1", "attributes": {"views": 11}}}`, "1")
+			So(w.Code, ShouldEqual, http.StatusOK)
+
+			views, _ := doc.First().AttributeInt("views")
+			So(views, ShouldEqual, 11)
+			title, _ := store.Get("1").AttributeString("title")
+			So(title, ShouldEqual, "First")
+
+			Convey("should 404 for missing objects", func() {
+				w, _ := serve(store.Update, "PATCH", "/articles/9", `{"data": {"type": "articles", "id": "9", "attributes": {"views": 1}}}`, "9")
+				So(w.Code, ShouldEqual, http.StatusNotFound)
+			})
+		})
+
+		Convey("->Delete()", func() {
+			w, _ := serve(store.Delete, "DELETE", "/articles/3", "", "3")
+			So(w.Code, ShouldEqual, http.StatusNoContent)
+			So(store.Get("3"), ShouldBeNil)
+
+			Convey("should 404 for missing objects", func() {
+				w, _ := serve(store.Delete, "DELETE", "/articles/3", "", "3")
+				So(w.Code, ShouldEqual, http.StatusNotFound)
+			})
+		})
+
+		Convey("should translate route IDs", func() {
+			jsh.Register(&jsh.Resource{Type: "articles", IDTranslator: prefixTranslator{}})
+			Reset(func() { jsh.Unregister("articles") })
+
+			w, doc := serve(store.Fetch, "GET", "/articles/pub-2", "", "pub-2")
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(doc.First().ID, ShouldEqual, "pub-2")
+
+			w, _ = serve(store.Fetch, "GET", "/articles/2", "", "2")
+			So(w.Code, ShouldEqual, http.StatusNotFound)
+
+			w, _ = serve(store.Delete, "DELETE", "/articles/pub-3", "", "pub-3")
+			So(w.Code, ShouldEqual, http.StatusNoContent)
+			So(store.Get("3"), ShouldBeNil)
+		})
+
+		Convey("should hand out copies", func() {
+			stored := store.Get("1")
+			stored.SetAttribute("title", "Changed")
+
+			title, _ := store.Get("1").AttributeString("title")
+			So(title, ShouldEqual, "First")
+			So(store.All().IDs(), ShouldResemble, []string{"1", "2", "3"})
+		})
+	})
+}
+
+// prefixTranslator makes IDs public by prefixing them with "pub-"
+type prefixTranslator struct{}
+
+func (prefixTranslator) Internal(resourceType string, id string) (string, *jsh.Error) {
+	if !strings.HasPrefix(id, "pub-") {
+		return "", jsh.NotFound(resourceType, id)
+	}
+
+	return strings.TrimPrefix(id, "pub-"), nil
+}
+
+func (prefixTranslator) Public(resourceType string, id string) (string, *jsh.Error) {
+	return "pub-" + id, nil
+}
@@ -0,0 +1,23 @@
+/*
+Package jshotel instruments jsh servers and jsc clients with OpenTelemetry
+spans. It is built with the otel tag, once go.opentelemetry.io/otel is
+vendored, so services that don't trace never pull in the dependency:
+
+	go build -tags otel
+
+Its tests record spans with go.opentelemetry.io/otel/sdk, which needs vendoring
+as well.
+
+Servers trace parses and sends by wrapping jsh.Logging, clients by wrapping
+their transport:
+
+	jshotel.Enable()
+
+	client := &jsc.Client{
+		HTTPClient: &http.Client{Transport: jshotel.Transport(nil)},
+	}
+
+Spans are recorded with the global TracerProvider and carry the resource type,
+ID, operation, and HTTP status as attributes.
+*/
+package jshotel
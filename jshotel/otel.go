@@ -0,0 +1,136 @@
+//go:build otel
+// +build otel
+
+package jshotel
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/derekdowling/go-json-spec-handler"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName identifies the spans recorded by jshotel.
+const InstrumentationName = "github.com/derekdowling/go-json-spec-handler"
+
+// Attribute keys set on jshotel spans
+const (
+	TypeKey      = attribute.Key("jsonapi.resource.type")
+	IDKey        = attribute.Key("jsonapi.resource.id")
+	OperationKey = attribute.Key("jsonapi.operation")
+	StatusKey    = attribute.Key("http.response.status_code")
+	MethodKey    = attribute.Key("http.request.method")
+)
+
+// Enable traces the parses and sends of jsh, in addition to any Logger already
+// set.
+func Enable() {
+	jsh.Logging = Logger(jsh.Logging)
+}
+
+/*
+Logger returns a jsh.Logger recording a span for each parse and send, before
+passing entries on to next if it isn't nil. Spans are children of the span in
+the request's context, and are timed with the entry's Duration.
+*/
+func Logger(next jsh.Logger) jsh.Logger {
+	tracer := otel.Tracer(InstrumentationName)
+
+	return jsh.LoggerFunc(func(entry *jsh.LogEntry) {
+		end := time.Now()
+
+		_, span := tracer.Start(entry.Context, spanName(entry),
+			trace.WithTimestamp(end.Add(-entry.Duration)),
+			trace.WithAttributes(entryAttributes(entry)...),
+		)
+
+		if entry.Error != nil {
+			span.SetStatus(codes.Error, entry.Error.Error())
+		}
+		span.End(trace.WithTimestamp(end))
+
+		if next != nil {
+			next.Log(entry)
+		}
+	})
+}
+
+// spanName names the span of an entry, such as "jsh.send fetch"
+func spanName(entry *jsh.LogEntry) string {
+	switch entry.Kind {
+	case jsh.RequestParsed, jsh.ValidationFailed:
+		return "jsh.parse " + string(entry.Operation)
+	}
+
+	return "jsh.send " + string(entry.Operation)
+}
+
+// entryAttributes returns the span attributes of an entry
+func entryAttributes(entry *jsh.LogEntry) []attribute.KeyValue {
+	attributes := []attribute.KeyValue{
+		OperationKey.String(string(entry.Operation)),
+	}
+
+	if entry.Method != "" {
+		attributes = append(attributes, MethodKey.String(entry.Method))
+	}
+	if entry.Type != "" {
+		attributes = append(attributes, TypeKey.String(entry.Type))
+	}
+	if entry.ID != "" {
+		attributes = append(attributes, IDKey.String(entry.ID))
+	}
+	if entry.Status != 0 {
+		attributes = append(attributes, StatusKey.Int(entry.Status))
+	}
+
+	return attributes
+}
+
+/*
+Transport wraps next, http.DefaultTransport if nil, recording a client span for
+each request and propagating its context to the server in the request headers.
+*/
+func Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	tracer := otel.Tracer(InstrumentationName)
+
+	return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		ctx, span := tracer.Start(r.Context(), "jsc "+r.Method,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(MethodKey.String(r.Method)),
+		)
+		defer span.End()
+
+		r = r.Clone(ctx)
+		otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(r.Header))
+
+		response, err := next.RoundTrip(r)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return nil, err
+		}
+
+		span.SetAttributes(StatusKey.Int(response.StatusCode))
+		if response.StatusCode >= http.StatusBadRequest {
+			span.SetStatus(codes.Error, http.StatusText(response.StatusCode))
+		}
+
+		return response, nil
+	})
+}
+
+type roundTripperFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
@@ -0,0 +1,121 @@
+//go:build otel
+// +build otel
+
+package jshotel
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/derekdowling/go-json-spec-handler"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestOTel(t *testing.T) {
+
+	Convey("OpenTelemetry Tests", t, func() {
+
+		recorder := tracetest.NewSpanRecorder()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+		otel.SetTextMapPropagator(propagation.TraceContext{})
+
+		attributes := func(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+			values := map[attribute.Key]attribute.Value{}
+			for _, kv := range span.Attributes() {
+				values[kv.Key] = kv.Value
+			}
+			return values
+		}
+
+		Convey("->Logger()", func() {
+			passed := []*jsh.LogEntry{}
+			logger := Logger(jsh.LoggerFunc(func(entry *jsh.LogEntry) {
+				passed = append(passed, entry)
+			}))
+
+			entry := &jsh.LogEntry{
+				Kind:      jsh.ResponseSent,
+				Context:   context.Background(),
+				Method:    "GET",
+				Operation: jsh.FetchOperation,
+				Type:      "users",
+				ID:        "1",
+				Status:    http.StatusOK,
+				Duration:  5 * time.Millisecond,
+			}
+			logger.Log(entry)
+
+			spans := recorder.Ended()
+			So(len(spans), ShouldEqual, 1)
+			So(spans[0].Name(), ShouldEqual, "jsh.send fetch")
+			So(spans[0].EndTime().Sub(spans[0].StartTime()), ShouldEqual, 5*time.Millisecond)
+			So(spans[0].Status().Code, ShouldEqual, codes.Unset)
+
+			values := attributes(spans[0])
+			So(values[TypeKey].AsString(), ShouldEqual, "users")
+			So(values[IDKey].AsString(), ShouldEqual, "1")
+			So(values[StatusKey].AsInt64(), ShouldEqual, 200)
+			So(values[MethodKey].AsString(), ShouldEqual, "GET")
+
+			So(passed, ShouldResemble, []*jsh.LogEntry{entry})
+
+			Convey("should mark failed parses as errors", func() {
+				logger.Log(&jsh.LogEntry{
+					Kind:      jsh.ValidationFailed,
+					Context:   context.Background(),
+					Operation: jsh.CreateOperation,
+					Status:    422,
+					Error:     jsh.InputError("Name is required", "name"),
+				})
+
+				span := recorder.Ended()[1]
+				So(span.Name(), ShouldEqual, "jsh.parse create")
+				So(span.Status().Code, ShouldEqual, codes.Error)
+				So(attributes(span), ShouldNotContainKey, TypeKey)
+			})
+
+			Convey("should work without a next Logger", func() {
+				So(func() { Logger(nil).Log(entry) }, ShouldNotPanic)
+			})
+		})
+
+		Convey("->Transport()", func() {
+			traceparent := ""
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				traceparent = r.Header.Get("traceparent")
+				if r.URL.Path == "/missing" {
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			client := &http.Client{Transport: Transport(nil)}
+			response, err := client.Get(server.URL + "/users")
+			So(err, ShouldBeNil)
+			response.Body.Close()
+
+			spans := recorder.Ended()
+			So(len(spans), ShouldEqual, 1)
+			So(spans[0].Name(), ShouldEqual, "jsc GET")
+			So(attributes(spans[0])[StatusKey].AsInt64(), ShouldEqual, 200)
+			So(traceparent, ShouldContainSubstring, spans[0].SpanContext().TraceID().String())
+
+			Convey("should mark error responses", func() {
+				response, err := client.Get(server.URL + "/missing")
+				So(err, ShouldBeNil)
+				response.Body.Close()
+
+				So(recorder.Ended()[1].Status().Code, ShouldEqual, codes.Error)
+			})
+		})
+	})
+}
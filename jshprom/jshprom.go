@@ -0,0 +1,19 @@
+/*
+Package jshprom exposes the parses and sends of jsh as Prometheus metrics. It is
+built with the prometheus tag, once github.com/prometheus/client_golang is
+vendored, so services without Prometheus never pull in the dependency:
+
+	go build -tags prometheus
+
+Create a Collector, enable it, and register it with the host app's registry:
+
+	collector := jshprom.New()
+	collector.Enable()
+	prometheus.MustRegister(collector)
+
+The collector counts parsed documents by mode, validation failures by JSON
+pointer, responses by status, and responses sending deprecated resource types,
+observes the sizes of parsed and sent payloads, and reports how many of jsh's
+pooled buffers are reused.
+*/
+package jshprom
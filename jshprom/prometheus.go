@@ -0,0 +1,178 @@
+//go:build prometheus
+// +build prometheus
+
+package jshprom
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/derekdowling/go-json-spec-handler"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Namespace prefixes the names of the collected metrics.
+const Namespace = "jsh"
+
+/*
+Collector is a prometheus.Collector fed by jsh, through its jsh.Logger and
+jsh.MetricsHook implementations.
+*/
+type Collector struct {
+	parsed       *prometheus.CounterVec
+	failures     *prometheus.CounterVec
+	responses    *prometheus.CounterVec
+	payloadBytes *prometheus.HistogramVec
+	deprecated   *prometheus.CounterVec
+	bufferPool   *prometheus.GaugeVec
+
+	nextLogger jsh.Logger
+	nextHook   jsh.MetricsHook
+}
+
+// New creates a Collector, which must be registered to be exposed.
+func New() *Collector {
+	return &Collector{
+		parsed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "parsed_documents_total",
+			Help:      "Request documents parsed successfully, by mode.",
+		}, []string{"mode"}),
+		failures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "validation_failures_total",
+			Help:      "Request documents failing validation, by status and JSON pointer.",
+		}, []string{"status", "pointer"}),
+		responses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "responses_total",
+			Help:      "Responses sent, by operation and status.",
+		}, []string{"operation", "status"}),
+		payloadBytes: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "payload_bytes",
+			Help:      "Sizes of parsed and sent JSON payloads.",
+			Buckets:   prometheus.ExponentialBuckets(256, 4, 8),
+		}, []string{"kind"}),
+		deprecated: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "deprecated_type_responses_total",
+			Help:      "Responses sending deprecated resource types, by type and method.",
+		}, []string{"type", "method"}),
+		bufferPool: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "buffer_pool_buffers",
+			Help:      "Buffers taken from jsh's pool since it started, by whether they were reused or allocated, and those discarded as too large.",
+		}, []string{"outcome"}),
+	}
+}
+
+// Enable sets the collector as jsh.Logging and jsh.Metrics, passing events on
+// to any Logger and MetricsHook already set.
+func (c *Collector) Enable() {
+	c.nextLogger = jsh.Logging
+	c.nextHook = jsh.Metrics
+
+	jsh.Logging = c
+	jsh.Metrics = c
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(descriptions chan<- *prometheus.Desc) {
+	c.parsed.Describe(descriptions)
+	c.failures.Describe(descriptions)
+	c.responses.Describe(descriptions)
+	c.payloadBytes.Describe(descriptions)
+	c.deprecated.Describe(descriptions)
+	c.bufferPool.Describe(descriptions)
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(metrics chan<- prometheus.Metric) {
+	c.parsed.Collect(metrics)
+	c.failures.Collect(metrics)
+	c.responses.Collect(metrics)
+	c.payloadBytes.Collect(metrics)
+	c.deprecated.Collect(metrics)
+	c.bufferPool.Collect(metrics)
+}
+
+// Log implements jsh.Logger, counting parses, failures, and responses.
+func (c *Collector) Log(entry *jsh.LogEntry) {
+	switch entry.Kind {
+	case jsh.RequestParsed:
+		c.parsed.WithLabelValues(modeLabel(entry.Mode)).Inc()
+	case jsh.ValidationFailed:
+		pointer := ""
+		if entry.Error != nil {
+			pointer = normalizePointer(entry.Error.Source.Pointer)
+		}
+		c.failures.WithLabelValues(strconv.Itoa(entry.Status), pointer).Inc()
+	case jsh.ResponseSent, jsh.ErrorSent:
+		c.responses.WithLabelValues(string(entry.Operation), strconv.Itoa(entry.Status)).Inc()
+	}
+
+	if c.nextLogger != nil {
+		c.nextLogger.Log(entry)
+	}
+}
+
+// Observe implements jsh.MetricsHook, recording payload sizes, buffer pool
+// usage, and deprecated type usage.
+func (c *Collector) Observe(event *jsh.MetricEvent) {
+	switch event.Kind {
+	case jsh.DeprecationEvent:
+		c.deprecated.WithLabelValues(event.Type, event.Method).Inc()
+	case jsh.ParseEvent, jsh.SendEvent:
+		kind := "send"
+		if event.Kind == jsh.ParseEvent {
+			kind = "parse"
+		}
+
+		if event.Bytes > 0 {
+			c.payloadBytes.WithLabelValues(kind).Observe(float64(event.Bytes))
+		}
+		c.recordPool(event.Pool)
+	}
+
+	if c.nextHook != nil {
+		c.nextHook.Observe(event)
+	}
+}
+
+// recordPool sets the buffer pool gauges to the stats of an event, unless it
+// carries none, such as streamed sends
+func (c *Collector) recordPool(stats jsh.PoolStats) {
+	if stats.Gets == 0 {
+		return
+	}
+
+	c.bufferPool.WithLabelValues("reused").Set(float64(stats.Gets - stats.Allocations))
+	c.bufferPool.WithLabelValues("allocated").Set(float64(stats.Allocations))
+	c.bufferPool.WithLabelValues("discarded").Set(float64(stats.Discards))
+}
+
+// modeLabel names a document mode
+func modeLabel(mode jsh.DocumentMode) string {
+	switch mode {
+	case jsh.ListMode:
+		return "list"
+	case jsh.ErrorMode:
+		return "error"
+	}
+
+	return "object"
+}
+
+// normalizePointer replaces the array indices of a JSON pointer with "*", so
+// that failures of each object of a list share a label
+func normalizePointer(pointer string) string {
+	tokens := strings.Split(pointer, "/")
+	for i, token := range tokens {
+		if _, err := strconv.Atoi(token); err == nil {
+			tokens[i] = "*"
+		}
+	}
+
+	return strings.Join(tokens, "/")
+}
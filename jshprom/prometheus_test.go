@@ -0,0 +1,90 @@
+//go:build prometheus
+// +build prometheus
+
+package jshprom
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/derekdowling/go-json-spec-handler"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCollector(t *testing.T) {
+
+	Convey("Prometheus Collector Tests", t, func() {
+
+		collector := New()
+
+		Convey("->Log()", func() {
+			collector.Log(&jsh.LogEntry{Kind: jsh.RequestParsed, Mode: jsh.ListMode})
+			collector.Log(&jsh.LogEntry{Kind: jsh.ResponseSent, Operation: jsh.FetchOperation, Status: 200})
+			collector.Log(&jsh.LogEntry{Kind: jsh.ErrorSent, Operation: jsh.FetchOperation, Status: 404})
+
+			So(testutil.ToFloat64(collector.parsed.WithLabelValues("list")), ShouldEqual, 1)
+			So(testutil.ToFloat64(collector.responses.WithLabelValues("fetch", "200")), ShouldEqual, 1)
+			So(testutil.ToFloat64(collector.responses.WithLabelValues("fetch", "404")), ShouldEqual, 1)
+
+			Convey("should share labels across the objects of a list", func() {
+				for _, pointer := range []string{"/data/0/attributes/name", "/data/12/attributes/name"} {
+					err := jsh.InputError("Name is required", "name")
+					err.Source.Pointer = pointer
+					collector.Log(&jsh.LogEntry{Kind: jsh.ValidationFailed, Status: 422, Error: err})
+				}
+
+				So(testutil.ToFloat64(collector.failures.WithLabelValues("422", "/data/*/attributes/name")), ShouldEqual, 2)
+			})
+		})
+
+		Convey("->Observe()", func() {
+			collector.Observe(&jsh.MetricEvent{
+				Kind:  jsh.SendEvent,
+				Bytes: 512,
+				Pool:  jsh.PoolStats{Gets: 10, Allocations: 3, Discards: 1},
+			})
+			collector.Observe(&jsh.MetricEvent{Kind: jsh.DeprecationEvent, Type: "users", Method: "GET"})
+
+			So(testutil.CollectAndCount(collector.payloadBytes), ShouldEqual, 1)
+			So(testutil.ToFloat64(collector.bufferPool.WithLabelValues("reused")), ShouldEqual, 7)
+			So(testutil.ToFloat64(collector.bufferPool.WithLabelValues("allocated")), ShouldEqual, 3)
+			So(testutil.ToFloat64(collector.bufferPool.WithLabelValues("discarded")), ShouldEqual, 1)
+			So(testutil.ToFloat64(collector.deprecated.WithLabelValues("users", "GET")), ShouldEqual, 1)
+
+			Convey("should leave the pool gauges of streamed sends alone", func() {
+				collector.Observe(&jsh.MetricEvent{Kind: jsh.SendEvent, Bytes: 64})
+				So(testutil.ToFloat64(collector.bufferPool.WithLabelValues("reused")), ShouldEqual, 7)
+			})
+		})
+
+		Convey("->Enable()", func() {
+			logging, metrics := jsh.Logging, jsh.Metrics
+			Reset(func() { jsh.Logging, jsh.Metrics = logging, metrics })
+
+			logged := 0
+			jsh.Logging = jsh.LoggerFunc(func(entry *jsh.LogEntry) { logged++ })
+			collector.Enable()
+
+			r := httptest.NewRequest("GET", "/users/1", nil)
+			object, _ := jsh.NewObject("1", "users", map[string]string{"name": "Alice"})
+			jsh.Send(httptest.NewRecorder(), r, object)
+
+			So(testutil.ToFloat64(collector.responses.WithLabelValues("fetch", "200")), ShouldEqual, 1)
+			So(logged, ShouldEqual, 1)
+		})
+
+		Convey("should register", func() {
+			registry := prometheus.NewRegistry()
+			So(registry.Register(collector), ShouldBeNil)
+
+			collector.Log(&jsh.LogEntry{Kind: jsh.ResponseSent, Operation: jsh.ListOperation, Status: http.StatusOK})
+			families, err := registry.Gather()
+			So(err, ShouldBeNil)
+			So(len(families), ShouldEqual, 1)
+			So(families[0].GetName(), ShouldEqual, "jsh_responses_total")
+		})
+	})
+}
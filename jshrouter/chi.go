@@ -0,0 +1,21 @@
+//go:build chi
+// +build chi
+
+package jshrouter
+
+import (
+	"net/http"
+
+	"github.com/derekdowling/go-json-spec-handler"
+	"github.com/go-chi/chi"
+)
+
+// Chi adapts a handler to a chi route.
+func Chi(handler jsh.ResourceHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		handler(w, r, jsh.RouteParams{
+			ID:           chi.URLParam(r, jsh.IDParam),
+			Relationship: chi.URLParam(r, jsh.RelationshipParam),
+		})
+	}
+}
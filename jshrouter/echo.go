@@ -0,0 +1,21 @@
+//go:build echo
+// +build echo
+
+package jshrouter
+
+import (
+	"github.com/derekdowling/go-json-spec-handler"
+	"github.com/labstack/echo"
+)
+
+// Echo adapts a handler to an echo route. Handlers send their own responses,
+// including errors, so the returned error is always nil.
+func Echo(handler jsh.ResourceHandler) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		handler(c.Response(), c.Request(), jsh.RouteParams{
+			ID:           c.Param(jsh.IDParam),
+			Relationship: c.Param(jsh.RelationshipParam),
+		})
+		return nil
+	}
+}
@@ -0,0 +1,19 @@
+//go:build gin
+// +build gin
+
+package jshrouter
+
+import (
+	"github.com/derekdowling/go-json-spec-handler"
+	"github.com/gin-gonic/gin"
+)
+
+// Gin adapts a handler to a gin route.
+func Gin(handler jsh.ResourceHandler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		handler(c.Writer, c.Request, jsh.RouteParams{
+			ID:           c.Param(jsh.IDParam),
+			Relationship: c.Param(jsh.RelationshipParam),
+		})
+	}
+}
@@ -0,0 +1,22 @@
+//go:build gorillamux
+// +build gorillamux
+
+package jshrouter
+
+import (
+	"net/http"
+
+	"github.com/derekdowling/go-json-spec-handler"
+	"github.com/gorilla/mux"
+)
+
+// GorillaMux adapts a handler to a gorilla/mux route.
+func GorillaMux(handler jsh.ResourceHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		handler(w, r, jsh.RouteParams{
+			ID:           vars[jsh.IDParam],
+			Relationship: vars[jsh.RelationshipParam],
+		})
+	}
+}
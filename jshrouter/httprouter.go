@@ -0,0 +1,21 @@
+//go:build httprouter
+// +build httprouter
+
+package jshrouter
+
+import (
+	"net/http"
+
+	"github.com/derekdowling/go-json-spec-handler"
+	"github.com/julienschmidt/httprouter"
+)
+
+// HTTPRouter adapts a handler to an httprouter route.
+func HTTPRouter(handler jsh.ResourceHandler) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		handler(w, r, jsh.RouteParams{
+			ID:           params.ByName(jsh.IDParam),
+			Relationship: params.ByName(jsh.RelationshipParam),
+		})
+	}
+}
@@ -0,0 +1,20 @@
+/*
+Package jshrouter adapts jsh.ResourceHandlers to popular routers, so that
+handlers receive their resource ID and relationship name the same way whichever
+router a service uses:
+
+	r := chi.NewRouter()
+	r.Get("/articles/{id}", jshrouter.Chi(fetchArticle))
+
+Routes must name their parameters jsh.IDParam and jsh.RelationshipParam. Each
+adapter is built with the tag of its router, once that router is vendored:
+
+	go build -tags chi           // Chi, github.com/go-chi/chi
+	go build -tags gorillamux    // GorillaMux, github.com/gorilla/mux
+	go build -tags httprouter    // HTTPRouter, github.com/julienschmidt/httprouter
+	go build -tags echo          // Echo, github.com/labstack/echo
+	go build -tags gin           // Gin, github.com/gin-gonic/gin
+
+Routers built on net/http's ServeMux need no adapter, see jsh.ResourceHandler.
+*/
+package jshrouter
@@ -0,0 +1,52 @@
+//go:build segmentio
+// +build segmentio
+
+package jshsegmentio
+
+import (
+	"io"
+
+	"github.com/derekdowling/go-json-spec-handler"
+	"github.com/segmentio/encoding/json"
+)
+
+// Codec is the jsh.JSONCodec of segmentio/encoding.
+var Codec jsh.JSONCodec = codec{}
+
+// Enable sets Codec as jsh.JSON.
+func Enable() {
+	jsh.JSON = Codec
+}
+
+// codec adapts segmentio/encoding/json to jsh.JSONCodec
+type codec struct{}
+
+// Name implements jsh.JSONCodec.
+func (codec) Name() string {
+	return "github.com/segmentio/encoding/json"
+}
+
+// Marshal implements jsh.JSONCodec.
+func (codec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// MarshalIndent implements jsh.JSONCodec.
+func (codec) MarshalIndent(v interface{}, prefix string, indent string) ([]byte, error) {
+	return json.MarshalIndent(v, prefix, indent)
+}
+
+// Unmarshal implements jsh.JSONCodec.
+func (codec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// NewEncoder implements jsh.JSONCodec.
+func (codec) NewEncoder(w io.Writer) jsh.JSONEncoder {
+	return json.NewEncoder(w)
+}
+
+// NewDecoder implements jsh.JSONCodec.
+func (codec) NewDecoder(r io.Reader) jsh.JSONDecoder {
+	return json.NewDecoder(r)
+}
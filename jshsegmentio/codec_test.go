@@ -0,0 +1,46 @@
+//go:build segmentio
+// +build segmentio
+
+package jshsegmentio
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/derekdowling/go-json-spec-handler"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCodec(t *testing.T) {
+
+	Convey("segmentio Codec Tests", t, func() {
+
+		list := func() string {
+			bob, _ := jsh.NewObject("1", "users", map[string]interface{}{"name": "Bob", "age": 40})
+			alice, _ := jsh.NewObject("2", "users", map[string]interface{}{"name": "Alice", "age": 38})
+
+			w := httptest.NewRecorder()
+			So(jsh.Send(w, httptest.NewRequest("GET", "/users", nil), jsh.List{bob, alice}), ShouldBeNil)
+			return w.Body.String()
+		}
+		standard := list()
+
+		Enable()
+		Reset(func() { jsh.JSON = jsh.StandardCodec{} })
+
+		Convey("should encode lists like encoding/json", func() {
+			So(jsh.JSON.Name(), ShouldEqual, "github.com/segmentio/encoding/json")
+			So(list(), ShouldEqual, standard)
+		})
+
+		Convey("should parse lists", func() {
+			r := httptest.NewRequest("GET", "/users", strings.NewReader(standard))
+			r.Header.Set("Content-Type", jsh.ContentType)
+
+			parsed, err := jsh.ParseList(r)
+			So(err, ShouldBeNil)
+			So(parsed.IDs(), ShouldResemble, []string{"1", "2"})
+		})
+	})
+}
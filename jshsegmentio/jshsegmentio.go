@@ -0,0 +1,18 @@
+/*
+Package jshsegmentio encodes and decodes jsh documents with segmentio/encoding,
+a faster drop-in replacement for encoding/json. It is built with the segmentio
+tag, once github.com/segmentio/encoding is vendored, so services sticking with
+encoding/json never pull in the dependency:
+
+	go build -tags segmentio
+
+Enable it at init, before any requests are handled:
+
+	func init() {
+		jshsegmentio.Enable()
+	}
+
+Since its errors are those of encoding/json, 422 errors for mistyped attributes
+still point at the attribute.
+*/
+package jshsegmentio
@@ -0,0 +1,24 @@
+/*
+Package jshsql serves JSON API resources straight from database/sql tables.
+Declare how a resource type maps to a table and its columns, and the Store
+handles listing, fetching, creating, updating, and deleting rows:
+
+	store := &jshsql.Store{
+		DB:      db,
+		Dialect: jshsql.DollarPlaceholders,
+		Table: &jshsql.Table{
+			Type: "articles",
+			Name: "articles",
+			Columns: map[string]string{
+				"title":   "title",
+				"created": "created_at",
+			},
+		},
+	}
+	store.Handle(mux)
+
+Lists support keyset pagination sorted by any mapped attribute, sparse
+fieldsets, and filters, which are pushed down into the query's WHERE clause.
+Any database/sql driver can be used, the package itself has no dependencies.
+*/
+package jshsql
@@ -0,0 +1,309 @@
+package jshsql
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/derekdowling/go-json-spec-handler"
+)
+
+// Dialect is the placeholder style of a database's driver.
+type Dialect int
+
+const (
+	// QuestionPlaceholders numbers no placeholders, as in MySQL and SQLite
+	QuestionPlaceholders Dialect = iota
+	// DollarPlaceholders numbers placeholders $1, $2, and so on, as in
+	// PostgreSQL
+	DollarPlaceholders
+)
+
+// Table maps a resource type to a database table.
+type Table struct {
+	// Type is the resource type served from the table
+	Type string
+	// Name is the name of the table
+	Name string
+	// IDColumn is the primary key column, "id" by default
+	IDColumn string
+	// Columns maps the resource's attribute names to their columns
+	Columns map[string]string
+}
+
+// idColumn returns the table's primary key column
+func (t *Table) idColumn() string {
+	if t.IDColumn == "" {
+		return "id"
+	}
+
+	return t.IDColumn
+}
+
+// column returns the column of an attribute, or the ID column for "id"
+func (t *Table) column(attribute string) (string, bool) {
+	if attribute == "id" {
+		return t.idColumn(), true
+	}
+
+	column, exists := t.Columns[attribute]
+	return column, exists
+}
+
+// attributes returns the table's attribute names, sorted
+func (t *Table) attributes() []string {
+	attributes := make([]string, 0, len(t.Columns))
+	for attribute := range t.Columns {
+		attributes = append(attributes, attribute)
+	}
+	sort.Strings(attributes)
+
+	return attributes
+}
+
+// query accumulates a SQL statement and its arguments
+type query struct {
+	dialect Dialect
+	sql     strings.Builder
+	args    []interface{}
+}
+
+// write appends SQL to the statement
+func (q *query) write(sql string) {
+	q.sql.WriteString(sql)
+}
+
+// arg appends a placeholder for value to the statement
+func (q *query) arg(value interface{}) {
+	q.args = append(q.args, value)
+	if q.dialect == DollarPlaceholders {
+		q.write("$" + strconv.Itoa(len(q.args)))
+		return
+	}
+
+	q.write("?")
+}
+
+// String returns the statement
+func (q *query) String() string {
+	return q.sql.String()
+}
+
+// selectQuery builds the SELECT of a list, the attributes being those to
+// select besides the ID
+func (s *Store) selectQuery(attributes []string, filter jsh.Filter, keyset *jsh.Keyset) (*query, *jsh.Error) {
+	table := s.Table
+	q := &query{dialect: s.Dialect}
+
+	columns := []string{table.idColumn()}
+	for _, attribute := range attributes {
+		column, _ := table.column(attribute)
+		columns = append(columns, column)
+	}
+	q.write("SELECT " + strings.Join(columns, ", ") + " FROM " + table.Name)
+
+	conditions := 0
+	where := func() {
+		if conditions == 0 {
+			q.write(" WHERE ")
+		} else {
+			q.write(" AND ")
+		}
+		conditions++
+	}
+
+	for _, condition := range filter {
+		column, exists := table.column(condition.Attribute)
+		if !exists {
+			return nil, jsh.ParameterError(fmt.Sprintf("Filtering by '%s' is not supported", condition.Attribute), condition.Parameter())
+		}
+
+		where()
+		writeCondition(q, column, condition)
+	}
+
+	if keyset != nil && keyset.Values != nil {
+		where()
+		err := s.writeKeyset(q, keyset)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if keyset != nil {
+		order := []string{}
+		for _, field := range keyset.Sort {
+			column, exists := table.column(field.Attribute)
+			if !exists {
+				return nil, jsh.ParameterError(fmt.Sprintf("Sorting by '%s' is not supported", field.Attribute), jsh.SortParam)
+			}
+			if field.Direction == jsh.Descending {
+				column += " DESC"
+			}
+			order = append(order, column)
+		}
+		q.write(" ORDER BY " + strings.Join(order, ", "))
+
+		// fetch one extra row to tell whether there is another page
+		q.write(" LIMIT ")
+		q.arg(keyset.Limit + 1)
+	}
+
+	return q, nil
+}
+
+// comparisons maps filter operators to SQL
+var comparisons = map[jsh.FilterOperator]string{
+	jsh.Equal:          " = ",
+	jsh.NotEqual:       " <> ",
+	jsh.GreaterThan:    " > ",
+	jsh.GreaterOrEqual: " >= ",
+	jsh.LessThan:       " < ",
+	jsh.LessOrEqual:    " <= ",
+}
+
+// likeEscaper escapes the wildcards of LIKE patterns, and the escape character
+// itself, which is not a backslash since MySQL would need it escaped in turn
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// writeCondition writes a filter condition, ORing its values
+func writeCondition(q *query, column string, condition jsh.FilterCondition) {
+	q.write("(")
+	for i, value := range condition.Values {
+		if i > 0 {
+			q.write(" OR ")
+		}
+
+		if condition.Operator == jsh.Like {
+			q.write("LOWER(" + column + ") LIKE ")
+			q.arg("%" + likeEscaper.Replace(strings.ToLower(value)) + "%")
+			q.write(" ESCAPE '!'")
+			continue
+		}
+
+		q.write(column + comparisons[condition.Operator])
+		q.arg(value)
+	}
+	q.write(")")
+}
+
+/*
+writeKeyset writes the condition selecting rows after the keyset's values,
+expanded so that each sort field can have its own direction:
+
+	(a > ?) OR (a = ? AND b < ?) OR (a = ? AND b = ? AND id > ?)
+*/
+func (s *Store) writeKeyset(q *query, keyset *jsh.Keyset) *jsh.Error {
+	q.write("(")
+	for i, field := range keyset.Sort {
+		if i > 0 {
+			q.write(" OR ")
+		}
+
+		q.write("(")
+		for j := 0; j < i; j++ {
+			column, _ := s.Table.column(keyset.Sort[j].Attribute)
+			q.write(column + " = ")
+			q.arg(keyset.Values[j])
+			q.write(" AND ")
+		}
+
+		column, exists := s.Table.column(field.Attribute)
+		if !exists {
+			return jsh.ParameterError(fmt.Sprintf("Sorting by '%s' is not supported", field.Attribute), jsh.SortParam)
+		}
+
+		comparison := " > "
+		if field.Direction == jsh.Descending {
+			comparison = " < "
+		}
+		q.write(column + comparison)
+		q.arg(keyset.Values[i])
+		q.write(")")
+	}
+	q.write(")")
+
+	return nil
+}
+
+// fetchQuery builds the SELECT of a single row
+func (s *Store) fetchQuery(id string) *query {
+	q, _ := s.selectQuery(s.Table.attributes(), nil, nil)
+	q.write(" WHERE " + s.Table.idColumn() + " = ")
+	q.arg(id)
+
+	return q
+}
+
+// insertQuery builds the INSERT of a row, id being empty for generated IDs
+func (s *Store) insertQuery(id string, values map[string]interface{}) *query {
+	q := &query{dialect: s.Dialect}
+
+	columns := []string{}
+	if id != "" {
+		columns = append(columns, s.Table.idColumn())
+	}
+	attributes := sortedKeys(values)
+	for _, attribute := range attributes {
+		column, _ := s.Table.column(attribute)
+		columns = append(columns, column)
+	}
+
+	q.write("INSERT INTO " + s.Table.Name + " (" + strings.Join(columns, ", ") + ") VALUES (")
+	if id != "" {
+		q.arg(id)
+	}
+	for i, attribute := range attributes {
+		if i > 0 || id != "" {
+			q.write(", ")
+		}
+		q.arg(values[attribute])
+	}
+	q.write(")")
+
+	if id == "" && s.Dialect == DollarPlaceholders {
+		q.write(" RETURNING " + s.Table.idColumn())
+	}
+
+	return q
+}
+
+// updateQuery builds the UPDATE of a row
+func (s *Store) updateQuery(id string, values map[string]interface{}) *query {
+	q := &query{dialect: s.Dialect}
+
+	q.write("UPDATE " + s.Table.Name + " SET ")
+	for i, attribute := range sortedKeys(values) {
+		if i > 0 {
+			q.write(", ")
+		}
+		column, _ := s.Table.column(attribute)
+		q.write(column + " = ")
+		q.arg(values[attribute])
+	}
+	q.write(" WHERE " + s.Table.idColumn() + " = ")
+	q.arg(id)
+
+	return q
+}
+
+// deleteQuery builds the DELETE of a row
+func (s *Store) deleteQuery(id string) *query {
+	q := &query{dialect: s.Dialect}
+	q.write("DELETE FROM " + s.Table.Name + " WHERE " + s.Table.idColumn() + " = ")
+	q.arg(id)
+
+	return q
+}
+
+// sortedKeys returns the keys of values, sorted
+func sortedKeys(values map[string]interface{}) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,130 @@
+package jshsql
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/derekdowling/go-json-spec-handler"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// testTable maps "articles" to a table with renamed columns
+func testTable() *Table {
+	return &Table{
+		Type: "articles",
+		Name: "articles",
+		Columns: map[string]string{
+			"title":   "title",
+			"created": "created_at",
+		},
+	}
+}
+
+func TestQuery(t *testing.T) {
+
+	Convey("Query Tests", t, func() {
+
+		store := &Store{Table: testTable()}
+
+		list := func(rawURL string) (*query, *jsh.Error) {
+			r := httptest.NewRequest("GET", rawURL, nil)
+
+			keyset, err := jsh.ParseKeyset(r, nil, store.Table.attributes()...)
+			So(err, ShouldBeNil)
+
+			filter, err := jsh.ParseFilter(r, nil)
+			So(err, ShouldBeNil)
+
+			return store.selectQuery(store.Table.attributes(), filter, keyset)
+		}
+
+		Convey("->selectQuery()", func() {
+
+			Convey("should sort and limit", func() {
+				q, err := list("/articles?sort=-created&page[limit]=2")
+				So(err, ShouldBeNil)
+				So(q.String(), ShouldEqual, "SELECT id, created_at, title FROM articles ORDER BY created_at DESC, id LIMIT ?")
+				So(q.args, ShouldResemble, []interface{}{3})
+			})
+
+			Convey("should push filters down", func() {
+				store.Dialect = DollarPlaceholders
+
+				q, err := list("/articles?filter[title][like]=Go&filter[created][gte]=2020,2021")
+				So(err, ShouldBeNil)
+				So(q.String(), ShouldEqual, "SELECT id, created_at, title FROM articles"+
+					" WHERE (created_at >= $1 OR created_at >= $2) AND (LOWER(title) LIKE $3 ESCAPE '!')"+
+					" ORDER BY id LIMIT $4")
+				So(q.args, ShouldResemble, []interface{}{"2020", "2021", "%go%", jsh.DefaultPageLimit + 1})
+			})
+
+			Convey("should escape LIKE wildcards", func() {
+				q, err := list("/articles?filter[title][like]=100%25_off!")
+				So(err, ShouldBeNil)
+				So(q.args[0], ShouldEqual, "%100!%!_off!!%")
+			})
+
+			Convey("should reject sorting by unmapped attributes", func() {
+				keyset := &jsh.Keyset{Sort: jsh.SortSpec{{Attribute: "body"}}, Limit: 10}
+
+				_, err := store.selectQuery(store.Table.attributes(), nil, keyset)
+				So(err, ShouldNotBeNil)
+				So(err.Status, ShouldEqual, 400)
+				So(err.Source.Parameter, ShouldEqual, jsh.SortParam)
+			})
+
+			Convey("should reject filters on unmapped attributes", func() {
+				_, err := list("/articles?filter[body]=x")
+				So(err, ShouldNotBeNil)
+				So(err.Source.Parameter, ShouldEqual, "filter[body][eq]")
+			})
+		})
+
+		Convey("->writeKeyset()", func() {
+			q := &query{}
+			keyset := &jsh.Keyset{
+				Sort: jsh.SortSpec{
+					{Attribute: "created", Direction: jsh.Descending},
+					{Attribute: "id", Direction: jsh.Ascending},
+				},
+				Values: []interface{}{"2020", "7"},
+			}
+
+			err := store.writeKeyset(q, keyset)
+			So(err, ShouldBeNil)
+			So(q.String(), ShouldEqual, "((created_at < ?) OR (created_at = ? AND id > ?))")
+			So(q.args, ShouldResemble, []interface{}{"2020", "2020", "7"})
+		})
+
+		Convey("->insertQuery()", func() {
+			values := map[string]interface{}{"title": "Hello", "created": "2020"}
+
+			q := store.insertQuery("", values)
+			So(q.String(), ShouldEqual, "INSERT INTO articles (created_at, title) VALUES (?, ?)")
+			So(q.args, ShouldResemble, []interface{}{"2020", "Hello"})
+
+			Convey("with client IDs", func() {
+				q := store.insertQuery("a1", values)
+				So(q.String(), ShouldEqual, "INSERT INTO articles (id, created_at, title) VALUES (?, ?, ?)")
+			})
+
+			Convey("returning generated IDs", func() {
+				store.Dialect = DollarPlaceholders
+
+				q := store.insertQuery("", values)
+				So(q.String(), ShouldEqual, "INSERT INTO articles (created_at, title) VALUES ($1, $2) RETURNING id")
+			})
+		})
+
+		Convey("->updateQuery()", func() {
+			q := store.updateQuery("1", map[string]interface{}{"title": "Hello"})
+			So(q.String(), ShouldEqual, "UPDATE articles SET title = ? WHERE id = ?")
+			So(q.args, ShouldResemble, []interface{}{"Hello", "1"})
+		})
+
+		Convey("->deleteQuery()", func() {
+			q := store.deleteQuery("1")
+			So(q.String(), ShouldEqual, "DELETE FROM articles WHERE id = ?")
+		})
+	})
+}
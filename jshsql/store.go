@@ -0,0 +1,416 @@
+package jshsql
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/derekdowling/go-json-spec-handler"
+)
+
+/*
+Store serves a Table's rows as resources. Its List, Fetch, Create, Update, and
+Delete methods are jsh.ResourceHandlers, which Handle registers at the
+conventional routes, or which can be adapted to other routers with jshrouter.
+*/
+type Store struct {
+	DB      *sql.DB
+	Dialect Dialect
+	Table   *Table
+	// Filters lists the filter operators allowed for each attribute, see
+	// jsh.ParseFilter. Any mapped attribute can be filtered with any operator
+	// if nil.
+	Filters map[string][]jsh.FilterOperator
+}
+
+// Handle registers the store's handlers with mux, at /TYPE and /TYPE/{id}.
+func (s *Store) Handle(mux *http.ServeMux) {
+	collection := "/" + jsh.ResourcePath(s.Table.Type)
+	member := collection + "/{" + jsh.IDParam + "}"
+
+	mux.Handle("GET "+collection, jsh.ResourceHandler(s.List))
+	mux.Handle("POST "+collection, jsh.ResourceHandler(s.Create))
+	mux.Handle("GET "+member, jsh.ResourceHandler(s.Fetch))
+	mux.Handle("PATCH "+member, jsh.ResourceHandler(s.Update))
+	mux.Handle("DELETE "+member, jsh.ResourceHandler(s.Delete))
+}
+
+/*
+List sends a page of rows. The page is sorted by the sort query parameter,
+restricted to the mapped attributes, and paginated by keyset. Only the
+attributes of a fields[TYPE] sparse fieldset are selected, and filter query
+parameters become conditions of the query.
+*/
+func (s *Store) List(w http.ResponseWriter, r *http.Request, params jsh.RouteParams) {
+	keyset, err := jsh.ParseKeyset(r, nil, s.Table.attributes()...)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	filters := s.Filters
+	if filters == nil {
+		filters = map[string][]jsh.FilterOperator{"id": nil}
+		for _, attribute := range s.Table.attributes() {
+			filters[attribute] = nil
+		}
+	}
+
+	filter, err := jsh.ParseFilter(r, filters)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	fields, err := s.fieldset(r)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	// sort attributes are selected to build the cursor, even if not requested
+	selected := append([]string{}, fields...)
+	for _, field := range keyset.Sort {
+		if field.Attribute != "id" && !containsString(selected, field.Attribute) {
+			selected = append(selected, field.Attribute)
+		}
+	}
+
+	q, err := s.selectQuery(selected, filter, keyset)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	rows, err := s.query(r, q, selected)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	var last *jsh.Object
+	if len(rows) > keyset.Limit {
+		rows = rows[:keyset.Limit]
+		last, err = s.object(rows[len(rows)-1], selected)
+		if err != nil {
+			jsh.Send(w, r, err)
+			return
+		}
+	}
+
+	list := jsh.List{}
+	for _, row := range rows {
+		object, err := s.object(row, fields)
+		if err != nil {
+			jsh.Send(w, r, err)
+			return
+		}
+		list = append(list, object)
+	}
+
+	document := jsh.Build(list)
+	document.Links, err = keyset.Links(last)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	jsh.SendDocument(w, r, document)
+}
+
+// Fetch sends the row identified by the route's ID, or a 404.
+func (s *Store) Fetch(w http.ResponseWriter, r *http.Request, params jsh.RouteParams) {
+	id, err := jsh.InternalID(s.Table.Type, params.ID)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	object, err := s.fetch(r, id)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	jsh.Send(w, r, object)
+}
+
+/*
+Create inserts a row for a POSTed object and sends the stored resource with a
+201. Rows are given IDs by the database unless the client generated one, which
+the ParseOptions must allow.
+*/
+func (s *Store) Create(w http.ResponseWriter, r *http.Request, params jsh.RouteParams) {
+	object, err := jsh.ParseObjectFor(r, s.Table.Type, "")
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+	if object == nil {
+		jsh.Send(w, r, jsh.InputError("Missing primary data", "data"))
+		return
+	}
+
+	values, err := s.values(object)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	id, err := s.insert(r, object.ID, values)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	created, err := s.fetch(r, id)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	created.Status = http.StatusCreated
+	jsh.Send(w, r, created)
+}
+
+// Update writes the attributes of a PATCHed object to its row, and sends the
+// updated resource.
+func (s *Store) Update(w http.ResponseWriter, r *http.Request, params jsh.RouteParams) {
+	object, err := jsh.ParseObjectFor(r, s.Table.Type, params.ID)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+	if object == nil {
+		jsh.Send(w, r, jsh.InputError("Missing primary data", "data"))
+		return
+	}
+
+	values, err := s.values(object)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	if len(values) > 0 {
+		err = s.exec(r, s.updateQuery(object.ID, values), object.ID)
+		if err != nil {
+			jsh.Send(w, r, err)
+			return
+		}
+	}
+
+	updated, err := s.fetch(r, object.ID)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	jsh.Send(w, r, updated)
+}
+
+// Delete deletes the row identified by the route's ID, sending a 204, or a 404
+// if there is no such row.
+func (s *Store) Delete(w http.ResponseWriter, r *http.Request, params jsh.RouteParams) {
+	id, err := jsh.InternalID(s.Table.Type, params.ID)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	err = s.exec(r, s.deleteQuery(id), id)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// fieldset returns the attributes of the request's sparse fieldset for the
+// table's type, or all of them
+func (s *Store) fieldset(r *http.Request) ([]string, *jsh.Error) {
+	param := "fields[" + s.Table.Type + "]"
+	raw, requested := r.URL.Query()[param]
+	if !requested {
+		return s.Table.attributes(), nil
+	}
+
+	fields := []string{}
+	for _, name := range strings.Split(strings.Join(raw, ","), ",") {
+		if name == "" {
+			continue
+		}
+
+		if _, exists := s.Table.Columns[name]; !exists {
+			return nil, jsh.ParameterError(fmt.Sprintf("Resources of type '%s' have no attribute '%s'", s.Table.Type, name), param)
+		}
+		fields = append(fields, name)
+	}
+
+	return fields, nil
+}
+
+// values converts an object's attributes to column values, rejecting
+// attributes without a column. Objects and arrays are stored as JSON.
+func (s *Store) values(object *jsh.Object) (map[string]interface{}, *jsh.Error) {
+	values := map[string]interface{}{}
+	if !object.HasAttributes() {
+		return values, nil
+	}
+
+	raw := map[string]json.RawMessage{}
+	jsonErr := json.Unmarshal(object.Attributes, &raw)
+	if jsonErr != nil {
+		return nil, jsh.ISE(fmt.Sprintf("Unable to decode attributes: %s", jsonErr.Error()))
+	}
+
+	for attribute, encoded := range raw {
+		if _, exists := s.Table.Columns[attribute]; !exists {
+			return nil, jsh.InputError(fmt.Sprintf("Resources of type '%s' have no attribute '%s'", s.Table.Type, attribute), attribute)
+		}
+
+		var value interface{}
+		json.Unmarshal(encoded, &value)
+		switch value.(type) {
+		case map[string]interface{}, []interface{}:
+			value = string(encoded)
+		}
+		values[attribute] = value
+	}
+
+	return values, nil
+}
+
+// fetch loads the row with the given internal ID
+func (s *Store) fetch(r *http.Request, id string) (*jsh.Object, *jsh.Error) {
+	attributes := s.Table.attributes()
+
+	rows, err := s.query(r, s.fetchQuery(id), attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return nil, jsh.NotFound(s.Table.Type, id)
+	}
+
+	return s.object(rows[0], attributes)
+}
+
+// insert inserts a row, returning its ID
+func (s *Store) insert(r *http.Request, id string, values map[string]interface{}) (string, *jsh.Error) {
+	q := s.insertQuery(id, values)
+
+	if id == "" && s.Dialect == DollarPlaceholders {
+		var generated interface{}
+		err := s.DB.QueryRowContext(r.Context(), q.String(), q.args...).Scan(&generated)
+		if err != nil {
+			return "", jsh.ISE(fmt.Sprintf("Unable to insert into %s: %s", s.Table.Name, err.Error()))
+		}
+
+		return formatID(generated), nil
+	}
+
+	result, err := s.DB.ExecContext(r.Context(), q.String(), q.args...)
+	if err != nil {
+		return "", jsh.ISE(fmt.Sprintf("Unable to insert into %s: %s", s.Table.Name, err.Error()))
+	}
+
+	if id != "" {
+		return id, nil
+	}
+
+	generated, err := result.LastInsertId()
+	if err != nil {
+		return "", jsh.ISE(fmt.Sprintf("Unable to read the ID inserted into %s: %s", s.Table.Name, err.Error()))
+	}
+
+	return formatID(generated), nil
+}
+
+// exec executes a statement affecting the row with the given ID, returning a
+// 404 if there is no such row
+func (s *Store) exec(r *http.Request, q *query, id string) *jsh.Error {
+	result, err := s.DB.ExecContext(r.Context(), q.String(), q.args...)
+	if err != nil {
+		return jsh.ISE(fmt.Sprintf("Unable to write to %s: %s", s.Table.Name, err.Error()))
+	}
+
+	affected, err := result.RowsAffected()
+	if err == nil && affected == 0 {
+		return jsh.NotFound(s.Table.Type, id)
+	}
+
+	return nil
+}
+
+// row is a scanned row, the ID followed by the selected attributes
+type row []interface{}
+
+// query runs a SELECT of the given attributes
+func (s *Store) query(r *http.Request, q *query, attributes []string) ([]row, *jsh.Error) {
+	rows, err := s.DB.QueryContext(r.Context(), q.String(), q.args...)
+	if err != nil {
+		return nil, jsh.ISE(fmt.Sprintf("Unable to query %s: %s", s.Table.Name, err.Error()))
+	}
+	defer rows.Close()
+
+	scanned := []row{}
+	for rows.Next() {
+		values := make(row, len(attributes)+1)
+		targets := make([]interface{}, len(values))
+		for i := range values {
+			targets[i] = &values[i]
+		}
+
+		err := rows.Scan(targets...)
+		if err != nil {
+			return nil, jsh.ISE(fmt.Sprintf("Unable to scan %s: %s", s.Table.Name, err.Error()))
+		}
+		scanned = append(scanned, values)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, jsh.ISE(fmt.Sprintf("Unable to query %s: %s", s.Table.Name, err.Error()))
+	}
+
+	return scanned, nil
+}
+
+// object builds a resource object from a row selecting the given attributes,
+// any further attributes of the row are left out
+func (s *Store) object(values row, attributes []string) (*jsh.Object, *jsh.Error) {
+	members := map[string]interface{}{}
+	for i, attribute := range attributes {
+		value := values[i+1]
+		if bytes, isBytes := value.([]byte); isBytes {
+			value = string(bytes)
+		}
+		members[attribute] = value
+	}
+
+	return jsh.NewObject(formatID(values[0]), s.Table.Type, members)
+}
+
+// formatID formats a scanned ID column
+func formatID(id interface{}) string {
+	if bytes, isBytes := id.([]byte); isBytes {
+		return string(bytes)
+	}
+
+	return fmt.Sprint(id)
+}
+
+// containsString returns true if values contains value
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,281 @@
+package jshsql
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/derekdowling/go-json-spec-handler"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// testResult is a scripted response to a statement
+type testResult struct {
+	columns  []string
+	rows     [][]driver.Value
+	affected int64
+	lastID   int64
+}
+
+func (r *testResult) LastInsertId() (int64, error) {
+	return r.lastID, nil
+}
+
+func (r *testResult) RowsAffected() (int64, error) {
+	return r.affected, nil
+}
+
+// testDriver records statements and answers them with scripted results
+type testDriver struct {
+	mu         sync.Mutex
+	statements []string
+	args       [][]driver.Value
+	results    []*testResult
+}
+
+var scripted = &testDriver{}
+
+func init() {
+	sql.Register("jshsql-test", scripted)
+}
+
+// script replaces the recorded statements and queued results
+func (d *testDriver) script(results ...*testResult) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.statements = nil
+	d.args = nil
+	d.results = results
+}
+
+// next records a statement and returns its result
+func (d *testDriver) next(statement string, args []driver.Value) *testResult {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.statements = append(d.statements, statement)
+	d.args = append(d.args, args)
+	if len(d.results) == 0 {
+		return &testResult{}
+	}
+
+	result := d.results[0]
+	d.results = d.results[1:]
+	return result
+}
+
+func (d *testDriver) Open(name string) (driver.Conn, error) {
+	return &testConn{driver: d}, nil
+}
+
+type testConn struct {
+	driver *testDriver
+}
+
+func (c *testConn) Prepare(query string) (driver.Stmt, error) {
+	return &testStmt{conn: c, query: query}, nil
+}
+
+func (c *testConn) Close() error {
+	return nil
+}
+
+func (c *testConn) Begin() (driver.Tx, error) {
+	return nil, driver.ErrSkip
+}
+
+type testStmt struct {
+	conn  *testConn
+	query string
+}
+
+func (s *testStmt) Close() error {
+	return nil
+}
+
+func (s *testStmt) NumInput() int {
+	return -1
+}
+
+func (s *testStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.conn.driver.next(s.query, args), nil
+}
+
+func (s *testStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return &testRows{result: s.conn.driver.next(s.query, args)}, nil
+}
+
+type testRows struct {
+	result *testResult
+	index  int
+}
+
+func (r *testRows) Columns() []string {
+	return r.result.columns
+}
+
+func (r *testRows) Close() error {
+	return nil
+}
+
+func (r *testRows) Next(dest []driver.Value) error {
+	if r.index >= len(r.result.rows) {
+		return io.EOF
+	}
+
+	copy(dest, r.result.rows[r.index])
+	r.index++
+	return nil
+}
+
+// articleRows scripts a SELECT of id, created_at, and title
+func articleRows(rows ...[]driver.Value) *testResult {
+	return &testResult{columns: []string{"id", "created_at", "title"}, rows: rows}
+}
+
+func TestStore(t *testing.T) {
+
+	Convey("Store Tests", t, func() {
+
+		db, err := sql.Open("jshsql-test", "")
+		So(err, ShouldBeNil)
+		Reset(func() { db.Close() })
+
+		store := &Store{DB: db, Table: testTable()}
+
+		serve := func(handler jsh.ResourceHandler, method string, target string, body string, id string) *httptest.ResponseRecorder {
+			var payload io.Reader
+			if body != "" {
+				payload = strings.NewReader(body)
+			}
+
+			r := httptest.NewRequest(method, target, payload)
+			r.Header.Set("Content-Type", jsh.ContentType)
+
+			w := httptest.NewRecorder()
+			handler(w, r, jsh.RouteParams{ID: id})
+			return w
+		}
+
+		Convey("->List()", func() {
+			// title is selected first, as the requested field
+			scripted.script(&testResult{
+				columns: []string{"id", "title", "created_at"},
+				rows: [][]driver.Value{
+					{int64(1), []byte("First"), []byte("2020")},
+					{int64(2), []byte("Second"), []byte("2021")},
+				},
+			})
+
+			w := serve(store.List, "GET", "/articles?sort=-created&page[limit]=1&fields[articles]=title", "", "")
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(scripted.statements[0], ShouldEqual, "SELECT id, title, created_at FROM articles ORDER BY created_at DESC, id LIMIT ?")
+
+			doc := &jsh.Document{}
+			So(json.Unmarshal(w.Body.Bytes(), doc), ShouldBeNil)
+			So(len(doc.Data), ShouldEqual, 1)
+			So(doc.Data[0].ID, ShouldEqual, "1")
+			So(doc.Data[0].HasAttribute("created"), ShouldBeFalse)
+			So(doc.Links.Next, ShouldNotBeNil)
+
+			Convey("should continue after the cursor", func() {
+				scripted.script(articleRows())
+
+				w := serve(store.List, "GET", doc.Links.Next.HREF, "", "")
+				So(w.Code, ShouldEqual, http.StatusOK)
+				So(scripted.statements[0], ShouldContainSubstring, "WHERE ((created_at < ?) OR (created_at = ? AND id > ?))")
+				So(scripted.args[0][0], ShouldEqual, "2020")
+			})
+		})
+
+		Convey("->List() should reject unknown fields", func() {
+			w := serve(store.List, "GET", "/articles?fields[articles]=body", "", "")
+			So(w.Code, ShouldEqual, http.StatusBadRequest)
+		})
+
+		Convey("->Fetch()", func() {
+			scripted.script(articleRows([]driver.Value{int64(1), []byte("2020"), []byte("First")}))
+
+			w := serve(store.Fetch, "GET", "/articles/1", "", "1")
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(scripted.statements[0], ShouldEqual, "SELECT id, created_at, title FROM articles WHERE id = ?")
+			So(w.Body.String(), ShouldContainSubstring, `"First"`)
+
+			Convey("should 404 for missing rows", func() {
+				scripted.script(articleRows())
+
+				w := serve(store.Fetch, "GET", "/articles/9", "", "9")
+				So(w.Code, ShouldEqual, http.StatusNotFound)
+			})
+		})
+
+		Convey("->Create()", func() {
+			scripted.script(
+				&testResult{affected: 1, lastID: 3},
+				articleRows([]driver.Value{int64(3), []byte("2022"), []byte("Third")}),
+			)
+
+			w := serve(store.Create, "POST", "/articles", `{"data": {"type": "articles", "attributes": {"title": "Third", "created": "2022"}}}`, "")
+			So(w.Code, ShouldEqual, http.StatusCreated)
+			So(scripted.statements[0], ShouldEqual, "INSERT INTO articles (created_at, title) VALUES (?, ?)")
+			So(scripted.args[1], ShouldResemble, []driver.Value{"3"})
+
+			Convey("should reject unmapped attributes", func() {
+				w := serve(store.Create, "POST", "/articles", `{"data": {"type": "articles", "attributes": {"body": "x"}}}`, "")
+				So(w.Code, ShouldEqual, 422)
+			})
+		})
+
+		Convey("->Create() with generated IDs returned", func() {
+			store.Dialect = DollarPlaceholders
+			scripted.script(
+				&testResult{columns: []string{"id"}, rows: [][]driver.Value{{int64(3)}}},
+				articleRows([]driver.Value{int64(3), []byte("2022"), []byte("Third")}),
+			)
+
+			w := serve(store.Create, "POST", "/articles", `{"data": {"type": "articles", "attributes": {"title": "Third"}}}`, "")
+			So(w.Code, ShouldEqual, http.StatusCreated)
+			So(scripted.statements[0], ShouldEqual, "INSERT INTO articles (title) VALUES ($1) RETURNING id")
+			So(scripted.args[1], ShouldResemble, []driver.Value{"3"})
+		})
+
+		Convey("->Update()", func() {
+			scripted.script(
+				&testResult{affected: 1},
+				articleRows([]driver.Value{int64(1), []byte("2020"), []byte("Renamed")}),
+			)
+
+			w := serve(store.Update, "PATCH", "/articles/1", `{"data": {"type": "articles", "id": "1", "attributes": {"title": "Renamed"}}}`, "1")
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(scripted.statements[0], ShouldEqual, "UPDATE articles SET title = ? WHERE id = ?")
+			So(w.Body.String(), ShouldContainSubstring, `"Renamed"`)
+
+			Convey("should 409 for mismatched IDs", func() {
+				w := serve(store.Update, "PATCH", "/articles/2", `{"data": {"type": "articles", "id": "1", "attributes": {}}}`, "2")
+				So(w.Code, ShouldEqual, http.StatusConflict)
+			})
+		})
+
+		Convey("->Delete()", func() {
+			scripted.script(&testResult{affected: 1})
+
+			w := serve(store.Delete, "DELETE", "/articles/1", "", "1")
+			So(w.Code, ShouldEqual, http.StatusNoContent)
+			So(scripted.statements[0], ShouldEqual, "DELETE FROM articles WHERE id = ?")
+
+			Convey("should 404 for missing rows", func() {
+				scripted.script(&testResult{affected: 0})
+
+				w := serve(store.Delete, "DELETE", "/articles/9", "", "9")
+				So(w.Code, ShouldEqual, http.StatusNotFound)
+			})
+		})
+	})
+}
@@ -0,0 +1,185 @@
+package jshtest
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/derekdowling/go-json-spec-handler"
+)
+
+// UpdateGolden rewrites golden files with the actual output rather than
+// comparing against them. Set it with the -jshtest.update test flag.
+var UpdateGolden = flag.Bool("jshtest.update", false, "rewrite jshtest golden files")
+
+// Recorder is an httptest.ResponseRecorder that parses the recorded response
+// back into a Document.
+type Recorder struct {
+	*httptest.ResponseRecorder
+}
+
+// NewRecorder returns an initialized Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{ResponseRecorder: httptest.NewRecorder()}
+}
+
+// Document parses the recorded response, see Parse.
+func (r *Recorder) Document(mode jsh.DocumentMode) (*jsh.Document, *jsh.Error) {
+	return Parse(r.ResponseRecorder, mode)
+}
+
+/*
+Parse parses the body of a recorded response back into a Document, including
+error documents. The document's Status is that of the response.
+*/
+func Parse(recorder *httptest.ResponseRecorder, mode jsh.DocumentMode) (*jsh.Document, *jsh.Error) {
+	parser := &jsh.Parser{Headers: recorder.Header()}
+
+	body := ioutil.NopCloser(bytes.NewReader(recorder.Body.Bytes()))
+	document, err := parser.Document(body, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	document.Status = recorder.Code
+	return document, nil
+}
+
+/*
+AssertObject checks that the recorded response is a successful single resource
+document for the given type and ID, returning the object:
+
+	recorder := jshtest.NewRecorder()
+	handler.ServeHTTP(recorder, jshtest.NewRequest("GET", "/users/123", nil))
+
+	user := jshtest.AssertObject(t, recorder.ResponseRecorder, "users", "123")
+
+Nil is returned, and the test marked as failed, if it isn't.
+*/
+func AssertObject(t testing.TB, recorder *httptest.ResponseRecorder, resourceType string, id string) *jsh.Object {
+	t.Helper()
+
+	document := assertSuccess(t, recorder, jsh.ObjectMode)
+	if document == nil {
+		return nil
+	}
+
+	object := document.First()
+	if object == nil {
+		t.Errorf("expected a '%s' resource with ID '%s', got no data", resourceType, id)
+		return nil
+	}
+
+	if object.Type != resourceType || object.ID != id {
+		t.Errorf("expected a '%s' resource with ID '%s', got '%s' with ID '%s'", resourceType, id, object.Type, object.ID)
+		return nil
+	}
+
+	return object
+}
+
+/*
+AssertList checks that the recorded response is a successful list of the given
+type, with the given IDs in order, returning the list.
+*/
+func AssertList(t testing.TB, recorder *httptest.ResponseRecorder, resourceType string, ids ...string) jsh.List {
+	t.Helper()
+
+	document := assertSuccess(t, recorder, jsh.ListMode)
+	if document == nil {
+		return nil
+	}
+
+	if len(document.Data) != len(ids) {
+		t.Errorf("expected %d '%s' resources, got %d: %v", len(ids), resourceType, len(document.Data), document.Data.IDs())
+		return nil
+	}
+
+	for i, object := range document.Data {
+		if object.Type != resourceType || object.ID != ids[i] {
+			t.Errorf("expected '%s' resource %d to have ID '%s', got '%s' with ID '%s'", resourceType, i, ids[i], object.Type, object.ID)
+			return nil
+		}
+	}
+
+	return document.Data
+}
+
+// AssertError checks that the recorded response is an error document with the
+// given status, returning its errors.
+func AssertError(t testing.TB, recorder *httptest.ResponseRecorder, status int) jsh.ErrorList {
+	t.Helper()
+
+	if recorder.Code != status {
+		t.Errorf("expected status %d, got %d:\n%s", status, recorder.Code, recorder.Body.String())
+		return nil
+	}
+
+	document, err := Parse(recorder, jsh.ErrorMode)
+	if err != nil {
+		t.Errorf("unable to parse response: %s", err.Error())
+		return nil
+	}
+
+	if !document.HasErrors() {
+		t.Errorf("expected an error document, got:\n%s", recorder.Body.String())
+		return nil
+	}
+
+	return document.Errors
+}
+
+// assertSuccess parses a recorded response that should have a 2xx status and
+// no errors
+func assertSuccess(t testing.TB, recorder *httptest.ResponseRecorder, mode jsh.DocumentMode) *jsh.Document {
+	t.Helper()
+
+	if recorder.Code < 200 || recorder.Code >= 300 {
+		t.Errorf("expected a successful response, got %d:\n%s", recorder.Code, recorder.Body.String())
+		return nil
+	}
+
+	document, err := Parse(recorder, mode)
+	if err != nil {
+		t.Errorf("unable to parse response: %s", err.Error())
+		return nil
+	}
+
+	return document
+}
+
+/*
+AssertGolden compares the recorded body against the golden file at path, such
+as testdata/users.golden.json. Run the tests with -jshtest.update to create or
+rewrite golden files after an intended change in output.
+*/
+func AssertGolden(t testing.TB, recorder *httptest.ResponseRecorder, path string) {
+	t.Helper()
+
+	actual := recorder.Body.Bytes()
+
+	if *UpdateGolden {
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+		if err == nil {
+			err = ioutil.WriteFile(path, actual, 0644)
+		}
+		if err != nil {
+			t.Errorf("unable to update golden file '%s': %s", path, err.Error())
+		}
+		return
+	}
+
+	expected, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Errorf("unable to read golden file '%s', run with -jshtest.update to create it: %s", path, err.Error())
+		return
+	}
+
+	if !bytes.Equal(expected, actual) {
+		t.Errorf("output doesn't match golden file '%s', expected:\n%s\ngot:\n%s", path, expected, actual)
+	}
+}
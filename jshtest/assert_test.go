@@ -0,0 +1,135 @@
+package jshtest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/derekdowling/go-json-spec-handler"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// recordingT records the failures of assertions under test
+type recordingT struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestAssert(t *testing.T) {
+
+	Convey("Assertion Tests", t, func() {
+
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case "POST":
+				object, err := jsh.ParseObject(r)
+				if err != nil {
+					jsh.Send(w, r, err)
+					return
+				}
+				object.ID = "123"
+				jsh.Send(w, r, object)
+			case "GET":
+				if r.URL.Path == "/users" {
+					first, _ := jsh.NewObject("1", "users", map[string]string{"name": "a"})
+					second, _ := jsh.NewObject("2", "users", map[string]string{"name": "b"})
+					jsh.Send(w, r, jsh.List{first, second})
+					return
+				}
+				jsh.Send(w, r, jsh.NotFound("users", "404"))
+			}
+		})
+
+		recordT := &recordingT{TB: t}
+		recorder := NewRecorder()
+
+		Convey("->NewRequest()", func() {
+			object, _ := jsh.NewObject("", "users", map[string]string{"name": "bob"})
+			handler.ServeHTTP(recorder, NewRequest("POST", "/users", object))
+
+			So(recorder.Code, ShouldEqual, http.StatusCreated)
+
+			doc, err := recorder.Document(jsh.ObjectMode)
+			So(err, ShouldBeNil)
+			So(doc.Status, ShouldEqual, http.StatusCreated)
+			So(doc.First().ID, ShouldEqual, "123")
+		})
+
+		Convey("->AssertObject()", func() {
+			object, _ := jsh.NewObject("", "users", map[string]string{"name": "bob"})
+			handler.ServeHTTP(recorder, NewRequest("POST", "/users", object))
+
+			Convey("should return matching objects", func() {
+				user := AssertObject(recordT, recorder.ResponseRecorder, "users", "123")
+				So(recordT.failures, ShouldBeEmpty)
+				So(user, ShouldNotBeNil)
+			})
+
+			Convey("should fail for other resources", func() {
+				user := AssertObject(recordT, recorder.ResponseRecorder, "users", "1")
+				So(len(recordT.failures), ShouldEqual, 1)
+				So(user, ShouldBeNil)
+			})
+		})
+
+		Convey("->AssertList()", func() {
+			handler.ServeHTTP(recorder, NewRequest("GET", "/users", nil))
+
+			list := AssertList(recordT, recorder.ResponseRecorder, "users", "1", "2")
+			So(recordT.failures, ShouldBeEmpty)
+			So(len(list), ShouldEqual, 2)
+
+			AssertList(recordT, recorder.ResponseRecorder, "users", "2", "1")
+			So(len(recordT.failures), ShouldEqual, 1)
+		})
+
+		Convey("->AssertError()", func() {
+			handler.ServeHTTP(recorder, NewRequest("GET", "/users/404", nil))
+
+			errors := AssertError(recordT, recorder.ResponseRecorder, http.StatusNotFound)
+			So(recordT.failures, ShouldBeEmpty)
+			So(len(errors), ShouldEqual, 1)
+
+			AssertObject(recordT, recorder.ResponseRecorder, "users", "404")
+			So(len(recordT.failures), ShouldEqual, 1)
+		})
+
+		Convey("->AssertGolden()", func() {
+			dir, _ := ioutil.TempDir("", "jshtest")
+			Reset(func() {
+				os.RemoveAll(dir)
+				*UpdateGolden = false
+			})
+			path := filepath.Join(dir, "users.golden.json")
+
+			handler.ServeHTTP(recorder, NewRequest("GET", "/users", nil))
+
+			Convey("should fail without a golden file", func() {
+				AssertGolden(recordT, recorder.ResponseRecorder, path)
+				So(len(recordT.failures), ShouldEqual, 1)
+			})
+
+			Convey("should compare against updated golden files", func() {
+				*UpdateGolden = true
+				AssertGolden(recordT, recorder.ResponseRecorder, path)
+				*UpdateGolden = false
+
+				AssertGolden(recordT, recorder.ResponseRecorder, path)
+				So(recordT.failures, ShouldBeEmpty)
+
+				ioutil.WriteFile(path, []byte("{}"), 0644)
+				AssertGolden(recordT, recorder.ResponseRecorder, path)
+				So(len(recordT.failures), ShouldEqual, 1)
+			})
+		})
+	})
+}
@@ -0,0 +1,136 @@
+package jshtest
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/derekdowling/go-json-spec-handler"
+)
+
+// ErrInjectedDrop is returned by a Faults transport for dropped connections.
+var ErrInjectedDrop = errors.New("jshtest: connection dropped by fault injection")
+
+/*
+Faults injects failures into servers and clients built on jsh, so that retry and
+fallback behavior can be verified without external chaos tooling. Each rate is
+the probability, between 0 and 1, of the failure occurring for a request.
+Injection is deterministic for a given Seed:
+
+	faults := &jshtest.Faults{Seed: 1, ServerErrorRate: 0.3, DropRate: 0.1}
+	server := httptest.NewServer(faults.Handler(api))
+	client := &jsc.Client{
+		HTTPClient: &http.Client{Transport: faults.Transport(nil)},
+		Retry:      jsc.DefaultRetryPolicy,
+	}
+
+Faults are meant for tests only, never wrap production handlers with them.
+*/
+type Faults struct {
+	Seed int64
+	// SlowParseRate is the rate at which reading request bodies is delayed by
+	// ParseDelay, simulating slow clients or large payloads
+	SlowParseRate float64
+	ParseDelay    time.Duration
+	// ServerErrorRate is the rate at which the handler is skipped and an error
+	// response with ServerErrorStatus, 503 by default, is sent instead
+	ServerErrorRate   float64
+	ServerErrorStatus int
+	// DropRate is the rate at which the client transport fails requests with
+	// ErrInjectedDrop without sending them
+	DropRate float64
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// Handler wraps next, injecting slow parses and server errors.
+func (f *Faults) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if f.roll(f.ServerErrorRate) {
+			jsh.Send(w, r, f.serverError())
+			return
+		}
+
+		if r.Body != nil && f.roll(f.SlowParseRate) {
+			r.Body = &slowReader{ReadCloser: r.Body, delay: f.ParseDelay}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Transport wraps next, http.DefaultTransport if nil, injecting dropped
+// connections.
+func (f *Faults) Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if f.roll(f.DropRate) {
+			if r.Body != nil {
+				r.Body.Close()
+			}
+			return nil, ErrInjectedDrop
+		}
+
+		return next.RoundTrip(r)
+	})
+}
+
+// roll returns true with the given probability
+func (f *Faults) roll(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.rand == nil {
+		f.rand = rand.New(rand.NewSource(f.Seed))
+	}
+
+	return f.rand.Float64() < rate
+}
+
+// serverError builds the injected error response
+func (f *Faults) serverError() *jsh.Error {
+	status := f.ServerErrorStatus
+	if status == 0 {
+		status = http.StatusServiceUnavailable
+	}
+
+	return &jsh.Error{
+		Title:  http.StatusText(status),
+		Detail: fmt.Sprintf("Injected %d response", status),
+		Status: status,
+	}
+}
+
+// slowReader delays the first read of a request body
+type slowReader struct {
+	io.ReadCloser
+	delay   time.Duration
+	delayed bool
+}
+
+func (s *slowReader) Read(p []byte) (int, error) {
+	if !s.delayed {
+		s.delayed = true
+		time.Sleep(s.delay)
+	}
+
+	return s.ReadCloser.Read(p)
+}
+
+type roundTripperFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
@@ -0,0 +1,104 @@
+package jshtest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/derekdowling/go-json-spec-handler"
+	"github.com/derekdowling/go-json-spec-handler/client"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFaults(t *testing.T) {
+
+	Convey("Fault Injection Tests", t, func() {
+
+		faults := &Faults{Seed: 1}
+
+		api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == "POST" {
+				object, err := jsh.ParseObject(r)
+				if err != nil {
+					jsh.Send(w, r, err)
+					return
+				}
+				object.ID = "1"
+				jsh.Send(w, r, object)
+				return
+			}
+
+			object, _ := jsh.NewObject("1", "tests", map[string]string{"foo": "bar"})
+			jsh.Send(w, r, object)
+		})
+
+		server := httptest.NewServer(faults.Handler(api))
+		Reset(func() {
+			server.Close()
+		})
+
+		Convey("->Handler()", func() {
+
+			Convey("should inject server errors", func() {
+				faults.ServerErrorRate = 1
+				faults.ServerErrorStatus = http.StatusBadGateway
+
+				_, resp, err := jsc.Fetch(server.URL, "tests", "1")
+				So(err, ShouldNotBeNil)
+				So(resp.StatusCode, ShouldEqual, http.StatusBadGateway)
+			})
+
+			Convey("should inject slow parses", func() {
+				faults.SlowParseRate = 1
+				faults.ParseDelay = 20 * time.Millisecond
+
+				object, _ := jsh.NewObject("", "tests", map[string]string{"foo": "bar"})
+
+				start := time.Now()
+				_, resp, err := jsc.Post(server.URL, object)
+				So(err, ShouldBeNil)
+				So(resp.StatusCode, ShouldEqual, http.StatusCreated)
+				So(time.Since(start), ShouldBeGreaterThanOrEqualTo, faults.ParseDelay)
+			})
+
+			Convey("should pass requests through otherwise", func() {
+				_, resp, err := jsc.Fetch(server.URL, "tests", "1")
+				So(err, ShouldBeNil)
+				So(resp.StatusCode, ShouldEqual, http.StatusOK)
+			})
+		})
+
+		Convey("->Transport()", func() {
+			client := &jsc.Client{HTTPClient: &http.Client{Transport: faults.Transport(nil)}}
+
+			Convey("should drop connections", func() {
+				faults.DropRate = 1
+
+				request, _ := jsc.FetchRequest(server.URL, "tests", "1")
+				_, _, err := client.Do(request, jsh.ObjectMode)
+				So(err, ShouldNotBeNil)
+				So(strings.Contains(err.Error(), ErrInjectedDrop.Error()), ShouldBeTrue)
+			})
+
+			Convey("should be recoverable with retries", func() {
+				faults.DropRate = 0.5
+				faults.ServerErrorRate = 0.3
+				client.Retry = &jsc.RetryPolicy{
+					MaxAttempts: 20,
+					BaseDelay:   time.Millisecond,
+					MaxDelay:    time.Millisecond,
+					Statuses:    jsc.DefaultRetryPolicy.Statuses,
+				}
+
+				for i := 0; i < 10; i++ {
+					request, _ := jsc.FetchRequest(server.URL, "tests", "1")
+					doc, _, err := client.Do(request, jsh.ObjectMode)
+					So(err, ShouldBeNil)
+					So(doc.First().ID, ShouldEqual, "1")
+				}
+			})
+		})
+	})
+}
@@ -0,0 +1,166 @@
+package jshtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+
+	"github.com/derekdowling/go-json-spec-handler"
+)
+
+const alphanum = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+/*
+Generator produces random Documents that are valid according to jsh, as well as
+"near miss" payloads that are almost valid but should be rejected by the parser.
+Generators are deterministic for a given seed so failures can be reproduced:
+
+	gen := jshtest.NewGenerator(seed)
+	doc := gen.Document()
+*/
+type Generator struct {
+	rand *rand.Rand
+}
+
+// NewGenerator creates a Generator seeded with the provided value.
+func NewGenerator(seed int64) *Generator {
+	return &Generator{rand: rand.New(rand.NewSource(seed))}
+}
+
+// Document generates a random valid Document in either ObjectMode or ListMode.
+func (g *Generator) Document() *jsh.Document {
+	var doc *jsh.Document
+
+	if g.rand.Intn(2) == 0 {
+		doc = jsh.Build(g.Object())
+	} else {
+		doc = jsh.Build(g.List())
+	}
+
+	if doc.HasData() && g.rand.Intn(3) == 0 {
+		doc.Included = g.List()
+	}
+
+	if g.rand.Intn(3) == 0 {
+		doc.Meta = g.attributes()
+	}
+
+	return doc
+}
+
+// List generates a list of zero to four random Objects, each with an ID.
+func (g *Generator) List() jsh.List {
+	list := jsh.List{}
+	for i := g.rand.Intn(5); i > 0; i-- {
+		list = append(list, g.Object())
+	}
+
+	return list
+}
+
+// Object generates a random Object with attributes, links, and relationships.
+func (g *Generator) Object() *jsh.Object {
+	object, err := jsh.NewObject(g.word(8), g.word(6), g.attributes())
+	if err != nil {
+		panic(err.Error())
+	}
+
+	if g.rand.Intn(2) == 0 {
+		object.Links["self"] = &jsh.Link{HREF: fmt.Sprintf("/%s/%s", object.Type, object.ID)}
+	}
+
+	for i := g.rand.Intn(3); i > 0; i-- {
+		if g.rand.Intn(2) == 0 {
+			object.AddToOneRelationship(g.word(5), g.word(6), g.word(8))
+			continue
+		}
+
+		identifiers := []*jsh.ResourceIdentifier{}
+		for j := g.rand.Intn(3) + 1; j > 0; j-- {
+			identifiers = append(identifiers, &jsh.ResourceIdentifier{Type: g.word(6), ID: g.word(8)})
+		}
+
+		object.AddToManyRelationship(g.word(5), identifiers...)
+	}
+
+	return object
+}
+
+/*
+NearMiss generates the JSON body of a document that is one mutation away from
+being valid, along with a description of that mutation. Parsing the result with
+jsh should always fail.
+*/
+func (g *Generator) NearMiss() ([]byte, string) {
+	doc := jsh.Build(jsh.List{g.Object(), g.Object()})
+
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	generic := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		panic(err.Error())
+	}
+
+	data := generic["data"].([]interface{})
+	target := data[g.rand.Intn(len(data))].(map[string]interface{})
+
+	var description string
+	switch g.rand.Intn(3) {
+	case 0:
+		delete(target, "type")
+		description = "resource object missing type"
+	case 1:
+		delete(target, "id")
+		description = "list member missing id"
+	default:
+		description = "truncated document"
+	}
+
+	mutated, err := json.Marshal(generic)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	if description == "truncated document" {
+		mutated = mutated[:g.rand.Intn(len(mutated)-1)+1]
+	}
+
+	return mutated, description
+}
+
+// attributes generates a flat map of random attribute values
+func (g *Generator) attributes() map[string]interface{} {
+	attributes := map[string]interface{}{}
+
+	for i := g.rand.Intn(4) + 1; i > 0; i-- {
+		key := g.word(6)
+
+		switch g.rand.Intn(4) {
+		case 0:
+			attributes[key] = g.word(12)
+		case 1:
+			attributes[key] = g.rand.Intn(10000)
+		case 2:
+			attributes[key] = g.rand.Intn(2) == 0
+		default:
+			attributes[key] = []string{g.word(4), g.word(4)}
+		}
+	}
+
+	return attributes
+}
+
+// word generates a random alphanumeric string of length n, always starting with
+// a letter
+func (g *Generator) word(n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = alphanum[g.rand.Intn(len(alphanum))]
+	}
+	b[0] = alphanum[g.rand.Intn(26)]
+
+	return string(b)
+}
@@ -0,0 +1,4 @@
+// Package jshtest provides utilities for testing code built on top of jsh, such
+// as request builders, response assertions, golden files, a mock API server,
+// random Document generators, and round trip property checks.
+package jshtest
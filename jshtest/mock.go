@@ -0,0 +1,407 @@
+package jshtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/derekdowling/go-json-spec-handler"
+)
+
+/*
+MockAPI is a JSON API server backed by canned resources, for testing client
+integrations without the real API. It serves and updates resources, their
+relationships, and related resources, with cursor pagination and includes:
+
+	api := jshtest.NewMockAPI()
+	defer api.Close()
+
+	author, _ := jsh.NewObject("1", "users", user)
+	article, _ := jsh.NewObject("1", "articles", post)
+	article.AddToOneRelationship("author", "users", "1")
+	api.Add(author, article)
+
+	doc, _, err := jsc.Fetch(api.URL, "articles", "1")
+	...
+	request := api.Requests()[0]
+
+Links to relationships follow the objects' linkage, so relationship graphs are
+built by adding relationships to the canned objects.
+*/
+type MockAPI struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	resources map[string]jsh.List
+	requests  []*RecordedRequest
+	nextID    int
+}
+
+// RecordedRequest is a request received by a MockAPI.
+type RecordedRequest struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// NewMockAPI starts a MockAPI, which should be closed when the test is done.
+func NewMockAPI() *MockAPI {
+	api := &MockAPI{resources: map[string]jsh.List{}}
+	api.Server = httptest.NewServer(http.HandlerFunc(api.serve))
+
+	return api
+}
+
+// Add adds canned resources, replacing any with the same type and ID.
+func (m *MockAPI) Add(objects ...*jsh.Object) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, object := range objects {
+		m.put(object)
+	}
+}
+
+// Resource returns the stored resource, reflecting any writes received, or nil
+// if there isn't one.
+func (m *MockAPI) Resource(resourceType string, id string) *jsh.Object {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.resources[resourceType].FindByID(resourceType, id)
+}
+
+// Requests returns the requests received so far, in order.
+func (m *MockAPI) Requests() []*RecordedRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]*RecordedRequest{}, m.requests...)
+}
+
+// put stores an object, replacing any existing object of the same identity
+func (m *MockAPI) put(object *jsh.Object) {
+	list := m.resources[object.Type]
+	for i, existing := range list {
+		if existing.ID == object.ID {
+			list[i] = object
+			return
+		}
+	}
+
+	m.resources[object.Type] = append(list, object)
+}
+
+// remove deletes a stored object, returning false if it doesn't exist
+func (m *MockAPI) remove(resourceType string, id string) bool {
+	list := m.resources[resourceType]
+	for i, existing := range list {
+		if existing.ID == id {
+			m.resources[resourceType] = append(list[:i], list[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// record keeps a copy of a received request, restoring its body
+func (m *MockAPI) record(r *http.Request) {
+	recorded := &RecordedRequest{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+	}
+
+	if r.Body != nil {
+		recorded.Body, _ = ioutil.ReadAll(r.Body)
+		r.Body = ioutil.NopCloser(bytes.NewReader(recorded.Body))
+	}
+
+	m.requests = append(m.requests, recorded)
+}
+
+// serve routes /type, /type/id, /type/id/relationships/name, and
+// /type/id/name requests
+func (m *MockAPI) serve(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.record(r)
+
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case len(segments) == 1 && segments[0] != "":
+		m.serveCollection(w, r, segments[0])
+	case len(segments) == 2:
+		m.serveResource(w, r, segments[0], segments[1])
+	case len(segments) == 3:
+		m.serveRelated(w, r, segments[0], segments[1], segments[2])
+	case len(segments) == 4 && segments[2] == "relationships":
+		m.serveRelationship(w, r, segments[0], segments[1], segments[3])
+	default:
+		jsh.Send(w, r, jsh.NotFound("route", r.URL.Path))
+	}
+}
+
+func (m *MockAPI) serveCollection(w http.ResponseWriter, r *http.Request, resourceType string) {
+	switch r.Method {
+	case "GET":
+		m.sendList(w, r, m.resources[resourceType])
+	case "POST":
+		object, err := jsh.ParseObjectFor(r, resourceType, "")
+		if err != nil {
+			jsh.Send(w, r, err)
+			return
+		}
+
+		if object.ID == "" {
+			m.nextID++
+			object.ID = "mock-" + strconv.Itoa(m.nextID)
+		}
+
+		m.put(object)
+		jsh.Send(w, r, object)
+	default:
+		jsh.Send(w, r, jsh.SpecificationError("Unsupported method "+r.Method))
+	}
+}
+
+func (m *MockAPI) serveResource(w http.ResponseWriter, r *http.Request, resourceType string, id string) {
+	existing := m.resources[resourceType].FindByID(resourceType, id)
+	if existing == nil {
+		jsh.Send(w, r, jsh.NotFound(resourceType, id))
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		m.sendObject(w, r, existing)
+	case "PATCH":
+		patch, err := jsh.ParseObjectFor(r, resourceType, id)
+		if err == nil {
+			err = existing.Merge(patch)
+		}
+		if err != nil {
+			jsh.Send(w, r, err)
+			return
+		}
+
+		m.sendObject(w, r, existing)
+	case "DELETE":
+		m.remove(resourceType, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		jsh.Send(w, r, jsh.SpecificationError("Unsupported method "+r.Method))
+	}
+}
+
+func (m *MockAPI) serveRelated(w http.ResponseWriter, r *http.Request, resourceType string, id string, name string) {
+	relationship, err := m.relationship(resourceType, id, name)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	related := m.resolve(relationship.Data)
+	if relationship.Cardinality == jsh.ToMany {
+		m.sendList(w, r, related)
+		return
+	}
+
+	if len(related) == 0 {
+		document := jsh.New()
+		document.Status = http.StatusOK
+		jsh.SendDocument(w, r, document)
+		return
+	}
+
+	m.sendObject(w, r, related[0])
+}
+
+func (m *MockAPI) serveRelationship(w http.ResponseWriter, r *http.Request, resourceType string, id string, name string) {
+	relationship, err := m.relationship(resourceType, id, name)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	if r.Method != "GET" {
+		update, err := jsh.ParseRelationship(r)
+		if err != nil {
+			jsh.Send(w, r, err)
+			return
+		}
+		relationship.Data = updateLinkage(r.Method, relationship.Data, update.Data)
+	}
+
+	body, jsonErr := json.Marshal(relationship)
+	if jsonErr != nil {
+		jsh.Send(w, r, jsh.ISE(jsonErr.Error()))
+		return
+	}
+
+	w.Header().Set("Content-Type", jsh.ContentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+// relationship finds a relationship of a stored resource
+func (m *MockAPI) relationship(resourceType string, id string, name string) (*jsh.Relationship, *jsh.Error) {
+	existing := m.resources[resourceType].FindByID(resourceType, id)
+	if existing == nil {
+		return nil, jsh.NotFound(resourceType, id)
+	}
+
+	relationship, exists := existing.Relationships[name]
+	if !exists {
+		return nil, jsh.NotFound("relationship", name)
+	}
+
+	return relationship, nil
+}
+
+// updateLinkage applies a relationship write to the existing linkage
+func updateLinkage(method string, existing jsh.ResourceLinkage, update jsh.ResourceLinkage) jsh.ResourceLinkage {
+	switch method {
+	case "POST":
+		for _, identifier := range update {
+			if !linkageContains(existing, identifier) {
+				existing = append(existing, identifier)
+			}
+		}
+		return existing
+	case "DELETE":
+		remaining := jsh.ResourceLinkage{}
+		for _, identifier := range existing {
+			if !linkageContains(update, identifier) {
+				remaining = append(remaining, identifier)
+			}
+		}
+		return remaining
+	}
+
+	return update
+}
+
+func linkageContains(linkage jsh.ResourceLinkage, identifier *jsh.ResourceIdentifier) bool {
+	for _, candidate := range linkage {
+		if candidate.Type == identifier.Type && candidate.ID == identifier.ID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// resolve finds the stored resources of a linkage, skipping missing ones
+func (m *MockAPI) resolve(linkage jsh.ResourceLinkage) jsh.List {
+	list := jsh.List{}
+	for _, identifier := range linkage {
+		object := m.resources[identifier.Type].FindByID(identifier.Type, identifier.ID)
+		if object != nil {
+			list = append(list, object)
+		}
+	}
+
+	return list
+}
+
+// sendObject sends a single resource with its includes
+func (m *MockAPI) sendObject(w http.ResponseWriter, r *http.Request, object *jsh.Object) {
+	// clear the status the object was created with
+	object.Status = 0
+
+	document := jsh.Build(object)
+	document.Status = http.StatusOK
+
+	m.sendIncluding(w, r, document)
+}
+
+// sendList sends a page of resources with their includes
+func (m *MockAPI) sendList(w http.ResponseWriter, r *http.Request, list jsh.List) {
+	page, err := jsh.ParseCursorPage(r, nil)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	start, _ := strconv.Atoi(page.Position)
+	if start > len(list) {
+		start = len(list)
+	}
+	end := start + page.Limit
+	if end > len(list) {
+		end = len(list)
+	}
+
+	next := ""
+	if end < len(list) {
+		next = strconv.Itoa(end)
+	}
+
+	document := jsh.Build(append(jsh.List{}, list[start:end]...))
+	document.Links, err = page.Links(next, "")
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	m.sendIncluding(w, r, document)
+}
+
+// sendIncluding adds the requested includes to the document and sends it
+func (m *MockAPI) sendIncluding(w http.ResponseWriter, r *http.Request, document *jsh.Document) {
+	include, err := jsh.ParseInclude(r)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	seen := map[string]bool{}
+	for _, object := range document.Data {
+		seen[object.Type+"/"+object.ID] = true
+	}
+
+	m.include(document, document.Data, include, seen)
+	jsh.SendDocument(w, r, document)
+}
+
+// include adds the related resources of each relationship in tree
+func (m *MockAPI) include(document *jsh.Document, objects jsh.List, tree jsh.IncludeTree, seen map[string]bool) {
+	names := make([]string, 0, len(tree))
+	for name := range tree {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		related := jsh.List{}
+		for _, object := range objects {
+			relationship, exists := object.Relationships[name]
+			if exists {
+				related = append(related, m.resolve(relationship.Data)...)
+			}
+		}
+
+		for _, object := range related {
+			key := object.Type + "/" + object.ID
+			if !seen[key] {
+				seen[key] = true
+				document.Included = append(document.Included, object)
+			}
+		}
+
+		m.include(document, related, tree[name], seen)
+	}
+}
@@ -0,0 +1,107 @@
+package jshtest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/derekdowling/go-json-spec-handler"
+	"github.com/derekdowling/go-json-spec-handler/client"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMockAPI(t *testing.T) {
+
+	Convey("Mock API Tests", t, func() {
+
+		api := NewMockAPI()
+		Reset(func() {
+			api.Close()
+		})
+
+		author, _ := jsh.NewObject("1", "users", map[string]string{"name": "ann"})
+		tag, _ := jsh.NewObject("1", "tags", map[string]string{"label": "go"})
+		api.Add(author, tag)
+
+		for _, id := range []string{"1", "2", "3"} {
+			article, _ := jsh.NewObject(id, "articles", map[string]string{"title": "post " + id})
+			article.AddToOneRelationship("author", "users", "1")
+			article.AddToManyRelationship("tags", &jsh.ResourceIdentifier{Type: "tags", ID: "1"})
+			api.Add(article)
+		}
+
+		Convey("should serve resources with includes", func() {
+			request, _ := jsc.FetchRequest(api.URL, "articles", "1")
+			query := request.URL.Query()
+			query.Set(jsh.IncludeParam, "author,tags")
+			request.URL.RawQuery = query.Encode()
+
+			doc, _, err := jsc.Do(request, jsh.ObjectMode)
+			So(err, ShouldBeNil)
+			So(doc.First().ID, ShouldEqual, "1")
+			So(len(doc.Included), ShouldEqual, 2)
+		})
+
+		Convey("should paginate lists", func() {
+			request, _ := jsc.ListRequest(api.URL, "articles")
+			query := request.URL.Query()
+			query.Set(jsh.LimitParam, "2")
+			request.URL.RawQuery = query.Encode()
+
+			list, err := (&jsc.Client{}).GetAll(request)
+			So(err, ShouldBeNil)
+			So(list.IDs(), ShouldResemble, []string{"1", "2", "3"})
+			So(len(api.Requests()), ShouldEqual, 2)
+		})
+
+		Convey("should serve related resources and relationships", func() {
+			request, _ := http.NewRequest("GET", api.URL+"/articles/1/author", nil)
+			doc, _, err := jsc.Do(request, jsh.ObjectMode)
+			So(err, ShouldBeNil)
+			So(doc.First().Type, ShouldEqual, "users")
+
+			request, _ = http.NewRequest("GET", api.URL+"/articles/1/tags", nil)
+			doc, _, err = jsc.Do(request, jsh.ListMode)
+			So(err, ShouldBeNil)
+			So(doc.Data.IDs(), ShouldResemble, []string{"1"})
+
+			response, err := jsc.DeleteRelationship(api.URL, "articles", "1", "tags", &jsh.ResourceIdentifier{Type: "tags", ID: "1"})
+			So(err, ShouldBeNil)
+			So(response.StatusCode, ShouldEqual, http.StatusOK)
+
+			tags, _ := api.Resource("articles", "1").ToMany("tags")
+			So(len(tags), ShouldEqual, 0)
+		})
+
+		Convey("should create, update, and delete resources", func() {
+			object, _ := jsh.NewObject("", "articles", map[string]string{"title": "new"})
+			doc, response, err := jsc.Post(api.URL, object)
+			So(err, ShouldBeNil)
+			So(response.StatusCode, ShouldEqual, http.StatusCreated)
+
+			id := doc.First().ID
+			So(api.Resource("articles", id), ShouldNotBeNil)
+
+			patch, _ := jsh.NewObject(id, "articles", map[string]string{"title": "edited"})
+			_, _, err = jsc.Patch(api.URL, patch)
+			So(err, ShouldBeNil)
+
+			attributes := map[string]string{}
+			api.Resource("articles", id).Unmarshal("articles", &attributes)
+			So(attributes["title"], ShouldEqual, "edited")
+
+			response, err = jsc.Delete(api.URL, "articles", id)
+			So(err, ShouldBeNil)
+			So(response.StatusCode, ShouldEqual, http.StatusNoContent)
+			So(api.Resource("articles", id), ShouldBeNil)
+		})
+
+		Convey("should record requests", func() {
+			jsc.Fetch(api.URL, "articles", "404")
+
+			requests := api.Requests()
+			So(len(requests), ShouldEqual, 1)
+			So(requests[0].Method, ShouldEqual, "GET")
+			So(requests[0].Path, ShouldEqual, "/articles/404")
+		})
+	})
+}
@@ -0,0 +1,40 @@
+package jshtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/derekdowling/go-json-spec-handler"
+)
+
+/*
+NewRequest builds a request for testing a handler, with the payload, if not
+nil, serialized as its JSON API body:
+
+	object, _ := jsh.NewObject("", "users", user)
+	request := jshtest.NewRequest("POST", "/users", object)
+
+NewRequest panics if the payload can't be serialized, like httptest.NewRequest
+does for invalid targets.
+*/
+func NewRequest(method string, target string, payload jsh.Sendable) *http.Request {
+	if payload == nil {
+		request := httptest.NewRequest(method, target, nil)
+		request.Header.Set("Accept", jsh.ContentType)
+		return request
+	}
+
+	body, err := json.Marshal(jsh.Build(payload))
+	if err != nil {
+		panic(fmt.Sprintf("jshtest: unable to serialize request payload: %s", err.Error()))
+	}
+
+	request := httptest.NewRequest(method, target, bytes.NewReader(body))
+	request.Header.Set("Accept", jsh.ContentType)
+	request.Header.Set("Content-Type", jsh.ContentType)
+
+	return request
+}
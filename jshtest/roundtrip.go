@@ -0,0 +1,87 @@
+package jshtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/derekdowling/go-json-spec-handler"
+)
+
+/*
+RoundTrip serializes the document, parses the result, serializes it again, and
+parses that in turn. An error is returned if either parse fails or if the two
+serialized or parsed forms differ.
+*/
+func RoundTrip(doc *jsh.Document) error {
+	first, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("Unable to serialize document: %s", err.Error())
+	}
+
+	parsed, parseErr := parse(first, doc.Mode)
+	if parseErr != nil {
+		return fmt.Errorf("Unable to parse serialized document: %s\n%s", parseErr.Error(), first)
+	}
+
+	second, err := json.Marshal(parsed)
+	if err != nil {
+		return fmt.Errorf("Unable to serialize parsed document: %s", err.Error())
+	}
+
+	if !bytes.Equal(first, second) {
+		return fmt.Errorf("Serialized output changed after round trip:\n%s\n%s", first, second)
+	}
+
+	reparsed, parseErr := parse(second, doc.Mode)
+	if parseErr != nil {
+		return fmt.Errorf("Unable to parse reserialized document: %s\n%s", parseErr.Error(), second)
+	}
+
+	if !reflect.DeepEqual(parsed, reparsed) {
+		return fmt.Errorf("Parsed document changed after round trip:\n%s", second)
+	}
+
+	return nil
+}
+
+/*
+CheckRoundTrip runs RoundTrip against the given number of randomly generated
+documents, reporting each failure along with the seed needed to reproduce it.
+*/
+func CheckRoundTrip(t testing.TB, iterations int, seed int64) {
+	gen := NewGenerator(seed)
+
+	for i := 0; i < iterations; i++ {
+		if err := RoundTrip(gen.Document()); err != nil {
+			t.Errorf("seed %d, iteration %d: %s", seed, i, err.Error())
+		}
+	}
+}
+
+/*
+CheckNearMisses parses the given number of generated near miss documents and
+reports any that jsh accepts.
+*/
+func CheckNearMisses(t testing.TB, iterations int, seed int64) {
+	gen := NewGenerator(seed)
+
+	for i := 0; i < iterations; i++ {
+		body, description := gen.NearMiss()
+		if _, err := parse(body, jsh.ListMode); err == nil {
+			t.Errorf("seed %d, iteration %d: accepted %s:\n%s", seed, i, description, body)
+		}
+	}
+}
+
+// parse runs a raw payload through the jsh request parser
+func parse(body []byte, mode jsh.DocumentMode) (*jsh.Document, *jsh.Error) {
+	headers := http.Header{}
+	headers.Set("Content-Type", jsh.ContentType)
+
+	parser := &jsh.Parser{Method: "GET", Headers: headers}
+	return parser.Document(jsh.CreateReadCloser(body), mode)
+}
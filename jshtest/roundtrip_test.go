@@ -0,0 +1,35 @@
+package jshtest
+
+import (
+	"testing"
+
+	"github.com/derekdowling/go-json-spec-handler"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRoundTrip(t *testing.T) {
+
+	Convey("Round Trip Tests", t, func() {
+
+		Convey("->RoundTrip()", func() {
+
+			Convey("should accept a generated document", func() {
+				doc := NewGenerator(1).Document()
+				So(RoundTrip(doc), ShouldBeNil)
+			})
+
+			Convey("should accept an error document", func() {
+				doc := jsh.Build(jsh.ISE("test"))
+				So(RoundTrip(doc), ShouldBeNil)
+			})
+		})
+
+		Convey("->CheckRoundTrip()", func() {
+			CheckRoundTrip(t, 200, 42)
+		})
+
+		Convey("->CheckNearMisses()", func() {
+			CheckNearMisses(t, 200, 42)
+		})
+	})
+}
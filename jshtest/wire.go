@@ -0,0 +1,155 @@
+package jshtest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/derekdowling/go-json-spec-handler"
+)
+
+/*
+WireSnapshot pins the exact bytes jsh sends for a representative payload.
+*/
+type WireSnapshot struct {
+	Name string
+	// Method of the request the payload is sent in response to
+	Method string
+	// Payload builds the Sendable to send
+	Payload func() jsh.Sendable
+	// Expected is the exact response body
+	Expected string
+}
+
+/*
+WireChange records a deliberate change to jsh's serialized output. Checksum is
+the SnapshotChecksum of WireSnapshots as of the change, so any change to the
+expected output fails CheckWireCompat until a new entry is added here.
+*/
+type WireChange struct {
+	Version     string
+	Checksum    string
+	Description string
+}
+
+/*
+WireChangelog lists every deliberate change to jsh's serialized output, oldest
+first. If you are changing what jsh sends, update the affected WireSnapshots and
+append an entry describing the change along with the new SnapshotChecksum.
+*/
+var WireChangelog = []WireChange{
+	{
+		Version:     "1",
+		Checksum:    "ac51c218e694206ab784253c75f89925a9fb7018b42fe43d7ad42cde5c19768a",
+		Description: "Initial snapshot of indented object, list, and error documents.",
+	},
+	{
+		Version:     "2",
+		Checksum:    "b84125a4fa74b57963cc09dbdaea1b5151c6f1b6cd6364123ec99ed6760254d5",
+		Description: "Adds a snapshot of empty relationships, sent as they always have been by default, see EmptyRelationships.",
+	},
+	{
+		Version:     "3",
+		Checksum:    "7589ee5eadae9583e8e9456974ac7e56558ad5006e977626fa962776921aed71",
+		Description: "Documents are sent as compact JSON unless indentation is turned on, see PrettyJSON.",
+	},
+}
+
+// WireSnapshots are the representative payloads checked by CheckWireCompat.
+var WireSnapshots = []WireSnapshot{
+	{
+		Name:   "object",
+		Method: "GET",
+		Payload: func() jsh.Sendable {
+			object, _ := jsh.NewObject("1", "articles", map[string]string{"title": "JSON API"})
+			object.AddToOneRelationship("author", "users", "9")
+			object.AddToManyRelationship("tags", &jsh.ResourceIdentifier{Type: "tags", ID: "2"})
+			object.Links["self"] = &jsh.Link{HREF: "/articles/1"}
+			object.Meta = map[string]interface{}{"views": 10}
+			return object
+		},
+		Expected: `{"jsonapi":{"version":"1.1"},"data":{"type":"articles","id":"1","attributes":{"title":"JSON API"},"links":{"self":{"href":"/articles/1"}},"relationships":{"author":{"data":{"type":"users","id":"9"}},"tags":{"data":[{"type":"tags","id":"2"}]}},"meta":{"views":10}}}`,
+	},
+	{
+		Name:   "list",
+		Method: "GET",
+		Payload: func() jsh.Sendable {
+			object, _ := jsh.NewObject("1", "tags", map[string]string{"name": "go"})
+			return jsh.List{object}
+		},
+		Expected: `{"data":[{"type":"tags","id":"1","attributes":{"name":"go"}}],"jsonapi":{"version":"1.1"}}`,
+	},
+	{
+		Name:   "empty list",
+		Method: "GET",
+		Payload: func() jsh.Sendable {
+			return jsh.List{}
+		},
+		Expected: `{"data":[],"jsonapi":{"version":"1.1"}}`,
+	},
+	{
+		Name:   "empty relationships",
+		Method: "GET",
+		Payload: func() jsh.Sendable {
+			object, _ := jsh.NewObject("2", "articles", map[string]string{"title": "Draft"})
+			object.AddToOneRelationship("author", "users", "")
+			object.AddToManyRelationship("tags")
+			return object
+		},
+		Expected: `{"jsonapi":{"version":"1.1"},"data":{"type":"articles","id":"2","attributes":{"title":"Draft"},"relationships":{"author":{"data":null},"tags":{}}}}`,
+	},
+	{
+		Name:   "error",
+		Method: "GET",
+		Payload: func() jsh.Sendable {
+			return jsh.InputError("Name is required", "name")
+		},
+		Expected: `{"errors":[{"title":"Invalid Attribute","detail":"Name is required","status":"422","source":{"pointer":"/data/attributes/name"}}],"jsonapi":{"version":"1.1"}}`,
+	},
+}
+
+// SnapshotChecksum returns a checksum of the expected output of every
+// WireSnapshot.
+func SnapshotChecksum(snapshots []WireSnapshot) string {
+	hash := sha256.New()
+	for _, snapshot := range snapshots {
+		hash.Write([]byte(snapshot.Name))
+		hash.Write([]byte{0})
+		hash.Write([]byte(snapshot.Expected))
+		hash.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+/*
+CheckWireCompat sends each of the WireSnapshots and reports any whose output
+differs from what is expected, as well as snapshot changes that haven't been
+recorded in the WireChangelog. Run it from your own tests to be sure an upgrade
+of jsh doesn't silently change your API's output:
+
+	func TestWireCompat(t *testing.T) {
+		jshtest.CheckWireCompat(t)
+	}
+*/
+func CheckWireCompat(t testing.TB) {
+	for _, snapshot := range WireSnapshots {
+		recorder := httptest.NewRecorder()
+		jsh.Send(recorder, &http.Request{Method: snapshot.Method}, snapshot.Payload())
+
+		if actual := recorder.Body.String(); actual != snapshot.Expected {
+			t.Errorf("wire output for '%s' changed, expected:\n%s\ngot:\n%s", snapshot.Name, snapshot.Expected, actual)
+		}
+	}
+
+	latest := WireChangelog[len(WireChangelog)-1]
+	if checksum := SnapshotChecksum(WireSnapshots); checksum != latest.Checksum {
+		t.Errorf(
+			"wire snapshots changed without a WireChangelog entry, latest is version %s, current checksum is %s",
+			latest.Version,
+			checksum,
+		)
+	}
+}
@@ -0,0 +1,23 @@
+package jshtest
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestWireCompat(t *testing.T) {
+
+	Convey("Wire Compatibility Tests", t, func() {
+
+		Convey("->SnapshotChecksum()", func() {
+			changed := []WireSnapshot{WireSnapshots[0]}
+			changed[0].Expected += " "
+			So(SnapshotChecksum(changed), ShouldNotEqual, SnapshotChecksum(WireSnapshots[:1]))
+		})
+
+		Convey("->CheckWireCompat()", func() {
+			CheckWireCompat(t)
+		})
+	})
+}
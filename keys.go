@@ -0,0 +1,196 @@
+package jsh
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+/*
+KeyConversion converts attribute member names between the naming convention of
+a Go API's structs and the one its documents use, such as the kebab-case the
+JSON API specification recommends:
+
+	jsh.AttributeKeys = &jsh.KeyConversion{Send: jsh.KebabCase, Receive: jsh.CamelCase}
+
+	object, err := jsh.NewObject("1", "users", &User{FirstName: "Bob"}) // "first-name": "Bob"
+	...
+	errs := parsed.Unmarshal("users", &user) // "first-name" fills `json:"firstName"`
+
+Names are converted as attributes are marshaled into an object, by NewObject
+and Object.Marshal, and as they are unmarshaled out of it, by Object.Unmarshal.
+Everything in between, including resource declarations and AttributeSerializers,
+sees the names as clients do. Use WithKeyConversion, with Object.MarshalFor and
+UnmarshalFor, to convert names differently for some requests, such as those of
+an API version.
+*/
+type KeyConversion struct {
+	// Send converts a Go member name to the one sent to clients
+	Send func(name string) string
+	// Receive converts a member name sent by a client to the Go one
+	Receive func(name string) string
+	// Nested converts the member names of objects nested in attribute values
+	// too, rather than just those of the attributes themselves. Leave it off if
+	// attributes hold maps keyed by data, such as IDs.
+	Nested bool
+}
+
+// AttributeKeys converts attribute member names for every object marshaled or
+// unmarshaled without a request of its own, see KeyConversion. It is nil, and
+// names are left as they are, by default.
+var AttributeKeys *KeyConversion
+
+type keyConversionKey struct{}
+
+// WithKeyConversion returns a copy of the request carrying its own key
+// conversion, used by Object.MarshalFor and UnmarshalFor instead of
+// AttributeKeys. A nil conversion leaves names as they are.
+func WithKeyConversion(r *http.Request, conversion *KeyConversion) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), keyConversionKey{}, conversion))
+}
+
+// RequestKeyConversion returns the key conversion of a request, the one set by
+// WithKeyConversion or AttributeKeys.
+func RequestKeyConversion(r *http.Request) *KeyConversion {
+	if r != nil {
+		if conversion, ok := r.Context().Value(keyConversionKey{}).(*KeyConversion); ok {
+			return conversion
+		}
+	}
+
+	return AttributeKeys
+}
+
+// MarshalFor marshals attributes into the object like Marshal, converting their
+// names with the request's key conversion, see WithKeyConversion.
+func (o *Object) MarshalFor(r *http.Request, attributes interface{}) *Error {
+	return o.marshal(attributes, RequestKeyConversion(r))
+}
+
+// UnmarshalFor unmarshals the object's attributes like Unmarshal, converting
+// their names with the request's key conversion, see WithKeyConversion.
+func (o *Object) UnmarshalFor(r *http.Request, resourceType string, target interface{}) ErrorList {
+	return o.unmarshal(resourceType, target, RequestKeyConversion(r))
+}
+
+// send converts the member names of encoded attributes for sending
+func (c *KeyConversion) send(raw json.RawMessage) (json.RawMessage, *Error) {
+	if c == nil || c.Send == nil || !hasAttributes(raw) {
+		return raw, nil
+	}
+
+	return convertKeys(raw, c.Send, c.Nested)
+}
+
+// receive converts the member names of encoded attributes sent by a client
+func (c *KeyConversion) receive(raw json.RawMessage) (json.RawMessage, *Error) {
+	if c == nil || c.Receive == nil || !hasAttributes(raw) {
+		return raw, nil
+	}
+
+	return convertKeys(raw, c.Receive, c.Nested)
+}
+
+/*
+sentPointers points errors found unmarshaling converted attributes, such as
+/data/attributes/firstName, back at the members the client sent, such as
+/data/attributes/first-name.
+*/
+func (c *KeyConversion) sentPointers(errs ErrorList) ErrorList {
+	if c == nil || c.Send == nil {
+		return errs
+	}
+
+	for _, err := range errs {
+		if !strings.HasPrefix(err.Source.Pointer, attributesPointer+"/") {
+			continue
+		}
+
+		segments := strings.Split(strings.TrimPrefix(err.Source.Pointer, attributesPointer+"/"), "/")
+		for i, segment := range segments {
+			if i > 0 && !c.Nested {
+				break
+			}
+
+			// array indexes aren't member names
+			if _, indexErr := strconv.Atoi(segment); indexErr == nil && i > 0 {
+				continue
+			}
+
+			segments[i] = c.Send(segment)
+		}
+		err.Source.Pointer = attributesPointer + "/" + strings.Join(segments, "/")
+	}
+
+	return errs
+}
+
+// convertKeys converts the member names of an encoded JSON object, and of the
+// objects nested in its values if nested is set
+func convertKeys(raw json.RawMessage, convert func(name string) string, nested bool) (json.RawMessage, *Error) {
+	members := map[string]json.RawMessage{}
+	jsonErr := json.Unmarshal(raw, &members)
+	if jsonErr != nil {
+		return nil, ISE(fmt.Sprintf("Unable to convert attribute names: %s", jsonErr.Error()))
+	}
+
+	converted := map[string]json.RawMessage{}
+	for name, value := range members {
+		if nested {
+			var err *Error
+			value, err = convertNestedKeys(value, convert)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		convertedName := convert(name)
+		if _, taken := converted[convertedName]; taken {
+			return nil, ISE(fmt.Sprintf("Attribute names convert to conflicting name '%s'", convertedName))
+		}
+		converted[convertedName] = value
+	}
+
+	encoded, jsonErr := json.Marshal(converted)
+	if jsonErr != nil {
+		return nil, ISE(fmt.Sprintf("Unable to convert attribute names: %s", jsonErr.Error()))
+	}
+
+	return encoded, nil
+}
+
+// convertNestedKeys converts the member names of the objects within a value
+func convertNestedKeys(raw json.RawMessage, convert func(name string) string) (json.RawMessage, *Error) {
+	trimmed := strings.TrimSpace(string(raw))
+	if strings.HasPrefix(trimmed, "{") {
+		return convertKeys(raw, convert, true)
+	}
+
+	if !strings.HasPrefix(trimmed, "[") {
+		return raw, nil
+	}
+
+	elements := []json.RawMessage{}
+	jsonErr := json.Unmarshal(raw, &elements)
+	if jsonErr != nil {
+		return nil, ISE(fmt.Sprintf("Unable to convert attribute names: %s", jsonErr.Error()))
+	}
+
+	for i, element := range elements {
+		converted, err := convertNestedKeys(element, convert)
+		if err != nil {
+			return nil, err
+		}
+		elements[i] = converted
+	}
+
+	encoded, jsonErr := json.Marshal(elements)
+	if jsonErr != nil {
+		return nil, ISE(fmt.Sprintf("Unable to convert attribute names: %s", jsonErr.Error()))
+	}
+
+	return encoded, nil
+}
@@ -0,0 +1,107 @@
+package jsh
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestKeyConversion(t *testing.T) {
+
+	Convey("Key Conversion Tests", t, func() {
+
+		type address struct {
+			ZipCode string `json:"zipCode"`
+		}
+		type user struct {
+			FirstName string    `json:"firstName"`
+			LastIP    string    `json:"lastIp" valid:"ipv4"`
+			Addresses []address `json:"addresses,omitempty"`
+		}
+
+		kebab := &KeyConversion{Send: KebabCase, Receive: CamelCase}
+
+		Convey("should leave names as they are by default", func() {
+			object, err := NewObject("1", "users", &user{FirstName: "Bob"})
+			So(err, ShouldBeNil)
+			So(string(object.Attributes), ShouldContainSubstring, `"firstName"`)
+		})
+
+		Convey("with AttributeKeys set", func() {
+			AttributeKeys = kebab
+			Reset(func() { AttributeKeys = nil })
+
+			Convey("should convert names when marshaling", func() {
+				object, err := NewObject("1", "users", &user{FirstName: "Bob", LastIP: "10.0.0.1"})
+				So(err, ShouldBeNil)
+				So(object.HasAttribute("first-name"), ShouldBeTrue)
+				So(object.HasAttribute("last-ip"), ShouldBeTrue)
+				So(object.HasAttribute("firstName"), ShouldBeFalse)
+			})
+
+			Convey("should convert names when unmarshaling", func() {
+				object := &Object{Type: "users", Attributes: json.RawMessage(`{"first-name": "Bob", "last-ip": "10.0.0.1"}`)}
+
+				target := &user{}
+				So(object.Unmarshal("users", target), ShouldBeNil)
+				So(target, ShouldResemble, &user{FirstName: "Bob", LastIP: "10.0.0.1"})
+			})
+
+			Convey("should point errors at the names clients sent", func() {
+				object := &Object{Type: "users", Attributes: json.RawMessage(`{"first-name": 1}`)}
+				errs := object.Unmarshal("users", &user{})
+				So(errs, ShouldNotBeNil)
+				So(errs[0].Source.Pointer, ShouldEqual, "/data/attributes/first-name")
+
+				object = &Object{Type: "users", Attributes: json.RawMessage(`{"last-ip": "nope"}`)}
+				errs = object.Unmarshal("users", &user{})
+				So(errs, ShouldNotBeNil)
+				So(errs[0].Source.Pointer, ShouldEqual, "/data/attributes/last-ip")
+			})
+
+			Convey("should only convert nested names if Nested is set", func() {
+				object, _ := NewObject("1", "users", &user{Addresses: []address{{ZipCode: "12345"}}})
+				So(string(object.Attributes), ShouldContainSubstring, `"zipCode"`)
+
+				kebab.Nested = true
+				object, _ = NewObject("1", "users", &user{Addresses: []address{{ZipCode: "12345"}}})
+				So(string(object.Attributes), ShouldContainSubstring, `"zip-code"`)
+
+				target := &user{}
+				So(object.Unmarshal("users", target), ShouldBeNil)
+				So(target.Addresses[0].ZipCode, ShouldEqual, "12345")
+			})
+
+			Convey("should not convert names twice when setting attributes", func() {
+				object, _ := NewObject("1", "users", &user{FirstName: "Bob"})
+				So(object.SetAttribute("can_edit", true), ShouldBeNil)
+				So(object.HasAttribute("can_edit"), ShouldBeTrue)
+				So(object.HasAttribute("first-name"), ShouldBeTrue)
+			})
+		})
+
+		Convey("->WithKeyConversion()", func() {
+			AttributeKeys = kebab
+			Reset(func() { AttributeKeys = nil })
+
+			request, _ := http.NewRequest("GET", "/users/1", nil)
+			snake := WithKeyConversion(request, &KeyConversion{Send: SnakeCase, Receive: CamelCase})
+
+			object := &Object{Type: "users"}
+			So(object.MarshalFor(snake, &user{FirstName: "Bob"}), ShouldBeNil)
+			So(object.HasAttribute("first_name"), ShouldBeTrue)
+
+			target := &user{}
+			So(object.UnmarshalFor(snake, "users", target), ShouldBeNil)
+			So(target.FirstName, ShouldEqual, "Bob")
+
+			So(object.MarshalFor(request, &user{FirstName: "Bob"}), ShouldBeNil)
+			So(object.HasAttribute("first-name"), ShouldBeTrue)
+
+			So(object.MarshalFor(WithKeyConversion(request, nil), &user{FirstName: "Bob"}), ShouldBeNil)
+			So(object.HasAttribute("firstName"), ShouldBeTrue)
+		})
+	})
+}
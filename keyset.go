@@ -0,0 +1,276 @@
+package jsh
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// SortParam is the query parameter listing the sort fields of a request.
+const SortParam = "sort"
+
+// SortField is a single field of a sort, an attribute or "id".
+type SortField struct {
+	Attribute string
+	Direction SortDirection
+}
+
+// SortSpec is a multi-column sort, ordered from most to least significant.
+type SortSpec []SortField
+
+// String formats the sort as the sort query parameter, such as "-created,id".
+func (s SortSpec) String() string {
+	fields := make([]string, len(s))
+	for i, field := range s {
+		fields[i] = field.Attribute
+		if field.Direction == Descending {
+			fields[i] = "-" + field.Attribute
+		}
+	}
+
+	return strings.Join(fields, ",")
+}
+
+// Compare orders two objects by the sort, returning -1, 0, or 1 like
+// strings.Compare, such as to merge sorted lists. See List.Sort to sort one.
+func (s SortSpec) Compare(a *Object, b *Object) (int, *Error) {
+	valuesA, err := s.values(a)
+	if err != nil {
+		return 0, err
+	}
+
+	valuesB, err := s.values(b)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.compare(valuesA, valuesB), nil
+}
+
+// values decodes the object's value of each field of the sort
+func (s SortSpec) values(object *Object) ([]interface{}, *Error) {
+	values := make([]interface{}, len(s))
+	for i, field := range s {
+		value, err := sortValue(object, field.Attribute)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+
+	return values, nil
+}
+
+// compare orders two sets of values from values, field by field
+func (s SortSpec) compare(a []interface{}, b []interface{}) int {
+	for i, field := range s {
+		order := compareValues(a[i], b[i], nil)
+		if field.Direction == Descending {
+			order = -order
+		}
+
+		switch {
+		case order < 0:
+			return -1
+		case order > 0:
+			return 1
+		}
+	}
+
+	return 0
+}
+
+/*
+ParseSort parses the sort query parameter of a request, "-created,title" sorting
+by created descending and then title ascending. The ID is appended as a
+tie-breaker if the sort doesn't already end with it, so that the order is total
+and stable. If allowed is provided, sorting by any other attribute is a 400.
+*/
+func ParseSort(r *http.Request, allowed ...string) (SortSpec, *Error) {
+	spec := SortSpec{}
+
+	sort := r.URL.Query().Get(SortParam)
+	if sort != "" {
+		for _, name := range strings.Split(sort, ",") {
+			field := SortField{Attribute: name, Direction: Ascending}
+			if strings.HasPrefix(name, "-") {
+				field = SortField{Attribute: name[1:], Direction: Descending}
+			}
+
+			if field.Attribute == "" {
+				return nil, ParameterError(fmt.Sprintf("Invalid sort field '%s'", name), SortParam)
+			}
+
+			if field.Attribute != "id" && len(allowed) > 0 && !contains(allowed, field.Attribute) {
+				return nil, ParameterError(fmt.Sprintf("Sorting by '%s' is not supported", field.Attribute), SortParam)
+			}
+
+			spec = append(spec, field)
+		}
+	}
+
+	if len(spec) == 0 || spec[len(spec)-1].Attribute != "id" {
+		spec = append(spec, SortField{Attribute: "id", Direction: Ascending})
+	}
+
+	return spec, nil
+}
+
+/*
+Keyset is a parsed keyset pagination request. Rather than an offset, its cursor
+carries the sort values of the last resource of the previous page, so pages
+have no duplicates or gaps as resources are written between requests:
+
+	keyset, err := jsh.ParseKeyset(r, nil, "created", "title")
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	// fetch keyset.Limit articles ordered by keyset.Sort, after the
+	// keyset.Values tuple if it isn't nil
+	articles, more := storage.Articles(keyset.Sort, keyset.Values, keyset.Limit)
+
+	var last *jsh.Object
+	if more {
+		last = articles[len(articles)-1]
+	}
+
+	doc := jsh.Build(articles)
+	doc.Links, err = keyset.Links(last)
+
+Cursors record the sort they were issued for, and are rejected with a 400 if
+the sort of the request has since changed.
+*/
+type Keyset struct {
+	Sort SortSpec
+	// Values are the sort values to continue after, one per field of Sort, and
+	// nil for the first page
+	Values []interface{}
+	// Limit is the number of resources to return
+	Limit int
+
+	page *CursorPage
+}
+
+// keysetPosition is the internal position of a keyset cursor
+type keysetPosition struct {
+	Sort   string        `json:"sort"`
+	Values []interface{} `json:"values"`
+}
+
+/*
+ParseKeyset parses the sort, page[cursor], and page[limit] query parameters of a
+request. The encoder and allowed sort attributes are the same as those of
+ParseCursorPage and ParseSort.
+*/
+func ParseKeyset(r *http.Request, encoder CursorEncoder, allowed ...string) (*Keyset, *Error) {
+	spec, err := ParseSort(r, allowed...)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := ParseCursorPage(r, encoder)
+	if err != nil {
+		return nil, err
+	}
+
+	keyset := &Keyset{Sort: spec, Limit: page.Limit, page: page}
+	if page.Position == "" {
+		return keyset, nil
+	}
+
+	position := keysetPosition{}
+	jsonErr := json.Unmarshal([]byte(page.Position), &position)
+	if jsonErr != nil || len(position.Values) != len(spec) {
+		return nil, ParameterError("Invalid pagination cursor", CursorParam)
+	}
+
+	if position.Sort != spec.String() {
+		return nil, ParameterError(
+			fmt.Sprintf("Pagination cursor was issued for sort '%s', not '%s'", position.Sort, spec.String()),
+			CursorParam,
+		)
+	}
+
+	keyset.Values = position.Values
+	return keyset, nil
+}
+
+/*
+Links builds the pagination links of the page, continuing after last. Pass nil
+for last on the final page to omit the next link.
+*/
+func (k *Keyset) Links(last *Object) (*Links, *Error) {
+	if last == nil {
+		return k.page.Links("", "")
+	}
+
+	next, err := k.position(last)
+	if err != nil {
+		return nil, err
+	}
+
+	return k.page.Links(next, "")
+}
+
+// position builds the cursor position continuing after object
+func (k *Keyset) position(object *Object) (string, *Error) {
+	position := keysetPosition{Sort: k.Sort.String()}
+
+	for _, field := range k.Sort {
+		value, err := sortValue(object, field.Attribute)
+		if err != nil {
+			return "", err
+		}
+		position.Values = append(position.Values, value)
+	}
+
+	raw, jsonErr := json.Marshal(position)
+	if jsonErr != nil {
+		return "", ISE(fmt.Sprintf("Unable to encode pagination position: %s", jsonErr.Error()))
+	}
+
+	return string(raw), nil
+}
+
+/*
+After returns true if the object sorts after the keyset's Values, and so belongs
+on the requested page or a later one. Every object is after the first page's
+nil Values. Useful for paging in memory, and for checking storage queries.
+*/
+func (k *Keyset) After(object *Object) (bool, *Error) {
+	if k.Values == nil {
+		return true, nil
+	}
+
+	for i, field := range k.Sort {
+		value, err := sortValue(object, field.Attribute)
+		if err != nil {
+			return false, err
+		}
+
+		comparison := compareValues(value, k.Values[i], nil)
+		if field.Direction == Descending {
+			comparison = -comparison
+		}
+
+		if comparison != 0 {
+			return comparison > 0, nil
+		}
+	}
+
+	return false, nil
+}
+
+// contains returns true if values contains value
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+
+	return false
+}
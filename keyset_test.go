@@ -0,0 +1,135 @@
+package jsh
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// testKeysetPage pages through list in memory, sorted by -rank then id
+func testKeysetPage(list List, rawURL string) (List, *Links, *Error) {
+	keyset, err := ParseKeyset(httptest.NewRequest("GET", rawURL, nil), nil, "rank")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sorted := append(List{}, list...)
+	sorted.SortBy("id", Ascending)
+	sorted.SortBy("rank", Descending)
+
+	page := List{}
+	for _, object := range sorted {
+		after, err := keyset.After(object)
+		if err != nil {
+			return nil, nil, err
+		}
+		if after && len(page) < keyset.Limit {
+			page = append(page, object)
+		}
+	}
+
+	var last *Object
+	if len(page) == keyset.Limit {
+		last = page[len(page)-1]
+	}
+
+	links, err := keyset.Links(last)
+	return page, links, err
+}
+
+func TestKeyset(t *testing.T) {
+
+	Convey("Keyset Pagination Tests", t, func() {
+
+		list := List{}
+		for i, rank := range []int{5, 3, 3, 3, 1} {
+			object, _ := NewObject(strconv.Itoa(i), "users", map[string]int{"rank": rank})
+			list = append(list, object)
+		}
+
+		Convey("->ParseSort()", func() {
+
+			Convey("should parse directions and add the ID tie-breaker", func() {
+				spec, err := ParseSort(httptest.NewRequest("GET", "/users?sort=-rank,name", nil))
+				So(err, ShouldBeNil)
+				So(spec, ShouldResemble, SortSpec{
+					{Attribute: "rank", Direction: Descending},
+					{Attribute: "name", Direction: Ascending},
+					{Attribute: "id", Direction: Ascending},
+				})
+				So(spec.String(), ShouldEqual, "-rank,name,id")
+			})
+
+			Convey("should reject attributes that aren't allowed", func() {
+				_, err := ParseSort(httptest.NewRequest("GET", "/users?sort=secret", nil), "rank")
+				So(err, ShouldNotBeNil)
+				So(err.Status, ShouldEqual, http.StatusBadRequest)
+				So(err.Source.Parameter, ShouldEqual, SortParam)
+			})
+		})
+
+		Convey("->Compare()", func() {
+			spec := SortSpec{{Attribute: "rank", Direction: Descending}, {Attribute: "id", Direction: Ascending}}
+
+			order, err := spec.Compare(list[0], list[1])
+			So(err, ShouldBeNil)
+			So(order, ShouldEqual, -1)
+
+			order, err = spec.Compare(list[2], list[1])
+			So(err, ShouldBeNil)
+			So(order, ShouldEqual, 1)
+
+			order, err = spec.Compare(list[1], list[1])
+			So(err, ShouldBeNil)
+			So(order, ShouldEqual, 0)
+		})
+
+		Convey("->ParseKeyset()", func() {
+
+			Convey("should page without duplicates or gaps", func() {
+				page, links, err := testKeysetPage(list, "/users?sort=-rank&page[limit]=2")
+				So(err, ShouldBeNil)
+				So(page.IDs(), ShouldResemble, []string{"0", "1"})
+
+				// a concurrent write ahead of the cursor doesn't shift the next page
+				inserted, _ := NewObject("9", "users", map[string]int{"rank": 9})
+				list = append(list, inserted)
+
+				page, links, err = testKeysetPage(list, links.Next.HREF)
+				So(err, ShouldBeNil)
+				So(page.IDs(), ShouldResemble, []string{"2", "3"})
+
+				page, links, err = testKeysetPage(list, links.Next.HREF)
+				So(err, ShouldBeNil)
+				So(page.IDs(), ShouldResemble, []string{"4"})
+				So(links.Next, ShouldBeNil)
+			})
+
+			Convey("should reject cursors issued for another sort", func() {
+				_, links, err := testKeysetPage(list, "/users?sort=-rank&page[limit]=2")
+				So(err, ShouldBeNil)
+
+				next, _ := url.Parse(links.Next.HREF)
+				query := next.Query()
+				query.Set(SortParam, "rank")
+				next.RawQuery = query.Encode()
+
+				_, _, err = testKeysetPage(list, next.String())
+				So(err, ShouldNotBeNil)
+				So(err.Source.Parameter, ShouldEqual, CursorParam)
+			})
+
+			Convey("should reject invalid cursors", func() {
+				cursor, _ := Base64Cursor{}.EncodeCursor("nope")
+
+				_, err := ParseKeyset(httptest.NewRequest("GET", "/users?page[cursor]="+cursor, nil), nil)
+				So(err, ShouldNotBeNil)
+				So(err.Status, ShouldEqual, http.StatusBadRequest)
+			})
+		})
+	})
+}
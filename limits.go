@@ -0,0 +1,110 @@
+package jsh
+
+import (
+	"encoding/json"
+	"fmt"
+	"unicode/utf8"
+)
+
+/*
+AttributeLimits bounds the size of the attributes clients may write to a
+resource type, as a declarative first line of defense against abusive payloads:
+
+	jsh.Register(&jsh.Resource{
+		Type: "comments",
+		Limits: &jsh.AttributeLimits{
+			MaxAttributes:   10,
+			MaxStringLength: 1024,
+			MaxArrayLength:  50,
+			StringLengths:   map[string]int{"body": 16384},
+		},
+	})
+
+Limits apply to values nested within attributes too, and a zero limit is not
+enforced. Violations are rejected with a 422 pointing at the offending value,
+such as /data/attributes/tags/51.
+*/
+type AttributeLimits struct {
+	// MaxAttributes is the maximum number of top level attributes
+	MaxAttributes int `json:"max_attributes"`
+	// MaxStringLength is the maximum length, in characters, of string values
+	MaxStringLength int `json:"max_string_length"`
+	// MaxArrayLength is the maximum number of elements of array values
+	MaxArrayLength int `json:"max_array_length"`
+	// StringLengths overrides MaxStringLength for the strings of individual
+	// attributes
+	StringLengths map[string]int `json:"string_lengths,omitempty"`
+}
+
+// check enforces the limits on an object's attributes
+func (l *AttributeLimits) check(object *Object) *Error {
+	attributes, err := object.attributeMap()
+	if err != nil {
+		return err
+	}
+
+	if l.MaxAttributes > 0 && len(attributes) > l.MaxAttributes {
+		return limitError(fmt.Sprintf("Resources of type '%s' accept at most %d attributes", object.Type, l.MaxAttributes), "/data/attributes")
+	}
+
+	for name, raw := range attributes {
+		var value interface{}
+		if json.Unmarshal(raw, &value) != nil {
+			continue
+		}
+
+		maxString := l.MaxStringLength
+		if override, exists := l.StringLengths[name]; exists {
+			maxString = override
+		}
+
+		err := l.checkValue("/data/attributes/"+pointerToken(name), value, maxString)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkValue walks decoded JSON enforcing the string and array limits
+func (l *AttributeLimits) checkValue(pointer string, value interface{}, maxString int) *Error {
+	switch typed := value.(type) {
+	case string:
+		if maxString > 0 && utf8.RuneCountInString(typed) > maxString {
+			return limitError(fmt.Sprintf("Value exceeds the maximum length of %d characters", maxString), pointer)
+		}
+	case []interface{}:
+		if l.MaxArrayLength > 0 && len(typed) > l.MaxArrayLength {
+			return limitError(fmt.Sprintf("Value exceeds the maximum of %d elements", l.MaxArrayLength), pointer)
+		}
+
+		for i, member := range typed {
+			err := l.checkValue(fmt.Sprintf("%s/%d", pointer, i), member, maxString)
+			if err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		for name, member := range typed {
+			err := l.checkValue(pointer+"/"+pointerToken(name), member, maxString)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// limitError creates a 422 error for a value exceeding an attribute limit
+func limitError(detail string, pointer string) *Error {
+	err := &Error{
+		Title:  "Attribute Limit Exceeded",
+		Detail: detail,
+		Status: 422,
+	}
+	err.Source.Pointer = pointer
+
+	return err
+}
@@ -0,0 +1,74 @@
+package jsh
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAttributeLimits(t *testing.T) {
+
+	Convey("Attribute Limits Tests", t, func() {
+
+		Register(&Resource{
+			Type: "comments",
+			Limits: &AttributeLimits{
+				MaxAttributes:   3,
+				MaxStringLength: 5,
+				MaxArrayLength:  2,
+				StringLengths:   map[string]int{"body": 10},
+			},
+		})
+		Reset(func() { Unregister("comments") })
+
+		parse := func(attributes string) *Error {
+			req, reqErr := testRequest([]byte(`{"data": {"type": "comments", "attributes": ` + attributes + `}}`))
+			So(reqErr, ShouldBeNil)
+			req.Method = "POST"
+
+			_, err := ParseObject(req)
+			return err
+		}
+
+		Convey("should accept attributes within limits", func() {
+			err := parse(`{"title": "hello", "body": "0123456789", "tags": ["a", "b"]}`)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("should limit the attribute count", func() {
+			err := parse(`{"a": 1, "b": 2, "c": 3, "d": 4}`)
+			So(err, ShouldNotBeNil)
+			So(err.Status, ShouldEqual, 422)
+			So(err.Source.Pointer, ShouldEqual, "/data/attributes")
+		})
+
+		Convey("should limit string lengths", func() {
+			err := parse(`{"title": "toolong"}`)
+			So(err, ShouldNotBeNil)
+			So(err.Source.Pointer, ShouldEqual, "/data/attributes/title")
+
+			Convey("with per attribute overrides", func() {
+				err := parse(`{"body": "0123456789x"}`)
+				So(err, ShouldNotBeNil)
+				So(err.Source.Pointer, ShouldEqual, "/data/attributes/body")
+			})
+
+			Convey("counting characters rather than bytes", func() {
+				err := parse(`{"title": "héllo"}`)
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("should limit array lengths", func() {
+			err := parse(`{"tags": ["a", "b", "c"]}`)
+			So(err, ShouldNotBeNil)
+			So(err.Source.Pointer, ShouldEqual, "/data/attributes/tags")
+		})
+
+		Convey("should point at nested values", func() {
+			err := parse(`{"address": {"lines": ["ok", "toolong"]}}`)
+			So(err, ShouldNotBeNil)
+			So(err.Source.Pointer, ShouldEqual, "/data/attributes/address/lines/1")
+		})
+	})
+}
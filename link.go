@@ -4,6 +4,12 @@ package jsh
 type Links struct {
 	Self    *Link `json:"self,omitempty"`
 	Related *Link `json:"related,omitempty"`
+	// First, Last, Prev, and Next are pagination links, only valid as top-level
+	// document links for a list response.
+	First *Link `json:"first,omitempty"`
+	Last  *Link `json:"last,omitempty"`
+	Prev  *Link `json:"prev,omitempty"`
+	Next  *Link `json:"next,omitempty"`
 }
 
 // Link is a JSON format type
@@ -0,0 +1,94 @@
+package jsh
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// FullLinkageMode determines how included resources that no relationship
+// links to are handled when sending.
+type FullLinkageMode int
+
+const (
+	// IgnoreOrphanedIncludes sends compound documents as they are
+	IgnoreOrphanedIncludes FullLinkageMode = iota
+	// WarnOrphanedIncludes sends them, logging an OrphanedInclude entry for
+	// each orphaned resource
+	WarnOrphanedIncludes
+	// RejectOrphanedIncludes sends a 500 error instead
+	RejectOrphanedIncludes
+)
+
+/*
+FullLinkage enforces the full linkage rule of compound documents: every included
+resource must be reachable from the primary data through relationship linkage,
+directly or via other included resources. Servers including resources by hand
+can catch those they forgot to link, before clients silently drop them:
+
+	jsh.FullLinkage = jsh.WarnOrphanedIncludes
+
+As the specification allows, the rule isn't enforced for requests with sparse
+fieldsets, which may leave out the relationships that link the includes.
+*/
+var FullLinkage = IgnoreOrphanedIncludes
+
+// checkFullLinkage enforces FullLinkage on a document being sent
+func checkFullLinkage(r *http.Request, document *Document) *Error {
+	if FullLinkage == IgnoreOrphanedIncludes || len(document.Included) == 0 || len(parseFieldsets(r)) > 0 {
+		return nil
+	}
+
+	for _, orphan := range document.orphans() {
+		err := ISE(fmt.Sprintf("Included resource '%s' of type '%s' is not linked to by any relationship", orphan.ID, orphan.Type))
+		if FullLinkage == RejectOrphanedIncludes {
+			return err
+		}
+
+		logOrphan(r, orphan, err)
+	}
+
+	return nil
+}
+
+// orphans returns the included resources that can't be reached through the
+// relationships of the primary data and the included resources it reaches
+func (d *Document) orphans() List {
+	included := map[string]*Object{}
+	for _, object := range d.Included {
+		included[resourceKey(object.Type, object.ID)] = object
+	}
+
+	reached := map[string]bool{}
+	pending := append(List{}, d.Data...)
+	for len(pending) > 0 {
+		object := pending[0]
+		pending = pending[1:]
+
+		for _, relationship := range object.Relationships {
+			if relationship == nil {
+				continue
+			}
+
+			for _, identifier := range relationship.Data {
+				key := resourceKey(identifier.Type, identifier.ID)
+				if reached[key] {
+					continue
+				}
+
+				reached[key] = true
+				if linked, exists := included[key]; exists {
+					pending = append(pending, linked)
+				}
+			}
+		}
+	}
+
+	orphans := List{}
+	for _, object := range d.Included {
+		if !reached[resourceKey(object.Type, object.ID)] {
+			orphans = append(orphans, object)
+		}
+	}
+
+	return orphans
+}
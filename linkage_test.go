@@ -0,0 +1,84 @@
+package jsh
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFullLinkage(t *testing.T) {
+
+	Convey("Full Linkage Tests", t, func() {
+
+		Reset(func() {
+			FullLinkage = IgnoreOrphanedIncludes
+			Logging = nil
+		})
+
+		linked := func(resourceType string, id string, relatedType string, relatedID string) *Object {
+			object := &Object{Type: resourceType, ID: id, Attributes: []byte(`{}`)}
+			if relatedType != "" {
+				object.Relationships = map[string]*Relationship{
+					relatedType: {Data: ResourceLinkage{{Type: relatedType, ID: relatedID}}},
+				}
+			}
+			return object
+		}
+
+		user := linked("users", "1", "teams", "1")
+		user.Status = http.StatusOK
+
+		document := Build(user)
+		document.Included = List{
+			linked("orgs", "1", "", ""),
+			linked("teams", "1", "orgs", "1"),
+			linked("teams", "9", "", ""),
+		}
+
+		send := func(url string) *httptest.ResponseRecorder {
+			request, _ := http.NewRequest("GET", url, nil)
+			recorder := httptest.NewRecorder()
+			SendDocument(recorder, request, document)
+			return recorder
+		}
+
+		Convey("->orphans()", func() {
+			orphans := document.orphans()
+			So(len(orphans), ShouldEqual, 1)
+			So(orphans[0].ID, ShouldEqual, "9")
+
+			document.Included = document.Included[:2]
+			So(len(document.orphans()), ShouldEqual, 0)
+		})
+
+		Convey("should ignore orphans by default", func() {
+			So(send("/users/1").Code, ShouldEqual, http.StatusOK)
+		})
+
+		Convey("should log orphans with WarnOrphanedIncludes", func() {
+			FullLinkage = WarnOrphanedIncludes
+			entries := []*LogEntry{}
+			Logging = LoggerFunc(func(entry *LogEntry) {
+				if entry.Kind == OrphanedInclude {
+					entries = append(entries, entry)
+				}
+			})
+
+			So(send("/users/1").Code, ShouldEqual, http.StatusOK)
+			So(len(entries), ShouldEqual, 1)
+			So(entries[0].Type, ShouldEqual, "teams")
+			So(entries[0].ID, ShouldEqual, "9")
+		})
+
+		Convey("should reject orphans with RejectOrphanedIncludes", func() {
+			FullLinkage = RejectOrphanedIncludes
+			So(send("/users/1").Code, ShouldEqual, http.StatusInternalServerError)
+
+			Convey("unless sparse fieldsets were requested", func() {
+				So(send("/users/1?fields[users]=name").Code, ShouldEqual, http.StatusOK)
+			})
+		})
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
 )
 
 // List is just a wrapper around an object array that implements Sendable
@@ -40,7 +41,7 @@ func (list *List) UnmarshalJSON(rawData []byte) error {
 
 	newList := UnmarshalList{}
 
-	err := json.Unmarshal(rawData, &newList)
+	err := JSON.Unmarshal(rawData, &newList)
 	if err != nil {
 		return err
 	}
@@ -50,3 +51,237 @@ func (list *List) UnmarshalJSON(rawData []byte) error {
 
 	return nil
 }
+
+// FindByID returns the object matching resourceType and id, or nil if the list
+// doesn't contain one.
+func (list List) FindByID(resourceType string, id string) *Object {
+	for _, object := range list {
+		if object.Type == resourceType && object.ID == id {
+			return object
+		}
+	}
+
+	return nil
+}
+
+// Filter returns a new List containing only the objects for which keep returns
+// true.
+func (list List) Filter(keep func(object *Object) bool) List {
+	filtered := List{}
+	for _, object := range list {
+		if keep(object) {
+			filtered = append(filtered, object)
+		}
+	}
+
+	return filtered
+}
+
+// IDs returns the ID of each object in the list, in order.
+func (list List) IDs() []string {
+	ids := make([]string, len(list))
+	for i, object := range list {
+		ids[i] = object.ID
+	}
+
+	return ids
+}
+
+// Append adds objects to the end of the list.
+func (list *List) Append(objects ...*Object) {
+	*list = append(*list, objects...)
+}
+
+// SortDirection specifies the order of a sort.
+type SortDirection int
+
+const (
+	// Ascending sorts from lowest to highest
+	Ascending SortDirection = iota
+	// Descending sorts from highest to lowest
+	Descending
+)
+
+/*
+Collator compares strings according to the rules of a language, so that
+user-facing alphabetical ordering is correct beyond ASCII. It returns -1, 0, or
+1 like strings.Compare. A *collate.Collator from golang.org/x/text/collate
+satisfies it:
+
+	collator := collate.New(language.German)
+	err := list.SortByCollation("name", jsh.Ascending, collator)
+*/
+type Collator interface {
+	CompareString(a, b string) int
+}
+
+/*
+SortBy performs a stable, in place, sort of the list by the value of a single
+attribute. Use "id" to sort by object ID. Objects missing the attribute sort
+before those that have it. Strings are compared byte-wise, see SortByCollation
+for language-aware ordering.
+*/
+func (list List) SortBy(attribute string, direction SortDirection) *Error {
+	return list.SortByCollation(attribute, direction, nil)
+}
+
+/*
+SortByCollation sorts the list like SortBy, comparing string values with the
+provided Collator. A nil collator compares strings byte-wise.
+*/
+func (list List) SortByCollation(attribute string, direction SortDirection, collator Collator) *Error {
+	values := make([]interface{}, len(list))
+	for i, object := range list {
+		value, err := sortValue(object, attribute)
+		if err != nil {
+			return err
+		}
+		values[i] = value
+	}
+
+	sort.Stable(&listSorter{
+		list:   list,
+		values: values,
+		less: func(a, b interface{}) bool {
+			if direction == Descending {
+				return compareValues(b, a, collator) < 0
+			}
+			return compareValues(a, b, collator) < 0
+		},
+	})
+
+	return nil
+}
+
+/*
+Sort performs a stable, in place, sort of the list by every field of the sort,
+from the most to the least significant, such as one parsed from the request:
+
+	spec, err := jsh.ParseSort(r, "name", "created")
+	...
+	err = list.Sort(spec)
+
+Values are compared like SortBy compares them.
+*/
+func (list List) Sort(spec SortSpec) *Error {
+	values := make([]interface{}, len(list))
+	for i, object := range list {
+		objectValues, err := spec.values(object)
+		if err != nil {
+			return err
+		}
+		values[i] = objectValues
+	}
+
+	sort.Stable(&listSorter{
+		list:   list,
+		values: values,
+		less: func(a, b interface{}) bool {
+			return spec.compare(a.([]interface{}), b.([]interface{})) < 0
+		},
+	})
+
+	return nil
+}
+
+// listSorter sorts a list along with its pre-computed sort values
+type listSorter struct {
+	list   List
+	values []interface{}
+	less   func(a, b interface{}) bool
+}
+
+func (s *listSorter) Len() int { return len(s.list) }
+
+func (s *listSorter) Less(i, j int) bool { return s.less(s.values[i], s.values[j]) }
+
+func (s *listSorter) Swap(i, j int) {
+	s.list[i], s.list[j] = s.list[j], s.list[i]
+	s.values[i], s.values[j] = s.values[j], s.values[i]
+}
+
+// sortValue decodes an object's attribute into a comparable value
+func sortValue(object *Object, attribute string) (interface{}, *Error) {
+	if attribute == "id" {
+		return object.ID, nil
+	}
+
+	attributes, err := object.attributeMap()
+	if err != nil {
+		return nil, err
+	}
+
+	raw, exists := attributes[attribute]
+	if !exists {
+		return nil, nil
+	}
+
+	var value interface{}
+	jsonErr := json.Unmarshal(raw, &value)
+	if jsonErr != nil {
+		return nil, ISE(fmt.Sprintf("Unable to decode sort attribute '%s': %s", attribute, jsonErr.Error()))
+	}
+
+	return value, nil
+}
+
+// compareValues orders decoded JSON values, values of differing types are
+// ordered null < bool < number < string < everything else. Strings are compared
+// using collator if it isn't nil.
+func compareValues(a, b interface{}, collator Collator) int {
+	rankA, rankB := valueRank(a), valueRank(b)
+	if rankA != rankB {
+		return rankA - rankB
+	}
+
+	switch aVal := a.(type) {
+	case bool:
+		bVal := b.(bool)
+		if aVal == bVal {
+			return 0
+		}
+		if !aVal {
+			return -1
+		}
+		return 1
+	case float64:
+		bVal := b.(float64)
+		switch {
+		case aVal < bVal:
+			return -1
+		case aVal > bVal:
+			return 1
+		}
+		return 0
+	case string:
+		bVal := b.(string)
+		if collator != nil {
+			return collator.CompareString(aVal, bVal)
+		}
+
+		switch {
+		case aVal < bVal:
+			return -1
+		case aVal > bVal:
+			return 1
+		}
+		return 0
+	}
+
+	return 0
+}
+
+func valueRank(value interface{}) int {
+	switch value.(type) {
+	case nil:
+		return 0
+	case bool:
+		return 1
+	case float64:
+		return 2
+	case string:
+		return 3
+	}
+
+	return 4
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strconv"
+	"strings"
 	"testing"
 
 	. "github.com/smartystreets/goconvey/convey"
@@ -116,6 +117,90 @@ func TestList(t *testing.T) {
 			})
 		})
 
+		Convey("helpers", func() {
+			list := List{
+				{ID: "1", Type: "user", Attributes: json.RawMessage(`{"name":"carol","age":30}`)},
+				{ID: "2", Type: "user", Attributes: json.RawMessage(`{"name":"alice","age":25}`)},
+				{ID: "3", Type: "user", Attributes: json.RawMessage(`{"name":"bob"}`)},
+			}
+
+			Convey("->FindByID()", func() {
+				So(list.FindByID("user", "2"), ShouldEqual, list[1])
+				So(list.FindByID("post", "2"), ShouldBeNil)
+			})
+
+			Convey("->Filter()", func() {
+				filtered := list.Filter(func(object *Object) bool {
+					return object.HasAttribute("age")
+				})
+				So(filtered.IDs(), ShouldResemble, []string{"1", "2"})
+			})
+
+			Convey("->Append()", func() {
+				list.Append(&Object{ID: "4", Type: "user"})
+				So(len(list), ShouldEqual, 4)
+			})
+
+			Convey("->SortBy()", func() {
+
+				Convey("should sort strings ascending", func() {
+					err := list.SortBy("name", Ascending)
+					So(err, ShouldBeNil)
+					So(list.IDs(), ShouldResemble, []string{"2", "3", "1"})
+				})
+
+				Convey("should sort numbers descending with missing values last", func() {
+					err := list.SortBy("age", Descending)
+					So(err, ShouldBeNil)
+					So(list.IDs(), ShouldResemble, []string{"1", "2", "3"})
+				})
+
+				Convey("should sort by id", func() {
+					err := list.SortBy("id", Descending)
+					So(err, ShouldBeNil)
+					So(list.IDs(), ShouldResemble, []string{"3", "2", "1"})
+				})
+			})
+
+			Convey("->Sort()", func() {
+				list.Append(&Object{ID: "4", Type: "user", Attributes: json.RawMessage(`{"name":"alice","age":40}`)})
+
+				Convey("should sort by each field in order", func() {
+					err := list.Sort(SortSpec{
+						{Attribute: "name", Direction: Ascending},
+						{Attribute: "age", Direction: Descending},
+					})
+					So(err, ShouldBeNil)
+					So(list.IDs(), ShouldResemble, []string{"4", "2", "3", "1"})
+				})
+
+				Convey("should sort by a parsed sort", func() {
+					spec, err := ParseSort(httptest.NewRequest("GET", "/users?sort=-age", nil), "age")
+					So(err, ShouldBeNil)
+
+					err = list.Sort(spec)
+					So(err, ShouldBeNil)
+					So(list.IDs(), ShouldResemble, []string{"4", "1", "2", "3"})
+				})
+			})
+
+			Convey("->SortByCollation()", func() {
+				list[2].SetAttribute("name", "Bob")
+
+				Convey("should compare byte-wise without a collator", func() {
+					err := list.SortByCollation("name", Ascending, nil)
+					So(err, ShouldBeNil)
+					So(list.IDs(), ShouldResemble, []string{"3", "2", "1"})
+				})
+
+				Convey("should compare strings with the collator", func() {
+					err := list.SortByCollation("name", Ascending, caseInsensitiveCollator{})
+					So(err, ShouldBeNil)
+					So(list.IDs(), ShouldResemble, []string{"2", "3", "1"})
+				})
+			})
+		})
+
 		Convey("->MarshalJSON()", func() {
 
 			Convey("should preserve an empty list", func() {
@@ -129,3 +214,9 @@ func TestList(t *testing.T) {
 		})
 	})
 }
+
+type caseInsensitiveCollator struct{}
+
+func (caseInsensitiveCollator) CompareString(a, b string) int {
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
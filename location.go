@@ -0,0 +1,27 @@
+package jsh
+
+import "net/http"
+
+/*
+applyLocation sets the Location header of a 201 Created response to a POST to
+the created resource's self link, or its URL, see URLBuilder.Self, unless the
+handler set one. Responses with a 202 Accepted or 204 No Content don't get one,
+since the resource isn't available yet, or the client already knows its URL.
+*/
+func applyLocation(w http.ResponseWriter, r *http.Request, document *Document) {
+	if r.Method != "POST" || document.Status != http.StatusCreated || document.Mode != ObjectMode {
+		return
+	}
+
+	object := document.First()
+	if object == nil || object.ID == "" || w.Header().Get("Location") != "" {
+		return
+	}
+
+	location := urlBuilder().Self(object)
+	if self := object.Links["self"]; self != nil && self.HREF != "" {
+		location = self.HREF
+	}
+
+	w.Header().Set("Location", location)
+}
@@ -0,0 +1,53 @@
+package jsh
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestLocation(t *testing.T) {
+
+	Convey("Location Tests", t, func() {
+
+		send := func(method string, object *Object) *httptest.ResponseRecorder {
+			request, _ := http.NewRequest(method, "/widgets", bytes.NewReader(nil))
+			writer := httptest.NewRecorder()
+			Send(writer, request, object)
+			return writer
+		}
+
+		object, err := NewObject("1", "widgets", map[string]string{"name": "gear"})
+		So(err, ShouldBeNil)
+
+		Convey("should answer creations with a 201 and their Location", func() {
+			writer := send("POST", object)
+			So(writer.Code, ShouldEqual, http.StatusCreated)
+			So(writer.Header().Get("Location"), ShouldEqual, "/widgets/1")
+
+			Convey("preferring the self link", func() {
+				object.Links["self"] = &Link{HREF: "https://api.example.com/widgets/1"}
+				So(send("POST", object).Header().Get("Location"), ShouldEqual, "https://api.example.com/widgets/1")
+			})
+		})
+
+		Convey("should not set a Location for other responses", func() {
+			So(send("PATCH", object).Header().Get("Location"), ShouldBeEmpty)
+
+			object.Status = http.StatusAccepted
+			So(send("POST", object).Header().Get("Location"), ShouldBeEmpty)
+		})
+
+		Convey("should send 204s without a body", func() {
+			object.Status = http.StatusNoContent
+
+			writer := send("POST", object)
+			So(writer.Code, ShouldEqual, http.StatusNoContent)
+			So(writer.Body.Len(), ShouldEqual, 0)
+			So(writer.Header().Get("Content-Type"), ShouldBeEmpty)
+		})
+	})
+}
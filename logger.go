@@ -0,0 +1,185 @@
+package jsh
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// LogKind identifies the point in the parse/send lifecycle a LogEntry was
+// recorded at.
+type LogKind int
+
+const (
+	// RequestParsed is logged after a request body has been parsed successfully
+	RequestParsed LogKind = iota
+	// ValidationFailed is logged when a request body fails to parse or validate
+	ValidationFailed
+	// ResponseSent is logged after a successful response has been written
+	ResponseSent
+	// ErrorSent is logged after an error response has been written
+	ErrorSent
+	// OrphanedInclude is logged for each included resource no relationship
+	// links to, see FullLinkage
+	OrphanedInclude
+)
+
+// String returns the name of the LogKind, suitable as a log message.
+func (k LogKind) String() string {
+	switch k {
+	case RequestParsed:
+		return "request parsed"
+	case ValidationFailed:
+		return "validation failed"
+	case ResponseSent:
+		return "response sent"
+	case ErrorSent:
+		return "error sent"
+	case OrphanedInclude:
+		return "orphaned include"
+	}
+
+	return "unknown"
+}
+
+/*
+LogEntry describes a single parse or send. Type and ID are those of the first
+resource of the document, and are left empty for lists and errors. Duration is
+the time taken by the parse or send itself, not the full request.
+*/
+type LogEntry struct {
+	Kind LogKind
+	// Context is that of the request, for correlating entries with traces
+	Context   context.Context
+	Method    string
+	Path      string
+	Operation Operation
+	// Mode is that of the document parsed or sent
+	Mode DocumentMode
+	Type string
+	ID   string
+	// Status is the HTTP Status sent or, for ValidationFailed, of the error
+	// returned to the handler
+	Status   int
+	Duration time.Duration
+	// Error is the first error of ValidationFailed and ErrorSent entries
+	Error *Error
+}
+
+/*
+Logger receives LogEntries as jsh parses and sends documents, providing
+consistent logging across handlers:
+
+	jsh.Logging = jsh.LoggerFunc(func(entry *jsh.LogEntry) {
+		log.Printf("%s %s %s: %d in %s", entry.Kind, entry.Method, entry.Path, entry.Status, entry.Duration)
+	})
+
+Log is called synchronously on the request goroutine, so implementations should
+be cheap and safe for concurrent use.
+*/
+type Logger interface {
+	Log(entry *LogEntry)
+}
+
+// LoggerFunc adapts a function to the Logger interface.
+type LoggerFunc func(entry *LogEntry)
+
+// Log calls f(entry).
+func (f LoggerFunc) Log(entry *LogEntry) {
+	f(entry)
+}
+
+// Logging is the Logger jsh reports to. It is nil, and logging is disabled, by
+// default.
+var Logging Logger
+
+// logParse logs the outcome of parsing a document in the given mode
+func (p *Parser) logParse(start time.Time, mode DocumentMode, document *Document, err *Error) {
+	if Logging == nil {
+		return
+	}
+
+	entry := &LogEntry{
+		Kind:      RequestParsed,
+		Context:   p.context(),
+		Method:    p.Method,
+		Path:      p.Path,
+		Operation: methodOperation(p.Method, mode),
+		Mode:      mode,
+		Duration:  time.Since(start),
+	}
+
+	if err != nil {
+		entry.Kind = ValidationFailed
+		entry.Status = err.Status
+		entry.Error = err
+	} else if document != nil {
+		entry.Type, entry.ID = documentIdentity(document)
+	}
+
+	Logging.Log(entry)
+}
+
+// logSend logs a sent document
+func logSend(r *http.Request, start time.Time, document *Document, status int) {
+	if Logging == nil {
+		return
+	}
+
+	entry := &LogEntry{
+		Kind:      ResponseSent,
+		Context:   r.Context(),
+		Method:    r.Method,
+		Path:      requestPath(r),
+		Operation: requestOperation(r, document),
+		Mode:      document.Mode,
+		Status:    status,
+		Duration:  time.Since(start),
+	}
+
+	if len(document.Errors) > 0 {
+		entry.Kind = ErrorSent
+		entry.Error = document.Errors[0]
+	} else {
+		entry.Type, entry.ID = documentIdentity(document)
+	}
+
+	Logging.Log(entry)
+}
+
+// logOrphan logs an included resource no relationship links to, Type and ID
+// being those of the orphan
+func logOrphan(r *http.Request, orphan *Object, err *Error) {
+	if Logging == nil {
+		return
+	}
+
+	Logging.Log(&LogEntry{
+		Kind:    OrphanedInclude,
+		Context: r.Context(),
+		Method:  r.Method,
+		Path:    requestPath(r),
+		Type:    orphan.Type,
+		ID:      orphan.ID,
+		Error:   err,
+	})
+}
+
+// documentIdentity returns the type and ID of a single resource document
+func documentIdentity(document *Document) (string, string) {
+	if document.Mode != ObjectMode || !document.HasData() {
+		return "", ""
+	}
+
+	object := document.First()
+	return object.Type, object.ID
+}
+
+// context returns the parser's Context, or the background context if unset
+func (p *Parser) context() context.Context {
+	if p.Context == nil {
+		return context.Background()
+	}
+
+	return p.Context
+}
@@ -0,0 +1,57 @@
+//go:build go1.21
+// +build go1.21
+
+package jsh
+
+import (
+	"context"
+	"log/slog"
+)
+
+/*
+SlogLogger adapts a *slog.Logger, slog.Default() if nil, to the Logger
+interface:
+
+	jsh.Logging = jsh.SlogLogger(nil)
+
+Successful parses and sends are logged at the Info level, failed validations at
+Warn, and error responses at Error for 5xx statuses or Warn otherwise.
+*/
+func SlogLogger(logger *slog.Logger) Logger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return LoggerFunc(func(entry *LogEntry) {
+		attrs := []slog.Attr{
+			slog.String("method", entry.Method),
+			slog.String("path", entry.Path),
+			slog.Int("status", entry.Status),
+			slog.Duration("duration", entry.Duration),
+		}
+
+		if entry.Type != "" {
+			attrs = append(attrs, slog.String("type", entry.Type))
+		}
+		if entry.ID != "" {
+			attrs = append(attrs, slog.String("id", entry.ID))
+		}
+		if entry.Error != nil {
+			attrs = append(attrs, slog.String("error", entry.Error.Error()))
+		}
+
+		logger.LogAttrs(context.Background(), slogLevel(entry), entry.Kind.String(), attrs...)
+	})
+}
+
+// slogLevel returns the level to log an entry at
+func slogLevel(entry *LogEntry) slog.Level {
+	switch {
+	case entry.Kind == ErrorSent && entry.Status >= 500:
+		return slog.LevelError
+	case entry.Kind == ErrorSent, entry.Kind == ValidationFailed, entry.Kind == OrphanedInclude:
+		return slog.LevelWarn
+	}
+
+	return slog.LevelInfo
+}
@@ -0,0 +1,39 @@
+//go:build go1.21
+// +build go1.21
+
+package jsh
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSlogLogger(t *testing.T) {
+
+	Convey("Slog Logger Tests", t, func() {
+
+		output := &bytes.Buffer{}
+		Logging = SlogLogger(slog.New(slog.NewTextHandler(output, nil)))
+		Reset(func() { Logging = nil })
+
+		Convey("should log resources at info", func() {
+			object, _ := NewObject("1", "user", map[string]string{"name": "Bob"})
+			Send(httptest.NewRecorder(), &http.Request{Method: "GET"}, object)
+
+			So(output.String(), ShouldContainSubstring, `level=INFO msg="response sent"`)
+			So(output.String(), ShouldContainSubstring, "type=user id=1")
+		})
+
+		Convey("should log server errors at error", func() {
+			Send(httptest.NewRecorder(), &http.Request{Method: "GET"}, ISE("boom"))
+
+			So(output.String(), ShouldContainSubstring, `level=ERROR msg="error sent"`)
+			So(output.String(), ShouldContainSubstring, "status=500")
+		})
+	})
+}
@@ -0,0 +1,82 @@
+package jsh
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type logContextKey struct{}
+
+var logKey = logContextKey{}
+
+func TestLogger(t *testing.T) {
+
+	Convey("Logger Tests", t, func() {
+
+		entries := []*LogEntry{}
+		Logging = LoggerFunc(func(entry *LogEntry) {
+			entries = append(entries, entry)
+		})
+		Reset(func() { Logging = nil })
+
+		Convey("should log parsed requests", func() {
+			req, reqErr := testRequest([]byte(`{"data": {"type": "user", "id": "1", "attributes": {"name": "Bob"}}}`))
+			So(reqErr, ShouldBeNil)
+			req = req.WithContext(context.WithValue(req.Context(), logKey, "traced"))
+
+			_, err := ParseObject(req)
+			So(err, ShouldBeNil)
+
+			So(len(entries), ShouldEqual, 1)
+			So(entries[0].Kind, ShouldEqual, RequestParsed)
+			So(entries[0].Type, ShouldEqual, "user")
+			So(entries[0].ID, ShouldEqual, "1")
+			So(entries[0].Operation, ShouldEqual, FetchOperation)
+			So(entries[0].Mode, ShouldEqual, ObjectMode)
+			So(entries[0].Context.Value(logKey), ShouldEqual, "traced")
+			So(entries[0].Duration, ShouldBeGreaterThan, 0)
+		})
+
+		Convey("should log failed validations", func() {
+			req, reqErr := testRequest([]byte(`{"data": {"id": "1", "attributes": {"name": "Bob"}}}`))
+			So(reqErr, ShouldBeNil)
+
+			_, err := ParseObject(req)
+			So(err, ShouldNotBeNil)
+
+			So(len(entries), ShouldEqual, 1)
+			So(entries[0].Kind, ShouldEqual, ValidationFailed)
+			So(entries[0].Status, ShouldEqual, err.Status)
+			So(entries[0].Error, ShouldEqual, err)
+		})
+
+		Convey("should log sent responses", func() {
+			object, _ := NewObject("1", "user", map[string]string{"name": "Bob"})
+
+			writer := httptest.NewRecorder()
+			Send(writer, &http.Request{Method: "GET"}, object)
+
+			So(len(entries), ShouldEqual, 1)
+			So(entries[0].Kind, ShouldEqual, ResponseSent)
+			So(entries[0].Status, ShouldEqual, http.StatusOK)
+			So(entries[0].Type, ShouldEqual, "user")
+			So(entries[0].ID, ShouldEqual, "1")
+			So(entries[0].Operation, ShouldEqual, FetchOperation)
+		})
+
+		Convey("should log sent errors", func() {
+			writer := httptest.NewRecorder()
+			Send(writer, &http.Request{Method: "GET"}, NotFound("user", "1"))
+
+			So(len(entries), ShouldEqual, 1)
+			So(entries[0].Kind, ShouldEqual, ErrorSent)
+			So(entries[0].Status, ShouldEqual, http.StatusNotFound)
+			So(entries[0].Error, ShouldNotBeNil)
+			So(entries[0].Type, ShouldBeEmpty)
+		})
+	})
+}
@@ -0,0 +1,70 @@
+package jsh
+
+import "fmt"
+
+// ServerManagedMode determines how server-managed attributes written by clients
+// are treated, see ServerManaged.
+type ServerManagedMode int
+
+const (
+	// RejectServerManaged rejects objects writing a server-managed attribute
+	// with a 422 pointing at it
+	RejectServerManaged ServerManagedMode = iota
+	// StripServerManaged removes server-managed attributes from parsed objects,
+	// for clients that send back the resources they fetched as they are
+	StripServerManaged
+)
+
+/*
+ServerManaged declares the attributes of a resource type that only the server
+sets, such as timestamps and ownership, so that clients can't set them on POST
+or PATCH:
+
+	jsh.Register(&jsh.Resource{
+		Type: "documents",
+		ServerManaged: &jsh.ServerManaged{
+			Attributes: []string{"created_at", "updated_at", "owner_id"},
+			Mode:       jsh.StripServerManaged,
+		},
+	})
+
+Server-managed attributes are still sent as usual.
+*/
+type ServerManaged struct {
+	// Attributes lists the names of the server-managed attributes
+	Attributes []string
+	// Mode determines whether writes of the attributes are rejected, the
+	// default, or stripped
+	Mode ServerManagedMode
+}
+
+// accept rejects or strips the server-managed attributes of a parsed object
+func (m *ServerManaged) accept(object *Object) *Error {
+	if !object.HasAttributes() {
+		return nil
+	}
+
+	attributes, err := object.attributeMap()
+	if err != nil {
+		return err
+	}
+
+	stripped := false
+	for _, name := range m.Attributes {
+		if _, exists := attributes[name]; !exists {
+			continue
+		}
+
+		if m.Mode != StripServerManaged {
+			return InputError(fmt.Sprintf("Attribute '%s' of type '%s' is set by the server and cannot be written", name, object.Type), name)
+		}
+
+		delete(attributes, name)
+		stripped = true
+	}
+	if !stripped {
+		return nil
+	}
+
+	return object.marshal(attributes, nil)
+}
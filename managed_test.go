@@ -0,0 +1,52 @@
+package jsh
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestServerManaged(t *testing.T) {
+
+	Convey("Server Managed Tests", t, func() {
+
+		managed := &ServerManaged{Attributes: []string{"created_at", "owner_id"}}
+		Register(&Resource{Type: "documents", ServerManaged: managed})
+		Reset(func() { Unregister("documents") })
+
+		parse := func(method string, body string) (*Object, *Error) {
+			req, reqErr := testRequest([]byte(body))
+			So(reqErr, ShouldBeNil)
+			req.Method = method
+
+			return ParseObject(req)
+		}
+
+		Convey("should reject writes by default", func() {
+			_, err := parse("POST", `{"data": {"type": "documents", "attributes": {"title": "Plan", "owner_id": "7"}}}`)
+			So(err, ShouldNotBeNil)
+			So(err.Status, ShouldEqual, 422)
+			So(err.Source.Pointer, ShouldEqual, "/data/attributes/owner_id")
+		})
+
+		Convey("should accept objects without them", func() {
+			object, err := parse("PATCH", `{"data": {"type": "documents", "id": "1", "attributes": {"title": "Plan"}}}`)
+			So(err, ShouldBeNil)
+			So(object.HasAttribute("title"), ShouldBeTrue)
+		})
+
+		Convey("should be accepted when read", func() {
+			_, err := parse("GET", `{"data": {"type": "documents", "id": "1", "attributes": {"created_at": "2016-01-02"}}}`)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("should be stripped in StripServerManaged mode", func() {
+			managed.Mode = StripServerManaged
+
+			object, err := parse("PATCH", `{"data": {"type": "documents", "id": "1", "attributes": {"title": "Plan", "created_at": "2016-01-02"}}}`)
+			So(err, ShouldBeNil)
+			So(object.HasAttribute("title"), ShouldBeTrue)
+			So(object.HasAttribute("created_at"), ShouldBeFalse)
+		})
+	})
+}
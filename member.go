@@ -0,0 +1,171 @@
+package jsh
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// MemberNameMode determines how strictly parsed member names are checked.
+type MemberNameMode int
+
+const (
+	// IgnoreMemberNames accepts any member name
+	IgnoreMemberNames MemberNameMode = iota
+	// LenientMemberNames rejects attributes and relationships named "id" or
+	// "type", or that share a name, since the specification reserves those
+	LenientMemberNames
+	// StrictMemberNames additionally rejects names of attributes, including
+	// nested ones, relationships, and meta that use characters the
+	// specification doesn't allow, such as "$", "." or a leading "-"
+	StrictMemberNames
+)
+
+/*
+ValidMemberName returns true if name only uses the characters the JSON API
+specification allows in member names: letters, digits, and non-ASCII
+characters anywhere, as well as "-", "_", and " " other than at the start or
+end. Names prefixed with "@" are at-members, which are also allowed.
+*/
+func ValidMemberName(name string) bool {
+	if len(name) > 1 && name[0] == '@' {
+		name = name[1:]
+	}
+
+	if name == "" {
+		return false
+	}
+
+	last := utf8.RuneCountInString(name) - 1
+	i := 0
+	for _, char := range name {
+		switch {
+		case char >= 'a' && char <= 'z', char >= 'A' && char <= 'Z', char >= '0' && char <= '9':
+		case char >= 0x80 && char != utf8.RuneError:
+		case char == '-' || char == '_' || char == ' ':
+			if i == 0 || i == last {
+				return false
+			}
+		default:
+			return false
+		}
+		i++
+	}
+
+	return true
+}
+
+// checkMemberNames validates the member names of a parsed object
+func checkMemberNames(mode MemberNameMode, object *Object) *Error {
+	if mode == IgnoreMemberNames {
+		return nil
+	}
+
+	attributes, err := object.attributeMap()
+	if err != nil {
+		return err
+	}
+
+	for name, raw := range attributes {
+		pointer := "/data/attributes/" + pointerToken(name)
+
+		if name == "id" || name == "type" {
+			return memberNameError(fmt.Sprintf("'%s' is reserved and cannot be used as an attribute", name), pointer)
+		}
+
+		if _, exists := object.Relationships[name]; exists {
+			return memberNameError(fmt.Sprintf("'%s' cannot be both an attribute and a relationship", name), pointer)
+		}
+
+		if mode == StrictMemberNames {
+			err = checkNestedNames(pointer, name, raw)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for name := range object.Relationships {
+		pointer := "/data/relationships/" + pointerToken(name)
+
+		if name == "id" || name == "type" {
+			return memberNameError(fmt.Sprintf("'%s' is reserved and cannot be used as a relationship", name), pointer)
+		}
+
+		if mode == StrictMemberNames && !ValidMemberName(name) {
+			return memberNameError(fmt.Sprintf("Invalid relationship name '%s'", name), pointer)
+		}
+	}
+
+	if mode == StrictMemberNames {
+		for name := range object.Meta {
+			if !ValidMemberName(name) {
+				return memberNameError(fmt.Sprintf("Invalid meta member name '%s'", name), "/data/meta/"+pointerToken(name))
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkNestedNames validates the name of a member along with the names of any
+// members of objects nested within its value
+func checkNestedNames(pointer string, name string, raw json.RawMessage) *Error {
+	if !ValidMemberName(name) {
+		return memberNameError(fmt.Sprintf("Invalid member name '%s'", name), pointer)
+	}
+
+	var value interface{}
+	if json.Unmarshal(raw, &value) != nil {
+		return nil
+	}
+
+	return checkNestedValue(pointer, value)
+}
+
+// checkNestedValue walks decoded JSON checking the names of object members
+func checkNestedValue(pointer string, value interface{}) *Error {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for name, member := range typed {
+			memberPointer := pointer + "/" + pointerToken(name)
+			if !ValidMemberName(name) {
+				return memberNameError(fmt.Sprintf("Invalid member name '%s'", name), memberPointer)
+			}
+
+			err := checkNestedValue(memberPointer, member)
+			if err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for i, member := range typed {
+			err := checkNestedValue(fmt.Sprintf("%s/%d", pointer, i), member)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// pointerToken escapes a member name for use in a JSON pointer
+func pointerToken(name string) string {
+	return pointerEscaper.Replace(name)
+}
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// memberNameError creates a 422 error for an invalid member name
+func memberNameError(detail string, pointer string) *Error {
+	err := &Error{
+		Title:  "Invalid Member Name",
+		Detail: detail,
+		Status: 422,
+	}
+	err.Source.Pointer = pointer
+
+	return err
+}
@@ -0,0 +1,90 @@
+package jsh
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMemberNames(t *testing.T) {
+
+	Convey("Member Name Tests", t, func() {
+
+		Convey("->ValidMemberName()", func() {
+			for _, name := range []string{"title", "first-name", "first_name", "first name", "café", "a1", "@context"} {
+				So(ValidMemberName(name), ShouldBeTrue)
+			}
+
+			for _, name := range []string{"", "-title", "title_", "$ref", "a.b", "a/b", "@"} {
+				So(ValidMemberName(name), ShouldBeFalse)
+			}
+		})
+
+		parse := func(body string, mode MemberNameMode) *Error {
+			req, reqErr := testRequest([]byte(body))
+			So(reqErr, ShouldBeNil)
+
+			parser := NewParser(req)
+			parser.Options = &ParseOptions{MemberNames: mode}
+			_, err := parser.Document(req.Body, ObjectMode)
+			return err
+		}
+
+		Convey("should ignore member names by default", func() {
+			err := parse(`{"data": {"type": "users", "id": "1", "attributes": {"id": "1", "$ref": "x"}}}`, IgnoreMemberNames)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("lenient mode", func() {
+
+			Convey("should reject reserved attribute names", func() {
+				err := parse(`{"data": {"type": "users", "id": "1", "attributes": {"type": "admin"}}}`, LenientMemberNames)
+				So(err, ShouldNotBeNil)
+				So(err.Status, ShouldEqual, 422)
+				So(err.Source.Pointer, ShouldEqual, "/data/attributes/type")
+			})
+
+			Convey("should reject attributes shared with relationships", func() {
+				err := parse(`{"data": {"type": "users", "id": "1", "attributes": {"company": "x"},
+					"relationships": {"company": {"data": {"type": "companies", "id": "1"}}}}}`, LenientMemberNames)
+				So(err, ShouldNotBeNil)
+				So(err.Source.Pointer, ShouldEqual, "/data/attributes/company")
+			})
+
+			Convey("should accept unusual characters", func() {
+				err := parse(`{"data": {"type": "users", "id": "1", "attributes": {"$ref": "x"}}}`, LenientMemberNames)
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("strict mode", func() {
+
+			Convey("should reject invalid nested attribute names", func() {
+				err := parse(`{"data": {"type": "users", "id": "1", "attributes": {"address": {"zip.code": "1"}}}}`, StrictMemberNames)
+				So(err, ShouldNotBeNil)
+				So(err.Source.Pointer, ShouldEqual, "/data/attributes/address/zip.code")
+			})
+
+			Convey("should reject invalid names in arrays", func() {
+				err := parse(`{"data": {"type": "users", "id": "1", "attributes": {"tags": [{"ok": 1}, {"a/b": 2}]}}}`, StrictMemberNames)
+				So(err, ShouldNotBeNil)
+				So(err.Source.Pointer, ShouldEqual, "/data/attributes/tags/1/a~1b")
+			})
+
+			Convey("should reject invalid relationship and meta names", func() {
+				err := parse(`{"data": {"type": "users", "id": "1", "relationships": {"-boss": {"data": null}}}}`, StrictMemberNames)
+				So(err, ShouldNotBeNil)
+				So(err.Source.Pointer, ShouldEqual, "/data/relationships/-boss")
+
+				err = parse(`{"data": {"type": "users", "id": "1", "meta": {"$v": 1}}}`, StrictMemberNames)
+				So(err, ShouldNotBeNil)
+				So(err.Source.Pointer, ShouldEqual, "/data/meta/$v")
+			})
+
+			Convey("should accept valid names", func() {
+				err := parse(`{"data": {"type": "users", "id": "1", "attributes": {"first-name": "a", "address": {"zip_code": "1"}}}}`, StrictMemberNames)
+				So(err, ShouldBeNil)
+			})
+		})
+	})
+}
@@ -0,0 +1,235 @@
+package jsh
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// MergeOptions configures how Merge combines documents.
+type MergeOptions struct {
+	// BaseURL, if set, replaces the scheme and host of absolute links sent by
+	// upstream services and prefixes their path with its own, so that links
+	// point at the aggregating service, such as https://api.example.com/v1.
+	BaseURL string
+	// ReconcileMeta resolves a top-level meta member that documents sent with
+	// differing values. The first value is kept if nil.
+	ReconcileMeta func(key string, values []interface{}) interface{}
+	// Provenance, if set, records the source of the attributes and
+	// relationships of each merged resource
+	Provenance *Provenance
+	// Sources names the source of each of the merged documents for
+	// Provenance. Documents without one are named by their self link, or
+	// failing that their index.
+	Sources []string
+}
+
+/*
+Merge combines documents fetched from upstream services into a single list
+document, for backend-for-frontend services aggregating several APIs:
+
+	merged, err := jsh.Merge(&jsh.MergeOptions{BaseURL: "https://api.example.com"}, users, teams)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+	jsh.SendDocument(w, r, merged)
+
+Primary data is concatenated, with repeated resources only kept once, and
+included resources are deduplicated, leaving out any already present in the
+primary data. The members of top-level meta objects are combined. Top-level
+links are dropped as none of the upstream ones describe the merged document.
+
+If any of the documents are error documents, their errors are combined into a
+single error document instead.
+*/
+func Merge(options *MergeOptions, documents ...*Document) (*Document, *Error) {
+	if options == nil {
+		options = &MergeOptions{}
+	}
+
+	base, err := mergeBase(options.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := New()
+	merged.Mode = ListMode
+	merged.Data = List{}
+
+	errors := ErrorList{}
+	seen := map[string]bool{}
+	metas := map[string][]interface{}{}
+	metaOrder := []string{}
+
+	for i, document := range documents {
+		if document == nil {
+			continue
+		}
+
+		errors = append(errors, document.Errors...)
+
+		for _, object := range document.Data {
+			if object.ID != "" {
+				key := resourceKey(object.Type, object.ID)
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+			}
+
+			err := options.record(i, document, object)
+			if err != nil {
+				return nil, err
+			}
+
+			rebaseObject(base, object)
+			merged.Data = append(merged.Data, object)
+		}
+
+		for key, value := range metaMap(document.Meta) {
+			if _, exists := metas[key]; !exists {
+				metaOrder = append(metaOrder, key)
+			}
+			metas[key] = append(metas[key], value)
+		}
+	}
+
+	if len(errors) > 0 {
+		return Build(errors), nil
+	}
+
+	for i, document := range documents {
+		if document == nil {
+			continue
+		}
+
+		for _, object := range document.Included {
+			key := resourceKey(object.Type, object.ID)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			err := options.record(i, document, object)
+			if err != nil {
+				return nil, err
+			}
+
+			rebaseObject(base, object)
+			merged.Included = append(merged.Included, object)
+		}
+	}
+
+	if len(metaOrder) > 0 {
+		meta := map[string]interface{}{}
+		for _, key := range metaOrder {
+			meta[key] = options.reconcile(key, metas[key])
+		}
+		merged.Meta = meta
+	}
+
+	merged.Status = http.StatusOK
+	return merged, nil
+}
+
+// record records the provenance of a merged object, if enabled
+func (o *MergeOptions) record(i int, document *Document, object *Object) *Error {
+	if o.Provenance == nil {
+		return nil
+	}
+
+	return o.Provenance.Record(object, o.mergeSource(i, document))
+}
+
+// reconcile picks the value of a meta member sent by several documents
+func (o *MergeOptions) reconcile(key string, values []interface{}) interface{} {
+	for _, value := range values[1:] {
+		if !reflect.DeepEqual(value, values[0]) {
+			if o.ReconcileMeta != nil {
+				return o.ReconcileMeta(key, values)
+			}
+			break
+		}
+	}
+
+	return values[0]
+}
+
+// mergeBase parses the base URL links are rebased onto
+func mergeBase(baseURL string) (*url.URL, *Error) {
+	if baseURL == "" {
+		return nil, nil
+	}
+
+	base, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil || base.Host == "" {
+		return nil, ISE(fmt.Sprintf("Invalid merge base URL: %s", baseURL))
+	}
+
+	return base, nil
+}
+
+// rebaseObject rebases the links of an object and its relationships
+func rebaseObject(base *url.URL, object *Object) {
+	if base == nil {
+		return
+	}
+
+	for _, link := range object.Links {
+		rebaseLink(base, link)
+	}
+
+	for _, relationship := range object.Relationships {
+		if relationship != nil && relationship.Links != nil {
+			rebaseLink(base, relationship.Links.Self)
+			rebaseLink(base, relationship.Links.Related)
+		}
+	}
+}
+
+// rebaseLink points an absolute link at the base URL
+func rebaseLink(base *url.URL, link *Link) {
+	if link == nil {
+		return
+	}
+
+	href, err := url.Parse(link.HREF)
+	if err != nil || !href.IsAbs() {
+		return
+	}
+
+	href.Scheme = base.Scheme
+	href.Host = base.Host
+	href.User = base.User
+	href.Path = base.Path + href.Path
+	href.RawPath = ""
+
+	link.HREF = href.String()
+}
+
+// metaMap returns the members of a top-level meta object
+func metaMap(meta interface{}) map[string]interface{} {
+	if meta == nil {
+		return nil
+	}
+
+	if members, isMap := meta.(map[string]interface{}); isMap {
+		return members
+	}
+
+	raw, err := json.Marshal(meta)
+	if err != nil {
+		return nil
+	}
+
+	members := map[string]interface{}{}
+	if json.Unmarshal(raw, &members) != nil {
+		return nil
+	}
+
+	return members
+}
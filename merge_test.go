@@ -0,0 +1,81 @@
+package jsh
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMerge(t *testing.T) {
+
+	Convey("Merge Tests", t, func() {
+
+		user, _ := NewObject("1", "users", map[string]string{"name": "bob"})
+		user.Links = map[string]*Link{"self": {HREF: "http://users.internal/users/1"}}
+		team, _ := NewObject("1", "teams", map[string]string{"name": "a"})
+		duplicate, _ := NewObject("1", "users", map[string]string{"name": "bob"})
+
+		users := Build(List{user})
+		users.Included = []*Object{team}
+		users.Meta = map[string]interface{}{"region": "us", "count": 1}
+
+		teams := Build(List{team, duplicate})
+		teams.Meta = map[string]interface{}{"region": "us", "count": 2}
+
+		Convey("should combine and deduplicate primary data", func() {
+			merged, err := Merge(nil, users, teams)
+			So(err, ShouldBeNil)
+			So(merged.Mode, ShouldEqual, ListMode)
+			So(len(merged.Data), ShouldEqual, 2)
+			So(merged.Data[0].Type, ShouldEqual, "users")
+			So(merged.Data[1].Type, ShouldEqual, "teams")
+		})
+
+		Convey("should leave out included resources present in primary data", func() {
+			merged, err := Merge(nil, users, teams)
+			So(err, ShouldBeNil)
+			So(merged.Included, ShouldBeEmpty)
+		})
+
+		Convey("should rebase links", func() {
+			merged, err := Merge(&MergeOptions{BaseURL: "https://api.example.com/v1/"}, users)
+			So(err, ShouldBeNil)
+			So(merged.Data[0].Links["self"].HREF, ShouldEqual, "https://api.example.com/v1/users/1")
+		})
+
+		Convey("should reconcile meta", func() {
+			merged, err := Merge(nil, users, teams)
+			So(err, ShouldBeNil)
+
+			meta := merged.Meta.(map[string]interface{})
+			So(meta["region"], ShouldEqual, "us")
+			So(meta["count"], ShouldEqual, 1)
+
+			Convey("with a custom reconciler", func() {
+				merged, err := Merge(&MergeOptions{
+					ReconcileMeta: func(key string, values []interface{}) interface{} {
+						sum := 0
+						for _, value := range values {
+							sum += value.(int)
+						}
+						return sum
+					},
+				}, users, teams)
+				So(err, ShouldBeNil)
+				So(merged.Meta.(map[string]interface{})["count"], ShouldEqual, 3)
+			})
+		})
+
+		Convey("should combine errors", func() {
+			merged, err := Merge(nil, users, Build(NotFound("teams", "2")))
+			So(err, ShouldBeNil)
+			So(merged.HasErrors(), ShouldBeTrue)
+			So(merged.Data, ShouldBeEmpty)
+		})
+
+		Convey("should reject invalid base URLs", func() {
+			_, err := Merge(&MergeOptions{BaseURL: "/relative"}, users)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
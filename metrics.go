@@ -0,0 +1,71 @@
+package jsh
+
+import "net/http"
+
+// MetricKind identifies the stage of the request lifecycle a MetricEvent
+// describes.
+type MetricKind int
+
+const (
+	// ParseEvent is reported after a JSON API body has been read by a Parser
+	ParseEvent MetricKind = iota
+	// SendEvent is reported after a response Document has been serialized and
+	// written
+	SendEvent
+	// DeprecationEvent is reported when a response sends primary data of a
+	// deprecated resource type, see TypeDeprecation
+	DeprecationEvent
+)
+
+/*
+MetricEvent carries the instrumentation data for a single parse or send. Byte
+counts make it possible to identify the endpoints and clients responsible for
+outsized documents.
+*/
+type MetricEvent struct {
+	Kind   MetricKind
+	Method string
+	// Path is the request URL path, empty if it is not known (such as when the
+	// client parses a response)
+	Path string
+	// Status is the HTTP Status sent, only set for SendEvents
+	Status int
+	// Bytes is the size of the JSON body that was parsed or serialized
+	Bytes int
+	// PeakBuffer is the capacity of the largest buffer allocated to hold the body
+	PeakBuffer int
+	// Type is the deprecated resource type, only set for DeprecationEvents
+	Type string
+	// Pool is the state of the buffer pool after the body was read or
+	// encoded, only set for ParseEvents and SendEvents of whole documents
+	Pool PoolStats
+}
+
+/*
+MetricsHook receives MetricEvents as jsh parses and sends documents. Observe is
+called synchronously on the request goroutine, so implementations should be
+cheap and safe for concurrent use.
+*/
+type MetricsHook interface {
+	Observe(event *MetricEvent)
+}
+
+// Metrics is the MetricsHook jsh reports to. It is nil, and reporting is
+// disabled, by default.
+var Metrics MetricsHook
+
+// observe reports an event to Metrics if a hook has been set
+func observe(event *MetricEvent) {
+	if Metrics != nil {
+		Metrics.Observe(event)
+	}
+}
+
+// requestPath safely returns the URL path of a request
+func requestPath(r *http.Request) string {
+	if r == nil || r.URL == nil {
+		return ""
+	}
+
+	return r.URL.Path
+}
@@ -0,0 +1,57 @@
+package jsh
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type recordingHook struct {
+	events []*MetricEvent
+}
+
+func (h *recordingHook) Observe(event *MetricEvent) {
+	h.events = append(h.events, event)
+}
+
+func TestMetrics(t *testing.T) {
+
+	Convey("Metrics Tests", t, func() {
+
+		hook := &recordingHook{}
+		Metrics = hook
+		Reset(func() { Metrics = nil })
+
+		Convey("should report parsed payload sizes", func() {
+			body := []byte(`{"data": {"type": "user", "id": "1", "attributes": {"name": "Bob"}}}`)
+			req, reqErr := testRequest(body)
+			So(reqErr, ShouldBeNil)
+
+			_, err := ParseObject(req)
+			So(err, ShouldBeNil)
+
+			So(len(hook.events), ShouldEqual, 1)
+			So(hook.events[0].Kind, ShouldEqual, ParseEvent)
+			So(hook.events[0].Bytes, ShouldEqual, len(body))
+			So(hook.events[0].PeakBuffer, ShouldBeGreaterThanOrEqualTo, len(body))
+		})
+
+		Convey("should report sent payload sizes", func() {
+			object, objErr := NewObject("1", "user", map[string]string{"name": "Bob"})
+			So(objErr, ShouldBeNil)
+
+			writer := httptest.NewRecorder()
+			req := &http.Request{Method: "GET"}
+
+			err := Send(writer, req, object)
+			So(err, ShouldBeNil)
+
+			So(len(hook.events), ShouldEqual, 1)
+			So(hook.events[0].Kind, ShouldEqual, SendEvent)
+			So(hook.events[0].Status, ShouldEqual, http.StatusOK)
+			So(hook.events[0].Bytes, ShouldEqual, writer.Body.Len())
+		})
+	})
+}
@@ -0,0 +1,189 @@
+package jsh
+
+import (
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+/*
+Middleware wraps a handler serving a JSON API, taking care of concerns common to
+every endpoint:
+
+	http.ListenAndServe(":8080", jsh.Middleware(mux))
+
+Requests with a body must have a valid JSON API Content-Type, and requests that
+only accept the JSON API media type with unsupported parameters are refused with
+a 406 as the specification requires. Panics in next are recovered and answered
+with an ISE error document, and responses leaving next without a Content-Type
+are given the JSON API one. The profiles negotiated for the request are stored
+in its context, see RequestProfiles.
+
+Requests to the routes of registered resources, see Routes, need no handlers for
+HEAD and OPTIONS. HEAD requests are handled as GET requests, sending only the
+headers, and OPTIONS requests are answered with a 204 and the AllowedMethods.
+CORS preflight requests are answered according to CORS if it is set, and left
+to next otherwise.
+*/
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if CORS.apply(w, r) {
+			return
+		}
+
+		if r.ContentLength != 0 {
+			err := validateHeaders(r.Header)
+			if err != nil {
+				err.Status = http.StatusUnsupportedMediaType
+				Send(w, r, err)
+				return
+			}
+		}
+
+		if !acceptable(r.Header) {
+			Send(w, r, SpecificationError(fmt.Sprintf(
+				"The Accept header must allow %s without media type parameters", ContentType,
+			)))
+			return
+		}
+
+		if r.Method == "OPTIONS" && r.Header.Get("Access-Control-Request-Method") == "" {
+			if allowed := AllowedMethods(r.URL.Path); allowed != nil {
+				w.Header().Set("Allow", strings.Join(allowed, ", "))
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+
+		if len(Profiles) > 0 {
+			r = WithProfiles(r)
+		}
+
+		writer := &middlewareWriter{ResponseWriter: w}
+		defer writer.recover(r)
+
+		if r.Method == "HEAD" && AllowedMethods(r.URL.Path) != nil {
+			// serve HEAD as GET, without the body
+			r = r.Clone(r.Context())
+			r.Method = "GET"
+			writer.head = true
+		}
+
+		next.ServeHTTP(writer, r)
+	})
+}
+
+/*
+acceptable returns false if the Accept header lists the JSON API media type, but
+only with parameters other than the "ext" and "profile" parameters the
+specification allows.
+*/
+func acceptable(headers http.Header) bool {
+	listed := false
+
+	for _, header := range headers["Accept"] {
+		for _, accepted := range strings.Split(header, ",") {
+			mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+			if err != nil || mediaType != ContentType {
+				continue
+			}
+
+			listed = true
+			delete(params, "q")
+			delete(params, "ext")
+			delete(params, "profile")
+			if len(params) == 0 {
+				return true
+			}
+		}
+	}
+
+	return !listed
+}
+
+/*
+middlewareWriter sets the JSON API Content-Type on responses without one, and
+tracks whether a response has begun so that panics can still be answered.
+*/
+type middlewareWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+	// head discards the body of responses to HEAD requests
+	head bool
+}
+
+func (m *middlewareWriter) WriteHeader(status int) {
+	if !m.wroteHeader {
+		m.wroteHeader = true
+
+		header := m.Header()
+		if header.Get("Content-Type") == "" && status != http.StatusNoContent && status != http.StatusNotModified {
+			header.Set("Content-Type", ContentType)
+		}
+	}
+
+	m.ResponseWriter.WriteHeader(status)
+}
+
+func (m *middlewareWriter) Write(content []byte) (int, error) {
+	if !m.wroteHeader {
+		m.WriteHeader(http.StatusOK)
+	}
+
+	if m.head {
+		return len(content), nil
+	}
+
+	return m.ResponseWriter.Write(content)
+}
+
+// Flush supports streamed responses, such as StreamList
+func (m *middlewareWriter) Flush() {
+	m.FlushError()
+}
+
+// FlushError supports streamed responses, such as StreamList
+func (m *middlewareWriter) FlushError() error {
+	switch flusher := m.ResponseWriter.(type) {
+	case errorFlusher:
+		return flusher.FlushError()
+	case http.Flusher:
+		flusher.Flush()
+	}
+
+	return nil
+}
+
+// SetWriteDeadline supports the WriteTimeout of StreamList
+func (m *middlewareWriter) SetWriteDeadline(deadline time.Time) error {
+	return http.NewResponseController(m.ResponseWriter).SetWriteDeadline(deadline)
+}
+
+// Unwrap allows http.ResponseController to reach the wrapped writer
+func (m *middlewareWriter) Unwrap() http.ResponseWriter {
+	return m.ResponseWriter
+}
+
+// recover answers a panic in the wrapped handler with an ISE, if the response
+// hasn't begun yet
+func (m *middlewareWriter) recover(r *http.Request) {
+	recovered := recover()
+	if recovered == nil {
+		return
+	}
+
+	if recovered == http.ErrAbortHandler {
+		panic(recovered)
+	}
+
+	log.Printf("jsh: recovered panic serving %s %s: %v\n%s", r.Method, requestPath(r), recovered, debug.Stack())
+	if m.wroteHeader {
+		return
+	}
+
+	Send(m, r, ISE(fmt.Sprintf("Recovered panic: %v", recovered)))
+}
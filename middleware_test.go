@@ -0,0 +1,127 @@
+package jsh
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMiddleware(t *testing.T) {
+
+	Convey("Middleware Tests", t, func() {
+
+		handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/panic":
+				panic("boom")
+			case "/plain":
+				w.Write([]byte(`{"meta": {}}`))
+			case "/stream":
+				StreamList(w, r, func() (*Object, *Error) { return nil, nil }, nil)
+			case "/widgets/1":
+				object, _ := NewObject("1", "widgets", map[string]string{"r": r.Method})
+				Send(w, r, object)
+			default:
+				w.WriteHeader(http.StatusNoContent)
+			}
+		}))
+
+		serve := func(request *http.Request) *httptest.ResponseRecorder {
+			writer := httptest.NewRecorder()
+			handler.ServeHTTP(writer, request)
+			return writer
+		}
+
+		Convey("should reject bodies without the JSON API Content-Type", func() {
+			request, _ := http.NewRequest("POST", "/", bytes.NewReader([]byte(`{}`)))
+			request.Header.Set("Content-Type", "application/json")
+
+			writer := serve(request)
+			So(writer.Code, ShouldEqual, http.StatusUnsupportedMediaType)
+			So(writer.Header().Get("Content-Type"), ShouldEqual, ContentType)
+		})
+
+		Convey("should accept bodies with the JSON API Content-Type", func() {
+			request, _ := http.NewRequest("POST", "/", bytes.NewReader([]byte(`{}`)))
+			request.Header.Set("Content-Type", ContentType)
+
+			So(serve(request).Code, ShouldEqual, http.StatusNoContent)
+		})
+
+		Convey("should check the Accept header", func() {
+			request, _ := http.NewRequest("GET", "/", nil)
+
+			request.Header.Set("Accept", ContentType+"; charset=utf-8")
+			So(serve(request).Code, ShouldEqual, http.StatusNotAcceptable)
+
+			request.Header.Set("Accept", ContentType+"; charset=utf-8, "+ContentType)
+			So(serve(request).Code, ShouldEqual, http.StatusNoContent)
+
+			request.Header.Set("Accept", `application/vnd.api+json; ext="bulk"`)
+			So(serve(request).Code, ShouldEqual, http.StatusNoContent)
+
+			request.Header.Set("Accept", "*/*")
+			So(serve(request).Code, ShouldEqual, http.StatusNoContent)
+		})
+
+		Convey("should recover panics into error documents", func() {
+			request, _ := http.NewRequest("GET", "/panic", nil)
+
+			writer := serve(request)
+			So(writer.Code, ShouldEqual, http.StatusInternalServerError)
+			So(writer.Header().Get("Content-Type"), ShouldEqual, ContentType)
+			So(writer.Body.String(), ShouldContainSubstring, DefaultErrorDetail)
+		})
+
+		Convey("should set a missing Content-Type", func() {
+			request, _ := http.NewRequest("GET", "/plain", nil)
+
+			writer := serve(request)
+			So(writer.Header().Get("Content-Type"), ShouldEqual, ContentType)
+		})
+
+		Convey("should not duplicate the Content-Type of sent documents", func() {
+			request, _ := http.NewRequest("GET", "/stream", nil)
+
+			writer := serve(request)
+			So(writer.Header()["Content-Type"], ShouldResemble, []string{ContentType})
+			So(writer.Flushed, ShouldBeTrue)
+		})
+
+		Convey("registered resource routes", func() {
+			Register(&Resource{Type: "widgets"})
+			Reset(func() { Unregister("widgets") })
+
+			Convey("should answer OPTIONS with the allowed methods", func() {
+				request, _ := http.NewRequest("OPTIONS", "/widgets/1", nil)
+
+				writer := serve(request)
+				So(writer.Code, ShouldEqual, http.StatusNoContent)
+				So(writer.Header().Get("Allow"), ShouldEqual, "DELETE, GET, HEAD, OPTIONS, PATCH")
+
+				Convey("but leave CORS preflights and other paths to the handler", func() {
+					request.Header.Set("Access-Control-Request-Method", "PATCH")
+					So(serve(request).Header().Get("Allow"), ShouldBeEmpty)
+
+					request, _ = http.NewRequest("OPTIONS", "/other", nil)
+					So(serve(request).Header().Get("Allow"), ShouldBeEmpty)
+				})
+			})
+
+			Convey("should serve HEAD as GET without the body", func() {
+				request, _ := http.NewRequest("GET", "/widgets/1", nil)
+				get := serve(request)
+
+				request, _ = http.NewRequest("HEAD", "/widgets/1", nil)
+				head := serve(request)
+				So(head.Code, ShouldEqual, http.StatusOK)
+				So(head.Body.Len(), ShouldEqual, 0)
+				So(head.Header().Get("Content-Length"), ShouldEqual, get.Header().Get("Content-Length"))
+				So(get.Body.String(), ShouldContainSubstring, `"GET"`)
+			})
+		})
+	})
+}
@@ -0,0 +1,197 @@
+package jsh
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// ModelFactory returns a new, empty Go value for objects of a resource type to
+// be unmarshaled into, usually a pointer to a struct.
+type ModelFactory func() interface{}
+
+// models holds the factories registered with RegisterType
+var models = struct {
+	sync.RWMutex
+	factories map[string]ModelFactory
+}{factories: map[string]ModelFactory{}}
+
+/*
+RegisterType registers the factory of the Go values that objects of a resource
+type unmarshal into, so documents can be hydrated without switching on each
+object's type:
+
+	jsh.RegisterType("users", func() interface{} { return &User{} })
+	jsh.RegisterType("companies", func() interface{} { return &Company{} })
+
+	models, doc, errs := jsh.ParseModels(r, jsh.ObjectMode)
+	...
+	user := models.Data[0].(*User)
+	employer := models.Get(doc.First().Relationships["employer"].Data[0]).(*Company)
+
+Registering a nil factory removes the registration.
+*/
+func RegisterType(resourceType string, factory ModelFactory) {
+	models.Lock()
+	defer models.Unlock()
+
+	if factory == nil {
+		delete(models.factories, resourceType)
+		return
+	}
+	models.factories[resourceType] = factory
+}
+
+/*
+RegisterModel registers a Go struct for a resource type like RegisterType,
+objects unmarshaling into new instances of it:
+
+	jsh.RegisterModel("articles", Article{})
+
+Pass the struct or a pointer to it. Registering a nil model removes the
+registration.
+*/
+func RegisterModel(resourceType string, model interface{}) {
+	if model == nil {
+		RegisterType(resourceType, nil)
+		return
+	}
+
+	modelType := reflect.TypeOf(model)
+	for modelType.Kind() == reflect.Ptr {
+		modelType = modelType.Elem()
+	}
+
+	RegisterType(resourceType, func() interface{} {
+		return reflect.New(modelType).Interface()
+	})
+}
+
+// registeredFactory returns the factory registered for a resource type
+func registeredFactory(resourceType string) (ModelFactory, bool) {
+	models.RLock()
+	defer models.RUnlock()
+
+	factory, registered := models.factories[resourceType]
+	return factory, registered
+}
+
+/*
+UnmarshalModel unmarshals the object's attributes into a new value from the
+factory registered for its type, see RegisterType, and returns it. As with
+Unmarshal, the result is validated. Objects of a type without a factory are
+rejected with a 422 pointing at their type.
+*/
+func (o *Object) UnmarshalModel() (interface{}, ErrorList) {
+	factory, registered := registeredFactory(o.Type)
+	if !registered {
+		err := InputError(fmt.Sprintf("Resources of type '%s' are not accepted", o.Type), "")
+		err.Title = "Invalid Type"
+		err.Source.Pointer = "/data/type"
+		return nil, ErrorList{err}
+	}
+
+	model := factory()
+	errs := o.Unmarshal(o.Type, model)
+	if errs != nil {
+		return nil, errs
+	}
+
+	return model, nil
+}
+
+// UnmarshalModels unmarshals each object of the list into the model registered
+// for its type, see UnmarshalModel. Errors point at the offending object.
+func (list List) UnmarshalModels() ([]interface{}, ErrorList) {
+	return unmarshalModels(list, "/data")
+}
+
+/*
+Models holds the Go values hydrated from a document's primary data and included
+resources, by the types registered with RegisterType.
+*/
+type Models struct {
+	// Data holds the models of the primary data, in order
+	Data []interface{}
+	// Included holds the models of the included resources, in order
+	Included []interface{}
+
+	index map[ResourceIdentifier]interface{}
+}
+
+// Get returns the model of the primary or included resource with the
+// identifier, such as that of a relationship's linkage, or nil.
+func (m *Models) Get(identifier *ResourceIdentifier) interface{} {
+	if identifier == nil {
+		return nil
+	}
+
+	return m.index[*identifier]
+}
+
+/*
+Models hydrates the document's primary data and included resources, see
+RegisterType. Every object must be of a registered type, errors pointing at the
+object that isn't, or whose attributes can't be unmarshaled.
+*/
+func (d *Document) Models() (*Models, ErrorList) {
+	data, errs := unmarshalModels(d.Data, "/data")
+	if errs != nil {
+		return nil, errs
+	}
+
+	included, errs := unmarshalModels(d.Included, "/included")
+	if errs != nil {
+		return nil, errs
+	}
+
+	models := &Models{Data: data, Included: included, index: map[ResourceIdentifier]interface{}{}}
+	for i, object := range d.Included {
+		models.index[ResourceIdentifier{Type: object.Type, ID: object.ID}] = included[i]
+	}
+	for i, object := range d.Data {
+		models.index[ResourceIdentifier{Type: object.Type, ID: object.ID}] = data[i]
+	}
+
+	return models, nil
+}
+
+// ParseModels parses a document like ParseDoc and hydrates it, see
+// Document.Models. The parsed document is returned along with its models, for
+// its relationships, links, and meta.
+func ParseModels(r *http.Request, mode DocumentMode) (*Models, *Document, ErrorList) {
+	document, err := ParseDoc(r, mode)
+	if err != nil {
+		return nil, nil, ErrorList{err}
+	}
+
+	models, errs := document.Models()
+	if errs != nil {
+		return nil, nil, errs
+	}
+
+	return models, document, nil
+}
+
+// unmarshalModels unmarshals objects into their registered models, pointing
+// errors at the object below pointer
+func unmarshalModels(objects []*Object, pointer string) ([]interface{}, ErrorList) {
+	unmarshaled := make([]interface{}, len(objects))
+	for i, object := range objects {
+		model, errs := object.UnmarshalModel()
+		if errs != nil {
+			for _, err := range errs {
+				if strings.HasPrefix(err.Source.Pointer, "/data") {
+					err.Source.Pointer = fmt.Sprintf("%s/%d%s", pointer, i, strings.TrimPrefix(err.Source.Pointer, "/data"))
+				}
+			}
+			return nil, errs
+		}
+
+		unmarshaled[i] = model
+	}
+
+	return unmarshaled, nil
+}
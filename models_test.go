@@ -0,0 +1,81 @@
+package jsh
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestModels(t *testing.T) {
+
+	Convey("Model Tests", t, func() {
+
+		type company struct {
+			Name string `json:"name"`
+		}
+		type user struct {
+			Name string `json:"name" valid:"required"`
+		}
+
+		RegisterType("users", func() interface{} { return &user{} })
+		RegisterModel("companies", company{})
+		Reset(func() {
+			RegisterType("users", nil)
+			RegisterModel("companies", nil)
+		})
+
+		body := `{
+			"data": {"type": "users", "id": "1", "attributes": {"name": "Bob"},
+				"relationships": {"employer": {"data": {"type": "companies", "id": "2"}}}},
+			"included": [{"type": "companies", "id": "2", "attributes": {"name": "Acme"}}]
+		}`
+
+		Convey("->ParseModels()", func() {
+			request, _ := testRequest([]byte(body))
+			models, document, errs := ParseModels(request, ObjectMode)
+			So(errs, ShouldBeNil)
+			So(models.Data, ShouldResemble, []interface{}{&user{Name: "Bob"}})
+			So(models.Included, ShouldResemble, []interface{}{&company{Name: "Acme"}})
+
+			employer := document.First().Relationships["employer"].Data[0]
+			So(models.Get(employer), ShouldResemble, &company{Name: "Acme"})
+			So(models.Get(&ResourceIdentifier{Type: "users", ID: "1"}), ShouldEqual, models.Data[0])
+			So(models.Get(&ResourceIdentifier{Type: "users", ID: "9"}), ShouldBeNil)
+		})
+
+		Convey("->Document.Models()", func() {
+			request, _ := testRequest([]byte(body))
+			document, err := ParseDoc(request, ObjectMode)
+			So(err, ShouldBeNil)
+
+			Convey("should point at included resources of unregistered types", func() {
+				RegisterType("companies", nil)
+
+				_, errs := document.Models()
+				So(errs, ShouldNotBeNil)
+				So(errs[0].Status, ShouldEqual, 422)
+				So(errs[0].Source.Pointer, ShouldEqual, "/included/0/type")
+			})
+
+			Convey("should validate models", func() {
+				document.Data[0].Attributes = []byte(`{"name": ""}`)
+
+				_, errs := document.Models()
+				So(errs, ShouldNotBeNil)
+				So(errs[0].Source.Pointer, ShouldEqual, "/data/0/attributes/name")
+			})
+		})
+
+		Convey("->UnmarshalModel()", func() {
+			object, _ := NewObject("1", "users", map[string]string{"name": "Alice"})
+
+			model, errs := object.UnmarshalModel()
+			So(errs, ShouldBeNil)
+			So(model, ShouldResemble, &user{Name: "Alice"})
+
+			// factories are called for every object
+			other, _ := object.UnmarshalModel()
+			So(other, ShouldNotPointTo, model)
+		})
+	})
+}
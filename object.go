@@ -4,8 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-
-	"github.com/asaskevich/govalidator"
+	"reflect"
 )
 
 // Object represents the default JSON spec for objects
@@ -15,13 +14,17 @@ type Object struct {
 	Attributes    json.RawMessage          `json:"attributes,omitempty"`
 	Links         map[string]*Link         `json:"links,omitempty"`
 	Relationships map[string]*Relationship `json:"relationships,omitempty"`
+	Meta          map[string]interface{}   `json:"meta,omitempty"`
 	// Status is the HTTP Status Code that should be associated with the object
 	// when it is sent.
 	Status int `json:"-"`
+	// Extra holds unrecognized members, see Document.Extra
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 // NewObject prepares a new JSON Object for an API response. Whatever is provided
-// as attributes will be marshalled to JSON.
+// as attributes will be marshalled to JSON. See LinksProvider, MetaProvider, and
+// RelationshipsProvider for populating the rest of the object from your model.
 func NewObject(id string, resourceType string, attributes interface{}) (*Object, *Error) {
 	object := &Object{
 		ID:            id,
@@ -30,12 +33,11 @@ func NewObject(id string, resourceType string, attributes interface{}) (*Object,
 		Relationships: map[string]*Relationship{},
 	}
 
-	rawJSON, err := json.MarshalIndent(attributes, "", " ")
+	err := object.Marshal(attributes)
 	if err != nil {
-		return nil, ISE(fmt.Sprintf("Error marshaling attrs while creating a new JSON Object: %s", err))
+		return nil, err
 	}
 
-	object.Attributes = rawJSON
 	return object, nil
 }
 
@@ -64,6 +66,12 @@ specifying each struct attribute that failed. In this case, all you need to do i
 	}
 */
 func (o *Object) Unmarshal(resourceType string, target interface{}) ErrorList {
+	return o.unmarshal(resourceType, target, AttributeKeys)
+}
+
+// unmarshal unmarshals the object's attributes, converting their names with
+// conversion
+func (o *Object) unmarshal(resourceType string, target interface{}, conversion *KeyConversion) ErrorList {
 
 	if resourceType != o.Type {
 		return []*Error{ISE(fmt.Sprintf(
@@ -73,8 +81,24 @@ func (o *Object) Unmarshal(resourceType string, target interface{}) ErrorList {
 		))}
 	}
 
-	jsonErr := json.Unmarshal(o.Attributes, target)
+	attributes := o.Attributes
+	if !o.HasAttributes() {
+		// meta-only objects unmarshal as if their attributes were empty
+		attributes = json.RawMessage("{}")
+	}
+
+	attributes, err := conversion.receive(attributes)
+	if err != nil {
+		return []*Error{err}
+	}
+
+	jsonErr := JSON.Unmarshal(attributes, target)
 	if jsonErr != nil {
+		inputErr := unmarshalError(attributes, jsonErr)
+		if inputErr != nil {
+			return conversion.sentPointers([]*Error{inputErr})
+		}
+
 		return []*Error{ISE(fmt.Sprintf(
 			"For type '%s' unable to marshal: %s\nError:%s",
 			resourceType,
@@ -83,20 +107,39 @@ func (o *Object) Unmarshal(resourceType string, target interface{}) ErrorList {
 		))}
 	}
 
-	return validateInput(target)
+	return conversion.sentPointers(validateInput(target))
 }
 
 /*
 Marshal allows you to load a modified payload back into an object to preserve
-all of the data it has.
+all of the data it has. As with NewObject, if attributes implements any of
+LinksProvider, MetaProvider, or RelationshipsProvider the object is updated
+accordingly.
 */
 func (o *Object) Marshal(attributes interface{}) *Error {
-	raw, err := json.MarshalIndent(attributes, "", " ")
+	return o.marshal(attributes, AttributeKeys)
+}
+
+// marshal marshals attributes into the object, converting their names with
+// conversion
+func (o *Object) marshal(attributes interface{}, conversion *KeyConversion) *Error {
+	raw, err := JSON.Marshal(attributes)
 	if err != nil {
 		return ISE(fmt.Sprintf("Error marshaling attrs while creating a new JSON Object: %s", err))
 	}
 
-	o.Attributes = raw
+	raw, convertErr := conversion.send(raw)
+	if convertErr != nil {
+		return convertErr
+	}
+
+	// leave attributes out of meta-only objects
+	o.Attributes = nil
+	if hasAttributes(raw) {
+		o.Attributes = raw
+	}
+	applyProviders(o, attributes)
+
 	return nil
 }
 
@@ -160,6 +203,65 @@ func (o *Object) Validate(r *http.Request, response bool) *Error {
 	return nil
 }
 
+/*
+Merge overlays a partial update, such as one parsed from a PATCH request, onto
+the object. Only attributes and relationships present in the patch are
+replaced, everything else is left untouched, including relationships of the
+patch without linkage, see Relationship.State:
+
+	patch, err := jsh.ParseObject(r)
+	...
+	existing, err := storage.Get(patch.ID)
+	...
+	err = existing.Merge(patch)
+
+A 409 Conflict error is returned if the patch's type or ID doesn't match the
+object's.
+*/
+func (o *Object) Merge(patch *Object) *Error {
+	if patch.Type != o.Type {
+		return Conflict(fmt.Sprintf("Type '%s' does not match the existing resource type '%s'", patch.Type, o.Type))
+	}
+
+	if patch.ID != o.ID {
+		return Conflict(fmt.Sprintf("ID '%s' does not match the existing resource ID '%s'", patch.ID, o.ID))
+	}
+
+	if len(patch.Attributes) > 0 {
+		attributes, err := o.attributeMap()
+		if err != nil {
+			return err
+		}
+
+		patchAttributes, err := patch.attributeMap()
+		if err != nil {
+			return err
+		}
+
+		for key, value := range patchAttributes {
+			attributes[key] = value
+		}
+
+		err = o.marshal(attributes, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(patch.Relationships) > 0 && o.Relationships == nil {
+		o.Relationships = map[string]*Relationship{}
+	}
+
+	for name, relationship := range patch.Relationships {
+		if relationship.State() == LinkageAbsent {
+			continue
+		}
+		o.Relationships[name] = relationship
+	}
+
+	return nil
+}
+
 // String prints a formatted string representation of the object
 func (o *Object) String() string {
 	raw, err := json.MarshalIndent(o, "", " ")
@@ -173,26 +275,5 @@ func (o *Object) String() string {
 // validateInput runs go-validator on each attribute on the struct and returns all
 // errors that it picks up
 func validateInput(target interface{}) ErrorList {
-
-	_, validationError := govalidator.ValidateStruct(target)
-	if validationError != nil {
-
-		errorList, isType := validationError.(govalidator.Errors)
-		if isType {
-
-			errors := ErrorList{}
-			for _, singleErr := range errorList.Errors() {
-
-				// parse out validation error
-				goValidErr, _ := singleErr.(govalidator.Error)
-				inputErr := InputError(goValidErr.Err.Error(), goValidErr.Name)
-
-				errors = append(errors, inputErr)
-			}
-
-			return errors
-		}
-	}
-
-	return nil
+	return validateStruct(attributesPointer, reflect.ValueOf(target))
 }
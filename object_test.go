@@ -32,6 +32,34 @@ func TestObject(t *testing.T) {
 				So(err, ShouldBeNil)
 				So(newObj.Attributes, ShouldNotBeEmpty)
 			})
+
+			Convey("should create a meta-only object without attributes", func() {
+				newObj, err := NewObject(testObject.ID, testObject.Type, nil)
+				So(err, ShouldBeNil)
+				So(newObj.HasAttributes(), ShouldBeFalse)
+
+				newObj.Meta = map[string]interface{}{"can_edit": true}
+				raw, jsonErr := json.Marshal(newObj)
+				So(jsonErr, ShouldBeNil)
+				So(string(raw), ShouldEqual, `{"type":"testObject","id":"ID123","meta":{"can_edit":true}}`)
+
+				Convey("that can be unmarshaled", func() {
+					target := struct {
+						Foo string `json:"foo"`
+					}{}
+
+					errs := newObj.Unmarshal(testObject.Type, &target)
+					So(errs, ShouldBeNil)
+					So(target.Foo, ShouldBeEmpty)
+				})
+
+				Convey("that can have attributes set", func() {
+					newObj.Attributes = json.RawMessage("null")
+					err := newObj.SetAttribute("foo", "bar")
+					So(err, ShouldBeNil)
+					So(newObj.HasAttributes(), ShouldBeTrue)
+				})
+			})
 		})
 
 		Convey("->Unmarshal()", func() {
@@ -107,12 +135,57 @@ func TestObject(t *testing.T) {
 				err := testObject.Marshal(attrs)
 				So(err, ShouldBeNil)
 
-				raw, jsonErr := json.MarshalIndent(attrs, "", " ")
+				raw, jsonErr := json.Marshal(attrs)
 				So(jsonErr, ShouldBeNil)
 				So(string(testObject.Attributes), ShouldEqual, string(raw))
 			})
 		})
 
+		Convey("->Merge()", func() {
+
+			existing := &Object{
+				ID:         "ID123",
+				Type:       "testObject",
+				Attributes: json.RawMessage(`{"foo":"bar","baz":"qux"}`),
+				Relationships: map[string]*Relationship{
+					"author": {Data: ResourceLinkage{{Type: "users", ID: "1"}}},
+				},
+			}
+
+			Convey("should overlay only the patched attributes and relationships", func() {
+				patch := &Object{
+					ID:         "ID123",
+					Type:       "testObject",
+					Attributes: json.RawMessage(`{"foo":"updated"}`),
+					Relationships: map[string]*Relationship{
+						"editor": {Data: ResourceLinkage{{Type: "users", ID: "2"}}},
+					},
+				}
+
+				err := existing.Merge(patch)
+				So(err, ShouldBeNil)
+
+				foo, _ := existing.AttributeString("foo")
+				baz, _ := existing.AttributeString("baz")
+				So(foo, ShouldEqual, "updated")
+				So(baz, ShouldEqual, "qux")
+				So(existing.Relationships["author"], ShouldNotBeNil)
+				So(existing.Relationships["editor"], ShouldNotBeNil)
+			})
+
+			Convey("should return a 409 for a type mismatch", func() {
+				err := existing.Merge(&Object{ID: "ID123", Type: "other"})
+				So(err, ShouldNotBeNil)
+				So(err.Status, ShouldEqual, http.StatusConflict)
+			})
+
+			Convey("should return a 409 for an ID mismatch", func() {
+				err := existing.Merge(&Object{ID: "ID456", Type: "testObject"})
+				So(err, ShouldNotBeNil)
+				So(err.Status, ShouldEqual, http.StatusConflict)
+			})
+		})
+
 		Convey("->JSON()", func() {
 
 			Convey("should handle a POST response correctly", func() {
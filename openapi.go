@@ -0,0 +1,436 @@
+package jsh
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// OpenAPIVersion is the version of the OpenAPI specification OpenAPI generates.
+const OpenAPIVersion = "3.0.3"
+
+/*
+OpenAPI generates an OpenAPI 3 document describing the endpoints of the
+registered resources, as listed by Routes, so consumers can generate clients:
+
+	spec := jsh.OpenAPI("Blog API", "1.0.0")
+	json.NewEncoder(os.Stdout).Encode(spec)
+
+Each resource type gets a component schema for its resource objects, with
+attributes described by its Schema when declared, along with single and list
+document envelopes. Operations document their path and query parameters, their
+request and response documents, and the error document sent for failures.
+*/
+func OpenAPI(title string, version string) map[string]interface{} {
+	schemas := map[string]interface{}{
+		"ResourceIdentifier": map[string]interface{}{
+			"type":     "object",
+			"required": []string{"type", "id"},
+			"properties": map[string]interface{}{
+				"type": map[string]interface{}{"type": "string"},
+				"id":   map[string]interface{}{"type": "string"},
+				"meta": map[string]interface{}{"type": "object"},
+			},
+		},
+		"Links": map[string]interface{}{
+			"type": "object",
+			"additionalProperties": map[string]interface{}{
+				"type":     "string",
+				"nullable": true,
+			},
+		},
+		"Error": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"code":   map[string]interface{}{"type": "string"},
+				"title":  map[string]interface{}{"type": "string"},
+				"detail": map[string]interface{}{"type": "string"},
+				"status": map[string]interface{}{"type": "string"},
+				"source": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"pointer":   map[string]interface{}{"type": "string"},
+						"parameter": map[string]interface{}{"type": "string"},
+					},
+				},
+				"meta": map[string]interface{}{"type": "object"},
+			},
+		},
+		"ErrorDocument": map[string]interface{}{
+			"type":     "object",
+			"required": []string{"errors"},
+			"properties": map[string]interface{}{
+				"errors": map[string]interface{}{
+					"type":  "array",
+					"items": schemaRef("Error"),
+				},
+				"meta": map[string]interface{}{"type": "object"},
+			},
+		},
+		"ToOneLinkage": linkageDocument(map[string]interface{}{
+			"allOf":    []interface{}{schemaRef("ResourceIdentifier")},
+			"nullable": true,
+		}),
+		"ToManyLinkage": linkageDocument(map[string]interface{}{
+			"type":  "array",
+			"items": schemaRef("ResourceIdentifier"),
+		}),
+	}
+
+	paths := map[string]interface{}{}
+	for _, resource := range registeredResources() {
+		addResourceSchemas(schemas, resource)
+
+		// related types that aren't registered are described generically
+		for name, relationship := range resource.Relationships {
+			for _, relatedType := range relationship.relatedTypes() {
+				if _, described := schemas[relatedType]; !described && Registered(relatedType) == nil {
+					addResourceSchemas(schemas, &Resource{Type: relatedType})
+				}
+			}
+
+			if len(relationship.Types) > 0 {
+				addPolymorphicSchemas(schemas, resource.Type+"."+name, relationship.Types)
+			}
+		}
+
+		for _, route := range resource.routes() {
+			path := strings.Replace(route.Pattern, ":id", "{id}", 1)
+			operations, exists := paths[path].(map[string]interface{})
+			if !exists {
+				operations = map[string]interface{}{}
+				paths[path] = operations
+			}
+
+			operations[strings.ToLower(route.Method)] = resource.openAPIOperation(route)
+		}
+	}
+
+	return map[string]interface{}{
+		"openapi": OpenAPIVersion,
+		"info": map[string]interface{}{
+			"title":   title,
+			"version": version,
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": schemas,
+		},
+	}
+}
+
+/*
+OpenAPIHandler serves the document generated by OpenAPI as JSON, so it can be
+mounted next to the API for client generators to fetch:
+
+	http.Handle("/openapi.json", jsh.OpenAPIHandler("Blog API", "1.0.0"))
+*/
+func OpenAPIHandler(title string, version string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			Send(w, r, SpecificationError("The OpenAPI document can only be fetched with GET"))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(OpenAPI(title, version))
+	})
+}
+
+/*
+JSONSchema returns the JSON Schema of the attributes object the schema
+describes. Required attributes are listed as required, and undeclared attributes
+are forbidden unless the schema allows AdditionalAttributes.
+*/
+func (s *Schema) JSONSchema() map[string]interface{} {
+	properties := map[string]interface{}{}
+	required := []string{}
+	for name, attribute := range s.Attributes {
+		properties[name] = attribute.jsonSchema()
+		if attribute.Required {
+			required = append(required, name)
+		}
+	}
+	sort.Strings(required)
+
+	schema := map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": s.AdditionalAttributes,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	return schema
+}
+
+// jsonSchema returns the JSON Schema of an attribute value
+func (a *AttributeSchema) jsonSchema() map[string]interface{} {
+	schema := map[string]interface{}{}
+	if a.Type != AnyAttribute {
+		schema["type"] = string(a.Type)
+	}
+	if a.Nullable {
+		schema["nullable"] = true
+	}
+	if a.Format != "" {
+		schema["format"] = a.Format
+	}
+
+	return schema
+}
+
+// jsonSchema returns the JSON Schema of the resource's resource objects
+func (r *Resource) jsonSchema() map[string]interface{} {
+	attributes := map[string]interface{}{"type": "object"}
+	if r.Schema != nil {
+		attributes = r.Schema.JSONSchema()
+	}
+
+	properties := map[string]interface{}{
+		"type": map[string]interface{}{
+			"type": "string",
+			"enum": []string{r.Type},
+		},
+		// IDs are only omitted by clients creating resources
+		"id":         map[string]interface{}{"type": "string"},
+		"attributes": attributes,
+		"links":      schemaRef("Links"),
+		"meta":       map[string]interface{}{"type": "object"},
+	}
+
+	if len(r.Relationships) > 0 {
+		relationships := map[string]interface{}{}
+		for name, relationship := range r.Relationships {
+			relationships[name] = schemaRef(linkageSchema(relationship))
+		}
+
+		properties["relationships"] = map[string]interface{}{
+			"type":       "object",
+			"properties": relationships,
+		}
+	}
+
+	return map[string]interface{}{
+		"type":       "object",
+		"required":   []string{"type"},
+		"properties": properties,
+	}
+}
+
+// openAPIOperation describes a route of the resource
+func (r *Resource) openAPIOperation(route Route) map[string]interface{} {
+	parameters := []interface{}{}
+	if strings.Contains(route.Pattern, ":id") {
+		parameters = append(parameters, map[string]interface{}{
+			"name":     IDParam,
+			"in":       "path",
+			"required": true,
+			"schema":   map[string]interface{}{"type": "string"},
+		})
+	}
+
+	// the document the route accepts and sends on success
+	document := r.Type + "Document"
+	if route.Operation == ListOperation {
+		document = r.Type + "ListDocument"
+	}
+
+	relationship, isRelationship := r.Relationships[route.Relationship]
+	related := isRelationship && !strings.Contains(route.Pattern, "/relationships/")
+	switch {
+	case related:
+		relatedSchema := relationship.Type
+		if len(relationship.Types) > 0 {
+			relatedSchema = r.Type + "." + route.Relationship
+		}
+
+		document = relatedSchema + "Document"
+		if relationship.Cardinality == ToMany {
+			document = relatedSchema + "ListDocument"
+		}
+	case isRelationship:
+		document = linkageSchema(relationship)
+	}
+
+	operationID := r.Type + "." + string(route.Operation)
+	switch {
+	case related:
+		operationID = r.Type + "." + route.Relationship + "." + string(route.Operation)
+	case isRelationship:
+		operationID = r.Type + "." + route.Relationship + ".relationship." + string(route.Operation)
+	}
+
+	if route.Method == "GET" && (!isRelationship || related) {
+		parameters = append(parameters, queryParameters(route.Operation == ListOperation)...)
+	}
+
+	operation := map[string]interface{}{
+		"operationId": operationID,
+		"tags":        []string{r.Type},
+		"parameters":  parameters,
+		"responses": map[string]interface{}{
+			"default": documentResponse("Error", "ErrorDocument"),
+		},
+	}
+	if r.Deprecation != nil {
+		operation["deprecated"] = true
+	}
+	responses := operation["responses"].(map[string]interface{})
+
+	switch {
+	case route.Method == "DELETE" && !isRelationship:
+		responses["204"] = map[string]interface{}{"description": "Deleted"}
+	case route.Method == "POST" && !isRelationship:
+		responses["201"] = documentResponse("Created", document)
+	default:
+		responses["200"] = documentResponse("OK", document)
+	}
+
+	if route.Method == "POST" || route.Method == "PATCH" || (route.Method == "DELETE" && isRelationship) {
+		operation["requestBody"] = map[string]interface{}{
+			"required": true,
+			"content": map[string]interface{}{
+				ContentType: map[string]interface{}{"schema": schemaRef(document)},
+			},
+		}
+	}
+
+	return operation
+}
+
+// addResourceSchemas adds the component schemas of a resource type
+func addResourceSchemas(schemas map[string]interface{}, resource *Resource) {
+	schemas[resource.Type] = resource.jsonSchema()
+	schemas[resource.Type+"Document"] = primaryDocument(schemaRef(resource.Type))
+	schemas[resource.Type+"ListDocument"] = primaryDocument(map[string]interface{}{
+		"type":  "array",
+		"items": schemaRef(resource.Type),
+	})
+}
+
+// addPolymorphicSchemas adds the component schemas of a polymorphic
+// relationship's related resources, which may be of any of the types
+func addPolymorphicSchemas(schemas map[string]interface{}, name string, types []string) {
+	oneOf := []interface{}{}
+	for _, relatedType := range types {
+		oneOf = append(oneOf, schemaRef(relatedType))
+	}
+
+	schemas[name] = map[string]interface{}{"oneOf": oneOf}
+	schemas[name+"Document"] = primaryDocument(schemaRef(name))
+	schemas[name+"ListDocument"] = primaryDocument(map[string]interface{}{
+		"type":  "array",
+		"items": schemaRef(name),
+	})
+}
+
+// queryParameters describes the query parameters of GET requests
+func queryParameters(list bool) []interface{} {
+	parameters := []interface{}{
+		map[string]interface{}{
+			"name":        IncludeParam,
+			"in":          "query",
+			"description": "Comma separated relationship paths to include",
+			"schema":      map[string]interface{}{"type": "string"},
+		},
+		map[string]interface{}{
+			"name":        "fields",
+			"in":          "query",
+			"description": "Sparse fieldsets, comma separated member names by type",
+			"style":       "deepObject",
+			"schema": map[string]interface{}{
+				"type":                 "object",
+				"additionalProperties": map[string]interface{}{"type": "string"},
+			},
+		},
+	}
+
+	if list {
+		parameters = append(parameters,
+			map[string]interface{}{
+				"name":        SortParam,
+				"in":          "query",
+				"description": "Comma separated sort fields, descending when prefixed with -",
+				"schema":      map[string]interface{}{"type": "string"},
+			},
+			map[string]interface{}{
+				"name":        FilterParam,
+				"in":          "query",
+				"description": "Filters by attribute and operator, such as filter[age][gte]=21",
+				"style":       "deepObject",
+				"schema": map[string]interface{}{
+					"type":                 "object",
+					"additionalProperties": true,
+				},
+			},
+			map[string]interface{}{
+				"name":   CursorParam,
+				"in":     "query",
+				"schema": map[string]interface{}{"type": "string"},
+			},
+			map[string]interface{}{
+				"name":   LimitParam,
+				"in":     "query",
+				"schema": map[string]interface{}{"type": "integer", "minimum": 1},
+			},
+		)
+	}
+
+	return parameters
+}
+
+// primaryDocument returns the schema of a document with the given data
+func primaryDocument(data map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"type":     "object",
+		"required": []string{"data"},
+		"properties": map[string]interface{}{
+			"data": data,
+			"included": map[string]interface{}{
+				"type":  "array",
+				"items": schemaRef("ResourceIdentifier"),
+			},
+			"links": schemaRef("Links"),
+			"meta":  map[string]interface{}{"type": "object"},
+		},
+	}
+}
+
+// linkageDocument returns the schema of a relationship with the given linkage
+func linkageDocument(data map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"data":  data,
+			"links": schemaRef("Links"),
+			"meta":  map[string]interface{}{"type": "object"},
+		},
+	}
+}
+
+// linkageSchema names the component schema of a relationship's linkage
+func linkageSchema(relationship RelationshipDeclaration) string {
+	if relationship.Cardinality == ToOne {
+		return "ToOneLinkage"
+	}
+
+	return "ToManyLinkage"
+}
+
+// documentResponse describes a response sending the named document schema
+func documentResponse(description string, document string) map[string]interface{} {
+	return map[string]interface{}{
+		"description": description,
+		"content": map[string]interface{}{
+			ContentType: map[string]interface{}{"schema": schemaRef(document)},
+		},
+	}
+}
+
+// schemaRef references a component schema
+func schemaRef(name string) map[string]interface{} {
+	return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+}
@@ -0,0 +1,112 @@
+package jsh
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestOpenAPI(t *testing.T) {
+
+	Convey("OpenAPI Tests", t, func() {
+
+		Register(&Resource{
+			Type: "articles",
+			Relationships: map[string]RelationshipDeclaration{
+				"author":   {Type: "people", Cardinality: ToOne},
+				"comments": {Type: "comments", Cardinality: ToMany},
+			},
+			Schema: &Schema{
+				Attributes: map[string]*AttributeSchema{
+					"title":     {Type: StringAttribute, Required: true},
+					"published": {Type: StringAttribute, Format: DateTimeFormat, Nullable: true},
+				},
+			},
+		})
+		Register(&Resource{Type: "people"})
+		Reset(func() {
+			Unregister("articles")
+			Unregister("people")
+		})
+
+		spec := OpenAPI("Test API", "1.0.0")
+		paths := spec["paths"].(map[string]interface{})
+		schemas := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+
+		Convey("->OpenAPI()", func() {
+
+			Convey("should describe each route", func() {
+				So(spec["openapi"], ShouldEqual, OpenAPIVersion)
+				So(paths["/articles"], ShouldNotBeNil)
+				So(paths["/articles/{id}"], ShouldNotBeNil)
+				So(paths["/articles/{id}/relationships/author"], ShouldNotBeNil)
+				So(paths["/articles/{id}/comments"], ShouldNotBeNil)
+				So(paths["/people/{id}"], ShouldNotBeNil)
+
+				member := paths["/articles/{id}"].(map[string]interface{})
+				So(member["get"], ShouldNotBeNil)
+				So(member["patch"], ShouldNotBeNil)
+				So(member["delete"], ShouldNotBeNil)
+			})
+
+			Convey("should reference the documents of related types", func() {
+				related := paths["/articles/{id}/comments"].(map[string]interface{})["get"].(map[string]interface{})
+				response := related["responses"].(map[string]interface{})["200"].(map[string]interface{})
+				schema := response["content"].(map[string]interface{})[ContentType].(map[string]interface{})["schema"]
+				So(schema, ShouldResemble, schemaRef("commentsListDocument"))
+
+				So(schemas["comments"], ShouldNotBeNil)
+				So(schemas["peopleDocument"], ShouldNotBeNil)
+			})
+
+			Convey("should use unique operation IDs", func() {
+				ids := map[string]bool{}
+				for _, operations := range paths {
+					for _, operation := range operations.(map[string]interface{}) {
+						id := operation.(map[string]interface{})["operationId"].(string)
+						So(ids[id], ShouldBeFalse)
+						ids[id] = true
+					}
+				}
+			})
+
+			Convey("should describe attributes with the resource schema", func() {
+				article := schemas["articles"].(map[string]interface{})
+				attributes := article["properties"].(map[string]interface{})["attributes"].(map[string]interface{})
+				So(attributes["required"], ShouldResemble, []string{"title"})
+				So(attributes["additionalProperties"], ShouldEqual, false)
+			})
+
+			Convey("should marshal to JSON", func() {
+				_, err := json.Marshal(spec)
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("->JSONSchema()", func() {
+			schema := Registered("articles").Schema.JSONSchema()
+			properties := schema["properties"].(map[string]interface{})
+			So(properties["published"], ShouldResemble, map[string]interface{}{
+				"type":     "string",
+				"format":   DateTimeFormat,
+				"nullable": true,
+			})
+		})
+
+		Convey("->OpenAPIHandler()", func() {
+			writer := httptest.NewRecorder()
+			request, _ := http.NewRequest("GET", "/openapi.json", nil)
+
+			OpenAPIHandler("Test API", "1.0.0").ServeHTTP(writer, request)
+			So(writer.Code, ShouldEqual, http.StatusOK)
+			So(writer.Header().Get("Content-Type"), ShouldEqual, "application/json")
+
+			served := map[string]interface{}{}
+			So(json.Unmarshal(writer.Body.Bytes(), &served), ShouldBeNil)
+			So(served["openapi"], ShouldEqual, OpenAPIVersion)
+		})
+	})
+}
@@ -0,0 +1,146 @@
+package jsh
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+/*
+ParseOptions configures how strictly a Parser enforces the JSON API
+specification. Set Parser.Options to configure a single endpoint, or change
+DefaultParseOptions to configure every parser that doesn't have its own:
+
+	parser := jsh.NewParser(r)
+	parser.Options = &jsh.ParseOptions{
+		ClientIDs:  jsh.RequireClientIDs,
+		ValidateID: jsh.UUIDValidator,
+	}
+	doc, err := parser.Document(r.Body, jsh.ObjectMode)
+*/
+type ParseOptions struct {
+	// ClientIDs determines whether clients may provide their own IDs when
+	// creating resources with a POST
+	ClientIDs ClientIDMode
+	// ValidateID, if set, checks each client generated ID
+	ValidateID IDValidator
+	// GenerateID, if set, assigns IDs to resources created without one. They
+	// are internal IDs, so IDTranslators don't translate them.
+	GenerateID IDGenerator
+	// MemberNames determines how strictly member names are checked
+	MemberNames MemberNameMode
+	// LenientContentType accepts "application/json" and media type parameters
+	// such as charset, rather than insisting on the JSON API media type
+	LenientContentType bool
+	// ForbidUnknownMembers rejects documents with top-level members other than
+	// those the specification defines, at-members, and namespaced extension
+	// members
+	ForbidUnknownMembers bool
+	// ForbidDataAndErrors rejects documents containing both "data" and "errors"
+	ForbidDataAndErrors bool
+	// RequireDataOrMeta rejects documents with neither "data" nor "meta"
+	RequireDataOrMeta bool
+	// ForbidDuplicates rejects documents whose primary data and included
+	// resources contain the same resource more than once
+	ForbidDuplicates bool
+	// Deduplicate removes repeated resources instead, see
+	// Document.Deduplicate. It takes precedence over ForbidDuplicates.
+	Deduplicate bool
+	// PreserveUnknownMembers keeps the members of documents, resource objects,
+	// and relationships that the specification doesn't define, such as those
+	// of vendor extensions, in their Extra so they are sent again unchanged
+	PreserveUnknownMembers bool
+	// MaxBodySize caps the size in bytes of documents, after decompression,
+	// larger ones are rejected with a 413. Zero means DefaultMaxBodySize, a
+	// negative size disables the cap.
+	MaxBodySize int64
+	// MaxDepth caps how deeply objects and arrays of documents may nest. Zero
+	// means DefaultMaxDepth, a negative depth disables the cap.
+	MaxDepth int
+}
+
+// DefaultParseOptions are used by parsers without Options of their own.
+var DefaultParseOptions = ParseOptions{}
+
+/*
+StrictParseOptions enforce the specification as closely as possible. Copy them
+to use them for a parser:
+
+	options := jsh.StrictParseOptions
+	parser.Options = &options
+*/
+var StrictParseOptions = ParseOptions{
+	MemberNames:          StrictMemberNames,
+	ForbidUnknownMembers: true,
+	ForbidDataAndErrors:  true,
+	RequireDataOrMeta:    true,
+	ForbidDuplicates:     true,
+}
+
+// topLevelMembers are the top-level members defined by the specification
+var topLevelMembers = map[string]bool{
+	"data":     true,
+	"errors":   true,
+	"meta":     true,
+	"jsonapi":  true,
+	"links":    true,
+	"included": true,
+}
+
+// checkDocument applies the options' document structure checks to a raw
+// request body
+func (o *ParseOptions) checkDocument(body []byte) *Error {
+	if !o.ForbidUnknownMembers && !o.ForbidDataAndErrors && !o.RequireDataOrMeta {
+		return nil
+	}
+
+	members := map[string]json.RawMessage{}
+	if json.Unmarshal(body, &members) != nil {
+		// leave reporting malformed JSON to the parser
+		return nil
+	}
+
+	if o.ForbidUnknownMembers {
+		for name := range members {
+			if !topLevelMembers[name] && !strings.HasPrefix(name, "@") && !strings.Contains(name, ":") {
+				return documentError(fmt.Sprintf("Unknown top-level member '%s'", name), "/"+pointerToken(name))
+			}
+		}
+	}
+
+	_, hasData := members["data"]
+	_, hasErrors := members["errors"]
+	_, hasMeta := members["meta"]
+
+	if o.ForbidDataAndErrors && hasData && hasErrors {
+		return documentError("A document cannot contain both 'data' and 'errors'", "/errors")
+	}
+
+	if o.RequireDataOrMeta && !hasData && !hasMeta {
+		return documentError("A document must contain 'data' or 'meta'", "")
+	}
+
+	return nil
+}
+
+// documentError creates a 400 error for a malformed request document
+func documentError(detail string, pointer string) *Error {
+	err := &Error{
+		Title:  "Bad Request",
+		Detail: detail,
+		Status: http.StatusBadRequest,
+	}
+	err.Source.Pointer = pointer
+
+	return err
+}
+
+// options returns the parser's options, or the defaults if it has none
+func (p *Parser) options() *ParseOptions {
+	if p.Options != nil {
+		return p.Options
+	}
+
+	return &DefaultParseOptions
+}
@@ -0,0 +1,95 @@
+package jsh
+
+import (
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseOptions(t *testing.T) {
+
+	Convey("Parse Options Tests", t, func() {
+
+		parse := func(body string, contentType string, options *ParseOptions) (*Document, *Error) {
+			req, reqErr := testRequest([]byte(body))
+			So(reqErr, ShouldBeNil)
+			req.Header.Set("Content-Type", contentType)
+
+			parser := NewParser(req)
+			parser.Options = options
+			return parser.Document(req.Body, ListMode)
+		}
+
+		strict := StrictParseOptions
+
+		Convey("should be lenient by default", func() {
+			_, err := parse(`{"data": [], "errors": [], "extra": 1}`, ContentType, nil)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("LenientContentType", func() {
+			_, err := parse(`{"data": []}`, "application/json; charset=utf-8", nil)
+			So(err, ShouldNotBeNil)
+
+			_, err = parse(`{"data": []}`, "application/json; charset=utf-8", &ParseOptions{LenientContentType: true})
+			So(err, ShouldBeNil)
+
+			_, err = parse(`{"data": []}`, "text/plain", &ParseOptions{LenientContentType: true})
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("ForbidUnknownMembers", func() {
+			_, err := parse(`{"data": [], "extra": 1}`, ContentType, &strict)
+			So(err, ShouldNotBeNil)
+			So(err.Status, ShouldEqual, http.StatusBadRequest)
+			So(err.Source.Pointer, ShouldEqual, "/extra")
+
+			Convey("should allow at-members and extension members", func() {
+				_, err := parse(`{"data": [], "@context": 1, "atomic:operations": []}`, ContentType, &strict)
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("ForbidDataAndErrors", func() {
+			_, err := parse(`{"data": [], "errors": []}`, ContentType, &strict)
+			So(err, ShouldNotBeNil)
+			So(err.Source.Pointer, ShouldEqual, "/errors")
+		})
+
+		Convey("RequireDataOrMeta", func() {
+			_, err := parse(`{"links": {}}`, ContentType, &strict)
+			So(err, ShouldNotBeNil)
+
+			_, err = parse(`{"meta": {"count": 1}}`, ContentType, &strict)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("ForbidDuplicates", func() {
+			body := `{"data": [{"type": "users", "id": "1"}, {"type": "users", "id": "2"}, {"type": "users", "id": "1"}]}`
+
+			_, err := parse(body, ContentType, nil)
+			So(err, ShouldBeNil)
+
+			_, err = parse(body, ContentType, &strict)
+			So(err, ShouldNotBeNil)
+			So(err.Source.Pointer, ShouldEqual, "/data/2")
+
+			Convey("should reject included duplicates", func() {
+				body := `{"data": [{"type": "users", "id": "1"}], "included": [{"type": "teams", "id": "1"}, {"type": "users", "id": "1"}]}`
+
+				_, err := parse(body, ContentType, &strict)
+				So(err, ShouldNotBeNil)
+				So(err.Source.Pointer, ShouldEqual, "/included/1")
+			})
+
+			Convey("should remove duplicates with Deduplicate", func() {
+				strict.Deduplicate = true
+
+				doc, err := parse(body, ContentType, &strict)
+				So(err, ShouldBeNil)
+				So(len(doc.Data), ShouldEqual, 2)
+			})
+		})
+	})
+}
@@ -0,0 +1,185 @@
+package jsh
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const (
+	// CursorParam is the query parameter carrying the opaque cursor of the
+	// requested page.
+	CursorParam = "page[cursor]"
+	// LimitParam is the query parameter specifying the maximum number of
+	// resources to return in a page.
+	LimitParam = "page[limit]"
+)
+
+// DefaultPageLimit is used as the page size when a request does not specify
+// page[limit].
+var DefaultPageLimit = 20
+
+// MaxPageLimit is the largest page[limit] a client may request. Set to 0 to
+// remove the upper bound.
+var MaxPageLimit = 100
+
+/*
+CursorEncoder converts between the internal position of a page (a primary key,
+a timestamp, a compound sort key, etc.) and the opaque cursor handed to clients.
+Implement this if you need to sign, encrypt, or otherwise control the format of
+your cursors.
+*/
+type CursorEncoder interface {
+	// EncodeCursor turns an internal position into an opaque cursor.
+	EncodeCursor(position string) (string, error)
+	// DecodeCursor turns an opaque cursor back into an internal position.
+	DecodeCursor(cursor string) (string, error)
+}
+
+// Base64Cursor is a CursorEncoder that URL safe base64 encodes positions.
+type Base64Cursor struct{}
+
+// EncodeCursor implements CursorEncoder.
+func (Base64Cursor) EncodeCursor(position string) (string, error) {
+	return base64.RawURLEncoding.EncodeToString([]byte(position)), nil
+}
+
+// DecodeCursor implements CursorEncoder.
+func (Base64Cursor) DecodeCursor(cursor string) (string, error) {
+	position, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", err
+	}
+
+	return string(position), nil
+}
+
+// DefaultCursorEncoder is used by ParseCursorPage when no encoder is provided.
+var DefaultCursorEncoder CursorEncoder = Base64Cursor{}
+
+/*
+CursorPage is a parsed cursor pagination request:
+
+	page, err := jsh.ParseCursorPage(r, nil)
+	if err != nil {
+		jsh.Send(w, r, err)
+		return
+	}
+
+	// fetch page.Limit users after page.Position
+	users, nextPosition := storage.UsersAfter(page.Position, page.Limit)
+
+	doc := jsh.Build(users)
+	doc.Links, err = page.Links(nextPosition, "")
+*/
+type CursorPage struct {
+	// Position is the decoded page[cursor] value, empty for the first page.
+	Position string
+	// Limit is the number of resources to return.
+	Limit int
+
+	encoder CursorEncoder
+	url     *url.URL
+}
+
+/*
+ParseCursorPage reads page[cursor] and page[limit] from the request query using
+the provided encoder, or DefaultCursorEncoder if it is nil. Invalid values
+result in an HTTP 400 error with Source.Parameter set.
+*/
+func ParseCursorPage(r *http.Request, encoder CursorEncoder) (*CursorPage, *Error) {
+	if encoder == nil {
+		encoder = DefaultCursorEncoder
+	}
+
+	page := &CursorPage{
+		Limit:   DefaultPageLimit,
+		encoder: encoder,
+		url:     r.URL,
+	}
+
+	query := r.URL.Query()
+
+	if cursor := query.Get(CursorParam); cursor != "" {
+		position, err := encoder.DecodeCursor(cursor)
+		if err != nil {
+			return nil, ParameterError("Invalid pagination cursor", CursorParam)
+		}
+		page.Position = position
+	}
+
+	if rawLimit := query.Get(LimitParam); rawLimit != "" {
+		limit, err := strconv.Atoi(rawLimit)
+		if err != nil || limit < 1 {
+			return nil, ParameterError("Page limit must be a positive integer", LimitParam)
+		}
+
+		if MaxPageLimit > 0 && limit > MaxPageLimit {
+			return nil, ParameterError(
+				fmt.Sprintf("Page limit cannot exceed %d", MaxPageLimit),
+				LimitParam,
+			)
+		}
+
+		page.Limit = limit
+	}
+
+	return page, nil
+}
+
+/*
+Links builds the top-level pagination links for the page. The next and prev
+arguments are the internal positions of the neighbouring pages, pass an empty
+string for either to omit that link. All other query parameters of the original
+request are preserved.
+*/
+func (p *CursorPage) Links(next string, prev string) (*Links, *Error) {
+	self, err := p.link(p.Position)
+	if err != nil {
+		return nil, err
+	}
+
+	links := &Links{Self: self}
+
+	if next != "" {
+		links.Next, err = p.link(next)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if prev != "" {
+		links.Prev, err = p.link(prev)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return links, nil
+}
+
+// link creates a Link to the page starting at position
+func (p *CursorPage) link(position string) (*Link, *Error) {
+	u := &url.URL{}
+	if p.url != nil {
+		*u = *p.url
+	}
+
+	query := u.Query()
+	query.Del(CursorParam)
+
+	if position != "" {
+		cursor, err := p.encoder.EncodeCursor(position)
+		if err != nil {
+			return nil, ISE(fmt.Sprintf("Unable to encode pagination cursor: %s", err.Error()))
+		}
+		query.Set(CursorParam, cursor)
+	}
+
+	query.Set(LimitParam, strconv.Itoa(p.Limit))
+	u.RawQuery = query.Encode()
+
+	return &Link{HREF: u.String()}, nil
+}
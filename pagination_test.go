@@ -0,0 +1,82 @@
+package jsh
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPagination(t *testing.T) {
+
+	Convey("Pagination Tests", t, func() {
+
+		Convey("->ParseCursorPage()", func() {
+
+			Convey("should default to the first page", func() {
+				req, err := http.NewRequest("GET", "/users", nil)
+				So(err, ShouldBeNil)
+
+				page, pageErr := ParseCursorPage(req, nil)
+				So(pageErr, ShouldBeNil)
+				So(page.Position, ShouldBeEmpty)
+				So(page.Limit, ShouldEqual, DefaultPageLimit)
+			})
+
+			Convey("should decode a cursor and limit", func() {
+				cursor, _ := Base64Cursor{}.EncodeCursor("user:50")
+				req, err := http.NewRequest("GET", "/users?page[cursor]="+cursor+"&page[limit]=5", nil)
+				So(err, ShouldBeNil)
+
+				page, pageErr := ParseCursorPage(req, nil)
+				So(pageErr, ShouldBeNil)
+				So(page.Position, ShouldEqual, "user:50")
+				So(page.Limit, ShouldEqual, 5)
+			})
+
+			Convey("should reject an invalid cursor", func() {
+				req, err := http.NewRequest("GET", "/users?page[cursor]=***", nil)
+				So(err, ShouldBeNil)
+
+				_, pageErr := ParseCursorPage(req, nil)
+				So(pageErr, ShouldNotBeNil)
+				So(pageErr.Status, ShouldEqual, http.StatusBadRequest)
+				So(pageErr.Source.Parameter, ShouldEqual, CursorParam)
+			})
+
+			Convey("should reject an out of range limit", func() {
+				req, err := http.NewRequest("GET", "/users?page[limit]=1000", nil)
+				So(err, ShouldBeNil)
+
+				_, pageErr := ParseCursorPage(req, nil)
+				So(pageErr, ShouldNotBeNil)
+				So(pageErr.Source.Parameter, ShouldEqual, LimitParam)
+			})
+		})
+
+		Convey("->Links()", func() {
+			req, err := http.NewRequest("GET", "/users?sort=name&page[limit]=5", nil)
+			So(err, ShouldBeNil)
+
+			page, pageErr := ParseCursorPage(req, nil)
+			So(pageErr, ShouldBeNil)
+
+			Convey("should build next links and preserve the query", func() {
+				links, linkErr := page.Links("user:5", "")
+				So(linkErr, ShouldBeNil)
+				So(links.Prev, ShouldBeNil)
+
+				next, parseErr := url.Parse(links.Next.HREF)
+				So(parseErr, ShouldBeNil)
+				So(next.Path, ShouldEqual, "/users")
+				So(next.Query().Get("sort"), ShouldEqual, "name")
+				So(next.Query().Get(LimitParam), ShouldEqual, "5")
+
+				position, decodeErr := DefaultCursorEncoder.DecodeCursor(next.Query().Get(CursorParam))
+				So(decodeErr, ShouldBeNil)
+				So(position, ShouldEqual, "user:5")
+			})
+		})
+	})
+}
@@ -0,0 +1,59 @@
+package jsh
+
+import (
+	"context"
+	"net/http"
+)
+
+const (
+	// IDParam is the name of the URL parameter holding resource IDs in routes,
+	// such as /articles/{id}
+	IDParam = "id"
+	// RelationshipParam is the name of the URL parameter holding relationship
+	// names in routes, such as /articles/{id}/relationships/{relationship}
+	RelationshipParam = "relationship"
+)
+
+// RouteParams are the URL parameters of a JSON API route.
+type RouteParams struct {
+	ID           string
+	Relationship string
+}
+
+/*
+ResourceHandler handles requests to a JSON API route, receiving its URL
+parameters regardless of the router in use. It can be registered directly with
+an http.ServeMux using IDParam and RelationshipParam wildcards:
+
+	mux.Handle("GET /articles/{id}", jsh.ResourceHandler(fetchArticle))
+
+The jshrouter package adapts ResourceHandlers to other routers.
+*/
+type ResourceHandler func(w http.ResponseWriter, r *http.Request, params RouteParams)
+
+// ServeHTTP calls the handler with the request's RouteParams.
+func (h ResourceHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h(w, r, Params(r))
+}
+
+// routeParamsKey is the context key of RouteParams set by WithRouteParams
+type routeParamsKey struct{}
+
+// WithRouteParams returns a copy of the request carrying params, for router
+// adapters to pass on the parameters they extracted.
+func WithRouteParams(r *http.Request, params RouteParams) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), routeParamsKey{}, params))
+}
+
+// Params returns the RouteParams of a request, as set by WithRouteParams or
+// matched by an http.ServeMux pattern.
+func Params(r *http.Request) RouteParams {
+	if params, ok := r.Context().Value(routeParamsKey{}).(RouteParams); ok {
+		return params
+	}
+
+	return RouteParams{
+		ID:           r.PathValue(IDParam),
+		Relationship: r.PathValue(RelationshipParam),
+	}
+}
@@ -0,0 +1,38 @@
+package jsh
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParams(t *testing.T) {
+
+	Convey("Route Params Tests", t, func() {
+
+		var received RouteParams
+		handler := ResourceHandler(func(w http.ResponseWriter, r *http.Request, params RouteParams) {
+			received = params
+			w.WriteHeader(http.StatusNoContent)
+		})
+
+		Convey("should read params set by WithRouteParams", func() {
+			request, _ := http.NewRequest("GET", "/articles/1/relationships/author", nil)
+			request = WithRouteParams(request, RouteParams{ID: "1", Relationship: "author"})
+
+			handler.ServeHTTP(httptest.NewRecorder(), request)
+			So(received, ShouldResemble, RouteParams{ID: "1", Relationship: "author"})
+		})
+
+		Convey("should read params matched by an http.ServeMux", func() {
+			request, _ := http.NewRequest("GET", "/articles/2/relationships/tags", nil)
+			request.SetPathValue(IDParam, "2")
+			request.SetPathValue(RelationshipParam, "tags")
+
+			handler.ServeHTTP(httptest.NewRecorder(), request)
+			So(received, ShouldResemble, RouteParams{ID: "2", Relationship: "tags"})
+		})
+	})
+}
@@ -1,11 +1,17 @@
 package jsh
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"mime"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 )
 
 /*
@@ -60,6 +66,54 @@ func ParseObject(r *http.Request) (*Object, *Error) {
 	return object, nil
 }
 
+/*
+ParseObjectFor parses an object like ParseObject, and then checks it against
+the resource type and ID from the request URL, such as PATCH /articles/1:
+
+	object, err := jsh.ParseObjectFor(r, "articles", id)
+
+As the specification requires, a 409 Conflict is returned if the object's type
+or ID don't match. Pass an empty expectedID when creating resources, in which
+case any client generated ID is left to the ParseOptions. The expectedID is the
+public ID from the URL, and is translated if the type has an IDTranslator.
+*/
+func ParseObjectFor(r *http.Request, expectedType string, expectedID string) (*Object, *Error) {
+	object, err := ParseObject(r)
+	if err != nil || object == nil {
+		return object, err
+	}
+
+	expectedID, err = InternalID(expectedType, expectedID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkIdentity(object, expectedType, expectedID)
+	if err != nil {
+		return nil, err
+	}
+
+	return object, nil
+}
+
+// checkIdentity returns a 409 Conflict if the object's type or ID don't match
+// those expected
+func checkIdentity(object *Object, expectedType string, expectedID string) *Error {
+	if object.Type != expectedType {
+		err := Conflict(fmt.Sprintf("Type '%s' does not match the endpoint's resource type '%s'", object.Type, expectedType))
+		err.Source.Pointer = "/data/type"
+		return err
+	}
+
+	if expectedID != "" && object.ID != expectedID {
+		err := Conflict(fmt.Sprintf("ID '%s' does not match the endpoint's resource ID '%s'", object.ID, expectedID))
+		err.Source.Pointer = "/data/id"
+		return err
+	}
+
+	return nil
+}
+
 /*
 ParseList validates the HTTP request and returns a resulting list of objects
 parsed from the request Body. Use just like ParseObject.
@@ -86,6 +140,14 @@ func ParseDoc(r *http.Request, mode DocumentMode) (*Document, *Error) {
 type Parser struct {
 	Method  string
 	Headers http.Header
+	// Path is the URL path of the request being parsed, used for reporting
+	// MetricEvents. May be left empty.
+	Path string
+	// Options configures parsing, DefaultParseOptions are used if nil
+	Options *ParseOptions
+	// Context is that of the request being parsed, passed on to the Logger.
+	// May be left nil.
+	Context context.Context
 }
 
 // NewParser creates a parser from an http.Request
@@ -93,6 +155,8 @@ func NewParser(request *http.Request) *Parser {
 	return &Parser{
 		Method:  request.Method,
 		Headers: request.Header,
+		Path:    requestPath(request),
+		Context: request.Context(),
 	}
 }
 
@@ -103,46 +167,282 @@ also validate any data objects against the JSON API.
 func (p *Parser) Document(payload io.ReadCloser, mode DocumentMode) (*Document, *Error) {
 	defer closeReader(payload)
 
-	err := validateHeaders(p.Headers)
+	start := time.Now()
+	document, err := p.document(payload, mode)
+	p.logParse(start, mode, document, err)
+
+	return document, err
+}
+
+// document parses and validates the payload
+func (p *Parser) document(payload io.Reader, mode DocumentMode) (*Document, *Error) {
+	body, err := p.readDocument(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.parse(body, mode)
+}
+
+// readDocument validates the request headers and reads the document body
+func (p *Parser) readDocument(payload io.Reader) ([]byte, *Error) {
+	err := validateContentHeaders(p.Headers, p.options())
 	if err != nil {
 		return nil, err
 	}
 
+	body, err := p.read(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.options().checkDepth(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.runBeforeParse(body)
+}
+
+// parse parses and validates a document body
+func (p *Parser) parse(body []byte, mode DocumentMode) (*Document, *Error) {
+	options := p.options()
+
 	document := &Document{
 		Data: List{},
 		Mode: mode,
 	}
 
-	decodeErr := json.NewDecoder(payload).Decode(document)
+	err := options.checkDocument(body)
+	if err != nil {
+		return nil, err
+	}
+
+	decodeErr := JSON.Unmarshal(body, document)
 	if decodeErr != nil {
 		return nil, ISE(fmt.Sprintf("Error parsing JSON Document: %s", decodeErr.Error()))
 	}
 
+	if options.PreserveUnknownMembers {
+		preserveUnknownMembers(body, document)
+	}
+
+	err = options.checkDuplicates(document)
+	if err != nil {
+		return nil, err
+	}
+
 	// If the document has data, validate against specification
 	if document.HasData() {
-		for _, object := range document.Data {
-
-			// TODO: currently this doesn't really do any user input
-			// validation since it is validating against the jsh
-			// "Object" type. Figure out how to options pass the
-			// corressponding user object struct in to enable this
-			// without making the API super clumsy.
-			inputErr := validateInput(object)
-			if inputErr != nil {
-				return nil, inputErr[0]
-			}
+		for i, object := range document.Data {
+			objectErr := p.acceptObject(options, document, object)
+			if objectErr != nil {
+				// point errors at the offending object of lists
+				if mode == ListMode {
+					objectErr = indexPointer(objectErr, i)
+				}
 
-			// if we have a list, then all resource objects should have IDs, will
-			// cross the bridge of bulk creation if and when there is a use case
-			if len(document.Data) > 1 && object.ID == "" {
-				return nil, InputError("Object without ID present in list", "id")
+				return nil, objectErr
 			}
 		}
 	}
 
+	err = p.runAfterParse(document)
+	if err != nil {
+		return nil, err
+	}
+
 	return document, nil
 }
 
+// acceptObject validates a resource object of the parsed document
+func (p *Parser) acceptObject(options *ParseOptions, document *Document, object *Object) *Error {
+
+	// "attributes": null is equivalent to leaving attributes out
+	if !object.HasAttributes() {
+		object.Attributes = nil
+	}
+
+	// TODO: currently this doesn't really do any user input
+	// validation since it is validating against the jsh
+	// "Object" type. Figure out how to options pass the
+	// corressponding user object struct in to enable this
+	// without making the API super clumsy.
+	inputErr := validateInput(object)
+	if inputErr != nil {
+		return inputErr[0]
+	}
+
+	ctx := p.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	acceptErr := acceptObject(ctx, p.Method, object)
+	if acceptErr != nil {
+		return acceptErr
+	}
+
+	idErr := acceptID(options, p.Method, object)
+	if idErr != nil {
+		return idErr
+	}
+
+	nameErr := checkMemberNames(options.MemberNames, object)
+	if nameErr != nil {
+		return nameErr
+	}
+
+	// if we have a list, then all resource objects should have IDs, unless
+	// they are being created using the bulk extension
+	bulkCreate := p.Method == "POST" && isBulk(p.Headers)
+	if len(document.Data) > 1 && object.ID == "" && !bulkCreate {
+		return InputError("Object without ID present in list", "id")
+	}
+
+	translateErr := internalObject(object)
+	if translateErr != nil {
+		return translateErr
+	}
+
+	return generateID(options, p.Method, object)
+}
+
+// read reads the full payload, decompressing it if need be, up to the maximum
+// body size, and reports its size as a ParseEvent
+func (p *Parser) read(payload io.Reader) ([]byte, *Error) {
+	decoded, err := decodeBody(p.Headers, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := p.options().maxBodySize()
+	if limit > 0 {
+		// read one byte past the limit to tell whether the body exceeds it
+		decoded = io.LimitReader(decoded, limit+1)
+	}
+
+	buffer := getBuffer()
+	defer releaseBuffer(buffer)
+
+	_, readErr := buffer.ReadFrom(decoded)
+	var decodingErr *decodingError
+	if errors.As(readErr, &decodingErr) {
+		return nil, headerError(decodingErr.Error())
+	}
+	if readErr != nil {
+		return nil, ISE(fmt.Sprintf("Error reading JSON Document: %s", readErr.Error()))
+	}
+
+	if limit > 0 && int64(buffer.Len()) > limit {
+		return nil, payloadTooLarge(limit)
+	}
+
+	observe(&MetricEvent{
+		Kind:       ParseEvent,
+		Method:     p.Method,
+		Path:       p.Path,
+		Bytes:      buffer.Len(),
+		PeakBuffer: buffer.Cap(),
+		Pool:       BufferPoolStats(),
+	})
+
+	// the buffer goes back to the pool, so the body is copied out of it at its
+	// exact size, rather than growing a new buffer for every request
+	body := make([]byte, buffer.Len())
+	copy(body, buffer.Bytes())
+
+	return body, nil
+}
+
+/*
+ParseRelationship parses the body of a request to a relationship endpoint, such
+as /articles/1/relationships/tags, which carries a resource linkage document
+rather than a resource. This includes DELETE requests, which remove the listed
+members from a to-many relationship:
+
+	relationship, err := jsh.ParseRelationship(r)
+	...
+	for _, tag := range relationship.Data {
+		...
+	}
+*/
+func ParseRelationship(r *http.Request) (*Relationship, *Error) {
+	return NewParser(r).Relationship(r.Body)
+}
+
+/*
+Relationship parses a resource linkage document, validating that it has "data"
+and that each resource identifier has a type and ID. DELETE requests may only
+remove members of to-many relationships, so their "data" must be an array.
+*/
+func (p *Parser) Relationship(payload io.ReadCloser) (*Relationship, *Error) {
+	defer closeReader(payload)
+
+	start := time.Now()
+	relationship, err := p.relationship(payload)
+	p.logParse(start, ObjectMode, nil, err)
+
+	return relationship, err
+}
+
+// relationship parses and validates the resource linkage payload
+func (p *Parser) relationship(payload io.Reader) (*Relationship, *Error) {
+	body, err := p.readDocument(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	document := struct {
+		Data json.RawMessage `json:"data"`
+	}{}
+
+	decodeErr := JSON.Unmarshal(body, &document)
+	if decodeErr != nil {
+		return nil, ISE(fmt.Sprintf("Error parsing JSON Document: %s", decodeErr.Error()))
+	}
+
+	if len(document.Data) == 0 {
+		return nil, linkageError("Missing resource linkage")
+	}
+
+	relationship := &Relationship{}
+	decodeErr = JSON.Unmarshal(body, relationship)
+	if decodeErr != nil {
+		return nil, linkageError(fmt.Sprintf("Invalid resource linkage: %s", decodeErr.Error()))
+	}
+
+	for _, identifier := range relationship.Data {
+		if identifier == nil || identifier.Type == "" || identifier.ID == "" {
+			return nil, linkageError("Resource identifiers must have a type and ID")
+		}
+	}
+
+	if p.Method == "DELETE" && relationship.Cardinality != ToMany {
+		return nil, linkageError("Only members of to-many relationships can be deleted")
+	}
+
+	err = internalLinkage(relationship, "/data")
+	if err != nil {
+		return nil, err
+	}
+
+	return relationship, nil
+}
+
+// linkageError creates a 422 error pointing at the data of a resource linkage
+// document
+func linkageError(msg string) *Error {
+	err := &Error{
+		Title:  "Invalid Resource Linkage",
+		Detail: msg,
+		Status: 422,
+	}
+	err.Source.Pointer = "/data"
+
+	return err
+}
+
 /*
 closeReader is a deferal helper function for closing a reader and logging any errors that might occur after the fact.
 */
@@ -154,9 +454,23 @@ func closeReader(reader io.ReadCloser) {
 }
 
 func validateHeaders(headers http.Header) *Error {
+	return validateContentHeaders(headers, &DefaultParseOptions)
+}
+
+// validateContentHeaders validates request headers according to the options
+func validateContentHeaders(headers http.Header, options *ParseOptions) *Error {
+
+	err := validateFraming(headers)
+	if err != nil {
+		return err
+	}
 
 	reqContentType := headers.Get("Content-Type")
-	if reqContentType != ContentType {
+	if options.LenientContentType && lenientContentType(reqContentType) {
+		return nil
+	}
+
+	if _, valid := contentTypeExtensions(reqContentType); !valid {
 		return SpecificationError(fmt.Sprintf(
 			"Expected Content-Type header to be %s, got: %s",
 			ContentType,
@@ -166,3 +480,73 @@ func validateHeaders(headers http.Header) *Error {
 
 	return nil
 }
+
+// lenientContentType accepts JSON API or plain JSON media types with any
+// parameters
+func lenientContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == ContentType || mediaType == "application/json")
+}
+
+/*
+validateFraming rejects requests whose headers disagree about the content type
+or length of the body. Proxies and servers that resolve such conflicts
+differently can end up disagreeing about where a request ends, which allows
+requests to be smuggled past a gateway, so they are refused before the body is
+read.
+*/
+func validateFraming(headers http.Header) *Error {
+	if conflicting(headers["Content-Type"], false) {
+		return headerError("Conflicting Content-Type headers")
+	}
+
+	lengths := headers["Content-Length"]
+	if conflicting(lengths, true) {
+		return headerError("Conflicting Content-Length headers")
+	}
+
+	if len(lengths) > 0 {
+		length := strings.TrimSpace(lengths[0])
+		if _, err := strconv.ParseUint(length, 10, 63); err != nil {
+			return headerError(fmt.Sprintf("Invalid Content-Length header: %s", lengths[0]))
+		}
+
+		if len(headers["Transfer-Encoding"]) > 0 {
+			return headerError("Content-Length and Transfer-Encoding headers cannot both be set")
+		}
+	}
+
+	return nil
+}
+
+// conflicting returns true if a header has more than one distinct value, if
+// split is set values joined by commas within a single header are compared too
+func conflicting(values []string, split bool) bool {
+	distinct := ""
+	for _, value := range values {
+		parts := []string{value}
+		if split {
+			parts = strings.Split(value, ",")
+		}
+
+		for _, part := range parts {
+			part = strings.TrimSpace(part)
+			if distinct == "" {
+				distinct = part
+			} else if part != distinct {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// headerError creates a 400 error for a malformed request header
+func headerError(detail string) *Error {
+	return &Error{
+		Title:  "Bad Request",
+		Detail: detail,
+		Status: http.StatusBadRequest,
+	}
+}
@@ -2,7 +2,9 @@ package jsh
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strings"
 	"testing"
 
 	. "github.com/smartystreets/goconvey/convey"
@@ -20,6 +22,34 @@ func TestParsing(t *testing.T) {
 			err := validateHeaders(req.Header)
 			So(err, ShouldNotBeNil)
 			So(err.Status, ShouldEqual, http.StatusNotAcceptable)
+
+			Convey("should reject conflicting framing headers", func() {
+				headers := http.Header{}
+				headers.Set("Content-Type", ContentType)
+
+				headers["Content-Type"] = []string{ContentType, "text/plain"}
+				err := validateHeaders(headers)
+				So(err, ShouldNotBeNil)
+				So(err.Status, ShouldEqual, http.StatusBadRequest)
+
+				headers["Content-Type"] = []string{ContentType, ContentType}
+				So(validateHeaders(headers), ShouldBeNil)
+
+				headers["Content-Length"] = []string{"10", "20"}
+				So(validateHeaders(headers), ShouldNotBeNil)
+
+				headers["Content-Length"] = []string{"10, 20"}
+				So(validateHeaders(headers), ShouldNotBeNil)
+
+				headers["Content-Length"] = []string{"-1"}
+				So(validateHeaders(headers), ShouldNotBeNil)
+
+				headers["Content-Length"] = []string{"10"}
+				So(validateHeaders(headers), ShouldBeNil)
+
+				headers.Set("Transfer-Encoding", "chunked")
+				So(validateHeaders(headers), ShouldNotBeNil)
+			})
 		})
 
 		Convey("->ParseObject()", func() {
@@ -54,7 +84,7 @@ func TestParsing(t *testing.T) {
 				So(object.Type, ShouldEqual, "user")
 				So(object.ID, ShouldEqual, "sweetID123")
 				So(object.Attributes, ShouldResemble, json.RawMessage(`{"ID":"123"}`))
-				So(object.Relationships["company"], ShouldResemble, &Relationship{Data: ResourceLinkage{&ResourceIdentifier{Type: "company", ID: "companyID123"}}})
+				So(object.Relationships["company"], ShouldResemble, &Relationship{Data: ResourceLinkage{&ResourceIdentifier{Type: "company", ID: "companyID123"}}, Cardinality: ToOne})
 				So(object.Relationships["comments"], ShouldResemble, &Relationship{Data: ResourceLinkage{{Type: "comments", ID: "commentID123"}, {Type: "comments", ID: "commentID456"}}})
 			})
 
@@ -70,6 +100,19 @@ func TestParsing(t *testing.T) {
 				So(err.Source.Pointer, ShouldEqual, "/data/attributes/type")
 			})
 
+			Convey("should parse a meta-only object", func() {
+				objectJSON := `{"data": {"type": "permissions", "id": "1", "attributes": null, "meta": {"can_edit": true}}}`
+
+				req, reqErr := testRequest([]byte(objectJSON))
+				So(reqErr, ShouldBeNil)
+
+				object, err := ParseObject(req)
+				So(err, ShouldBeNil)
+				So(object.HasAttributes(), ShouldBeFalse)
+				So(object.Attributes, ShouldBeNil)
+				So(object.Meta["can_edit"], ShouldEqual, true)
+			})
+
 			Convey("should accept empty ID only for POST", func() {
 				objectJSON := `{"data": {"id": "", "type":"test", "attributes": {"ID":"123"}}}`
 				req, reqErr := testRequest([]byte(objectJSON))
@@ -89,6 +132,40 @@ func TestParsing(t *testing.T) {
 			})
 		})
 
+		Convey("->ParseObjectFor()", func() {
+			objectJSON := `{"data": {"type": "articles", "id": "1", "attributes": {"title": "a"}}}`
+			req, reqErr := testRequest([]byte(objectJSON))
+			So(reqErr, ShouldBeNil)
+			req.Method = "PATCH"
+
+			Convey("should accept a matching object", func() {
+				object, err := ParseObjectFor(req, "articles", "1")
+				So(err, ShouldBeNil)
+				So(object.ID, ShouldEqual, "1")
+			})
+
+			Convey("should conflict on a type mismatch", func() {
+				_, err := ParseObjectFor(req, "users", "1")
+				So(err, ShouldNotBeNil)
+				So(err.Status, ShouldEqual, http.StatusConflict)
+				So(err.Source.Pointer, ShouldEqual, "/data/type")
+			})
+
+			Convey("should conflict on an ID mismatch", func() {
+				_, err := ParseObjectFor(req, "articles", "2")
+				So(err, ShouldNotBeNil)
+				So(err.Status, ShouldEqual, http.StatusConflict)
+				So(err.Source.Pointer, ShouldEqual, "/data/id")
+			})
+
+			Convey("should not check the ID when none is expected", func() {
+				req.Method = "POST"
+
+				_, err := ParseObjectFor(req, "articles", "")
+				So(err, ShouldBeNil)
+			})
+		})
+
 		Convey("->ParseList()", func() {
 
 			Convey("should parse a valid list", func() {
@@ -124,8 +201,88 @@ func TestParsing(t *testing.T) {
 				_, err := ParseList(req)
 				So(err, ShouldNotBeNil)
 				So(err.Status, ShouldEqual, 422)
-				So(err.Source.Pointer, ShouldEqual, "/data/attributes/id")
+				So(err.Source.Pointer, ShouldEqual, "/data/1/attributes/id")
+			})
+		})
+
+		Convey("->ParseRelationship()", func() {
+
+			Convey("should parse to-many linkage", func() {
+				req, reqErr := testRequest([]byte(`{"data": [{"type": "tags", "id": "1"}, {"type": "tags", "id": "2"}]}`))
+				So(reqErr, ShouldBeNil)
+				req.Method = "DELETE"
+
+				relationship, err := ParseRelationship(req)
+				So(err, ShouldBeNil)
+				So(relationship.Cardinality, ShouldEqual, ToMany)
+				So(len(relationship.Data), ShouldEqual, 2)
+			})
+
+			Convey("should parse null to-one linkage", func() {
+				req, reqErr := testRequest([]byte(`{"data": null}`))
+				So(reqErr, ShouldBeNil)
+				req.Method = "PATCH"
+
+				relationship, err := ParseRelationship(req)
+				So(err, ShouldBeNil)
+				So(relationship.Cardinality, ShouldEqual, ToOne)
+				So(relationship.Data, ShouldBeEmpty)
+			})
+
+			Convey("should reject a DELETE of to-one linkage", func() {
+				req, reqErr := testRequest([]byte(`{"data": {"type": "users", "id": "1"}}`))
+				So(reqErr, ShouldBeNil)
+				req.Method = "DELETE"
+
+				_, err := ParseRelationship(req)
+				So(err, ShouldNotBeNil)
+				So(err.Status, ShouldEqual, 422)
+				So(err.Source.Pointer, ShouldEqual, "/data")
+			})
+
+			Convey("should reject missing data and invalid identifiers", func() {
+				req, reqErr := testRequest([]byte(`{"meta": {}}`))
+				So(reqErr, ShouldBeNil)
+
+				_, err := ParseRelationship(req)
+				So(err, ShouldNotBeNil)
+
+				req, reqErr = testRequest([]byte(`{"data": [{"type": "tags"}]}`))
+				So(reqErr, ShouldBeNil)
+
+				_, err = ParseRelationship(req)
+				So(err, ShouldNotBeNil)
 			})
 		})
 	})
 }
+
+func BenchmarkParseObject(b *testing.B) {
+	body := []byte(`{"data": {"type": "widgets", "id": "1", "attributes": {"name": "gear", "teeth": 12, "tags": ["metal", "round"]}}}`)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		request, _ := testRequest(body)
+		_, err := ParseObject(request)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseList(b *testing.B) {
+	objects := []string{}
+	for i := 0; i < 100; i++ {
+		objects = append(objects, fmt.Sprintf(`{"type": "widgets", "id": "%d", "attributes": {"name": "gear", "teeth": %d}}`, i, i))
+	}
+	body := []byte(`{"data": [` + strings.Join(objects, ",") + `]}`)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		request, _ := testRequest(body)
+		_, err := ParseList(request)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
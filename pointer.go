@@ -0,0 +1,247 @@
+package jsh
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/asaskevich/govalidator"
+)
+
+// attributesPointer is the JSON pointer of a resource's attributes
+const attributesPointer = "/data/attributes"
+
+/*
+pointerAt returns the JSON pointer, relative to raw, of the value that ends at
+offset, which is where encoding/json reports an UnmarshalTypeError. For example
+an offset within ["a", {"b": 1}] of 13 gives "/1/b".
+*/
+func pointerAt(raw []byte, offset int64) (string, bool) {
+	type frame struct {
+		array     bool
+		index     int
+		key       string
+		expectKey bool
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	stack := []*frame{}
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", false
+		}
+
+		delim, isDelim := token.(json.Delim)
+		if isDelim && (delim == '}' || delim == ']') {
+			stack = stack[:len(stack)-1]
+			if len(stack) > 0 {
+				stack[len(stack)-1].expectKey = true
+			}
+			continue
+		}
+
+		var top *frame
+		if len(stack) > 0 {
+			top = stack[len(stack)-1]
+
+			if !top.array && top.expectKey {
+				top.key, _ = token.(string)
+				top.expectKey = false
+				continue
+			}
+
+			if top.array {
+				top.index++
+			}
+		}
+
+		if decoder.InputOffset() >= offset {
+			pointer := ""
+			for _, f := range stack {
+				if f.array {
+					pointer += "/" + strconv.Itoa(f.index)
+				} else {
+					pointer += "/" + pointerToken(f.key)
+				}
+			}
+
+			return pointer, true
+		}
+
+		if isDelim {
+			stack = append(stack, &frame{array: delim == '[', index: -1, expectKey: true})
+			continue
+		}
+
+		if top != nil {
+			top.expectKey = true
+		}
+	}
+}
+
+/*
+indexPointer points an error's "/data" source pointer at the object at index i
+of a list, so "/data/attributes/id" becomes "/data/1/attributes/id".
+*/
+func indexPointer(err *Error, i int) *Error {
+	if err == nil || !strings.HasPrefix(err.Source.Pointer, "/data") {
+		return err
+	}
+
+	err.Source.Pointer = fmt.Sprintf("/data/%d%s", i, strings.TrimPrefix(err.Source.Pointer, "/data"))
+	return err
+}
+
+// unmarshalError converts an error unmarshaling attributes into a 422 pointing
+// at the offending value where possible
+func unmarshalError(attributes []byte, jsonErr error) *Error {
+	typeErr, isType := jsonErr.(*json.UnmarshalTypeError)
+	if !isType {
+		return nil
+	}
+
+	pointer, found := pointerAt(attributes, typeErr.Offset)
+	if !found {
+		return nil
+	}
+
+	return invalidAttribute(fmt.Sprintf("Cannot use %s value as %s", typeErr.Value, typeErr.Type), attributesPointer+pointer)
+}
+
+/*
+validateStruct runs go-validator on a struct, pointing each error at the
+struct's member below pointer. Errors within nested structs are pointed at the
+nested member, such as /data/attributes/address/zipcode, or
+/data/attributes/addresses/1/zipcode for slices.
+*/
+func validateStruct(pointer string, value reflect.Value) ErrorList {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	_, validationError := govalidator.ValidateStruct(value.Interface())
+	errorList, isType := validationError.(govalidator.Errors)
+	if !isType {
+		return nil
+	}
+
+	errors := ErrorList{}
+	nested := false
+	for _, singleErr := range errorList.Errors() {
+		// errors for nested structs aren't attributed to a field by
+		// go-validator, so those are found by validating each nested value
+		goValidErr, isField := singleErr.(govalidator.Error)
+		if !isField {
+			nested = true
+			continue
+		}
+
+		name := strings.ToLower(goValidErr.Name)
+		if field, exists := value.Type().FieldByName(goValidErr.Name); exists {
+			name = memberName(field)
+		}
+
+		errors = append(errors, invalidAttribute(goValidErr.Err.Error(), pointer+"/"+pointerToken(name)))
+	}
+
+	if nested {
+		errors = append(errors, validateNested(pointer, value)...)
+	}
+
+	if len(errors) == 0 {
+		return nil
+	}
+
+	return errors
+}
+
+// validateNested validates the structs nested within the validated fields of a
+// struct
+func validateNested(pointer string, value reflect.Value) ErrorList {
+	errors := ErrorList{}
+
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		tag := field.Tag.Get("valid")
+		if field.PkgPath != "" || tag == "" || tag == "-" {
+			continue
+		}
+
+		fieldPointer := pointer
+		if !field.Anonymous || jsonName(field) != "" {
+			fieldPointer += "/" + pointerToken(memberName(field))
+		}
+
+		fieldValue := value.Field(i)
+		for fieldValue.Kind() == reflect.Ptr || fieldValue.Kind() == reflect.Interface {
+			if fieldValue.IsNil() {
+				break
+			}
+			fieldValue = fieldValue.Elem()
+		}
+
+		switch fieldValue.Kind() {
+		case reflect.Struct:
+			errors = append(errors, validateStruct(fieldPointer, fieldValue)...)
+		case reflect.Slice, reflect.Array:
+			for j := 0; j < fieldValue.Len(); j++ {
+				errors = append(errors, validateStruct(fmt.Sprintf("%s/%d", fieldPointer, j), fieldValue.Index(j))...)
+			}
+		case reflect.Map:
+			if fieldValue.Type().Key().Kind() != reflect.String {
+				continue
+			}
+
+			for _, key := range fieldValue.MapKeys() {
+				errors = append(errors, validateStruct(fieldPointer+"/"+pointerToken(key.String()), fieldValue.MapIndex(key))...)
+			}
+		}
+	}
+
+	return errors
+}
+
+// memberName returns the JSON member name of a struct field, falling back to
+// the lowercased field name
+func memberName(field reflect.StructField) string {
+	name := jsonName(field)
+	if name == "" {
+		return strings.ToLower(field.Name)
+	}
+
+	return name
+}
+
+// jsonName returns the name set by a field's json tag, if any
+func jsonName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "-" {
+		return ""
+	}
+
+	return name
+}
+
+// invalidAttribute creates a 422 error for the attribute value at pointer
+func invalidAttribute(detail string, pointer string) *Error {
+	err := &Error{
+		Title:  "Invalid Attribute",
+		Detail: detail,
+		Status: 422,
+	}
+	err.Source.Pointer = pointer
+
+	return err
+}
@@ -0,0 +1,78 @@
+package jsh
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPointers(t *testing.T) {
+
+	Convey("Pointer Tests", t, func() {
+
+		Convey("->pointerAt()", func() {
+
+			Convey("should find values nested in objects and arrays", func() {
+				raw := []byte(`{"a": ["x", {"b": 1}], "c": 2}`)
+
+				pointer, found := pointerAt(raw, 19)
+				So(found, ShouldBeTrue)
+				So(pointer, ShouldEqual, "/a/1/b")
+
+				pointer, found = pointerAt(raw, int64(len(raw)-1))
+				So(found, ShouldBeTrue)
+				So(pointer, ShouldEqual, "/c")
+			})
+
+			Convey("should escape member names", func() {
+				pointer, found := pointerAt([]byte(`{"a/b": 1}`), 9)
+				So(found, ShouldBeTrue)
+				So(pointer, ShouldEqual, "/a~1b")
+			})
+		})
+
+		Convey("->indexPointer()", func() {
+			err := indexPointer(InputError("Invalid", "name"), 2)
+			So(err.Source.Pointer, ShouldEqual, "/data/2/attributes/name")
+		})
+
+		Convey("nested attribute errors", func() {
+			object := &Object{
+				Type:       "users",
+				Attributes: json.RawMessage(`{"address": {"zipcode": 123}, "pets": [{"name": "rex"}, {"name": "!!"}]}`),
+			}
+
+			Convey("should point at values of the wrong type", func() {
+				target := struct {
+					Address struct {
+						Zipcode string `json:"zipcode"`
+					} `json:"address"`
+				}{}
+
+				errs := object.Unmarshal("users", &target)
+				So(errs, ShouldNotBeNil)
+				So(errs[0].Status, ShouldEqual, 422)
+				So(errs[0].Source.Pointer, ShouldEqual, "/data/attributes/address/zipcode")
+			})
+
+			Convey("should point at nested validation failures", func() {
+				type pet struct {
+					Name string `json:"name" valid:"alpha"`
+				}
+
+				target := struct {
+					Address struct {
+						Zipcode int `json:"zipcode"`
+					} `json:"address"`
+					Pets []pet `json:"pets" valid:"required"`
+				}{}
+
+				errs := object.Unmarshal("users", &target)
+				So(errs, ShouldNotBeNil)
+				So(len(errs), ShouldEqual, 1)
+				So(errs[0].Source.Pointer, ShouldEqual, "/data/attributes/pets/1/name")
+			})
+		})
+	})
+}
@@ -0,0 +1,170 @@
+package jsh
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+// AnyRole keys the rules of a FieldPolicy that apply to roles it doesn't list.
+const AnyRole = "*"
+
+/*
+FieldPolicy declares which attributes and relationships of a resource type each
+caller role may see and write. Declare one when registering the resource:
+
+	jsh.Register(&jsh.Resource{
+		Type: "users",
+		Policy: &jsh.FieldPolicy{
+			Role: func(ctx context.Context) string {
+				return auth.FromContext(ctx).Role
+			},
+			Visible: map[string][]string{
+				"admin":     {"name", "email", "salary", "manager"},
+				jsh.AnyRole: {"name", "manager"},
+			},
+			Writable: map[string][]string{
+				"admin":     {"name", "email", "salary", "manager"},
+				jsh.AnyRole: {"name"},
+			},
+		},
+	})
+
+Members a caller may not see are left out of the objects sent to it, included
+resources too. Writing members a caller may not write is rejected with a 403
+when parsing, so parse with a Parser carrying the request's context, as
+NewParser does.
+
+A role's rules are those listed for it, or for AnyRole if it isn't listed.
+Roles without either may see or write every member.
+*/
+type FieldPolicy struct {
+	// Role returns the role of the caller making a request, such as one an
+	// authentication middleware stored in its context. The role is empty if
+	// Role is nil.
+	Role func(ctx context.Context) string
+	// Visible lists the attributes and relationships each role may see
+	Visible map[string][]string
+	// Writable lists the attributes and relationships each role may write
+	Writable map[string][]string
+}
+
+// role returns the role of the caller
+func (p *FieldPolicy) role(ctx context.Context) string {
+	if p.Role == nil {
+		return ""
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return p.Role(ctx)
+}
+
+// allowed returns the members the rules allow the caller, or nil if they
+// don't restrict it
+func (p *FieldPolicy) allowed(ctx context.Context, rules map[string][]string) map[string]bool {
+	members, exists := rules[p.role(ctx)]
+	if !exists {
+		members, exists = rules[AnyRole]
+	}
+	if !exists {
+		return nil
+	}
+
+	allowed := make(map[string]bool, len(members))
+	for _, member := range members {
+		allowed[member] = true
+	}
+
+	return allowed
+}
+
+// redact removes the members of an object being sent that the caller may not
+// see
+func (p *FieldPolicy) redact(r *http.Request, object *Object) *Error {
+	var ctx context.Context
+	if r != nil {
+		ctx = r.Context()
+	}
+
+	visible := p.allowed(ctx, p.Visible)
+	if visible == nil {
+		return nil
+	}
+
+	for name := range object.Relationships {
+		if !visible[name] {
+			delete(object.Relationships, name)
+		}
+	}
+
+	attributes, err := object.attributeMap()
+	if err != nil {
+		return err
+	}
+
+	hidden := false
+	for name := range attributes {
+		if !visible[name] {
+			delete(attributes, name)
+			hidden = true
+		}
+	}
+	if !hidden {
+		return nil
+	}
+
+	return object.marshal(attributes, nil)
+}
+
+// checkWrite rejects writes of members of a parsed object that the caller may
+// not write
+func (p *FieldPolicy) checkWrite(ctx context.Context, object *Object) *Error {
+	writable := p.allowed(ctx, p.Writable)
+	if writable == nil {
+		return nil
+	}
+
+	attributes, err := object.attributeMap()
+	if err != nil {
+		return err
+	}
+
+	// report the first member by name, so the error doesn't vary
+	denied := []string{}
+	for name := range attributes {
+		if !writable[name] {
+			denied = append(denied, name)
+		}
+	}
+	sort.Strings(denied)
+	if len(denied) > 0 {
+		return forbiddenMember(fmt.Sprintf("Attribute '%s' of type '%s' is read-only", denied[0], object.Type), "/data/attributes/"+denied[0])
+	}
+
+	for name := range object.Relationships {
+		if !writable[name] {
+			denied = append(denied, name)
+		}
+	}
+	sort.Strings(denied)
+	if len(denied) > 0 {
+		return forbiddenMember(fmt.Sprintf("Relationship '%s' of type '%s' is read-only", denied[0], object.Type), "/data/relationships/"+denied[0])
+	}
+
+	return nil
+}
+
+// forbiddenMember creates a 403 error pointing at a member of the primary data
+func forbiddenMember(detail string, pointer string) *Error {
+	err := &Error{
+		Title:  "Forbidden",
+		Detail: detail,
+		Status: http.StatusForbidden,
+	}
+	err.Source.Pointer = pointer
+
+	return err
+}
@@ -0,0 +1,144 @@
+package jsh
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type roleKey struct{}
+
+func TestPolicy(t *testing.T) {
+
+	Convey("Policy Tests", t, func() {
+
+		Register(&Resource{
+			Type: "users",
+			Policy: &FieldPolicy{
+				Role: func(ctx context.Context) string {
+					role, _ := ctx.Value(roleKey{}).(string)
+					return role
+				},
+				Visible: map[string][]string{
+					"admin": {"name", "salary", "manager"},
+					"guest": {"name"},
+					AnyRole: {"name", "manager"},
+				},
+				Writable: map[string][]string{
+					"guest": {"name"},
+					AnyRole: {"name", "manager"},
+				},
+			},
+		})
+		Reset(func() { Unregister("users") })
+
+		withRole := func(r *http.Request, role string) *http.Request {
+			return r.WithContext(context.WithValue(r.Context(), roleKey{}, role))
+		}
+
+		user, objErr := NewObject("1", "users", map[string]interface{}{"name": "bob", "salary": 100})
+		So(objErr, ShouldBeNil)
+		user.AddToOneRelationship("manager", "users", "2")
+
+		sent := func(role string) *Object {
+			writer := httptest.NewRecorder()
+			err := Send(writer, withRole(&http.Request{Method: "GET"}, role), user)
+			So(err, ShouldBeNil)
+
+			return sentObject(writer)
+		}
+
+		Convey("->Send()", func() {
+
+			Convey("should send every visible member", func() {
+				object := sent("admin")
+
+				attributes, err := object.attributeMap()
+				So(err, ShouldBeNil)
+				So(attributes["salary"], ShouldNotBeNil)
+				So(object.Relationships["manager"], ShouldNotBeNil)
+			})
+
+			Convey("should omit hidden attributes and relationships", func() {
+				object := sent("guest")
+
+				attributes, err := object.attributeMap()
+				So(err, ShouldBeNil)
+				So(string(attributes["name"]), ShouldEqual, `"bob"`)
+				So(attributes["salary"], ShouldBeNil)
+				So(object.Relationships["manager"], ShouldBeNil)
+			})
+
+			Convey("should leave the sent object alone for other roles", func() {
+				sent("guest")
+
+				attributes, err := sent("admin").attributeMap()
+				So(err, ShouldBeNil)
+				So(attributes["salary"], ShouldNotBeNil)
+
+				attributes, err = user.attributeMap()
+				So(err, ShouldBeNil)
+				So(attributes["salary"], ShouldNotBeNil)
+				So(user.Relationships["manager"], ShouldNotBeNil)
+			})
+
+			Convey("should fall back to the rules of AnyRole", func() {
+				object := sent("")
+
+				attributes, err := object.attributeMap()
+				So(err, ShouldBeNil)
+				So(attributes["salary"], ShouldBeNil)
+				So(object.Relationships["manager"], ShouldNotBeNil)
+			})
+		})
+
+		Convey("->ParseObject()", func() {
+
+			parse := func(role string, body string) (*Object, *Error) {
+				req, reqErr := testRequest([]byte(body))
+				So(reqErr, ShouldBeNil)
+				req.Method = "PATCH"
+
+				return ParseObject(withRole(req, role))
+			}
+
+			Convey("should accept writable attributes", func() {
+				object, err := parse("guest", `{"data": {"type": "users", "id": "1", "attributes": {"name": "bobby"}}}`)
+				So(err, ShouldBeNil)
+				So(object, ShouldNotBeNil)
+			})
+
+			Convey("should reject read-only attributes", func() {
+				_, err := parse("guest", `{"data": {"type": "users", "id": "1", "attributes": {"name": "bobby", "salary": 200}}}`)
+				So(err, ShouldNotBeNil)
+				So(err.Status, ShouldEqual, http.StatusForbidden)
+				So(err.Source.Pointer, ShouldEqual, "/data/attributes/salary")
+			})
+
+			Convey("should reject read-only relationships", func() {
+				_, err := parse("guest", `{"data": {"type": "users", "id": "1", "relationships": {"manager": {"data": {"type": "users", "id": "3"}}}}}`)
+				So(err, ShouldNotBeNil)
+				So(err.Status, ShouldEqual, http.StatusForbidden)
+				So(err.Source.Pointer, ShouldEqual, "/data/relationships/manager")
+			})
+
+			Convey("should not restrict roles without rules", func() {
+				Registered("users").Policy.Writable = map[string][]string{"guest": {"name"}}
+
+				_, err := parse("admin", `{"data": {"type": "users", "id": "1", "attributes": {"salary": 200}}}`)
+				So(err, ShouldBeNil)
+			})
+
+			Convey("should not restrict reads", func() {
+				req, reqErr := testRequest([]byte(`{"data": {"type": "users", "id": "1", "attributes": {"salary": 200}}}`))
+				So(reqErr, ShouldBeNil)
+
+				_, err := ParseObject(withRole(req, "guest"))
+				So(err, ShouldBeNil)
+			})
+		})
+	})
+}
@@ -0,0 +1,68 @@
+package jsh
+
+import "sort"
+
+// OfType returns the objects of the list with any of the given types, in
+// order.
+func (list List) OfType(types ...string) List {
+	return list.Filter(func(object *Object) bool {
+		return contains(types, object.Type)
+	})
+}
+
+// Types returns the distinct types of the objects in the list, sorted.
+func (list List) Types() []string {
+	types := make([]string, len(list))
+	for i, object := range list {
+		types[i] = object.Type
+	}
+
+	return distinctTypes(types)
+}
+
+// OfType returns the identifiers of the linkage with any of the given types, in
+// order, such as the comments of a polymorphic "activity" relationship.
+func (rl ResourceLinkage) OfType(types ...string) ResourceLinkage {
+	linkage := ResourceLinkage{}
+	for _, identifier := range rl {
+		if contains(types, identifier.Type) {
+			linkage = append(linkage, identifier)
+		}
+	}
+
+	return linkage
+}
+
+// Types returns the distinct types of the identifiers in the linkage, sorted.
+func (rl ResourceLinkage) Types() []string {
+	types := make([]string, len(rl))
+	for i, identifier := range rl {
+		types[i] = identifier.Type
+	}
+
+	return distinctTypes(types)
+}
+
+// IDs returns the ID of each identifier in the linkage, in order.
+func (rl ResourceLinkage) IDs() []string {
+	ids := make([]string, len(rl))
+	for i, identifier := range rl {
+		ids[i] = identifier.ID
+	}
+
+	return ids
+}
+
+// distinctTypes sorts types and removes duplicates
+func distinctTypes(types []string) []string {
+	sort.Strings(types)
+
+	distinct := []string{}
+	for i, resourceType := range types {
+		if i == 0 || resourceType != types[i-1] {
+			distinct = append(distinct, resourceType)
+		}
+	}
+
+	return distinct
+}
@@ -0,0 +1,116 @@
+package jsh
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPolymorphic(t *testing.T) {
+
+	Convey("Polymorphic Tests", t, func() {
+
+		type article struct {
+			Title string `json:"title"`
+		}
+		type video struct {
+			Length int `json:"length"`
+		}
+
+		RegisterModel("articles", article{})
+		RegisterModel("videos", &video{})
+		Reset(func() {
+			RegisterModel("articles", nil)
+			RegisterModel("videos", nil)
+		})
+
+		Convey("should parse lists mixing types", func() {
+			request, _ := testRequest([]byte(`{"data": [
+				{"type": "articles", "id": "1", "attributes": {"title": "Hello"}},
+				{"type": "videos", "id": "2", "attributes": {"length": 90}},
+				{"type": "articles", "id": "3", "attributes": {"title": "Again"}}
+			]}`))
+
+			list, err := ParseList(request)
+			So(err, ShouldBeNil)
+			So(list.Types(), ShouldResemble, []string{"articles", "videos"})
+			So(list.OfType("articles").IDs(), ShouldResemble, []string{"1", "3"})
+
+			Convey("->UnmarshalModels()", func() {
+				models, errs := list.UnmarshalModels()
+				So(errs, ShouldBeNil)
+				So(models, ShouldResemble, []interface{}{&article{Title: "Hello"}, &video{Length: 90}, &article{Title: "Again"}})
+			})
+
+			Convey("should point model errors at the object", func() {
+				list[1].Attributes = []byte(`{"length": "long"}`)
+				_, errs := list.UnmarshalModels()
+				So(errs, ShouldNotBeNil)
+				So(errs[0].Source.Pointer, ShouldEqual, "/data/1/attributes/length")
+			})
+
+			Convey("should reject unregistered types", func() {
+				RegisterModel("videos", nil)
+				_, errs := list.UnmarshalModels()
+				So(errs, ShouldNotBeNil)
+				So(errs[0].Status, ShouldEqual, 422)
+				So(errs[0].Source.Pointer, ShouldEqual, "/data/1/type")
+			})
+		})
+
+		Convey("ResourceLinkage", func() {
+			linkage := ResourceLinkage{
+				{Type: "comments", ID: "1"},
+				{Type: "likes", ID: "2"},
+				{Type: "comments", ID: "3"},
+			}
+
+			So(linkage.Types(), ShouldResemble, []string{"comments", "likes"})
+			So(linkage.OfType("comments").IDs(), ShouldResemble, []string{"1", "3"})
+			So(linkage.OfType("shares"), ShouldResemble, ResourceLinkage{})
+		})
+
+		Convey("with a polymorphic relationship declared", func() {
+			Register(&Resource{
+				Type: "activities",
+				Relationships: map[string]RelationshipDeclaration{
+					"subjects": {Types: []string{"articles", "videos"}, Cardinality: ToMany},
+				},
+			})
+			Register(&Resource{Type: "articles"})
+			Register(&Resource{Type: "videos"})
+			Reset(func() {
+				Unregister("activities")
+				Unregister("articles")
+				Unregister("videos")
+			})
+
+			activity, _ := NewObject("1", "activities", nil)
+
+			Convey("should accept linkage of any declared type", func() {
+				activity.AddToManyRelationship("subjects", &ResourceIdentifier{Type: "articles", ID: "1"}, &ResourceIdentifier{Type: "videos", ID: "2"})
+
+				ids, err := activity.RelatedIDs("subjects")
+				So(err, ShouldBeNil)
+				So(ids, ShouldResemble, []string{"1", "2"})
+			})
+
+			Convey("should reject linkage of other types", func() {
+				activity.AddToManyRelationship("subjects", &ResourceIdentifier{Type: "people", ID: "1"})
+
+				_, err := activity.RelatedIDs("subjects")
+				So(err, ShouldNotBeNil)
+				So(err.Detail, ShouldEqual, "Expected linkage of type 'articles' or 'videos', got 'people'")
+			})
+
+			Convey("should pass validation", func() {
+				So(Validate(), ShouldBeNil)
+			})
+
+			Convey("should describe the related resources in OpenAPI", func() {
+				schemas := OpenAPI("API", "1")["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+				So(schemas["activities.subjects"], ShouldResemble, map[string]interface{}{"oneOf": []interface{}{schemaRef("articles"), schemaRef("videos")}})
+			})
+		})
+	})
+}
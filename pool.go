@@ -0,0 +1,65 @@
+package jsh
+
+import (
+	"bytes"
+	"sync"
+	"sync/atomic"
+)
+
+// maxPooledBuffer is the capacity above which buffers aren't returned to the
+// pool, so that one large body doesn't pin its memory
+const maxPooledBuffer = 1 << 20
+
+// PoolStats counts the use of the buffers jsh pools for reading request bodies
+// and encoding responses, which MetricEvents carry so that hooks can tell how
+// well the pool absorbs allocations under load.
+type PoolStats struct {
+	// Gets is the number of buffers taken from the pool
+	Gets int64 `json:"gets"`
+	// Allocations is the number of those the pool allocated rather than reused
+	Allocations int64 `json:"allocations"`
+	// Discards is the number of buffers not returned to the pool, having grown
+	// past the size it keeps
+	Discards int64 `json:"discards"`
+}
+
+// pool counters, updated atomically
+var poolGets, poolAllocations, poolDiscards int64
+
+// bufferPool holds the buffers bodies are read and encoded into
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		atomic.AddInt64(&poolAllocations, 1)
+		return &bytes.Buffer{}
+	},
+}
+
+// BufferPoolStats returns the counters of jsh's buffer pool since the process
+// started.
+func BufferPoolStats() PoolStats {
+	return PoolStats{
+		Gets:        atomic.LoadInt64(&poolGets),
+		Allocations: atomic.LoadInt64(&poolAllocations),
+		Discards:    atomic.LoadInt64(&poolDiscards),
+	}
+}
+
+// getBuffer takes an empty buffer from the pool, which the caller returns with
+// releaseBuffer once it's done with its contents
+func getBuffer() *bytes.Buffer {
+	atomic.AddInt64(&poolGets, 1)
+
+	buffer := bufferPool.Get().(*bytes.Buffer)
+	buffer.Reset()
+	return buffer
+}
+
+// releaseBuffer returns a buffer from getBuffer to the pool
+func releaseBuffer(buffer *bytes.Buffer) {
+	if buffer.Cap() > maxPooledBuffer {
+		atomic.AddInt64(&poolDiscards, 1)
+		return
+	}
+
+	bufferPool.Put(buffer)
+}
@@ -0,0 +1,61 @@
+package jsh
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPool(t *testing.T) {
+
+	Convey("Pool Tests", t, func() {
+
+		hook := &recordingHook{}
+		Metrics = hook
+		Reset(func() { Metrics = nil })
+
+		Convey("->getBuffer()", func() {
+
+			Convey("should count the buffers taken", func() {
+				before := BufferPoolStats()
+
+				buffer := getBuffer()
+				buffer.WriteString("leftover")
+				releaseBuffer(buffer)
+
+				So(getBuffer().Len(), ShouldEqual, 0)
+				So(BufferPoolStats().Gets, ShouldEqual, before.Gets+2)
+			})
+		})
+
+		Convey("->releaseBuffer()", func() {
+
+			Convey("should discard large buffers", func() {
+				before := BufferPoolStats()
+
+				large := bytes.NewBuffer(make([]byte, 0, maxPooledBuffer+1))
+				releaseBuffer(large)
+				So(BufferPoolStats().Discards, ShouldEqual, before.Discards+1)
+
+				for i := 0; i < 10; i++ {
+					So(bufferPool.Get(), ShouldNotEqual, large)
+				}
+			})
+		})
+
+		Convey("should report the pool's state with parses", func() {
+			body := []byte(`{"data": {"type": "user", "id": "1", "attributes": {"name": "Bob"}}}`)
+			req, reqErr := testRequest(body)
+			So(reqErr, ShouldBeNil)
+
+			object, err := ParseObject(req)
+			So(err, ShouldBeNil)
+			So(object.ID, ShouldEqual, "1")
+
+			So(len(hook.events), ShouldEqual, 1)
+			So(hook.events[0].Pool.Gets, ShouldBeGreaterThan, 0)
+			So(hook.events[0].Pool.Allocations, ShouldBeLessThanOrEqualTo, hook.events[0].Pool.Gets)
+		})
+	})
+}
@@ -0,0 +1,125 @@
+package jsh
+
+import (
+	"context"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+/*
+Profiles lists the profile URIs of the optional behaviors the server supports,
+giving clients a structured way to opt in to features as they are rolled out:
+
+	jsh.Profiles = []string{"https://example.com/profiles/soft-delete"}
+
+Clients request profiles with the "profile" parameter of the JSON API media type
+in their Accept header. Profiles the server doesn't support are ignored, as the
+specification requires.
+*/
+var Profiles []string
+
+// profilesKey is the context key of the profiles set by WithProfiles
+type profilesKey struct{}
+
+/*
+NegotiateProfiles returns the supported Profiles requested by the client, in the
+order they are listed in Profiles.
+*/
+func NegotiateProfiles(r *http.Request) []string {
+	requested := map[string]bool{}
+	for _, header := range []string{"Accept", "Content-Type"} {
+		for _, profile := range headerProfiles(r.Header[header]) {
+			requested[profile] = true
+		}
+	}
+
+	negotiated := []string{}
+	for _, profile := range Profiles {
+		if requested[profile] {
+			negotiated = append(negotiated, profile)
+		}
+	}
+
+	return negotiated
+}
+
+// WithProfiles returns a copy of the request carrying its negotiated profiles,
+// so they are only negotiated once. Middleware does so for every request.
+func WithProfiles(r *http.Request) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), profilesKey{}, NegotiateProfiles(r)))
+}
+
+// RequestProfiles returns the profiles negotiated for a request, from its
+// context if set by WithProfiles.
+func RequestProfiles(r *http.Request) []string {
+	if profiles, ok := r.Context().Value(profilesKey{}).([]string); ok {
+		return profiles
+	}
+
+	return NegotiateProfiles(r)
+}
+
+// HasProfile returns true if the profile was negotiated for the request, for
+// handlers to enable optional behaviors:
+//
+//	if jsh.HasProfile(r, softDeleteProfile) {
+func HasProfile(r *http.Request, profile string) bool {
+	for _, negotiated := range RequestProfiles(r) {
+		if negotiated == profile {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ProfileContentType returns the JSON API Content-Type applying profiles, such
+// as for a client's Accept header.
+func ProfileContentType(profiles ...string) string {
+	return withProfiles(ContentType, profiles)
+}
+
+// withProfiles adds the profile media type parameter to a Content-Type
+func withProfiles(contentType string, profiles []string) string {
+	if len(profiles) == 0 {
+		return contentType
+	}
+
+	return contentType + `; profile="` + strings.Join(profiles, " ") + `"`
+}
+
+// headerProfiles returns the profiles listed by JSON API media types in the
+// header values
+func headerProfiles(values []string) []string {
+	profiles := []string{}
+
+	for _, value := range values {
+		for _, mediaRange := range strings.Split(value, ",") {
+			mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+			if err != nil || mediaType != ContentType {
+				continue
+			}
+
+			profiles = append(profiles, strings.Fields(params["profile"])...)
+		}
+	}
+
+	return profiles
+}
+
+// applyProfiles echoes the negotiated profiles in the document's jsonapi
+// object, returning the Content-Type applying them
+func applyProfiles(r *http.Request, document *Document, contentType string) string {
+	if len(Profiles) == 0 {
+		return contentType
+	}
+
+	profiles := RequestProfiles(r)
+	if len(profiles) == 0 {
+		return contentType
+	}
+
+	document.JSONAPI.Profile = profiles
+	return withProfiles(contentType, profiles)
+}
@@ -0,0 +1,74 @@
+package jsh
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestProfiles(t *testing.T) {
+
+	Convey("Profile Tests", t, func() {
+
+		softDelete := "https://example.com/profiles/soft-delete"
+		timestamps := "https://example.com/profiles/timestamps"
+
+		Profiles = []string{softDelete, timestamps}
+		Reset(func() { Profiles = nil })
+
+		request := httptest.NewRequest("GET", "/users/1", nil)
+
+		Convey("->NegotiateProfiles()", func() {
+
+			Convey("should only negotiate supported profiles", func() {
+				request.Header.Set("Accept", ProfileContentType(timestamps, "https://example.com/unknown"))
+				So(NegotiateProfiles(request), ShouldResemble, []string{timestamps})
+			})
+
+			Convey("should negotiate none without the parameter", func() {
+				request.Header.Set("Accept", ContentType)
+				So(NegotiateProfiles(request), ShouldBeEmpty)
+			})
+		})
+
+		Convey("->HasProfile()", func() {
+			request.Header.Set("Accept", ProfileContentType(softDelete))
+
+			So(HasProfile(WithProfiles(request), softDelete), ShouldBeTrue)
+			So(HasProfile(request, timestamps), ShouldBeFalse)
+		})
+
+		Convey("should echo negotiated profiles in responses", func() {
+			request.Header.Set("Accept", ProfileContentType(softDelete, timestamps))
+			object, _ := NewObject("1", "users", map[string]string{"name": "bob"})
+
+			writer := httptest.NewRecorder()
+			Send(writer, request, object)
+
+			So(writer.Header().Get("Content-Type"), ShouldEqual, ProfileContentType(softDelete, timestamps))
+			So(writer.Body.String(), ShouldContainSubstring, `"profile":[`)
+		})
+
+		Convey("should accept request bodies applying profiles", func() {
+			req, reqErr := testRequest([]byte(`{"data": {"type": "users", "id": "1", "attributes": {"name": "bob"}}}`))
+			So(reqErr, ShouldBeNil)
+			req.Header.Set("Content-Type", ProfileContentType(softDelete))
+
+			_, err := ParseObject(req)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("should expose profiles to handlers behind Middleware", func() {
+			negotiated := []string{}
+			handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				negotiated = RequestProfiles(r)
+			}))
+
+			request.Header.Set("Accept", ProfileContentType(softDelete))
+			handler.ServeHTTP(httptest.NewRecorder(), request)
+			So(negotiated, ShouldResemble, []string{softDelete})
+		})
+	})
+}
@@ -0,0 +1,108 @@
+package jsh
+
+import (
+	"strconv"
+	"sync"
+)
+
+/*
+Provenance records the source of each attribute and relationship of merged
+objects, such as the upstream request or sync batch it came from, for auditing
+aggregation and sync pipelines. It is kept alongside the objects rather than in
+them, so nothing extra is sent to clients:
+
+	provenance := &jsh.Provenance{}
+	merged, err := jsh.Merge(&jsh.MergeOptions{
+		Provenance: provenance,
+		Sources:    []string{usersURL, teamsURL},
+	}, users, teams)
+
+	provenance.Source("users", "1", "name") // usersURL
+
+The zero value is ready to use, and is safe for concurrent use.
+*/
+type Provenance struct {
+	mu      sync.RWMutex
+	sources map[string]map[string]string
+}
+
+// Source returns the source of an object's attribute or relationship, or an
+// empty string if it wasn't recorded.
+func (p *Provenance) Source(resourceType string, id string, member string) string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.sources[resourceKey(resourceType, id)][member]
+}
+
+// Sources returns the recorded source of each of the members of an object.
+func (p *Provenance) Sources(resourceType string, id string) map[string]string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	sources := map[string]string{}
+	for member, source := range p.sources[resourceKey(resourceType, id)] {
+		sources[member] = source
+	}
+
+	return sources
+}
+
+/*
+Merge merges patch onto object as Object.Merge does, recording source as the
+origin of each of the members the patch replaced.
+*/
+func (p *Provenance) Merge(object *Object, patch *Object, source string) *Error {
+	err := object.Merge(patch)
+	if err != nil {
+		return err
+	}
+
+	return p.Record(patch, source)
+}
+
+// Record sets source as the origin of each of the object's attributes and
+// relationships.
+func (p *Provenance) Record(object *Object, source string) *Error {
+	attributes, err := object.attributeMap()
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.sources == nil {
+		p.sources = map[string]map[string]string{}
+	}
+
+	key := resourceKey(object.Type, object.ID)
+	members, exists := p.sources[key]
+	if !exists {
+		members = map[string]string{}
+		p.sources[key] = members
+	}
+
+	for name := range attributes {
+		members[name] = source
+	}
+
+	for name := range object.Relationships {
+		members[name] = source
+	}
+
+	return nil
+}
+
+// mergeSource names the source of the document at index i of a Merge
+func (o *MergeOptions) mergeSource(i int, document *Document) string {
+	if i < len(o.Sources) {
+		return o.Sources[i]
+	}
+
+	if document.Links != nil && document.Links.Self != nil {
+		return document.Links.Self.HREF
+	}
+
+	return strconv.Itoa(i)
+}
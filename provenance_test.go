@@ -0,0 +1,52 @@
+package jsh
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestProvenance(t *testing.T) {
+
+	Convey("Provenance Tests", t, func() {
+
+		provenance := &Provenance{}
+
+		object, _ := NewObject("1", "users", map[string]string{"name": "bob", "email": "bob@example.com"})
+		object.AddToOneRelationship("team", "teams", "1")
+
+		Convey("->Record()", func() {
+			So(provenance.Record(object, "crm"), ShouldBeNil)
+			So(provenance.Source("users", "1", "name"), ShouldEqual, "crm")
+			So(provenance.Source("users", "1", "team"), ShouldEqual, "crm")
+			So(provenance.Source("users", "2", "name"), ShouldEqual, "")
+		})
+
+		Convey("->Merge()", func() {
+			So(provenance.Record(object, "crm"), ShouldBeNil)
+
+			patch, _ := NewObject("1", "users", map[string]string{"email": "robert@example.com"})
+			So(provenance.Merge(object, patch, "PATCH /users/1"), ShouldBeNil)
+
+			So(provenance.Sources("users", "1"), ShouldResemble, map[string]string{
+				"name":  "crm",
+				"email": "PATCH /users/1",
+				"team":  "crm",
+			})
+
+			email, _ := object.AttributeString("email")
+			So(email, ShouldEqual, "robert@example.com")
+		})
+
+		Convey("document merging", func() {
+			team, _ := NewObject("1", "teams", map[string]string{"name": "a"})
+			teams := Build(List{team})
+			teams.Links = &Links{Self: &Link{HREF: "http://teams.internal/teams"}}
+
+			_, err := Merge(&MergeOptions{Provenance: provenance, Sources: []string{"users"}}, Build(List{object}), teams)
+			So(err, ShouldBeNil)
+			So(provenance.Source("users", "1", "name"), ShouldEqual, "users")
+			So(provenance.Source("teams", "1", "name"), ShouldEqual, "http://teams.internal/teams")
+		})
+	})
+}
@@ -0,0 +1,61 @@
+package jsh
+
+/*
+LinksProvider can be implemented by the model passed to NewObject or
+Object.Marshal in order to keep computed links next to the model rather than in
+handler code:
+
+	func (u *User) JSONAPILinks() map[string]*jsh.Link {
+		return map[string]*jsh.Link{
+			"avatar": {HREF: u.AvatarURL()},
+		}
+	}
+*/
+type LinksProvider interface {
+	JSONAPILinks() map[string]*Link
+}
+
+// MetaProvider can be implemented by a model to supply resource level meta.
+type MetaProvider interface {
+	JSONAPIMeta() map[string]interface{}
+}
+
+// RelationshipsProvider can be implemented by a model to supply its
+// relationships.
+type RelationshipsProvider interface {
+	JSONAPIRelationships() map[string]*Relationship
+}
+
+// applyProviders merges anything the model provides into the object, values
+// from the model take precedence over those already set
+func applyProviders(object *Object, model interface{}) {
+	if provider, ok := model.(LinksProvider); ok {
+		if object.Links == nil {
+			object.Links = map[string]*Link{}
+		}
+
+		for name, link := range provider.JSONAPILinks() {
+			object.Links[name] = link
+		}
+	}
+
+	if provider, ok := model.(MetaProvider); ok {
+		if object.Meta == nil {
+			object.Meta = map[string]interface{}{}
+		}
+
+		for key, value := range provider.JSONAPIMeta() {
+			object.Meta[key] = value
+		}
+	}
+
+	if provider, ok := model.(RelationshipsProvider); ok {
+		if object.Relationships == nil {
+			object.Relationships = map[string]*Relationship{}
+		}
+
+		for name, relationship := range provider.JSONAPIRelationships() {
+			object.Relationships[name] = relationship
+		}
+	}
+}
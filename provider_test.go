@@ -0,0 +1,53 @@
+package jsh
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type providerModel struct {
+	Name string `json:"name"`
+}
+
+func (p *providerModel) JSONAPILinks() map[string]*Link {
+	return map[string]*Link{"profile": {HREF: "/profiles/" + p.Name}}
+}
+
+func (p *providerModel) JSONAPIMeta() map[string]interface{} {
+	return map[string]interface{}{"editable": true}
+}
+
+func (p *providerModel) JSONAPIRelationships() map[string]*Relationship {
+	return map[string]*Relationship{
+		"company": {Data: ResourceLinkage{{Type: "companies", ID: "1"}}},
+	}
+}
+
+func TestProvider(t *testing.T) {
+
+	Convey("Provider Tests", t, func() {
+
+		Convey("->NewObject()", func() {
+			object, err := NewObject("1", "users", &providerModel{Name: "bob"})
+			So(err, ShouldBeNil)
+			So(object.Links["profile"].HREF, ShouldEqual, "/profiles/bob")
+			So(object.Meta["editable"], ShouldEqual, true)
+			So(object.Relationships["company"].Data[0].ID, ShouldEqual, "1")
+		})
+
+		Convey("->Marshal()", func() {
+			object := &Object{ID: "1", Type: "users"}
+			err := object.Marshal(&providerModel{Name: "alice"})
+			So(err, ShouldBeNil)
+			So(object.Links["profile"].HREF, ShouldEqual, "/profiles/alice")
+		})
+
+		Convey("should ignore models that don't provide anything", func() {
+			object, err := NewObject("1", "users", map[string]string{"name": "bob"})
+			So(err, ShouldBeNil)
+			So(object.Meta, ShouldBeNil)
+			So(object.Links, ShouldBeEmpty)
+		})
+	})
+}
@@ -0,0 +1,140 @@
+package jsh
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Headers carrying quota information, following the IETF RateLimit header
+// fields draft
+const (
+	QuotaLimitHeader     = "RateLimit-Limit"
+	QuotaRemainingHeader = "RateLimit-Remaining"
+	QuotaResetHeader     = "RateLimit-Reset"
+)
+
+// Quota describes a client's allowance for requests against a resource type.
+type Quota struct {
+	// Limit is the number of requests allowed per window
+	Limit int `json:"limit"`
+	// Remaining is the number of requests left in the current window
+	Remaining int `json:"remaining"`
+	// Reset is when the current window ends and Remaining returns to Limit
+	Reset time.Time `json:"reset"`
+}
+
+/*
+QuotaProvider supplies the quota of the client making a request, so that
+responses can tell API consumers how much of their allowance remains and they
+can throttle themselves. Declare one per resource type when registering it, or
+set DefaultQuotaProvider:
+
+	jsh.Register(&jsh.Resource{
+		Type: "reports",
+		Quota: jsh.QuotaProviderFunc(func(r *http.Request, resourceType string) *jsh.Quota {
+			return limiter.Quota(apiKey(r), resourceType)
+		}),
+	})
+
+The quota is sent in the RateLimit headers and in the "quota" member of the
+document's top-level meta, with reset given as the number of seconds until the
+window ends. ResourceType is the type of the primary data, empty for documents
+without any, such as errors. Return nil to send no quota information.
+*/
+type QuotaProvider interface {
+	Quota(r *http.Request, resourceType string) *Quota
+}
+
+// QuotaProviderFunc adapts a function to a QuotaProvider.
+type QuotaProviderFunc func(r *http.Request, resourceType string) *Quota
+
+// Quota calls f(r, resourceType).
+func (f QuotaProviderFunc) Quota(r *http.Request, resourceType string) *Quota {
+	return f(r, resourceType)
+}
+
+// DefaultQuotaProvider applies to resource types without a declared Quota,
+// and to documents without primary data. Leave nil to send no quota
+// information.
+var DefaultQuotaProvider QuotaProvider
+
+// quotaProviderFor returns the QuotaProvider of a resource type, or nil
+func quotaProviderFor(resourceType string) QuotaProvider {
+	resource := Registered(resourceType)
+	if resource != nil && resource.Quota != nil {
+		return resource.Quota
+	}
+
+	return DefaultQuotaProvider
+}
+
+/*
+SetQuota sends quota information with a response, for handlers that track
+quotas themselves rather than through a QuotaProvider:
+
+	jsh.SetQuota(w, &jsh.Quota{Limit: 100, Remaining: 42, Reset: window.End})
+	jsh.Send(w, r, object)
+*/
+func SetQuota(w http.ResponseWriter, quota *Quota) {
+	w.Header().Set(QuotaLimitHeader, strconv.Itoa(quota.Limit))
+	w.Header().Set(QuotaRemainingHeader, strconv.Itoa(quota.Remaining))
+	w.Header().Set(QuotaResetHeader, strconv.Itoa(quota.resetSeconds(time.Now())))
+}
+
+// resetSeconds returns the number of whole seconds until the quota resets
+func (q *Quota) resetSeconds(now time.Time) int {
+	seconds := math.Ceil(q.Reset.Sub(now).Seconds())
+	if seconds < 0 {
+		return 0
+	}
+
+	return int(seconds)
+}
+
+// applyQuota sends the quota of the request, unless the handler already set
+// one, and copies it into the document's meta
+func applyQuota(w http.ResponseWriter, r *http.Request, document *Document) {
+	if w.Header().Get(QuotaLimitHeader) == "" {
+		resourceType := ""
+		if object := document.First(); object != nil {
+			resourceType = object.Type
+		}
+
+		provider := quotaProviderFor(resourceType)
+		if provider == nil {
+			return
+		}
+
+		quota := provider.Quota(r, resourceType)
+		if quota == nil {
+			return
+		}
+		SetQuota(w, quota)
+	}
+
+	meta := metaMap(document.Meta)
+	if meta == nil {
+		if document.Meta != nil {
+			// meta that isn't an object can't carry the quota
+			return
+		}
+		meta = map[string]interface{}{}
+	}
+
+	quota := map[string]interface{}{}
+	for member, header := range map[string]string{
+		"limit":     QuotaLimitHeader,
+		"remaining": QuotaRemainingHeader,
+		"reset":     QuotaResetHeader,
+	} {
+		value, err := strconv.Atoi(w.Header().Get(header))
+		if err == nil {
+			quota[member] = value
+		}
+	}
+
+	meta["quota"] = quota
+	document.Meta = meta
+}
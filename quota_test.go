@@ -0,0 +1,77 @@
+package jsh
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestQuota(t *testing.T) {
+
+	Convey("Quota Tests", t, func() {
+
+		request := &http.Request{Method: "GET", Header: http.Header{}}
+		object, _ := NewObject("1", "reports", map[string]string{"name": "q3"})
+
+		quotaMeta := func(writer *httptest.ResponseRecorder) map[string]int {
+			doc := struct {
+				Meta map[string]map[string]int `json:"meta"`
+			}{}
+			So(json.Unmarshal(writer.Body.Bytes(), &doc), ShouldBeNil)
+			return doc.Meta["quota"]
+		}
+
+		Convey("->SetQuota()", func() {
+			writer := httptest.NewRecorder()
+			SetQuota(writer, &Quota{Limit: 100, Remaining: 42, Reset: time.Now().Add(30 * time.Second)})
+
+			err := Send(writer, request, object)
+			So(err, ShouldBeNil)
+			So(writer.Header().Get(QuotaLimitHeader), ShouldEqual, "100")
+			So(writer.Header().Get(QuotaRemainingHeader), ShouldEqual, "42")
+			So(writer.Header().Get(QuotaResetHeader), ShouldEqual, "30")
+			So(quotaMeta(writer), ShouldResemble, map[string]int{"limit": 100, "remaining": 42, "reset": 30})
+		})
+
+		Convey("should use the resource's provider", func() {
+			requested := ""
+			Register(&Resource{
+				Type: "reports",
+				Quota: QuotaProviderFunc(func(r *http.Request, resourceType string) *Quota {
+					requested = resourceType
+					return &Quota{Limit: 10, Remaining: 0, Reset: time.Now().Add(-time.Second)}
+				}),
+			})
+			Reset(func() { Unregister("reports") })
+
+			writer := httptest.NewRecorder()
+			Send(writer, request, object)
+			So(requested, ShouldEqual, "reports")
+			So(quotaMeta(writer), ShouldResemble, map[string]int{"limit": 10, "remaining": 0, "reset": 0})
+		})
+
+		Convey("should fall back to the DefaultQuotaProvider for errors", func() {
+			DefaultQuotaProvider = QuotaProviderFunc(func(r *http.Request, resourceType string) *Quota {
+				return &Quota{Limit: 5, Remaining: 0, Reset: time.Now()}
+			})
+			Reset(func() { DefaultQuotaProvider = nil })
+
+			writer := httptest.NewRecorder()
+			Send(writer, request, &Error{Title: "Too Many Requests", Status: http.StatusTooManyRequests})
+			So(writer.Code, ShouldEqual, http.StatusTooManyRequests)
+			So(writer.Header().Get(QuotaRemainingHeader), ShouldEqual, "0")
+			So(quotaMeta(writer)["limit"], ShouldEqual, 5)
+		})
+
+		Convey("should send nothing without a quota", func() {
+			writer := httptest.NewRecorder()
+			Send(writer, request, object)
+			So(writer.Header().Get(QuotaLimitHeader), ShouldBeEmpty)
+			So(writer.Body.String(), ShouldNotContainSubstring, "quota")
+		})
+	})
+}
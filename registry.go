@@ -0,0 +1,423 @@
+package jsh
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// BaseURL is prepended to links that jsh generates, such as relationship links.
+// Leave empty to generate root relative links, or set URLs for prefixed or
+// pluralized paths.
+var BaseURL = ""
+
+/*
+Resource declares per-type behavior that jsh applies whenever objects of that
+type are sent or parsed. Register each of your resource types once at startup:
+
+	jsh.Register(&jsh.Resource{
+		Type:              "articles",
+		RelationshipLinks: true,
+	})
+*/
+type Resource struct {
+	// Type is the JSON API resource type the declaration applies to
+	Type string
+	// RelationshipLinks enables generation of "self" and "related" links for
+	// each of the resource's relationships when sending, such as
+	// /articles/1/relationships/author and /articles/1/author.
+	RelationshipLinks bool
+	// Computed attributes are added to each object of this type when it is sent,
+	// and rejected if a client attempts to write them.
+	Computed map[string]ComputedAttribute
+	// Deprecated maps the names of deprecated attributes and relationships to a
+	// message for clients, such as what to use instead. Clients writing a
+	// deprecated member are logged.
+	Deprecated map[string]string
+	// DeprecationMeta adds a "deprecated" listing of the deprecated members
+	// present on an object to its meta when it is sent.
+	DeprecationMeta bool
+	// Relationships declares the resource's relationships, which RelatedID and
+	// RelatedIDs check objects against.
+	Relationships map[string]RelationshipDeclaration
+	// MetaOnly declares that objects of this type carry no attributes, only
+	// meta, such as permission descriptors. Clients writing attributes to a
+	// meta-only type are rejected.
+	MetaOnly bool
+	// CachePolicies declares the caching headers sent for each operation on the
+	// resource, see CachePolicy.
+	CachePolicies map[Operation]*CachePolicy
+	// Includes maps relationship names to the resolvers used by ResolveIncludes
+	// to fetch related resources.
+	Includes map[string]IncludeResolver
+	// Limits bounds the size of the attributes clients may write, see
+	// AttributeLimits.
+	Limits *AttributeLimits
+	// IDTranslator converts between the public and internal IDs of the
+	// resource, see IDTranslator.
+	IDTranslator IDTranslator
+	// Stats describes the resource's typical objects for EstimatePayload, see
+	// ResourceStats.
+	Stats *ResourceStats
+	// Schema declares the attributes the resource accepts, see Schema.
+	Schema *Schema
+	// Quota supplies the quota information sent with the resource, see
+	// QuotaProvider.
+	Quota QuotaProvider
+	// Deprecation declares the whole resource type deprecated, see
+	// TypeDeprecation.
+	Deprecation *TypeDeprecation
+	// Externalization moves oversized attributes out of documents when they
+	// are sent, see Externalization.
+	Externalization *Externalization
+	// Serializers transform the attributes of objects of this type when they
+	// are sent, after the package level Serializers, see AttributeSerializer.
+	Serializers []AttributeSerializer
+	// Policy restricts the attributes and relationships each caller role may
+	// see and write, see FieldPolicy.
+	Policy *FieldPolicy
+	// ServerManaged declares the attributes only the server sets, which clients
+	// writing them are rejected or stripped of, see ServerManaged.
+	ServerManaged *ServerManaged
+	// Defaults maps attribute names to the values objects created without them
+	// get, static or computed by a DefaultFunc.
+	Defaults map[string]interface{}
+}
+
+// RelationshipDeclaration describes a relationship of a registered resource.
+type RelationshipDeclaration struct {
+	// Type is the resource type the relationship links to
+	Type string
+	// Types lists the resource types a polymorphic relationship links to, in
+	// place of Type, such as the "articles" and "videos" of a "subject"
+	Types       []string
+	Cardinality Cardinality
+}
+
+// relatedTypes returns the resource types the relationship links to
+func (d RelationshipDeclaration) relatedTypes() []string {
+	if len(d.Types) > 0 {
+		return d.Types
+	}
+	if d.Type == "" {
+		return nil
+	}
+
+	return []string{d.Type}
+}
+
+/*
+ComputedAttribute calculates a virtual attribute at send time, such as a full
+name or a permission flag, without it needing to exist on the storage model:
+
+	jsh.Register(&jsh.Resource{
+		Type: "users",
+		Computed: map[string]jsh.ComputedAttribute{
+			"can_edit": func(r *http.Request, user *jsh.Object) (interface{}, *jsh.Error) {
+				return currentUser(r).ID == user.ID, nil
+			},
+		},
+	})
+*/
+type ComputedAttribute func(r *http.Request, object *Object) (interface{}, *Error)
+
+var registry = struct {
+	sync.RWMutex
+	resources map[string]*Resource
+}{resources: map[string]*Resource{}}
+
+// Register adds a resource declaration, replacing any existing declaration for
+// the same type.
+func Register(resource *Resource) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	registry.resources[resource.Type] = resource
+}
+
+// Unregister removes the declaration for a resource type.
+func Unregister(resourceType string) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	delete(registry.resources, resourceType)
+}
+
+// Registered returns the declaration for a resource type, or nil if it hasn't
+// been registered.
+func Registered(resourceType string) *Resource {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	return registry.resources[resourceType]
+}
+
+// registeredResources returns the registered declarations sorted by type
+func registeredResources() []*Resource {
+	registry.RLock()
+	resources := make([]*Resource, 0, len(registry.resources))
+	for _, resource := range registry.resources {
+		resources = append(resources, resource)
+	}
+	registry.RUnlock()
+
+	sort.Slice(resources, func(i, j int) bool {
+		return resources[i].Type < resources[j].Type
+	})
+
+	return resources
+}
+
+/*
+prepare returns a copy of the document to serialize, holding copies of its
+objects with the registered resource declarations applied to them.
+*/
+func (d *Document) prepare(r *http.Request) (*Document, *Error) {
+	prepared := *d
+
+	var err *Error
+	prepared.Data, err = prepareObjects(r, d.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	prepared.Included, err = prepareObjects(r, d.Included)
+	if err != nil {
+		return nil, err
+	}
+
+	return &prepared, nil
+}
+
+// prepareObjects prepares each of the objects, returning the prepared copies
+// in a new slice
+func prepareObjects(r *http.Request, objects []*Object) ([]*Object, *Error) {
+	if objects == nil {
+		return nil, nil
+	}
+
+	prepared := make([]*Object, len(objects))
+	for i, object := range objects {
+		sent, err := prepareSent(r, object)
+		if err != nil {
+			return nil, err
+		}
+		prepared[i] = sent
+	}
+
+	return prepared, nil
+}
+
+/*
+prepareSent returns a copy of an object being sent with its IDs made public and
+its resource declaration applied. The object itself is left alone, as handlers
+may go on using it or send it again, to callers of other roles for instance.
+*/
+func prepareSent(r *http.Request, object *Object) (*Object, *Error) {
+	if object == nil {
+		return nil, nil
+	}
+
+	sent := object.Copy()
+
+	err := publicObject(sent)
+	if err != nil {
+		return nil, err
+	}
+
+	err = prepareObject(r, sent)
+	if err != nil {
+		return nil, err
+	}
+
+	return sent, nil
+}
+
+// prepareObject applies the object's resource declaration, if there is one, and
+// removes any empty relationships that shouldn't be sent
+func prepareObject(r *http.Request, object *Object) *Error {
+	resource := Registered(object.Type)
+	if resource != nil && resource.RelationshipLinks {
+		addRelationshipLinks(object)
+	}
+
+	// generated links need to be in place to tell which relationships are empty
+	omitEmptyRelationships(object)
+
+	if resource == nil {
+		return serialize(r, nil, object)
+	}
+
+	for name, compute := range resource.Computed {
+		value, err := compute(r, object)
+		if err != nil {
+			return err
+		}
+
+		err = object.SetAttribute(name, value)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := serialize(r, resource, object)
+	if err != nil {
+		return err
+	}
+
+	if resource.Policy != nil {
+		err := resource.Policy.redact(r, object)
+		if err != nil {
+			return err
+		}
+	}
+
+	if resource.Externalization != nil {
+		err := resource.Externalization.apply(r, object)
+		if err != nil {
+			return err
+		}
+	}
+
+	if resource.DeprecationMeta {
+		addDeprecationMeta(object, resource.Deprecated)
+	}
+
+	return nil
+}
+
+/*
+acceptObject applies the object's resource declaration to an object that has
+just been parsed, rejecting anything the declaration forbids clients to write.
+Its Policy is checked against the attributes the client sent, before defaults
+are added, for the caller of the request ctx belongs to, or not at all if ctx
+is nil.
+*/
+func acceptObject(ctx context.Context, method string, object *Object) *Error {
+	resource := Registered(object.Type)
+	if resource == nil || !isWrite(method) {
+		return nil
+	}
+
+	if resource.MetaOnly && object.HasAttributes() {
+		err := &Error{
+			Title:  "Invalid Attribute",
+			Detail: fmt.Sprintf("Resources of type '%s' do not accept attributes", object.Type),
+			Status: 422,
+		}
+		err.Source.Pointer = "/data/attributes"
+		return err
+	}
+
+	if resource.ServerManaged != nil {
+		err := resource.ServerManaged.accept(object)
+		if err != nil {
+			return err
+		}
+	}
+
+	if ctx != nil && resource.Policy != nil {
+		err := resource.Policy.checkWrite(ctx, object)
+		if err != nil {
+			return err
+		}
+	}
+
+	if resource.Limits != nil {
+		err := resource.Limits.check(object)
+		if err != nil {
+			return err
+		}
+	}
+
+	if method == "POST" && len(resource.Defaults) > 0 {
+		err := applyDefaults(resource.Defaults, object)
+		if err != nil {
+			return err
+		}
+	}
+
+	if resource.Schema != nil {
+		err := resource.Schema.check(method, object)
+		if err != nil {
+			return err
+		}
+	}
+
+	for name := range resource.Computed {
+		if object.HasAttribute(name) {
+			return InputError("Computed attributes cannot be written", name)
+		}
+	}
+
+	for name := range resource.Deprecated {
+		if hasMember(object, name) {
+			log.Printf("jsh: %s request wrote deprecated member '%s' of type '%s'", method, name, object.Type)
+		}
+	}
+
+	return nil
+}
+
+// hasMember returns true if the object has an attribute or relationship of the
+// given name
+func hasMember(object *Object, name string) bool {
+	if _, exists := object.Relationships[name]; exists {
+		return true
+	}
+
+	return object.HasAttribute(name)
+}
+
+// addDeprecationMeta lists the deprecated members present on the object in
+// meta.deprecated
+func addDeprecationMeta(object *Object, deprecated map[string]string) {
+	present := map[string]string{}
+	for name, message := range deprecated {
+		if hasMember(object, name) {
+			present[name] = message
+		}
+	}
+
+	if len(present) == 0 {
+		return
+	}
+
+	if object.Meta == nil {
+		object.Meta = map[string]interface{}{}
+	}
+
+	object.Meta["deprecated"] = present
+}
+
+// isWrite returns true for request methods that carry resources to be stored
+func isWrite(method string) bool {
+	return method == "POST" || method == "PATCH"
+}
+
+// addRelationshipLinks sets any missing self and related links on an object's
+// relationships
+func addRelationshipLinks(object *Object) {
+	if object.ID == "" {
+		return
+	}
+
+	urls := urlBuilder()
+
+	for name, relationship := range object.Relationships {
+		if relationship.Links == nil {
+			relationship.Links = &Links{}
+		}
+
+		if relationship.Links.Self == nil {
+			relationship.Links.Self = &Link{HREF: urls.Relationship(object.Type, object.ID, name)}
+		}
+
+		if relationship.Links.Related == nil {
+			relationship.Links.Related = &Link{HREF: urls.Related(object.Type, object.ID, name)}
+		}
+	}
+}
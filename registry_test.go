@@ -0,0 +1,178 @@
+package jsh
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRegistry(t *testing.T) {
+
+	Convey("Registry Tests", t, func() {
+
+		Register(&Resource{
+			Type:              "articles",
+			RelationshipLinks: true,
+			Computed: map[string]ComputedAttribute{
+				"path": func(r *http.Request, object *Object) (interface{}, *Error) {
+					return "/articles/" + object.ID, nil
+				},
+			},
+		})
+		Reset(func() {
+			Unregister("articles")
+			BaseURL = ""
+		})
+
+		Convey("->Registered()", func() {
+			So(Registered("articles"), ShouldNotBeNil)
+			So(Registered("unknown"), ShouldBeNil)
+		})
+
+		Convey("relationship links", func() {
+			object := &Object{ID: "1", Type: "articles"}
+			object.AddToOneRelationship("author", "users", "9")
+
+			Convey("should be generated when sending", func() {
+				BaseURL = "https://api.example.com/"
+
+				writer := httptest.NewRecorder()
+				err := Send(writer, &http.Request{Method: "GET"}, object)
+				So(err, ShouldBeNil)
+				So(object.Relationships["author"].Links, ShouldBeNil)
+
+				links := sentObject(writer).Relationships["author"].Links
+				So(links.Self.HREF, ShouldEqual, "https://api.example.com/articles/1/relationships/author")
+				So(links.Related.HREF, ShouldEqual, "https://api.example.com/articles/1/author")
+			})
+
+			Convey("should not replace existing links", func() {
+				object.Relationships["author"].Links = &Links{Related: &Link{HREF: "/custom"}}
+				addRelationshipLinks(object)
+
+				links := object.Relationships["author"].Links
+				So(links.Self.HREF, ShouldEqual, "/articles/1/relationships/author")
+				So(links.Related.HREF, ShouldEqual, "/custom")
+			})
+
+			Convey("should not be generated for unregistered types", func() {
+				object.Type = "posts"
+
+				writer := httptest.NewRecorder()
+				err := Send(writer, &http.Request{Method: "GET"}, object)
+				So(err, ShouldBeNil)
+				So(sentObject(writer).Relationships["author"].Links, ShouldBeNil)
+			})
+		})
+
+		Convey("computed attributes", func() {
+
+			Convey("should be added when sending", func() {
+				object := &Object{ID: "1", Type: "articles"}
+
+				writer := httptest.NewRecorder()
+				err := Send(writer, &http.Request{Method: "GET"}, object)
+				So(err, ShouldBeNil)
+				So(object.HasAttributes(), ShouldBeFalse)
+
+				path, attrErr := sentObject(writer).AttributeString("path")
+				So(attrErr, ShouldBeNil)
+				So(path, ShouldEqual, "/articles/1")
+			})
+
+			Convey("should be rejected when written", func() {
+				req, reqErr := testRequest([]byte(`{"data": {"type": "articles", "attributes": {"path": "/hack"}}}`))
+				So(reqErr, ShouldBeNil)
+				req.Method = "POST"
+
+				_, err := ParseObject(req)
+				So(err, ShouldNotBeNil)
+				So(err.Status, ShouldEqual, 422)
+				So(err.Source.Pointer, ShouldEqual, "/data/attributes/path")
+			})
+
+			Convey("should be accepted when read", func() {
+				req, reqErr := testRequest([]byte(`{"data": {"type": "articles", "id": "1", "attributes": {"path": "/articles/1"}}}`))
+				So(reqErr, ShouldBeNil)
+
+				_, err := ParseObject(req)
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("deprecated members", func() {
+			Register(&Resource{
+				Type:            "users",
+				Deprecated:      map[string]string{"nickname": "Use display_name instead"},
+				DeprecationMeta: true,
+			})
+			Reset(func() { Unregister("users") })
+
+			Convey("should be listed in meta when sending", func() {
+				object, objErr := NewObject("1", "users", map[string]string{"nickname": "bobby"})
+				So(objErr, ShouldBeNil)
+
+				writer := httptest.NewRecorder()
+				err := Send(writer, &http.Request{Method: "GET"}, object)
+				So(err, ShouldBeNil)
+				So(object.Meta, ShouldBeNil)
+				So(sentObject(writer).Meta["deprecated"], ShouldResemble, map[string]interface{}{"nickname": "Use display_name instead"})
+			})
+
+			Convey("should not be listed when absent", func() {
+				object, objErr := NewObject("1", "users", map[string]string{"name": "bob"})
+				So(objErr, ShouldBeNil)
+
+				writer := httptest.NewRecorder()
+				err := Send(writer, &http.Request{Method: "GET"}, object)
+				So(err, ShouldBeNil)
+				So(sentObject(writer).Meta, ShouldBeNil)
+			})
+
+			Convey("should still be accepted when written", func() {
+				req, reqErr := testRequest([]byte(`{"data": {"type": "users", "attributes": {"nickname": "bobby"}}}`))
+				So(reqErr, ShouldBeNil)
+				req.Method = "POST"
+
+				_, err := ParseObject(req)
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("meta-only resources", func() {
+			Register(&Resource{Type: "permissions", MetaOnly: true})
+			Reset(func() { Unregister("permissions") })
+
+			Convey("should be accepted without attributes", func() {
+				req, reqErr := testRequest([]byte(`{"data": {"type": "permissions", "meta": {"can_edit": true}}}`))
+				So(reqErr, ShouldBeNil)
+				req.Method = "POST"
+
+				object, err := ParseObject(req)
+				So(err, ShouldBeNil)
+				So(object.Meta["can_edit"], ShouldEqual, true)
+			})
+
+			Convey("should reject written attributes", func() {
+				req, reqErr := testRequest([]byte(`{"data": {"type": "permissions", "attributes": {"can_edit": true}}}`))
+				So(reqErr, ShouldBeNil)
+				req.Method = "POST"
+
+				_, err := ParseObject(req)
+				So(err, ShouldNotBeNil)
+				So(err.Status, ShouldEqual, 422)
+				So(err.Source.Pointer, ShouldEqual, "/data/attributes")
+			})
+		})
+	})
+}
+
+// sentObject decodes the primary data object of a sent response
+func sentObject(writer *httptest.ResponseRecorder) *Object {
+	document := &Document{}
+	So(json.Unmarshal(writer.Body.Bytes(), document), ShouldBeNil)
+	return document.First()
+}
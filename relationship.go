@@ -1,17 +1,219 @@
 package jsh
 
 import (
+	"bytes"
 	"fmt"
+	"strings"
 
 	"encoding/json"
 )
 
-// Relationship represents a reference from the resource object in which it's
-// defined to other resource objects.
+// Cardinality distinguishes to-one relationships from to-many relationships.
+type Cardinality int
+
+const (
+	// ToMany relationships marshal their linkage as an array
+	ToMany Cardinality = iota
+	// ToOne relationships marshal their linkage as a single resource identifier,
+	// or null if empty
+	ToOne
+)
+
+// LinkageState distinguishes relationships that leave their linkage out from
+// those clearing or setting it, since a PATCH must only change the latter.
+type LinkageState int
+
+const (
+	// LinkageAbsent relationships have no "data" member, only links or meta,
+	// or are missing from the object altogether
+	LinkageAbsent LinkageState = iota
+	// LinkageCleared relationships have "data" set to null for to-one
+	// relationships, or [] for to-many relationships
+	LinkageCleared
+	// LinkageSet relationships link one or more resources
+	LinkageSet
+)
+
+// EmptyRelationshipMode determines how relationships with cleared linkage are
+// sent.
+type EmptyRelationshipMode int
+
+const (
+	// OmitEmptyToManyLinkage sends empty to-one relationships with "data" set to
+	// null, and empty to-many relationships without "data", as jsh always has
+	OmitEmptyToManyLinkage EmptyRelationshipMode = iota
+	// EmitEmptyLinkage sends empty relationships with "data" set to null for
+	// to-one relationships, or [] for to-many relationships
+	EmitEmptyLinkage
+	// OmitEmptyRelationships leaves empty relationships out of the object
+	OmitEmptyRelationships
+	// EmptyRelationshipLinksOnly sends empty relationships without "data", so
+	// only their links and meta remain. Empty relationships without either are
+	// left out since they would be invalid.
+	EmptyRelationshipLinksOnly
+)
+
+/*
+EmptyRelationships controls how relationships with cleared linkage, see
+LinkageCleared, are sent. Clients differ in what they expect, Ember Data for
+example relies on the explicit empty linkage of EmitEmptyLinkage, while others
+treat "data" as a signal that the relationship was loaded and prefer it be
+omitted. The default, OmitEmptyToManyLinkage, keeps the output of earlier
+versions. Relationships with absent linkage are always sent without "data".
+*/
+var EmptyRelationships = OmitEmptyToManyLinkage
+
+/*
+Relationship represents a reference from the resource object in which it's
+defined to other resource objects. Data is nil when the relationship's linkage
+is absent, and empty but not nil when it is cleared, see State.
+*/
 type Relationship struct {
 	Links *Links                 `json:"links,omitempty"`
 	Data  ResourceLinkage        `json:"data,omitempty"`
 	Meta  map[string]interface{} `json:"meta,omitempty"`
+	// Cardinality is detected from the shape of "data" when parsing, and
+	// determines how Data is marshaled.
+	Cardinality Cardinality `json:"-"`
+	// Extra holds unrecognized members, see Document.Extra
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+/*
+MarshalJSON marshals to-one relationship linkage as a single resource identifier
+object rather than an array. Absent linkage is left out, and cleared linkage is
+marshaled according to EmptyRelationships. Extra members are added to the
+result.
+*/
+func (r Relationship) MarshalJSON() ([]byte, error) {
+	raw, err := r.marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	return addMembers(raw, r.Extra, relationshipMembers)
+}
+
+// marshal marshals the relationship's own members
+func (r Relationship) marshal() ([]byte, error) {
+	type MarshalRelationship Relationship
+	relationship := MarshalRelationship(r)
+
+	if !r.sendsLinkage() {
+		// Data is empty, which its omitempty leaves out
+		return JSON.Marshal(relationship)
+	}
+
+	if r.Cardinality != ToOne {
+		return JSON.Marshal(struct {
+			MarshalRelationship
+			Data ResourceLinkage `json:"data"`
+		}{
+			MarshalRelationship: relationship,
+			Data:                append(ResourceLinkage{}, r.Data...),
+		})
+	}
+
+	var data *ResourceIdentifier
+	if len(r.Data) > 0 {
+		data = r.Data[0]
+	}
+
+	return JSON.Marshal(struct {
+		MarshalRelationship
+		Data *ResourceIdentifier `json:"data"`
+	}{
+		MarshalRelationship: relationship,
+		Data:                data,
+	})
+}
+
+// sendsLinkage returns false if the relationship is sent without "data", as
+// are those with absent linkage, and cleared ones if EmptyRelationships says so
+func (r *Relationship) sendsLinkage() bool {
+	switch r.State() {
+	case LinkageAbsent:
+		return false
+	case LinkageCleared:
+		switch EmptyRelationships {
+		case EmptyRelationshipLinksOnly:
+			return false
+		case OmitEmptyToManyLinkage:
+			return r.Cardinality == ToOne
+		}
+	}
+
+	return true
+}
+
+/*
+UnmarshalJSON sets the relationship's Cardinality based on whether "data" is an
+object (or null), or an array.
+*/
+func (r *Relationship) UnmarshalJSON(data []byte) error {
+	type UnmarshalRelationship Relationship
+
+	raw := struct {
+		*UnmarshalRelationship
+		Data json.RawMessage `json:"data"`
+	}{UnmarshalRelationship: (*UnmarshalRelationship)(r)}
+
+	err := JSON.Unmarshal(data, &raw)
+	if err != nil {
+		return err
+	}
+
+	linkage := bytes.TrimSpace(raw.Data)
+	if len(linkage) == 0 {
+		return nil
+	}
+
+	r.Cardinality = ToMany
+	if linkage[0] != '[' {
+		r.Cardinality = ToOne
+	}
+
+	err = r.Data.UnmarshalJSON(linkage)
+	if err != nil {
+		return err
+	}
+
+	// keep "data": null apart from absent linkage
+	if r.Data == nil {
+		r.Data = ResourceLinkage{}
+	}
+
+	return nil
+}
+
+/*
+State returns whether the relationship's linkage is absent, cleared, or set, so
+that handlers of PATCH requests only change relationships the client linked:
+
+	for name, relationship := range patch.Relationships {
+		switch relationship.State() {
+		case jsh.LinkageCleared:
+			storage.Unlink(patch.ID, name)
+		case jsh.LinkageSet:
+			storage.Link(patch.ID, name, relationship.Data)
+		}
+	}
+*/
+func (r *Relationship) State() LinkageState {
+	switch {
+	case r == nil || r.Data == nil:
+		return LinkageAbsent
+	case len(r.Data) == 0:
+		return LinkageCleared
+	}
+
+	return LinkageSet
+}
+
+// RelationshipState returns the State of one of the object's relationships,
+// LinkageAbsent if the object doesn't have it.
+func (o *Object) RelationshipState(name string) LinkageState {
+	return o.Relationships[name].State()
 }
 
 // ResourceLinkage is a typedef around a slice of resource identifiers. This
@@ -41,7 +243,7 @@ func (rl *ResourceLinkage) UnmarshalJSON(data []byte) error {
 
 	newLinkage := UnmarshalLinkage{}
 
-	err := json.Unmarshal(data, &newLinkage)
+	err := JSON.Unmarshal(data, &newLinkage)
 	if err != nil {
 		return err
 	}
@@ -51,3 +253,217 @@ func (rl *ResourceLinkage) UnmarshalJSON(data []byte) error {
 
 	return nil
 }
+
+/*
+AddToOneRelationship sets a to-one relationship on the object, replacing any
+existing to-one relationship of the same name. Pass an empty id to set an empty
+(null) to-one relationship.
+*/
+func (o *Object) AddToOneRelationship(name string, resourceType string, id string) *Error {
+	existing, exists := o.Relationships[name]
+	if exists && existing.Cardinality != ToOne {
+		return ISE(fmt.Sprintf("Relationship '%s' is already set as to-many", name))
+	}
+
+	relationship := &Relationship{Cardinality: ToOne, Data: ResourceLinkage{}}
+	if id != "" {
+		relationship.Data = ResourceLinkage{{Type: resourceType, ID: id}}
+	}
+
+	o.setRelationship(name, relationship)
+	return nil
+}
+
+/*
+AddToManyRelationship appends identifiers to a to-many relationship, creating it
+if necessary. Calling it without identifiers creates an empty relationship.
+*/
+func (o *Object) AddToManyRelationship(name string, identifiers ...*ResourceIdentifier) *Error {
+	for _, identifier := range identifiers {
+		if identifier == nil || identifier.Type == "" || identifier.ID == "" {
+			return ISE(fmt.Sprintf("Relationship '%s' identifiers must have a type and ID", name))
+		}
+	}
+
+	relationship, exists := o.Relationships[name]
+	if !exists {
+		relationship = &Relationship{Cardinality: ToMany, Data: ResourceLinkage{}}
+		o.setRelationship(name, relationship)
+	}
+
+	if relationship.Cardinality != ToMany {
+		return ISE(fmt.Sprintf("Relationship '%s' is already set as to-one", name))
+	}
+
+	relationship.Data = append(relationship.Data, identifiers...)
+	return nil
+}
+
+// RemoveRelationship deletes a relationship from the object if present.
+func (o *Object) RemoveRelationship(name string) {
+	delete(o.Relationships, name)
+}
+
+/*
+ToOne returns the resource identifier of a to-one relationship, or nil if it is
+empty. A 422 error is returned if the relationship is missing or is to-many.
+*/
+func (o *Object) ToOne(name string) (*ResourceIdentifier, *Error) {
+	relationship, exists := o.Relationships[name]
+	if !exists {
+		return nil, RelationshipError("Missing relationship", name)
+	}
+
+	if relationship.Cardinality != ToOne {
+		return nil, RelationshipError("Expected a to-one relationship", name)
+	}
+
+	if len(relationship.Data) == 0 {
+		return nil, nil
+	}
+
+	return relationship.Data[0], nil
+}
+
+/*
+ToMany returns the resource linkage of a to-many relationship. A 422 error is
+returned if the relationship is missing or is to-one.
+*/
+func (o *Object) ToMany(name string) (ResourceLinkage, *Error) {
+	relationship, exists := o.Relationships[name]
+	if !exists {
+		return nil, RelationshipError("Missing relationship", name)
+	}
+
+	if relationship.Cardinality != ToMany {
+		return nil, RelationshipError("Expected a to-many relationship", name)
+	}
+
+	return relationship.Data, nil
+}
+
+/*
+RelatedID returns the ID linked by a to-one relationship, or an empty string if
+the relationship is empty. If the object's type is registered, the relationship
+must be declared as to-one and its linkage must match the declared type:
+
+	jsh.Register(&jsh.Resource{
+		Type: "articles",
+		Relationships: map[string]jsh.RelationshipDeclaration{
+			"author": {Type: "users", Cardinality: jsh.ToOne},
+		},
+	})
+
+	authorID, err := article.RelatedID("author")
+*/
+func (o *Object) RelatedID(name string) (string, *Error) {
+	declaration, err := o.declaredRelationship(name, ToOne)
+	if err != nil {
+		return "", err
+	}
+
+	identifier, err := o.ToOne(name)
+	if err != nil || identifier == nil {
+		return "", err
+	}
+
+	err = checkLinkageType(name, declaration, identifier)
+	if err != nil {
+		return "", err
+	}
+
+	return identifier.ID, nil
+}
+
+/*
+RelatedIDs returns the IDs linked by a to-many relationship. As with RelatedID,
+relationships of registered types are checked against their declaration.
+*/
+func (o *Object) RelatedIDs(name string) ([]string, *Error) {
+	declaration, err := o.declaredRelationship(name, ToMany)
+	if err != nil {
+		return nil, err
+	}
+
+	linkage, err := o.ToMany(name)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(linkage))
+	for _, identifier := range linkage {
+		err = checkLinkageType(name, declaration, identifier)
+		if err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, identifier.ID)
+	}
+
+	return ids, nil
+}
+
+// declaredRelationship looks up the registered declaration of a relationship,
+// returning nil for objects of unregistered types
+func (o *Object) declaredRelationship(name string, cardinality Cardinality) (*RelationshipDeclaration, *Error) {
+	resource := Registered(o.Type)
+	if resource == nil {
+		return nil, nil
+	}
+
+	declaration, exists := resource.Relationships[name]
+	if !exists {
+		return nil, ISE(fmt.Sprintf("Relationship '%s' is not declared for type '%s'", name, o.Type))
+	}
+
+	if declaration.Cardinality != cardinality {
+		return nil, ISE(fmt.Sprintf("Relationship '%s' of type '%s' is not declared with the requested cardinality", name, o.Type))
+	}
+
+	return &declaration, nil
+}
+
+// checkLinkageType ensures a resource identifier matches the declared types
+func checkLinkageType(name string, declaration *RelationshipDeclaration, identifier *ResourceIdentifier) *Error {
+	if declaration == nil {
+		return nil
+	}
+
+	types := declaration.relatedTypes()
+	if len(types) == 0 || contains(types, identifier.Type) {
+		return nil
+	}
+
+	return RelationshipError(
+		fmt.Sprintf("Expected linkage of type '%s', got '%s'", strings.Join(types, "' or '"), identifier.Type),
+		name,
+	)
+}
+
+// omitEmptyRelationships removes the object's cleared relationships that
+// EmptyRelationships says shouldn't be sent
+func omitEmptyRelationships(object *Object) {
+	if EmptyRelationships != OmitEmptyRelationships && EmptyRelationships != EmptyRelationshipLinksOnly {
+		return
+	}
+
+	for name, relationship := range object.Relationships {
+		if relationship.State() != LinkageCleared {
+			continue
+		}
+
+		hasContent := (relationship.Links != nil && *relationship.Links != Links{}) || len(relationship.Meta) > 0
+		if EmptyRelationships == OmitEmptyRelationships || !hasContent {
+			delete(object.Relationships, name)
+		}
+	}
+}
+
+// setRelationship adds a relationship, creating the map if needed
+func (o *Object) setRelationship(name string, relationship *Relationship) {
+	if o.Relationships == nil {
+		o.Relationships = map[string]*Relationship{}
+	}
+
+	o.Relationships[name] = relationship
+}
@@ -1,6 +1,9 @@
 package jsh
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	. "github.com/smartystreets/goconvey/convey"
@@ -32,4 +35,293 @@ func TestRelationship(t *testing.T) {
 			})
 		})
 	})
+
+	Convey("Relationship Tests", t, func() {
+
+		Convey("->UnmarshalJSON()", func() {
+
+			Convey("should detect a to-one relationship", func() {
+				relationship := &Relationship{}
+				err := json.Unmarshal([]byte(`{"data": {"type": "users", "id": "1"}}`), relationship)
+				So(err, ShouldBeNil)
+				So(relationship.Cardinality, ShouldEqual, ToOne)
+				So(len(relationship.Data), ShouldEqual, 1)
+			})
+
+			Convey("should detect an empty to-one relationship", func() {
+				relationship := &Relationship{}
+				err := json.Unmarshal([]byte(`{"data": null}`), relationship)
+				So(err, ShouldBeNil)
+				So(relationship.Cardinality, ShouldEqual, ToOne)
+				So(relationship.Data, ShouldBeEmpty)
+			})
+
+			Convey("should detect a to-many relationship", func() {
+				relationship := &Relationship{}
+				err := json.Unmarshal([]byte(`{"data": [{"type": "users", "id": "1"}]}`), relationship)
+				So(err, ShouldBeNil)
+				So(relationship.Cardinality, ShouldEqual, ToMany)
+			})
+		})
+
+		Convey("->State()", func() {
+			state := func(body string) LinkageState {
+				relationship := &Relationship{}
+				So(json.Unmarshal([]byte(body), relationship), ShouldBeNil)
+				return relationship.State()
+			}
+
+			So(state(`{"links": {"related": {"href": "/users/1/team"}}}`), ShouldEqual, LinkageAbsent)
+			So(state(`{"data": null}`), ShouldEqual, LinkageCleared)
+			So(state(`{"data": []}`), ShouldEqual, LinkageCleared)
+			So(state(`{"data": {"type": "teams", "id": "1"}}`), ShouldEqual, LinkageSet)
+
+			Convey("should be absent for missing relationships", func() {
+				object := &Object{Type: "users", ID: "1"}
+				So(object.RelationshipState("team"), ShouldEqual, LinkageAbsent)
+
+				object.AddToOneRelationship("team", "teams", "")
+				So(object.RelationshipState("team"), ShouldEqual, LinkageCleared)
+			})
+
+			Convey("should survive being parsed and sent", func() {
+				Register(&Resource{Type: "users", IDTranslator: prefixTranslator{}})
+				Reset(func() { Unregister("users") })
+
+				req, reqErr := testRequest([]byte(`{"data": {"type": "articles", "id": "1", "relationships": {
+					"author": {"links": {"related": {"href": "/articles/1/author"}}},
+					"editor": {"data": null},
+					"readers": {"data": [{"type": "users", "id": "pub-7"}]}
+				}}}`))
+				So(reqErr, ShouldBeNil)
+
+				object, err := ParseObject(req)
+				So(err, ShouldBeNil)
+
+				writer := httptest.NewRecorder()
+				So(Send(writer, &http.Request{Method: "GET"}, object), ShouldBeNil)
+				So(writer.Body.String(), ShouldContainSubstring, `"author":{"links":{"related":{"href":"/articles/1/author"}}}`)
+
+				sent := sentObject(writer)
+				So(sent.RelationshipState("author"), ShouldEqual, LinkageAbsent)
+				So(sent.RelationshipState("editor"), ShouldEqual, LinkageCleared)
+				So(sent.RelationshipState("readers"), ShouldEqual, LinkageSet)
+			})
+
+			Convey("should let Merge skip relationships without linkage", func() {
+				object := &Object{Type: "users", ID: "1"}
+				object.AddToOneRelationship("team", "teams", "1")
+
+				patch := &Object{Type: "users", ID: "1"}
+				So(json.Unmarshal([]byte(`{"type": "users", "id": "1", "relationships": {"team": {"links": {"self": {"href": "/x"}}}}}`), patch), ShouldBeNil)
+				So(object.Merge(patch), ShouldBeNil)
+				So(object.RelationshipState("team"), ShouldEqual, LinkageSet)
+
+				So(json.Unmarshal([]byte(`{"type": "users", "id": "1", "relationships": {"team": {"data": null}}}`), patch), ShouldBeNil)
+				So(object.Merge(patch), ShouldBeNil)
+				So(object.RelationshipState("team"), ShouldEqual, LinkageCleared)
+			})
+		})
+
+		Convey("->MarshalJSON()", func() {
+
+			Convey("should marshal to-one linkage as an object", func() {
+				raw, err := json.Marshal(&Relationship{
+					Cardinality: ToOne,
+					Data:        ResourceLinkage{{Type: "users", ID: "1"}},
+				})
+				So(err, ShouldBeNil)
+				So(string(raw), ShouldEqual, `{"data":{"type":"users","id":"1"}}`)
+			})
+
+			Convey("should marshal cleared to-one linkage as null", func() {
+				raw, err := json.Marshal(&Relationship{Cardinality: ToOne, Data: ResourceLinkage{}})
+				So(err, ShouldBeNil)
+				So(string(raw), ShouldEqual, `{"data":null}`)
+			})
+
+			Convey("should leave cleared to-many linkage out by default", func() {
+				raw, err := json.Marshal(&Relationship{Cardinality: ToMany, Data: ResourceLinkage{}})
+				So(err, ShouldBeNil)
+				So(string(raw), ShouldEqual, `{}`)
+			})
+
+			Convey("should marshal cleared to-many linkage as an empty array with EmitEmptyLinkage", func() {
+				EmptyRelationships = EmitEmptyLinkage
+				Reset(func() { EmptyRelationships = OmitEmptyToManyLinkage })
+
+				raw, err := json.Marshal(&Relationship{Cardinality: ToMany, Data: ResourceLinkage{}})
+				So(err, ShouldBeNil)
+				So(string(raw), ShouldEqual, `{"data":[]}`)
+			})
+
+			Convey("should leave absent linkage out", func() {
+				links := &Links{Related: &Link{HREF: "/articles/1/author"}}
+
+				raw, err := json.Marshal(&Relationship{Cardinality: ToOne, Links: links})
+				So(err, ShouldBeNil)
+				So(string(raw), ShouldEqual, `{"links":{"related":{"href":"/articles/1/author"}}}`)
+
+				raw, err = json.Marshal(&Relationship{Cardinality: ToMany, Links: links})
+				So(err, ShouldBeNil)
+				So(string(raw), ShouldEqual, `{"links":{"related":{"href":"/articles/1/author"}}}`)
+			})
+		})
+
+		Convey("EmptyRelationships", func() {
+			object := &Object{ID: "1", Type: "articles"}
+			object.AddToOneRelationship("author", "users", "")
+			object.AddToManyRelationship("tags")
+			object.Relationships["tags"].Links = &Links{Related: &Link{HREF: "/articles/1/tags"}}
+			object.AddToOneRelationship("editor", "users", "2")
+
+			Reset(func() {
+				EmptyRelationships = OmitEmptyToManyLinkage
+			})
+
+			Convey("should keep empty relationships by default", func() {
+				omitEmptyRelationships(object)
+				So(len(object.Relationships), ShouldEqual, 3)
+			})
+
+			Convey("should keep empty relationships with EmitEmptyLinkage", func() {
+				EmptyRelationships = EmitEmptyLinkage
+				omitEmptyRelationships(object)
+				So(len(object.Relationships), ShouldEqual, 3)
+			})
+
+			Convey("should omit empty relationships", func() {
+				EmptyRelationships = OmitEmptyRelationships
+				omitEmptyRelationships(object)
+				So(len(object.Relationships), ShouldEqual, 1)
+				So(object.Relationships["editor"], ShouldNotBeNil)
+			})
+
+			Convey("should not omit relationships with absent linkage", func() {
+				object.Relationships["comments"] = &Relationship{Links: &Links{Related: &Link{HREF: "/articles/1/comments"}}}
+
+				EmptyRelationships = OmitEmptyRelationships
+				omitEmptyRelationships(object)
+				So(object.Relationships["comments"], ShouldNotBeNil)
+			})
+
+			Convey("should emit links only", func() {
+				EmptyRelationships = EmptyRelationshipLinksOnly
+				omitEmptyRelationships(object)
+				So(len(object.Relationships), ShouldEqual, 2)
+				So(object.Relationships["author"], ShouldBeNil)
+
+				raw, err := json.Marshal(object.Relationships["tags"])
+				So(err, ShouldBeNil)
+				So(string(raw), ShouldEqual, `{"links":{"related":{"href":"/articles/1/tags"}}}`)
+			})
+		})
+
+		Convey("builders", func() {
+			object := &Object{ID: "1", Type: "articles"}
+
+			Convey("->AddToOneRelationship()", func() {
+				err := object.AddToOneRelationship("author", "users", "9")
+				So(err, ShouldBeNil)
+
+				author, err := object.ToOne("author")
+				So(err, ShouldBeNil)
+				So(author, ShouldResemble, &ResourceIdentifier{Type: "users", ID: "9"})
+
+				Convey("should not be readable as to-many", func() {
+					_, err := object.ToMany("author")
+					So(err, ShouldNotBeNil)
+					So(err.Source.Pointer, ShouldEqual, "/data/relationships/author")
+				})
+			})
+
+			Convey("->AddToManyRelationship()", func() {
+				err := object.AddToManyRelationship("comments", &ResourceIdentifier{Type: "comments", ID: "1"})
+				So(err, ShouldBeNil)
+
+				err = object.AddToManyRelationship("comments", &ResourceIdentifier{Type: "comments", ID: "2"})
+				So(err, ShouldBeNil)
+
+				comments, err := object.ToMany("comments")
+				So(err, ShouldBeNil)
+				So(len(comments), ShouldEqual, 2)
+
+				Convey("should reject invalid identifiers", func() {
+					err := object.AddToManyRelationship("comments", &ResourceIdentifier{Type: "comments"})
+					So(err, ShouldNotBeNil)
+				})
+
+				Convey("should reject changing cardinality", func() {
+					err := object.AddToOneRelationship("comments", "comments", "3")
+					So(err, ShouldNotBeNil)
+				})
+			})
+
+			Convey("->RemoveRelationship()", func() {
+				object.AddToOneRelationship("author", "users", "9")
+				object.RemoveRelationship("author")
+
+				_, err := object.ToOne("author")
+				So(err, ShouldNotBeNil)
+				So(err.Status, ShouldEqual, 422)
+			})
+		})
+
+		Convey("declared relationship accessors", func() {
+			Register(&Resource{
+				Type: "articles",
+				Relationships: map[string]RelationshipDeclaration{
+					"author": {Type: "users", Cardinality: ToOne},
+					"tags":   {Type: "tags", Cardinality: ToMany},
+				},
+			})
+			Reset(func() { Unregister("articles") })
+
+			object := &Object{ID: "1", Type: "articles"}
+			object.AddToOneRelationship("author", "users", "9")
+			object.AddToManyRelationship("tags", &ResourceIdentifier{Type: "tags", ID: "1"}, &ResourceIdentifier{Type: "tags", ID: "2"})
+
+			Convey("->RelatedID()", func() {
+				id, err := object.RelatedID("author")
+				So(err, ShouldBeNil)
+				So(id, ShouldEqual, "9")
+
+				Convey("should reject linkage of the wrong type", func() {
+					object.AddToOneRelationship("author", "admins", "9")
+
+					_, err := object.RelatedID("author")
+					So(err, ShouldNotBeNil)
+					So(err.Status, ShouldEqual, 422)
+					So(err.Source.Pointer, ShouldEqual, "/data/relationships/author")
+				})
+
+				Convey("should reject undeclared relationships", func() {
+					object.AddToOneRelationship("editor", "users", "2")
+
+					_, err := object.RelatedID("editor")
+					So(err, ShouldNotBeNil)
+					So(err.Status, ShouldEqual, 500)
+				})
+
+				Convey("should reject the declared cardinality", func() {
+					_, err := object.RelatedID("tags")
+					So(err, ShouldNotBeNil)
+				})
+			})
+
+			Convey("->RelatedIDs()", func() {
+				ids, err := object.RelatedIDs("tags")
+				So(err, ShouldBeNil)
+				So(ids, ShouldResemble, []string{"1", "2"})
+			})
+
+			Convey("should only check presence for unregistered types", func() {
+				object.Type = "posts"
+
+				ids, err := object.RelatedIDs("tags")
+				So(err, ShouldBeNil)
+				So(len(ids), ShouldEqual, 2)
+			})
+		})
+	})
 }
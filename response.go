@@ -1,10 +1,10 @@
 package jsh
 
 import (
-	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 )
 
 // JSONAPIVersion is version of JSON API Spec that is currently compatible:
@@ -47,6 +47,12 @@ error it encountered to help with debugging in the event of an Internal Server
 Error.
 */
 func SendDocument(w http.ResponseWriter, r *http.Request, document *Document) *Error {
+	return sendDocument(w, r, document, ContentType)
+}
+
+// sendDocument sends the document with the provided Content-Type
+func sendDocument(w http.ResponseWriter, r *http.Request, document *Document, contentType string) *Error {
+	start := time.Now()
 
 	validationErr := document.Validate(r, true)
 	if validationErr != nil {
@@ -62,17 +68,79 @@ func SendDocument(w http.ResponseWriter, r *http.Request, document *Document) *E
 		document = Build(validationErr)
 	}
 
-	content, jsonErr := json.MarshalIndent(document, "", " ")
+	etag := documentETag(r, document)
+
+	// apply registered resource declarations and BeforeSend hooks, and check
+	// FullLinkage, falling back to an error response if they fail
+	prepared, prepareErr := document.prepare(r)
+	if prepareErr == nil {
+		document = prepared
+		prepareErr = runBeforeSend(r, document)
+	}
+	if prepareErr == nil {
+		prepareErr = checkFullLinkage(r, document)
+	}
+	if prepareErr != nil {
+		document = Build(prepareErr)
+		validationErr = prepareErr
+	}
+
+	echoRequest(r, document)
+	addConsistencyMeta(w, document)
+	applyQuota(w, r, document)
+	applyTypeDeprecation(w, r, document)
+	applyLocation(w, r, document)
+	contentType = applyProfiles(r, document, contentType)
+
+	buffer, jsonErr := encodeDocument(document)
 	if jsonErr != nil {
 		http.Error(w, DefaultErrorTitle, http.StatusInternalServerError)
 		return ISE(fmt.Sprintf("Unable to marshal JSON payload: %s", jsonErr.Error()))
 	}
+	defer releaseBuffer(buffer)
+	content := buffer.Bytes()
+
+	applyCachePolicy(w, r, document)
+	if etag != "" && prepareErr == nil && w.Header().Get("ETag") == "" {
+		w.Header().Set("ETag", etag)
+	}
+
+	if validationErr == nil && sendNotModified(w, r, document) {
+		logSend(r, start, document, http.StatusNotModified)
+		return nil
+	}
+
+	// 204 responses, such as to writes sending back no document, have no body
+	if document.Status == http.StatusNoContent {
+		w.WriteHeader(http.StatusNoContent)
+		logSend(r, start, document, http.StatusNoContent)
+		return validationErr
+	}
 
-	w.Header().Add("Content-Type", ContentType)
+	content, compressed := ResponseCompression.compress(r, content)
+	if compressed {
+		w.Header().Set("Content-Encoding", "gzip")
+	}
+	if ResponseCompression != nil {
+		w.Header().Add("Vary", "Accept-Encoding")
+	}
+
+	w.Header().Add("Content-Type", contentType)
 	w.Header().Set("Content-Length", strconv.Itoa(len(content)))
 	w.WriteHeader(document.Status)
 	w.Write(content)
 
+	observe(&MetricEvent{
+		Kind:       SendEvent,
+		Method:     r.Method,
+		Path:       requestPath(r),
+		Status:     document.Status,
+		Bytes:      len(content),
+		PeakBuffer: cap(content),
+		Pool:       BufferPoolStats(),
+	})
+	logSend(r, start, document, document.Status)
+
 	return validationErr
 }
 
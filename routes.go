@@ -0,0 +1,145 @@
+package jsh
+
+import (
+	"sort"
+	"strings"
+)
+
+/*
+Route describes an endpoint implied by a registered resource, following the URL
+conventions of the JSON API specification.
+*/
+type Route struct {
+	Method string
+	// Pattern is the URL path, with ":id" standing in for resource IDs, such
+	// as /articles/:id/relationships/author
+	Pattern   string
+	Resource  string
+	Operation Operation
+	// Relationship is the relationship a relationship or related resource
+	// route addresses, empty otherwise
+	Relationship string
+}
+
+/*
+Routes returns the routing table of the registered resources, so deployment
+tooling can generate gateway configuration, IAM policies, or documentation from
+the declarations the service actually runs with:
+
+	func writeRoutes(w io.Writer) {
+		for _, route := range jsh.Routes() {
+			fmt.Fprintf(w, "%s %s -> %s %s\n", route.Method, route.Pattern, route.Resource, route.Operation)
+		}
+	}
+
+jsh doesn't route requests itself, the table lists the conventional endpoints of
+each resource type: collection and resource routes, plus the relationship and
+related resource routes of its declared Relationships. Patterns are root
+relative, regardless of BaseURL, but follow the prefix and pluralization of
+URLs if it is set. Routes are sorted by resource type.
+*/
+func Routes() []Route {
+	routes := []Route{}
+	for _, resource := range registeredResources() {
+		routes = append(routes, resource.routes()...)
+	}
+
+	return routes
+}
+
+// routes lists the endpoints of a resource
+func (r *Resource) routes() []Route {
+	collection := urlBuilder().collectionPath(r.Type)
+	member := collection + "/:id"
+
+	routes := []Route{
+		{Method: "GET", Pattern: collection, Resource: r.Type, Operation: ListOperation},
+		{Method: "POST", Pattern: collection, Resource: r.Type, Operation: CreateOperation},
+		{Method: "GET", Pattern: member, Resource: r.Type, Operation: FetchOperation},
+		{Method: "PATCH", Pattern: member, Resource: r.Type, Operation: UpdateOperation},
+		{Method: "DELETE", Pattern: member, Resource: r.Type, Operation: DeleteOperation},
+	}
+
+	names := make([]string, 0, len(r.Relationships))
+	for name := range r.Relationships {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		relationship := member + "/relationships/" + name
+		toMany := r.Relationships[name].Cardinality == ToMany
+
+		routes = append(routes,
+			Route{Method: "GET", Pattern: relationship, Resource: r.Type, Operation: FetchOperation, Relationship: name},
+			Route{Method: "PATCH", Pattern: relationship, Resource: r.Type, Operation: UpdateOperation, Relationship: name},
+		)
+
+		// only to-many relationships can have members added and removed
+		if toMany {
+			routes = append(routes,
+				Route{Method: "POST", Pattern: relationship, Resource: r.Type, Operation: CreateOperation, Relationship: name},
+				Route{Method: "DELETE", Pattern: relationship, Resource: r.Type, Operation: DeleteOperation, Relationship: name},
+			)
+		}
+
+		related := Route{Method: "GET", Pattern: member + "/" + name, Resource: r.Type, Operation: FetchOperation, Relationship: name}
+		if toMany {
+			related.Operation = ListOperation
+		}
+		routes = append(routes, related)
+	}
+
+	return routes
+}
+
+/*
+AllowedMethods returns the methods of the registered resources' routes matching
+a request path, see Routes, plus HEAD for those allowing GET and OPTIONS. It
+returns nil if no route matches. Middleware answers OPTIONS requests with them
+in the Allow header.
+*/
+func AllowedMethods(path string) []string {
+	allowed := map[string]bool{}
+	for _, route := range Routes() {
+		if matchPattern(route.Pattern, path) {
+			allowed[route.Method] = true
+		}
+	}
+
+	if len(allowed) == 0 {
+		return nil
+	}
+
+	if allowed["GET"] {
+		allowed["HEAD"] = true
+	}
+	allowed["OPTIONS"] = true
+
+	return sortedNames(allowed)
+}
+
+// matchPattern returns true if the path matches a route pattern, ":id"
+// matching any single segment
+func matchPattern(pattern string, path string) bool {
+	patternSegments := strings.Split(strings.Trim(pattern, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(patternSegments) != len(pathSegments) {
+		return false
+	}
+
+	for i, segment := range patternSegments {
+		if segment == ":id" {
+			if pathSegments[i] == "" {
+				return false
+			}
+			continue
+		}
+
+		if segment != pathSegments[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,64 @@
+package jsh
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRoutes(t *testing.T) {
+
+	Convey("Routes Tests", t, func() {
+
+		Register(&Resource{
+			Type: "articles",
+			Relationships: map[string]RelationshipDeclaration{
+				"author": {Type: "users", Cardinality: ToOne},
+				"tags":   {Type: "tags", Cardinality: ToMany},
+			},
+		})
+		Register(&Resource{Type: "tags"})
+		Reset(func() {
+			Unregister("articles")
+			Unregister("tags")
+		})
+
+		routes := Routes()
+
+		patterns := []string{}
+		for _, route := range routes {
+			patterns = append(patterns, route.Method+" "+route.Pattern)
+		}
+
+		Convey("should list resource routes sorted by type", func() {
+			So(routes[0], ShouldResemble, Route{Method: "GET", Pattern: "/articles", Resource: "articles", Operation: ListOperation})
+			So(routes[len(routes)-1].Resource, ShouldEqual, "tags")
+			So(patterns, ShouldContain, "DELETE /tags/:id")
+		})
+
+		Convey("should list relationship routes by cardinality", func() {
+			So(patterns, ShouldContain, "PATCH /articles/:id/relationships/author")
+			So(patterns, ShouldNotContain, "POST /articles/:id/relationships/author")
+			So(patterns, ShouldContain, "POST /articles/:id/relationships/tags")
+			So(patterns, ShouldContain, "DELETE /articles/:id/relationships/tags")
+		})
+
+		Convey("should list related resource routes", func() {
+			for _, route := range routes {
+				if route.Pattern == "/articles/:id/tags" {
+					So(route.Operation, ShouldEqual, ListOperation)
+					So(route.Relationship, ShouldEqual, "tags")
+				}
+			}
+			So(patterns, ShouldContain, "GET /articles/:id/author")
+		})
+
+		Convey("->AllowedMethods()", func() {
+			So(AllowedMethods("/articles"), ShouldResemble, []string{"GET", "HEAD", "OPTIONS", "POST"})
+			So(AllowedMethods("/articles/1/relationships/author"), ShouldResemble, []string{"GET", "HEAD", "OPTIONS", "PATCH"})
+			So(AllowedMethods("/articles/1/relationships/tags/"), ShouldContain, "DELETE")
+			So(AllowedMethods("/articles/1/comments"), ShouldBeNil)
+			So(AllowedMethods("/articles//author"), ShouldBeNil)
+		})
+	})
+}
@@ -0,0 +1,273 @@
+package jsh
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/asaskevich/govalidator"
+)
+
+// AttributeType is the JSON type of an attribute value.
+type AttributeType string
+
+const (
+	// AnyAttribute accepts values of any type
+	AnyAttribute AttributeType = ""
+	// StringAttribute accepts strings
+	StringAttribute AttributeType = "string"
+	// NumberAttribute accepts numbers
+	NumberAttribute AttributeType = "number"
+	// IntegerAttribute accepts numbers without a fractional part
+	IntegerAttribute AttributeType = "integer"
+	// BooleanAttribute accepts true and false
+	BooleanAttribute AttributeType = "boolean"
+	// ObjectAttribute accepts JSON objects
+	ObjectAttribute AttributeType = "object"
+	// ArrayAttribute accepts arrays
+	ArrayAttribute AttributeType = "array"
+)
+
+// Formats checked for string attributes
+const (
+	EmailFormat    = "email"
+	URIFormat      = "uri"
+	UUIDFormat     = "uuid"
+	DateTimeFormat = "date-time"
+)
+
+// AttributeSchema describes the values an attribute accepts.
+type AttributeSchema struct {
+	Type AttributeType
+	// Required attributes must be present when a resource is created
+	Required bool
+	// Nullable attributes accept null
+	Nullable bool
+	// Format is checked for strings, one of EmailFormat, URIFormat,
+	// UUIDFormat, or DateTimeFormat
+	Format string
+}
+
+/*
+Schema declares the attributes of a resource type, which ParseObject and the
+other parsers validate incoming objects against. Declare it on the resource,
+either as a map or derived from a struct with SchemaOf:
+
+	jsh.Register(&jsh.Resource{
+		Type: "users",
+		Schema: &jsh.Schema{
+			Attributes: map[string]*jsh.AttributeSchema{
+				"name":  {Type: jsh.StringAttribute, Required: true},
+				"email": {Type: jsh.StringAttribute, Format: jsh.EmailFormat},
+				"age":   {Type: jsh.IntegerAttribute, Nullable: true},
+			},
+		},
+	})
+
+Violations are 422 errors pointing at the offending attribute. Required
+attributes are only enforced when creating, so PATCHes may send a subset.
+*/
+type Schema struct {
+	Attributes map[string]*AttributeSchema
+	// AdditionalAttributes accepts attributes that the schema doesn't declare
+	AdditionalAttributes bool
+}
+
+/*
+SchemaOf derives a Schema from the exported fields of a struct. Member names
+and types come from the json tags and field types, fields tagged
+`valid:"required"` are Required, and pointers are Nullable. The email, url, and
+uuid validators set the Format, as do time.Time fields.
+*/
+func SchemaOf(model interface{}) *Schema {
+	schema := &Schema{Attributes: map[string]*AttributeSchema{}}
+
+	modelType := reflect.TypeOf(model)
+	for modelType != nil && modelType.Kind() == reflect.Ptr {
+		modelType = modelType.Elem()
+	}
+	if modelType == nil || modelType.Kind() != reflect.Struct {
+		return schema
+	}
+
+	for i := 0; i < modelType.NumField(); i++ {
+		field := modelType.Field(i)
+		if field.PkgPath != "" || field.Tag.Get("json") == "-" {
+			continue
+		}
+
+		name := memberName(field)
+		if name == "id" {
+			continue
+		}
+
+		schema.Attributes[name] = fieldSchema(field)
+	}
+
+	return schema
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// fieldSchema derives the schema of a struct field
+func fieldSchema(field reflect.StructField) *AttributeSchema {
+	attribute := &AttributeSchema{}
+
+	fieldType := field.Type
+	if fieldType.Kind() == reflect.Ptr {
+		attribute.Nullable = true
+		fieldType = fieldType.Elem()
+	}
+
+	switch fieldType.Kind() {
+	case reflect.String:
+		attribute.Type = StringAttribute
+	case reflect.Bool:
+		attribute.Type = BooleanAttribute
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		attribute.Type = IntegerAttribute
+	case reflect.Float32, reflect.Float64:
+		attribute.Type = NumberAttribute
+	case reflect.Slice, reflect.Array:
+		attribute.Type = ArrayAttribute
+		attribute.Nullable = true
+	case reflect.Map, reflect.Struct:
+		attribute.Type = ObjectAttribute
+		attribute.Nullable = fieldType.Kind() == reflect.Map || attribute.Nullable
+	}
+
+	if fieldType == timeType {
+		attribute.Type = StringAttribute
+		attribute.Format = DateTimeFormat
+	}
+
+	for _, validator := range strings.Split(field.Tag.Get("valid"), ",") {
+		switch validator {
+		case "required":
+			attribute.Required = true
+		case "email":
+			attribute.Format = EmailFormat
+		case "url", "requrl":
+			attribute.Format = URIFormat
+		case "uuid":
+			attribute.Format = UUIDFormat
+		}
+	}
+
+	return attribute
+}
+
+// check validates an object's attributes against the schema
+func (s *Schema) check(method string, object *Object) *Error {
+	attributes, err := object.attributeMap()
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(s.Attributes))
+	for name := range s.Attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		attribute := s.Attributes[name]
+		pointer := attributesPointer + "/" + pointerToken(name)
+
+		raw, exists := attributes[name]
+		if !exists {
+			if attribute.Required && method == "POST" {
+				return invalidAttribute(fmt.Sprintf("Missing required attribute '%s'", name), pointer)
+			}
+			continue
+		}
+
+		err := attribute.check(raw, pointer)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !s.AdditionalAttributes {
+		unknown := []string{}
+		for name := range attributes {
+			if _, declared := s.Attributes[name]; !declared {
+				unknown = append(unknown, name)
+			}
+		}
+
+		if len(unknown) > 0 {
+			sort.Strings(unknown)
+			return invalidAttribute(
+				fmt.Sprintf("Resources of type '%s' have no attribute '%s'", object.Type, unknown[0]),
+				attributesPointer+"/"+pointerToken(unknown[0]),
+			)
+		}
+	}
+
+	return nil
+}
+
+// check validates a raw attribute value against the schema
+func (a *AttributeSchema) check(raw json.RawMessage, pointer string) *Error {
+	var value interface{}
+	if json.Unmarshal(raw, &value) != nil {
+		return invalidAttribute("Invalid attribute value", pointer)
+	}
+
+	if value == nil {
+		if !a.Nullable {
+			return invalidAttribute("Value cannot be null", pointer)
+		}
+		return nil
+	}
+
+	valid := true
+	switch a.Type {
+	case StringAttribute:
+		_, valid = value.(string)
+	case NumberAttribute:
+		_, valid = value.(float64)
+	case IntegerAttribute:
+		number, isNumber := value.(float64)
+		valid = isNumber && number == math.Trunc(number)
+	case BooleanAttribute:
+		_, valid = value.(bool)
+	case ObjectAttribute:
+		_, valid = value.(map[string]interface{})
+	case ArrayAttribute:
+		_, valid = value.([]interface{})
+	}
+
+	if !valid {
+		return invalidAttribute(fmt.Sprintf("Value must be of type %s", a.Type), pointer)
+	}
+
+	if str, isString := value.(string); isString && a.Format != "" && !validFormat(a.Format, str) {
+		return invalidAttribute(fmt.Sprintf("Value must be a valid %s", a.Format), pointer)
+	}
+
+	return nil
+}
+
+// validFormat checks a string against a format, unknown formats are accepted
+func validFormat(format string, value string) bool {
+	switch format {
+	case EmailFormat:
+		return govalidator.IsEmail(value)
+	case URIFormat:
+		return govalidator.IsURL(value)
+	case UUIDFormat:
+		return uuidPattern.MatchString(value)
+	case DateTimeFormat:
+		_, err := time.Parse(time.RFC3339, value)
+		return err == nil
+	}
+
+	return true
+}
@@ -0,0 +1,97 @@
+package jsh
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type schemaUser struct {
+	ID       string    `json:"-"`
+	Name     string    `json:"name" valid:"required"`
+	Email    string    `json:"email" valid:"email"`
+	Age      *int      `json:"age"`
+	Joined   time.Time `json:"joined"`
+	Tags     []string  `json:"tags"`
+	internal string
+}
+
+func TestSchema(t *testing.T) {
+
+	Convey("Schema Tests", t, func() {
+
+		Register(&Resource{
+			Type: "users",
+			Schema: &Schema{
+				Attributes: map[string]*AttributeSchema{
+					"name":  {Type: StringAttribute, Required: true},
+					"email": {Type: StringAttribute, Format: EmailFormat},
+					"age":   {Type: IntegerAttribute, Nullable: true},
+				},
+			},
+		})
+		Reset(func() { Unregister("users") })
+
+		parse := func(method string, attributes string) *Error {
+			req, reqErr := testRequest([]byte(`{"data": {"type": "users", "id": "1", "attributes": ` + attributes + `}}`))
+			So(reqErr, ShouldBeNil)
+			req.Method = method
+
+			_, err := ParseObject(req)
+			return err
+		}
+
+		Convey("should accept valid attributes", func() {
+			err := parse("POST", `{"name": "jo", "email": "jo@example.com", "age": null}`)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("should require attributes on creation only", func() {
+			err := parse("POST", `{"email": "jo@example.com"}`)
+			So(err, ShouldNotBeNil)
+			So(err.Status, ShouldEqual, 422)
+			So(err.Source.Pointer, ShouldEqual, "/data/attributes/name")
+
+			err = parse("PATCH", `{"email": "jo@example.com"}`)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("should check types", func() {
+			err := parse("PATCH", `{"age": 1.5}`)
+			So(err, ShouldNotBeNil)
+			So(err.Source.Pointer, ShouldEqual, "/data/attributes/age")
+			So(err.Detail, ShouldContainSubstring, "integer")
+
+			err = parse("PATCH", `{"name": null}`)
+			So(err, ShouldNotBeNil)
+			So(err.Source.Pointer, ShouldEqual, "/data/attributes/name")
+		})
+
+		Convey("should check formats", func() {
+			err := parse("PATCH", `{"email": "nope"}`)
+			So(err, ShouldNotBeNil)
+			So(err.Source.Pointer, ShouldEqual, "/data/attributes/email")
+		})
+
+		Convey("should reject undeclared attributes", func() {
+			err := parse("PATCH", `{"nickname": "j"}`)
+			So(err, ShouldNotBeNil)
+			So(err.Source.Pointer, ShouldEqual, "/data/attributes/nickname")
+
+			Registered("users").Schema.AdditionalAttributes = true
+			err = parse("PATCH", `{"nickname": "j"}`)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("->SchemaOf()", func() {
+			schema := SchemaOf(&schemaUser{})
+			So(len(schema.Attributes), ShouldEqual, 5)
+			So(schema.Attributes["name"], ShouldResemble, &AttributeSchema{Type: StringAttribute, Required: true})
+			So(schema.Attributes["email"].Format, ShouldEqual, EmailFormat)
+			So(schema.Attributes["age"], ShouldResemble, &AttributeSchema{Type: IntegerAttribute, Nullable: true})
+			So(schema.Attributes["joined"].Format, ShouldEqual, DateTimeFormat)
+			So(schema.Attributes["tags"].Type, ShouldEqual, ArrayAttribute)
+		})
+	})
+}
@@ -0,0 +1,202 @@
+package jsh
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode"
+)
+
+/*
+AttributeSerializer transforms the attributes of an object as it is sent, such
+as to rename, redact, or add attributes. It is given the object's attributes
+decoded into a map, which it modifies in place, and the object itself for
+context.
+
+Serializers are chained, with the package level Serializers running on every
+object before those of the object's Resource:
+
+	jsh.Serializers = []jsh.AttributeSerializer{jsh.RenameAttributes(jsh.CamelCase)}
+
+	jsh.Register(&jsh.Resource{
+		Type: "users",
+		Serializers: []jsh.AttributeSerializer{
+			jsh.RedactAttributes(isAdmin, "email", "lastLoginIp"),
+			jsh.VirtualAttribute("displayName", displayName),
+		},
+	})
+
+They run after Computed attributes are added, so they see those too, and
+before attributes are externalized. Serializers only apply to sent documents,
+use an AfterParse hook to transform what clients write.
+*/
+type AttributeSerializer func(r *http.Request, object *Object, attributes map[string]json.RawMessage) *Error
+
+// Serializers are applied to the attributes of every object sent, before the
+// Serializers of its Resource, see AttributeSerializer.
+var Serializers []AttributeSerializer
+
+/*
+RenameAttributes returns a serializer renaming every attribute with rename,
+such as CamelCase, SnakeCase, or KebabCase. An error is returned if two
+attributes are renamed to the same name.
+*/
+func RenameAttributes(rename func(name string) string) AttributeSerializer {
+	return func(r *http.Request, object *Object, attributes map[string]json.RawMessage) *Error {
+		renamed := map[string]json.RawMessage{}
+		for name, value := range attributes {
+			newName := rename(name)
+			if _, taken := renamed[newName]; taken {
+				return ISE(fmt.Sprintf("Attributes of %s renamed to conflicting name '%s'", object.String(), newName))
+			}
+			renamed[newName] = value
+		}
+
+		for name := range attributes {
+			delete(attributes, name)
+		}
+		for name, value := range renamed {
+			attributes[name] = value
+		}
+
+		return nil
+	}
+}
+
+/*
+RedactAttributes returns a serializer removing the named attributes from
+objects unless visible returns true, such as when the caller has a role
+allowed to see them:
+
+	jsh.RedactAttributes(func(r *http.Request, user *jsh.Object) bool {
+		return currentUser(r).IsAdmin || currentUser(r).ID == user.ID
+	}, "email", "phone")
+*/
+func RedactAttributes(visible func(r *http.Request, object *Object) bool, names ...string) AttributeSerializer {
+	return func(r *http.Request, object *Object, attributes map[string]json.RawMessage) *Error {
+		if visible(r, object) {
+			return nil
+		}
+
+		for _, name := range names {
+			delete(attributes, name)
+		}
+
+		return nil
+	}
+}
+
+// VirtualAttribute returns a serializer setting the named attribute to the value
+// computed by compute, like a Resource's Computed attributes. The object is
+// passed to compute as it was before the serializers ran.
+func VirtualAttribute(name string, compute ComputedAttribute) AttributeSerializer {
+	return func(r *http.Request, object *Object, attributes map[string]json.RawMessage) *Error {
+		value, err := compute(r, object)
+		if err != nil {
+			return err
+		}
+
+		raw, jsonErr := json.Marshal(value)
+		if jsonErr != nil {
+			return ISE(fmt.Sprintf("Error marshaling attribute '%s': %s", name, jsonErr.Error()))
+		}
+		attributes[name] = raw
+
+		return nil
+	}
+}
+
+// CamelCase converts names such as "first_name" or "first-name" to
+// "firstName".
+func CamelCase(name string) string {
+	words := nameWords(name)
+	for i, word := range words {
+		if i > 0 {
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+
+	return strings.Join(words, "")
+}
+
+// SnakeCase converts names such as "firstName" or "first-name" to
+// "first_name".
+func SnakeCase(name string) string {
+	return strings.Join(nameWords(name), "_")
+}
+
+// KebabCase converts names such as "firstName" or "first_name" to
+// "first-name".
+func KebabCase(name string) string {
+	return strings.Join(nameWords(name), "-")
+}
+
+// nameWords splits a member name into lower case words, at underscores,
+// hyphens, spaces, and the start of capitalized words, keeping acronyms such as
+// the "IP" of "lastLoginIP" together
+func nameWords(name string) []string {
+	runes := []rune(name)
+	words := []string{}
+	word := []rune{}
+
+	for i, current := range runes {
+		if current == '_' || current == '-' || current == ' ' {
+			if len(word) > 0 {
+				words = append(words, strings.ToLower(string(word)))
+				word = []rune{}
+			}
+			continue
+		}
+
+		if unicode.IsUpper(current) && len(word) > 0 {
+			previous := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if !unicode.IsUpper(previous) || nextLower {
+				words = append(words, strings.ToLower(string(word)))
+				word = []rune{}
+			}
+		}
+
+		word = append(word, current)
+	}
+
+	if len(word) > 0 {
+		words = append(words, strings.ToLower(string(word)))
+	}
+
+	return words
+}
+
+// serialize runs the package level and the resource's serializers on the
+// object's attributes
+func serialize(r *http.Request, resource *Resource, object *Object) *Error {
+	chain := Serializers
+	if resource != nil && len(resource.Serializers) > 0 {
+		chain = append(append([]AttributeSerializer{}, Serializers...), resource.Serializers...)
+	}
+	if len(chain) == 0 {
+		return nil
+	}
+
+	attributes, err := object.attributeMap()
+	if err != nil {
+		return err
+	}
+
+	hadAttributes := object.HasAttributes()
+
+	for _, serializer := range chain {
+		err = serializer(r, object, attributes)
+		if err != nil {
+			return err
+		}
+	}
+
+	// leave meta-only objects as they are unless attributes were added
+	if !hadAttributes && len(attributes) == 0 {
+		return nil
+	}
+
+	return object.marshal(attributes, nil)
+}
@@ -0,0 +1,94 @@
+package jsh
+
+import (
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSerializers(t *testing.T) {
+
+	Convey("Serializer Tests", t, func() {
+
+		request := &http.Request{Method: "GET", Header: http.Header{"Role": {"user"}}}
+		object, _ := NewObject("1", "users", map[string]interface{}{
+			"first_name": "Bob",
+			"email":      "bob@example.com",
+		})
+
+		attributes := func() map[string]interface{} {
+			decoded := map[string]interface{}{}
+			So(object.Unmarshal("users", &decoded), ShouldBeNil)
+			return decoded
+		}
+
+		Convey("->CamelCase(), ->SnakeCase(), ->KebabCase()", func() {
+			So(CamelCase("first_name"), ShouldEqual, "firstName")
+			So(CamelCase("first-name"), ShouldEqual, "firstName")
+			So(CamelCase("firstName"), ShouldEqual, "firstName")
+			So(SnakeCase("lastLoginIP"), ShouldEqual, "last_login_ip")
+			So(SnakeCase("HTTPStatus"), ShouldEqual, "http_status")
+			So(KebabCase("userProfile_id"), ShouldEqual, "user-profile-id")
+			So(KebabCase("name"), ShouldEqual, "name")
+		})
+
+		Convey("should apply the package level serializers to unregistered types", func() {
+			Serializers = []AttributeSerializer{RenameAttributes(CamelCase)}
+			Reset(func() { Serializers = nil })
+
+			So(prepareObject(request, object), ShouldBeNil)
+			So(attributes(), ShouldResemble, map[string]interface{}{"firstName": "Bob", "email": "bob@example.com"})
+		})
+
+		Convey("should chain the resource's serializers after the package level ones", func() {
+			Serializers = []AttributeSerializer{RenameAttributes(KebabCase)}
+			Register(&Resource{
+				Type: "users",
+				Serializers: []AttributeSerializer{
+					RedactAttributes(func(r *http.Request, object *Object) bool {
+						return r.Header.Get("Role") == "admin"
+					}, "email"),
+					VirtualAttribute("display-name", func(r *http.Request, object *Object) (interface{}, *Error) {
+						name, err := object.AttributeString("first_name")
+						return "~" + name, err
+					}),
+				},
+			})
+			Reset(func() {
+				Serializers = nil
+				Unregister("users")
+			})
+
+			So(prepareObject(request, object), ShouldBeNil)
+			So(attributes(), ShouldResemble, map[string]interface{}{"first-name": "Bob", "display-name": "~Bob"})
+
+			Convey("should let visible callers see redacted attributes", func() {
+				object, _ = NewObject("1", "users", map[string]interface{}{"first_name": "Bob", "email": "bob@example.com"})
+				request.Header.Set("Role", "admin")
+
+				So(prepareObject(request, object), ShouldBeNil)
+				So(attributes()["email"], ShouldEqual, "bob@example.com")
+			})
+		})
+
+		Convey("should reject conflicting renames", func() {
+			object, _ = NewObject("1", "users", map[string]interface{}{"first_name": "Bob", "firstName": "Bob"})
+			Serializers = []AttributeSerializer{RenameAttributes(CamelCase)}
+			Reset(func() { Serializers = nil })
+
+			err := prepareObject(request, object)
+			So(err, ShouldNotBeNil)
+			So(err.Status, ShouldEqual, http.StatusInternalServerError)
+		})
+
+		Convey("should leave meta-only objects without attributes", func() {
+			object, _ = NewObject("1", "permissions", nil)
+			Serializers = []AttributeSerializer{RenameAttributes(CamelCase)}
+			Reset(func() { Serializers = nil })
+
+			So(prepareObject(request, object), ShouldBeNil)
+			So(object.HasAttributes(), ShouldBeFalse)
+		})
+	})
+}
@@ -0,0 +1,156 @@
+package jsh
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+/*
+ShutdownCoordinator tracks in-flight streaming responses so they can be drained
+when the server stops. http.Server.Shutdown waits for active connections to go
+idle, which a long running stream never does on its own. Attaching a
+coordinator to the server lets those streams finish within a grace period, or
+terminate cleanly with a final meta frame, so Shutdown can complete:
+
+	coordinator := jsh.NewShutdownCoordinator(5 * time.Second)
+	coordinator.Attach(server)
+
+	options := &jsh.StreamOptions{Shutdown: coordinator}
+	jsh.StreamList(w, r, iterator, options)
+
+Handlers that hold responses open in other ways, such as long polling, can use
+Track and Stopping directly.
+*/
+type ShutdownCoordinator struct {
+	// Grace is how long in-flight streams may keep running once shutdown begins
+	Grace time.Duration
+
+	mu       sync.Mutex
+	stopping chan struct{}
+	stopped  time.Time
+	active   sync.WaitGroup
+}
+
+// NewShutdownCoordinator creates a coordinator with the provided grace period.
+// The zero value, with Grace set as needed, is ready to use as well.
+func NewShutdownCoordinator(grace time.Duration) *ShutdownCoordinator {
+	return &ShutdownCoordinator{Grace: grace}
+}
+
+// Attach registers the coordinator to begin draining when server.Shutdown is
+// called.
+func (c *ShutdownCoordinator) Attach(server *http.Server) {
+	server.RegisterOnShutdown(c.Stop)
+}
+
+// Stop begins shutdown, it is safe to call more than once.
+func (c *ShutdownCoordinator) Stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.stopped.IsZero() {
+		c.stopped = time.Now()
+		close(c.stoppingChannel())
+	}
+}
+
+// Stopping returns a channel that is closed once shutdown has begun.
+func (c *ShutdownCoordinator) Stopping() <-chan struct{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.stoppingChannel()
+}
+
+// stoppingChannel returns the Stopping channel, creating it for coordinators
+// that weren't made by NewShutdownCoordinator, c.mu must be held
+func (c *ShutdownCoordinator) stoppingChannel() chan struct{} {
+	if c.stopping == nil {
+		c.stopping = make(chan struct{})
+	}
+
+	return c.stopping
+}
+
+// Track registers an in-flight response. The returned function must be called
+// once the response has completed.
+func (c *ShutdownCoordinator) Track() func() {
+	c.active.Add(1)
+
+	var once sync.Once
+	return func() {
+		once.Do(c.active.Done)
+	}
+}
+
+// Wait blocks until every tracked response has completed.
+func (c *ShutdownCoordinator) Wait() {
+	c.active.Wait()
+}
+
+// expired returns true once shutdown has begun and the grace period has elapsed
+func (c *ShutdownCoordinator) expired() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return !c.stopped.IsZero() && time.Since(c.stopped) >= c.Grace
+}
+
+// remaining returns how much of the grace period is left once shutdown has
+// begun
+func (c *ShutdownCoordinator) remaining() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	left := c.Grace - time.Since(c.stopped)
+	if left < 0 {
+		return 0
+	}
+
+	return left
+}
+
+/*
+nextWithin pulls the next object like nextValid, giving up with a shutdown
+error if the coordinator's grace period ends first, so that an iterator blocked
+waiting on its source can't hold shutdown up. The abandoned call finishes in
+the background and its result is discarded.
+*/
+func (c *ShutdownCoordinator) nextWithin(r *http.Request, next ObjectIterator) (*Object, *Error) {
+	type pulled struct {
+		object *Object
+		err    *Error
+	}
+
+	result := make(chan pulled, 1)
+	go func() {
+		object, err := nextValid(r, next)
+		result <- pulled{object, err}
+	}()
+
+	select {
+	case p := <-result:
+		return p.object, p.err
+	case <-c.Stopping():
+	}
+
+	grace := time.NewTimer(c.remaining())
+	defer grace.Stop()
+
+	select {
+	case p := <-result:
+		return p.object, p.err
+	case <-grace.C:
+		return nil, shutdownError()
+	}
+}
+
+// shutdownError is used to terminate streams that outlive the grace period
+func shutdownError() *Error {
+	return &Error{
+		Title:  "Service Unavailable",
+		Detail: "The server is shutting down, the response is incomplete.",
+		Status: http.StatusServiceUnavailable,
+	}
+}
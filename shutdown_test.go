@@ -0,0 +1,117 @@
+package jsh
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestShutdown(t *testing.T) {
+
+	Convey("Shutdown Tests", t, func() {
+
+		coordinator := NewShutdownCoordinator(0)
+		req := &http.Request{Method: "GET"}
+
+		Convey("->Stop()", func() {
+			coordinator.Stop()
+			coordinator.Stop()
+
+			select {
+			case <-coordinator.Stopping():
+			default:
+				t.Error("expected Stopping channel to be closed")
+			}
+		})
+
+		Convey("should terminate an in-flight stream with a meta frame", func() {
+			writer := httptest.NewRecorder()
+			options := &StreamOptions{Shutdown: coordinator}
+
+			calls := 0
+			err := StreamList(writer, req, func() (*Object, *Error) {
+				calls++
+				if calls == 2 {
+					coordinator.Stop()
+				}
+				return NewObject("1", "user", nil)
+			}, options)
+			So(err, ShouldNotBeNil)
+			So(err.Status, ShouldEqual, http.StatusServiceUnavailable)
+
+			doc := map[string]json.RawMessage{}
+			jsonErr := json.Unmarshal(writer.Body.Bytes(), &doc)
+			So(jsonErr, ShouldBeNil)
+			So(string(doc["meta"]), ShouldContainSubstring, `"incomplete":true`)
+
+			// the stream must have released its tracking
+			done := make(chan struct{})
+			go func() {
+				coordinator.Wait()
+				close(done)
+			}()
+
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Error("coordinator still waiting on a finished stream")
+			}
+		})
+
+		Convey("should work without NewShutdownCoordinator", func() {
+			literal := &ShutdownCoordinator{Grace: time.Second}
+			stopping := literal.Stopping()
+			So(literal.Stop, ShouldNotPanic)
+
+			select {
+			case <-stopping:
+			default:
+				t.Error("expected Stopping channel to be closed")
+			}
+		})
+
+		Convey("should cut off an iterator blocked past the grace period", func() {
+			coordinator.Grace = 20 * time.Millisecond
+			blocked := make(chan struct{})
+			defer close(blocked)
+
+			writer := httptest.NewRecorder()
+			options := &StreamOptions{Shutdown: coordinator}
+
+			calls := 0
+			start := time.Now()
+			err := StreamList(writer, req, func() (*Object, *Error) {
+				calls++
+				if calls == 2 {
+					coordinator.Stop()
+					<-blocked
+				}
+				return NewObject("1", "user", nil)
+			}, options)
+			So(err, ShouldNotBeNil)
+			So(err.Status, ShouldEqual, http.StatusServiceUnavailable)
+			So(time.Since(start), ShouldBeLessThan, time.Second)
+
+			doc := map[string]json.RawMessage{}
+			So(json.Unmarshal(writer.Body.Bytes(), &doc), ShouldBeNil)
+			So(string(doc["meta"]), ShouldContainSubstring, `"incomplete":true`)
+		})
+
+		Convey("->Attach()", func() {
+			server := &http.Server{}
+			coordinator.Attach(server)
+			So(server.Shutdown(context.Background()), ShouldBeNil)
+
+			select {
+			case <-coordinator.Stopping():
+			case <-time.After(time.Second):
+				t.Error("expected server shutdown to stop the coordinator")
+			}
+		})
+	})
+}
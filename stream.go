@@ -0,0 +1,344 @@
+package jsh
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// ChecksumTrailer is the HTTP trailer carrying the hex encoded SHA-256 of a
+	// streamed response body, see StreamOptions.Checksum.
+	ChecksumTrailer = "Jsh-Checksum"
+	// CountTrailer is the HTTP trailer carrying the number of resources in a
+	// streamed response.
+	CountTrailer = "Jsh-Resource-Count"
+)
+
+/*
+ObjectIterator produces the objects of a streamed list one at a time. It should
+return nil once the list is exhausted, or an error to end the stream early.
+Because StreamList pulls from the iterator, a stream that is aborted simply
+stops calling it, leaving no producer goroutine blocked behind it, unless
+StreamOptions.Shutdown cuts off a call in progress.
+*/
+type ObjectIterator func() (*Object, *Error)
+
+// StreamOptions controls the flow of a streamed list response.
+type StreamOptions struct {
+	// WriteTimeout bounds how long writing and flushing a single object to the
+	// client may take. Consumers that fall behind it are considered stalled and
+	// the stream is aborted. Zero disables the limit.
+	WriteTimeout time.Duration
+	// FlushInterval is the minimum time between flushes of buffered output to
+	// the client. Zero flushes after every object.
+	FlushInterval time.Duration
+	// Shutdown, if set, terminates the stream with a final meta frame once the
+	// coordinator's grace period has elapsed, even while the iterator is
+	// blocked producing the next object. The iterator is then called from
+	// another goroutine, which is left to finish the abandoned call.
+	Shutdown *ShutdownCoordinator
+	// Checksum emits the ChecksumTrailer and CountTrailer HTTP trailers once the
+	// stream completes, so that clients can verify the integrity of long
+	// transfers. jsc verifies them automatically.
+	Checksum bool
+}
+
+// DefaultStreamOptions are used by StreamList when no options are provided.
+var DefaultStreamOptions = StreamOptions{
+	WriteTimeout: 30 * time.Second,
+}
+
+/*
+StreamList sends a list response, writing objects to the client as they are
+produced by next rather than building the whole Document in memory:
+
+	rows := db.Query(...)
+	jsh.StreamList(w, r, func() (*jsh.Object, *jsh.Error) {
+		if !rows.Next() {
+			return nil, nil
+		}
+		return rowToObject(rows)
+	}, nil)
+
+Objects are sent as Send sends them, with their IDs made public and their
+registered resource declarations applied, field policies included.
+
+If the iterator errors before anything has been written, the error is sent as a
+regular error response. Once streaming has begun the status can no longer change,
+so the document is instead terminated with a top-level "meta" member describing
+why it is incomplete. Clients that can't keep up with WriteTimeout are
+disconnected and the event is logged.
+*/
+func StreamList(w http.ResponseWriter, r *http.Request, next ObjectIterator, options *StreamOptions) *Error {
+	if options == nil {
+		options = &DefaultStreamOptions
+	}
+
+	pull := nextValid
+	if options.Shutdown != nil {
+		pull = options.Shutdown.nextWithin
+	}
+
+	object, err := pull(r, next)
+	if err != nil {
+		Send(w, r, err)
+		return err
+	}
+
+	stream := newListStream(w, r, options)
+	defer stream.report()
+	defer stream.clearDeadline()
+
+	if options.Shutdown != nil {
+		defer options.Shutdown.Track()()
+	}
+
+	if options.Checksum {
+		w.Header().Add("Trailer", ChecksumTrailer)
+		w.Header().Add("Trailer", CountTrailer)
+		defer stream.trailers()
+	}
+
+	w.Header().Add("Content-Type", ContentType)
+	w.WriteHeader(http.StatusOK)
+
+	err = stream.write([]byte(`{"data":[`))
+
+	for i := 0; err == nil && object != nil; i++ {
+		err = stream.writeObject(object, i > 0)
+
+		if err == nil {
+			err = stream.flush(false)
+		}
+
+		if err == nil && options.Shutdown != nil && options.Shutdown.expired() {
+			return stream.terminate(shutdownError())
+		}
+
+		if err == nil {
+			object, err = pull(r, next)
+			if err != nil {
+				return stream.terminate(err)
+			}
+		}
+	}
+
+	if err != nil {
+		// the client is no longer reading, nothing more can be sent
+		return err
+	}
+
+	return stream.close(nil)
+}
+
+// nextValid pulls the next object from the iterator, validates it, and
+// prepares it for sending as Send does
+func nextValid(r *http.Request, next ObjectIterator) (*Object, *Error) {
+	object, err := next()
+	if err != nil || object == nil {
+		return nil, err
+	}
+
+	err = object.Validate(r, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return prepareSent(r, object)
+}
+
+// writeDeadliner is implemented by the net/http server's ResponseWriter
+type writeDeadliner interface {
+	SetWriteDeadline(deadline time.Time) error
+}
+
+// errorFlusher is implemented by the net/http server's ResponseWriter
+type errorFlusher interface {
+	FlushError() error
+}
+
+// listStream tracks the state of an in-flight StreamList response
+type listStream struct {
+	w         http.ResponseWriter
+	r         *http.Request
+	options   *StreamOptions
+	written   int
+	count     int
+	checksum  hash.Hash
+	lastFlush time.Time
+	started   time.Time
+	// deadlined is set once a write deadline has been set on the connection
+	deadlined bool
+}
+
+func newListStream(w http.ResponseWriter, r *http.Request, options *StreamOptions) *listStream {
+	now := time.Now()
+	return &listStream{
+		w:         w,
+		r:         r,
+		options:   options,
+		checksum:  sha256.New(),
+		lastFlush: now,
+		started:   now,
+	}
+}
+
+// writeObject marshals and writes a single object to the client, preceded by a
+// separator for all but the first. The separator is only written along with
+// the object, so that a failure to marshal it leaves the list closable.
+func (s *listStream) writeObject(object *Object, separate bool) *Error {
+	raw, err := JSON.Marshal(object)
+	if err != nil {
+		return s.terminate(ISE(fmt.Sprintf("Unable to marshal streamed object: %s", err.Error())))
+	}
+
+	if separate {
+		raw = append([]byte(","), raw...)
+	}
+
+	writeErr := s.write(raw)
+	if writeErr == nil {
+		s.count++
+	}
+
+	return writeErr
+}
+
+// write writes to the client within the configured WriteTimeout
+func (s *listStream) write(content []byte) *Error {
+	start := s.deadline()
+
+	n, err := s.w.Write(content)
+	s.written += n
+	s.checksum.Write(content[:n])
+
+	if err != nil {
+		return s.abort(fmt.Sprintf("write failed: %s", err.Error()))
+	}
+
+	return s.checkElapsed(start)
+}
+
+// flush pushes buffered output to the client if FlushInterval has elapsed, or
+// unconditionally if forced
+func (s *listStream) flush(force bool) *Error {
+	if !force && time.Since(s.lastFlush) < s.options.FlushInterval {
+		return nil
+	}
+
+	start := s.deadline()
+	s.lastFlush = start
+
+	switch flusher := s.w.(type) {
+	case errorFlusher:
+		err := flusher.FlushError()
+		if err != nil {
+			return s.abort(fmt.Sprintf("flush failed: %s", err.Error()))
+		}
+	case http.Flusher:
+		flusher.Flush()
+	}
+
+	return s.checkElapsed(start)
+}
+
+// deadline sets the connection write deadline if supported, and returns the
+// time the write started
+func (s *listStream) deadline() time.Time {
+	now := time.Now()
+
+	if deadliner, ok := s.w.(writeDeadliner); ok && s.options.WriteTimeout > 0 {
+		deadliner.SetWriteDeadline(now.Add(s.options.WriteTimeout))
+		s.deadlined = true
+	}
+
+	return now
+}
+
+// clearDeadline removes the write deadline set by the stream, which would
+// otherwise outlive it and time out later responses on a kept-alive connection
+func (s *listStream) clearDeadline() {
+	if deadliner, ok := s.w.(writeDeadliner); ok && s.deadlined {
+		deadliner.SetWriteDeadline(time.Time{})
+	}
+}
+
+// checkElapsed detects consumers that are too slow even when the underlying
+// writer doesn't support write deadlines
+func (s *listStream) checkElapsed(start time.Time) *Error {
+	elapsed := time.Since(start)
+	if s.options.WriteTimeout > 0 && elapsed > s.options.WriteTimeout {
+		return s.abort(fmt.Sprintf("write took %s, exceeding the %s limit", elapsed, s.options.WriteTimeout))
+	}
+
+	return nil
+}
+
+// abort logs a slow or disconnected consumer and returns an error to stop the
+// stream
+func (s *listStream) abort(reason string) *Error {
+	log.Printf("jsh: aborting list stream for %s %s after %d bytes, %s", s.r.Method, requestPath(s.r), s.written, reason)
+	return ISE(fmt.Sprintf("List stream aborted: %s", reason))
+}
+
+// terminate ends an in-flight stream because of err, describing it in meta
+func (s *listStream) terminate(err *Error) *Error {
+	closeErr := s.close(map[string]interface{}{
+		"incomplete": true,
+		"detail":     err.Detail,
+	})
+	if closeErr != nil {
+		return closeErr
+	}
+
+	return err
+}
+
+// close writes the remainder of the document, including an optional top-level
+// meta member
+func (s *listStream) close(meta interface{}) *Error {
+	tail := struct {
+		Meta    interface{} `json:"meta,omitempty"`
+		JSONAPI struct {
+			Version string `json:"version"`
+		} `json:"jsonapi"`
+	}{Meta: meta}
+	tail.JSONAPI.Version = JSONAPIVersion
+
+	raw, err := JSON.Marshal(tail)
+	if err != nil {
+		return ISE(fmt.Sprintf("Unable to marshal list stream terminator: %s", err.Error()))
+	}
+
+	// splice the tail's members onto the open document: `],` + `"meta":...}`
+	writeErr := s.write(append([]byte("],"), raw[1:]...))
+	if writeErr != nil {
+		return writeErr
+	}
+
+	return s.flush(true)
+}
+
+// trailers sets the checksum and count trailers of the written stream
+func (s *listStream) trailers() {
+	s.w.Header().Set(ChecksumTrailer, hex.EncodeToString(s.checksum.Sum(nil)))
+	s.w.Header().Set(CountTrailer, strconv.Itoa(s.count))
+}
+
+// report sends the stream's SendEvent and LogEntry once it has finished
+func (s *listStream) report() {
+	observe(&MetricEvent{
+		Kind:   SendEvent,
+		Method: s.r.Method,
+		Path:   requestPath(s.r),
+		Status: http.StatusOK,
+		Bytes:  s.written,
+	})
+	logSend(s.r, s.started, &Document{Mode: ListMode}, http.StatusOK)
+}
@@ -0,0 +1,194 @@
+package jsh
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// slowWriter simulates a stalled client
+type slowWriter struct {
+	*httptest.ResponseRecorder
+	delay time.Duration
+}
+
+func (s *slowWriter) Write(b []byte) (int, error) {
+	time.Sleep(s.delay)
+	return s.ResponseRecorder.Write(b)
+}
+
+// deadlineWriter fails writes past its write deadline, as a connection does
+type deadlineWriter struct {
+	*httptest.ResponseRecorder
+	deadline time.Time
+}
+
+func (d *deadlineWriter) SetWriteDeadline(deadline time.Time) error {
+	d.deadline = deadline
+	return nil
+}
+
+func (d *deadlineWriter) Write(b []byte) (int, error) {
+	if !d.deadline.IsZero() && time.Now().After(d.deadline) {
+		return 0, errors.New("i/o timeout")
+	}
+	return d.ResponseRecorder.Write(b)
+}
+
+func testIterator(count int) ObjectIterator {
+	sent := 0
+	return func() (*Object, *Error) {
+		if sent == count {
+			return nil, nil
+		}
+		sent++
+		return NewObject(strconv.Itoa(sent), "user", map[string]int{"n": sent})
+	}
+}
+
+func TestStream(t *testing.T) {
+
+	Convey("Stream Tests", t, func() {
+
+		req := &http.Request{Method: "GET"}
+
+		Convey("->StreamList()", func() {
+
+			Convey("should stream a parseable list", func() {
+				writer := httptest.NewRecorder()
+				err := StreamList(writer, req, testIterator(3), nil)
+				So(err, ShouldBeNil)
+				So(writer.Code, ShouldEqual, http.StatusOK)
+				So(writer.Flushed, ShouldBeTrue)
+
+				parseReq, reqErr := testRequest(writer.Body.Bytes())
+				So(reqErr, ShouldBeNil)
+
+				list, parseErr := ParseList(parseReq)
+				So(parseErr, ShouldBeNil)
+				So(len(list), ShouldEqual, 3)
+			})
+
+			Convey("should stream an empty list", func() {
+				writer := httptest.NewRecorder()
+				err := StreamList(writer, req, testIterator(0), nil)
+				So(err, ShouldBeNil)
+				So(writer.Body.String(), ShouldEqual, `{"data":[],"jsonapi":{"version":"1.1"}}`)
+			})
+
+			Convey("should apply resource declarations to streamed objects", func() {
+				Register(&Resource{
+					Type: "user",
+					Computed: map[string]ComputedAttribute{
+						"name": func(r *http.Request, object *Object) (interface{}, *Error) {
+							return "user " + object.ID, nil
+						},
+					},
+					Policy: &FieldPolicy{Visible: map[string][]string{AnyRole: {"name"}}},
+				})
+				Reset(func() { Unregister("user") })
+
+				writer := httptest.NewRecorder()
+				err := StreamList(writer, req, testIterator(2), nil)
+				So(err, ShouldBeNil)
+				So(writer.Body.String(), ShouldContainSubstring, `"name":"user 2"`)
+				So(writer.Body.String(), ShouldNotContainSubstring, `"n":`)
+			})
+
+			Convey("should emit checksum trailers", func() {
+				writer := httptest.NewRecorder()
+				err := StreamList(writer, req, testIterator(2), &StreamOptions{Checksum: true})
+				So(err, ShouldBeNil)
+
+				sum := sha256.Sum256(writer.Body.Bytes())
+				trailers := writer.Result().Trailer
+				So(trailers.Get(ChecksumTrailer), ShouldEqual, hex.EncodeToString(sum[:]))
+				So(trailers.Get(CountTrailer), ShouldEqual, "2")
+			})
+
+			Convey("should send an error response if the first object fails", func() {
+				writer := httptest.NewRecorder()
+				err := StreamList(writer, req, func() (*Object, *Error) {
+					return nil, NotFound("user", "1")
+				}, nil)
+				So(err, ShouldNotBeNil)
+				So(writer.Code, ShouldEqual, http.StatusNotFound)
+			})
+
+			Convey("should terminate an in-flight stream with meta on error", func() {
+				writer := httptest.NewRecorder()
+				iterator := testIterator(1)
+				calls := 0
+
+				err := StreamList(writer, req, func() (*Object, *Error) {
+					calls++
+					if calls == 2 {
+						return nil, ISE("storage failure")
+					}
+					return iterator()
+				}, nil)
+				So(err, ShouldNotBeNil)
+
+				doc := map[string]json.RawMessage{}
+				jsonErr := json.Unmarshal(writer.Body.Bytes(), &doc)
+				So(jsonErr, ShouldBeNil)
+				So(string(doc["meta"]), ShouldContainSubstring, `"incomplete":true`)
+			})
+
+			Convey("should terminate with valid JSON if an object fails to marshal", func() {
+				writer := httptest.NewRecorder()
+				iterator := testIterator(2)
+
+				err := StreamList(writer, req, func() (*Object, *Error) {
+					object, err := iterator()
+					if object != nil && object.ID == "2" {
+						object.Attributes = []byte(`{"n":`)
+					}
+					return object, err
+				}, nil)
+				So(err, ShouldNotBeNil)
+
+				doc := map[string]json.RawMessage{}
+				jsonErr := json.Unmarshal(writer.Body.Bytes(), &doc)
+				So(jsonErr, ShouldBeNil)
+				So(string(doc["meta"]), ShouldContainSubstring, `"incomplete":true`)
+				So(writer.Body.String(), ShouldStartWith, `{"data":[{`)
+				So(writer.Body.String(), ShouldContainSubstring, `}],"meta"`)
+			})
+
+			Convey("should clear its write deadline once done", func() {
+				writer := &deadlineWriter{ResponseRecorder: httptest.NewRecorder()}
+				err := StreamList(writer, req, testIterator(2), &StreamOptions{WriteTimeout: 5 * time.Millisecond})
+				So(err, ShouldBeNil)
+				So(writer.deadline.IsZero(), ShouldBeTrue)
+
+				// the next response on the connection is written after the
+				// stream's deadline would have passed
+				time.Sleep(10 * time.Millisecond)
+				object, _ := NewObject("1", "user", nil)
+				So(Send(writer, req, object), ShouldBeNil)
+			})
+
+			Convey("should abort for a slow consumer", func() {
+				writer := &slowWriter{httptest.NewRecorder(), 20 * time.Millisecond}
+				options := &StreamOptions{WriteTimeout: 5 * time.Millisecond}
+
+				calls := 0
+				err := StreamList(writer, req, func() (*Object, *Error) {
+					calls++
+					return NewObject("1", "user", nil)
+				}, options)
+				So(err, ShouldNotBeNil)
+				So(calls, ShouldEqual, 1)
+			})
+		})
+	})
+}
@@ -0,0 +1,169 @@
+package jsh
+
+import "fmt"
+
+/*
+IDTranslator converts between the IDs clients see and the IDs a service uses
+internally, so that sequential database IDs can be hidden behind hashids or
+other public identifiers without touching every handler. Declare one per
+resource type when registering it, or set DefaultIDTranslator:
+
+	jsh.Register(&jsh.Resource{Type: "users", IDTranslator: hashids})
+
+Translation applies to the IDs of primary data, included resources, and
+relationship linkage, by the type of each. Parsed objects carry internal IDs,
+and objects sent or streamed are given public IDs. Use InternalID to translate
+IDs taken from request URLs.
+*/
+type IDTranslator interface {
+	// Internal translates a public ID from a request. Invalid IDs should
+	// return an error, such as a 404.
+	Internal(resourceType string, id string) (string, *Error)
+	// Public translates an internal ID for a response
+	Public(resourceType string, id string) (string, *Error)
+}
+
+// DefaultIDTranslator applies to resource types without a declared
+// IDTranslator. Leave nil to not translate IDs.
+var DefaultIDTranslator IDTranslator
+
+// translatorFor returns the IDTranslator of a resource type, or nil
+func translatorFor(resourceType string) IDTranslator {
+	resource := Registered(resourceType)
+	if resource != nil && resource.IDTranslator != nil {
+		return resource.IDTranslator
+	}
+
+	return DefaultIDTranslator
+}
+
+/*
+InternalID translates a public ID of the given resource type, such as one taken
+from a request URL, into its internal ID. IDs of types without an IDTranslator
+are returned as is.
+*/
+func InternalID(resourceType string, id string) (string, *Error) {
+	translator := translatorFor(resourceType)
+	if translator == nil || id == "" {
+		return id, nil
+	}
+
+	return translator.Internal(resourceType, id)
+}
+
+// PublicID translates an internal ID of the given resource type into the ID
+// clients see.
+func PublicID(resourceType string, id string) (string, *Error) {
+	translator := translatorFor(resourceType)
+	if translator == nil || id == "" {
+		return id, nil
+	}
+
+	return translator.Public(resourceType, id)
+}
+
+// internalObject translates the IDs of a parsed object to internal IDs
+func internalObject(object *Object) *Error {
+	id, err := InternalID(object.Type, object.ID)
+	if err != nil {
+		return withPointer(err, "/data/id")
+	}
+	object.ID = id
+
+	for name, relationship := range object.Relationships {
+		if relationship == nil {
+			continue
+		}
+
+		pointer := "/data/relationships/" + pointerToken(name) + "/data"
+		err := internalLinkage(relationship, pointer)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// internalLinkage translates the IDs of parsed relationship linkage in place
+func internalLinkage(relationship *Relationship, pointer string) *Error {
+	for i, identifier := range relationship.Data {
+		if identifier == nil {
+			continue
+		}
+
+		id, err := InternalID(identifier.Type, identifier.ID)
+		if err != nil {
+			if relationship.Cardinality == ToMany {
+				return withPointer(err, fmt.Sprintf("%s/%d/id", pointer, i))
+			}
+			return withPointer(err, pointer+"/id")
+		}
+		identifier.ID = id
+	}
+
+	return nil
+}
+
+// publicObject makes the IDs of an object being sent and of its relationship
+// linkage public, in place, so it is given the copy prepareSent makes
+func publicObject(object *Object) *Error {
+	if !translates(object) {
+		return nil
+	}
+
+	var err *Error
+	object.ID, err = PublicID(object.Type, object.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, relationship := range object.Relationships {
+		if relationship == nil {
+			continue
+		}
+
+		for _, identifier := range relationship.Data {
+			if identifier == nil {
+				continue
+			}
+
+			identifier.ID, err = PublicID(identifier.Type, identifier.ID)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// translates returns true if any of the object's IDs are translated
+func translates(object *Object) bool {
+	if translatorFor(object.Type) != nil {
+		return true
+	}
+
+	for _, relationship := range object.Relationships {
+		if relationship == nil {
+			continue
+		}
+
+		for _, identifier := range relationship.Data {
+			if identifier != nil && translatorFor(identifier.Type) != nil {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// withPointer sets the source pointer of an error that doesn't have one
+func withPointer(err *Error, pointer string) *Error {
+	if err.Source.Pointer == "" {
+		err.Source.Pointer = pointer
+	}
+
+	return err
+}
@@ -0,0 +1,112 @@
+package jsh
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// prefixTranslator makes IDs public by prefixing them with "pub-"
+type prefixTranslator struct{}
+
+func (prefixTranslator) Internal(resourceType string, id string) (string, *Error) {
+	if !strings.HasPrefix(id, "pub-") {
+		return "", NotFound(resourceType, id)
+	}
+
+	return strings.TrimPrefix(id, "pub-"), nil
+}
+
+func (prefixTranslator) Public(resourceType string, id string) (string, *Error) {
+	return "pub-" + id, nil
+}
+
+func TestIDTranslator(t *testing.T) {
+
+	Convey("ID Translator Tests", t, func() {
+
+		Register(&Resource{Type: "users", IDTranslator: prefixTranslator{}})
+		Reset(func() { Unregister("users") })
+
+		Convey("parsing", func() {
+			req, reqErr := testRequest([]byte(`{"data": {"type": "articles", "id": "1", "relationships": {
+				"author": {"data": {"type": "users", "id": "pub-7"}},
+				"readers": {"data": [{"type": "users", "id": "pub-8"}, {"type": "users", "id": "9"}]}
+			}}}`))
+			So(reqErr, ShouldBeNil)
+			req.Method = "PATCH"
+
+			Convey("should translate linkage to internal IDs", func() {
+				req.Body = CreateReadCloser([]byte(`{"data": {"type": "articles", "id": "1", "relationships": {
+					"author": {"data": {"type": "users", "id": "pub-7"}}
+				}}}`))
+
+				object, err := ParseObject(req)
+				So(err, ShouldBeNil)
+				So(object.ID, ShouldEqual, "1")
+				So(object.Relationships["author"].Data[0].ID, ShouldEqual, "7")
+			})
+
+			Convey("should point at invalid public IDs", func() {
+				_, err := ParseObject(req)
+				So(err, ShouldNotBeNil)
+				So(err.Status, ShouldEqual, http.StatusNotFound)
+				So(err.Source.Pointer, ShouldEqual, "/data/relationships/readers/data/1/id")
+			})
+
+			Convey("->ParseObjectFor() should translate the expected ID", func() {
+				req.Body = CreateReadCloser([]byte(`{"data": {"type": "users", "id": "pub-7"}}`))
+
+				object, err := ParseObjectFor(req, "users", "pub-7")
+				So(err, ShouldBeNil)
+				So(object.ID, ShouldEqual, "7")
+			})
+		})
+
+		Convey("sending", func() {
+			object, objErr := NewObject("7", "users", map[string]string{"name": "bob"})
+			So(objErr, ShouldBeNil)
+
+			writer := httptest.NewRecorder()
+			err := Send(writer, &http.Request{Method: "GET"}, object)
+			So(err, ShouldBeNil)
+
+			Convey("should send public IDs", func() {
+				doc := &Document{}
+				So(json.Unmarshal(writer.Body.Bytes(), doc), ShouldBeNil)
+				So(doc.First().ID, ShouldEqual, "pub-7")
+			})
+
+			Convey("should leave the sent object alone", func() {
+				So(object.ID, ShouldEqual, "7")
+			})
+		})
+
+		Convey("streaming should send public IDs", func() {
+			object, objErr := NewObject("7", "users", map[string]string{"name": "bob"})
+			So(objErr, ShouldBeNil)
+			object.AddToOneRelationship("friend", "users", "8")
+
+			streamed := false
+			writer := httptest.NewRecorder()
+			err := StreamList(writer, &http.Request{Method: "GET"}, func() (*Object, *Error) {
+				if streamed {
+					return nil, nil
+				}
+				streamed = true
+				return object, nil
+			}, nil)
+			So(err, ShouldBeNil)
+
+			doc := &Document{}
+			So(json.Unmarshal(writer.Body.Bytes(), doc), ShouldBeNil)
+			So(doc.First().ID, ShouldEqual, "pub-7")
+			So(doc.First().Relationships["friend"].Data[0].ID, ShouldEqual, "pub-8")
+			So(object.ID, ShouldEqual, "7")
+		})
+	})
+}
@@ -0,0 +1,196 @@
+package jsh
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// paths holds the collection path segments registered with RegisterPath
+var paths = struct {
+	sync.RWMutex
+	segments map[string]string
+}{segments: map[string]string{}}
+
+/*
+RegisterPath overrides the collection path segment of a resource type, for APIs
+whose paths don't match their types, such as singular or kebab-case paths:
+
+	jsh.RegisterPath("userProfile", "user-profiles")
+
+Registered paths are used by URLBuilder, and so by generated links and Routes,
+by jsc's request builders, and by the Handle methods of the storage adapters.
+Register them at startup, in servers and clients alike. An empty segment removes
+the override.
+*/
+func RegisterPath(resourceType string, segment string) {
+	paths.Lock()
+	defer paths.Unlock()
+
+	if segment == "" {
+		delete(paths.segments, resourceType)
+		return
+	}
+	paths.segments[resourceType] = strings.Trim(segment, "/")
+}
+
+// ResourcePath returns the collection path segment of a resource type, the
+// one registered with RegisterPath or the type itself.
+func ResourcePath(resourceType string) string {
+	segment, _ := registeredPath(resourceType)
+	return segment
+}
+
+// registeredPath returns the path segment registered for a resource type
+func registeredPath(resourceType string) (string, bool) {
+	paths.RLock()
+	defer paths.RUnlock()
+
+	segment, registered := paths.segments[resourceType]
+	if !registered {
+		return resourceType, false
+	}
+
+	return segment, true
+}
+
+// URLs builds the links jsh generates, such as relationship links, when set.
+// BaseURL is used otherwise.
+var URLs *URLBuilder
+
+/*
+URLBuilder builds the URLs of a JSON API's endpoints from a single description
+of its route scheme, so servers emitting links and clients constructing
+requests agree on them:
+
+	urls := &jsh.URLBuilder{
+		BaseURL:   "https://api.example.com",
+		Prefix:    "/v1",
+		Pluralize: jsh.EnglishPlural,
+		Plurals:   map[string]string{"person": "people"},
+	}
+
+	urls.Resource("person", "1")                      // https://api.example.com/v1/people/1
+	urls.Relationship("company", "1", "owner")        // https://api.example.com/v1/companies/1/relationships/owner
+	urls.Page("person", url.Values{"sort": {"name"}}) // https://api.example.com/v1/people?sort=name
+
+Set jsh.URLs to have the server's generated links, and the patterns listed by
+Routes, follow the same scheme.
+*/
+type URLBuilder struct {
+	// BaseURL is the scheme and host of the API, such as
+	// https://api.example.com. URLs are root relative if empty.
+	BaseURL string
+	// Prefix is prepended to every path, such as /v1
+	Prefix string
+	// Pluralize converts resource types to their collection path segment,
+	// leaving them as is if nil. Plurals and paths registered with
+	// RegisterPath take precedence.
+	Pluralize func(resourceType string) string
+	// Plurals lists the collection path segments of individual resource types,
+	// such as irregular plurals
+	Plurals map[string]string
+}
+
+// Collection returns the URL of a resource type's collection, such as
+// /articles.
+func (b *URLBuilder) Collection(resourceType string) string {
+	return strings.TrimSuffix(b.BaseURL, "/") + b.collectionPath(resourceType)
+}
+
+// Resource returns the URL of a resource, such as /articles/1.
+func (b *URLBuilder) Resource(resourceType string, id string) string {
+	return b.Collection(resourceType) + "/" + url.PathEscape(id)
+}
+
+// Self returns the URL of an object, see Resource.
+func (b *URLBuilder) Self(object *Object) string {
+	return b.Resource(object.Type, object.ID)
+}
+
+// Related returns the URL of the resources related to a resource by one of its
+// relationships, such as /articles/1/author.
+func (b *URLBuilder) Related(resourceType string, id string, relationship string) string {
+	return b.Resource(resourceType, id) + "/" + relationship
+}
+
+// Relationship returns the URL of a resource's relationship, such as
+// /articles/1/relationships/author.
+func (b *URLBuilder) Relationship(resourceType string, id string, relationship string) string {
+	return b.Resource(resourceType, id) + "/relationships/" + relationship
+}
+
+// Page returns the URL of a collection with the given query parameters, such as
+// the sort and filter of a list.
+func (b *URLBuilder) Page(resourceType string, query url.Values) string {
+	collection := b.Collection(resourceType)
+	if len(query) == 0 {
+		return collection
+	}
+
+	return collection + "?" + query.Encode()
+}
+
+/*
+PageLinks builds the pagination links of a collection, the self link pointing to
+the page at position and the next and prev links to those at next and prev,
+omitted when empty. Cursors are encoded by encoder, or DefaultCursorEncoder if
+it is nil, and added to query, which is left untouched.
+*/
+func (b *URLBuilder) PageLinks(resourceType string, query url.Values, encoder CursorEncoder, limit int, position string, next string, prev string) (*Links, *Error) {
+	if encoder == nil {
+		encoder = DefaultCursorEncoder
+	}
+
+	collection, parseErr := url.Parse(b.Page(resourceType, query))
+	if parseErr != nil {
+		return nil, ISE("Invalid collection URL: " + parseErr.Error())
+	}
+
+	page := &CursorPage{Position: position, Limit: limit, encoder: encoder, url: collection}
+	return page.Links(next, prev)
+}
+
+// collectionPath returns the root relative path of a collection
+func (b *URLBuilder) collectionPath(resourceType string) string {
+	segment, registered := registeredPath(resourceType)
+	if plural, exists := b.Plurals[resourceType]; exists {
+		segment = plural
+	} else if !registered && b.Pluralize != nil {
+		segment = b.Pluralize(resourceType)
+	}
+
+	prefix := strings.Trim(b.Prefix, "/")
+	if prefix != "" {
+		prefix = "/" + prefix
+	}
+
+	return prefix + "/" + url.PathEscape(segment)
+}
+
+// urlBuilder returns URLs, or a builder for BaseURL if it isn't set
+func urlBuilder() *URLBuilder {
+	if URLs != nil {
+		return URLs
+	}
+
+	return &URLBuilder{BaseURL: BaseURL}
+}
+
+/*
+EnglishPlural pluralizes resource types following the regular rules of English,
+"person" becoming "persons" rather than "people". Declare irregular plurals in
+URLBuilder.Plurals.
+*/
+func EnglishPlural(resourceType string) string {
+	lower := strings.ToLower(resourceType)
+	switch {
+	case strings.HasSuffix(lower, "s"), strings.HasSuffix(lower, "x"), strings.HasSuffix(lower, "z"),
+		strings.HasSuffix(lower, "ch"), strings.HasSuffix(lower, "sh"):
+		return resourceType + "es"
+	case strings.HasSuffix(lower, "y") && len(lower) > 1 && !strings.ContainsAny(lower[len(lower)-2:len(lower)-1], "aeiou"):
+		return resourceType[:len(resourceType)-1] + "ies"
+	}
+
+	return resourceType + "s"
+}
@@ -0,0 +1,100 @@
+package jsh
+
+import (
+	"net/url"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestURLBuilder(t *testing.T) {
+
+	Convey("URL Builder Tests", t, func() {
+
+		urls := &URLBuilder{
+			BaseURL:   "https://api.example.com/",
+			Prefix:    "v1/",
+			Pluralize: EnglishPlural,
+			Plurals:   map[string]string{"person": "people"},
+		}
+
+		Convey("should build resource URLs", func() {
+			So(urls.Collection("article"), ShouldEqual, "https://api.example.com/v1/articles")
+			So(urls.Resource("person", "1"), ShouldEqual, "https://api.example.com/v1/people/1")
+			So(urls.Resource("article", "a/b"), ShouldEqual, "https://api.example.com/v1/articles/a%2Fb")
+			So(urls.Related("company", "1", "owner"), ShouldEqual, "https://api.example.com/v1/companies/1/owner")
+			So(urls.Relationship("company", "1", "owner"), ShouldEqual, "https://api.example.com/v1/companies/1/relationships/owner")
+
+			object, _ := NewObject("7", "box", nil)
+			So(urls.Self(object), ShouldEqual, "https://api.example.com/v1/boxes/7")
+		})
+
+		Convey("should build root relative URLs without a base URL", func() {
+			So((&URLBuilder{}).Resource("articles", "1"), ShouldEqual, "/articles/1")
+		})
+
+		Convey("->Page()", func() {
+			So(urls.Page("article", nil), ShouldEqual, "https://api.example.com/v1/articles")
+			So(urls.Page("article", url.Values{SortParam: {"-title"}}), ShouldEqual, "https://api.example.com/v1/articles?sort=-title")
+		})
+
+		Convey("->PageLinks()", func() {
+			query := url.Values{SortParam: {"title"}}
+			links, err := urls.PageLinks("article", query, nil, 10, "", "20", "")
+			So(err, ShouldBeNil)
+			So(links.Self.HREF, ShouldEqual, "https://api.example.com/v1/articles?page%5Blimit%5D=10&sort=title")
+			So(links.Next.HREF, ShouldStartWith, "https://api.example.com/v1/articles?page%5Bcursor%5D=")
+			So(links.Prev, ShouldBeNil)
+			So(query, ShouldResemble, url.Values{SortParam: {"title"}})
+		})
+
+		Convey("->EnglishPlural()", func() {
+			So(EnglishPlural("article"), ShouldEqual, "articles")
+			So(EnglishPlural("category"), ShouldEqual, "categories")
+			So(EnglishPlural("day"), ShouldEqual, "days")
+			So(EnglishPlural("match"), ShouldEqual, "matches")
+			So(EnglishPlural("status"), ShouldEqual, "statuses")
+		})
+
+		Convey("should use registered paths", func() {
+			RegisterPath("userProfile", "/user-profiles/")
+			RegisterPath("person", "person")
+			Reset(func() {
+				RegisterPath("userProfile", "")
+				RegisterPath("person", "")
+			})
+
+			So(ResourcePath("userProfile"), ShouldEqual, "user-profiles")
+			So(ResourcePath("company"), ShouldEqual, "company")
+			So(urls.Resource("userProfile", "1"), ShouldEqual, "https://api.example.com/v1/user-profiles/1")
+			So(urls.Collection("person"), ShouldEqual, "https://api.example.com/v1/people")
+			So(urls.Collection("company"), ShouldEqual, "https://api.example.com/v1/companies")
+
+			urls.Pluralize = nil
+			RegisterPath("company", "firm")
+			Reset(func() { RegisterPath("company", "") })
+			So(urls.Collection("company"), ShouldEqual, "https://api.example.com/v1/firm")
+		})
+
+		Convey("should drive generated links and routes when set", func() {
+			URLs = urls
+			Register(&Resource{
+				Type:              "person",
+				RelationshipLinks: true,
+				Relationships:     map[string]RelationshipDeclaration{"employer": {Type: "company", Cardinality: ToOne}},
+			})
+			Reset(func() {
+				URLs = nil
+				Unregister("person")
+			})
+
+			object, _ := NewObject("1", "person", nil)
+			So(object.AddToOneRelationship("employer", "company", "2"), ShouldBeNil)
+			So(prepareObject(nil, object), ShouldBeNil)
+			So(object.Relationships["employer"].Links.Self.HREF, ShouldEqual, "https://api.example.com/v1/people/1/relationships/employer")
+			So(object.Relationships["employer"].Links.Related.HREF, ShouldEqual, "https://api.example.com/v1/people/1/employer")
+
+			So(Registered("person").routes()[0].Pattern, ShouldEqual, "/v1/people")
+		})
+	})
+}
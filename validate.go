@@ -0,0 +1,279 @@
+package jsh
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// ConfigurationError describes a problem with the settings or resource
+// declarations jsh is running with, found by Validate.
+type ConfigurationError struct {
+	// Resource is the type of the resource declaration at fault, empty for
+	// package level settings
+	Resource string
+	// Setting names the offending setting, such as "Relationships.author"
+	Setting string
+	// Problem describes what is wrong, and how to fix it
+	Problem string
+}
+
+func (e *ConfigurationError) Error() string {
+	if e.Resource == "" {
+		return fmt.Sprintf("%s: %s", e.Setting, e.Problem)
+	}
+
+	return fmt.Sprintf("resource '%s' %s: %s", e.Resource, e.Setting, e.Problem)
+}
+
+// ConfigurationErrors lists every problem found by Validate.
+type ConfigurationErrors []*ConfigurationError
+
+func (e ConfigurationErrors) Error() string {
+	problems := make([]string, len(e))
+	for i, err := range e {
+		problems[i] = err.Error()
+	}
+
+	return fmt.Sprintf("jsh: %d configuration problem(s):\n\t%s", len(e), strings.Join(problems, "\n\t"))
+}
+
+/*
+Validate checks the package level settings and the registered resources for
+options that conflict or can't work, so misconfigurations are caught at startup
+rather than by the first request they affect:
+
+	jsh.Register(articles)
+	jsh.Register(people)
+
+	if err := jsh.Validate(); err != nil {
+		log.Fatal(err)
+	}
+
+Every problem found is listed, as ConfigurationErrors, rather than just the
+first. Validate returns nil if there are none.
+*/
+func Validate() error {
+	errs := DefaultParseOptions.problems("DefaultParseOptions")
+
+	if DefaultPageLimit < 1 {
+		errs = append(errs, &ConfigurationError{
+			Setting: "DefaultPageLimit",
+			Problem: fmt.Sprintf("must be positive, not %d", DefaultPageLimit),
+		})
+	}
+
+	if MaxPageLimit > 0 && DefaultPageLimit > MaxPageLimit {
+		errs = append(errs, &ConfigurationError{
+			Setting: "DefaultPageLimit",
+			Problem: fmt.Sprintf("%d exceeds MaxPageLimit %d, so requests without page[limit] would get oversized pages", DefaultPageLimit, MaxPageLimit),
+		})
+	}
+
+	for _, profile := range Profiles {
+		uri, err := url.Parse(profile)
+		if err != nil || !uri.IsAbs() || strings.ContainsAny(profile, ` "`) {
+			errs = append(errs, &ConfigurationError{
+				Setting: "Profiles",
+				Problem: fmt.Sprintf("'%s' is not an absolute URI, as profiles must be", profile),
+			})
+		}
+	}
+
+	// collection paths must be unique for routes and links to be unambiguous
+	paths := map[string]string{}
+	for _, resource := range registeredResources() {
+		path := urlBuilder().collectionPath(resource.Type)
+		if other, taken := paths[path]; taken {
+			errs = append(errs, &ConfigurationError{
+				Resource: resource.Type,
+				Setting:  "Type",
+				Problem:  fmt.Sprintf("shares the collection path %s with '%s', declare distinct URLs.Plurals or RegisterPath paths", path, other),
+			})
+		}
+		paths[path] = resource.Type
+
+		errs = append(errs, resource.problems()...)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return errs
+}
+
+/*
+Validate checks the options for settings that conflict, returning them as
+ConfigurationErrors, or nil if there are none. Use it for Parser.Options, the
+package level Validate checks DefaultParseOptions.
+*/
+func (o *ParseOptions) Validate() error {
+	errs := o.problems("ParseOptions")
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return errs
+}
+
+// problems lists the conflicting settings of the options
+func (o *ParseOptions) problems(name string) ConfigurationErrors {
+	errs := ConfigurationErrors{}
+
+	if o.ValidateID != nil && o.ClientIDs == ForbidClientIDs {
+		errs = append(errs, &ConfigurationError{
+			Setting: name + ".ValidateID",
+			Problem: "is never called, since ClientIDs forbids client generated IDs",
+		})
+	}
+
+	if o.GenerateID != nil && o.ClientIDs == RequireClientIDs {
+		errs = append(errs, &ConfigurationError{
+			Setting: name + ".GenerateID",
+			Problem: "is never called, since ClientIDs requires client generated IDs",
+		})
+	}
+
+	if _, known := memberNameModeNames[o.MemberNames]; !known {
+		errs = append(errs, &ConfigurationError{
+			Setting: name + ".MemberNames",
+			Problem: fmt.Sprintf("unknown mode %d", o.MemberNames),
+		})
+	}
+
+	if _, known := clientIDModeNames[o.ClientIDs]; !known {
+		errs = append(errs, &ConfigurationError{
+			Setting: name + ".ClientIDs",
+			Problem: fmt.Sprintf("unknown mode %d", o.ClientIDs),
+		})
+	}
+
+	return errs
+}
+
+// problems lists the conflicting settings of a resource declaration
+func (r *Resource) problems() ConfigurationErrors {
+	errs := ConfigurationErrors{}
+	problem := func(setting string, format string, args ...interface{}) {
+		errs = append(errs, &ConfigurationError{Resource: r.Type, Setting: setting, Problem: fmt.Sprintf(format, args...)})
+	}
+
+	strict := DefaultParseOptions.MemberNames == StrictMemberNames
+
+	for _, name := range sortedKeys(r.Relationships) {
+		relationship := r.Relationships[name]
+		setting := "Relationships." + name
+
+		if relationship.Type != "" && len(relationship.Types) > 0 {
+			problem(setting, "sets both Type and Types, set Types alone for polymorphic relationships")
+		}
+		if len(relationship.relatedTypes()) == 0 {
+			problem(setting, "declares no Type")
+		}
+		for _, relatedType := range relationship.relatedTypes() {
+			if Registered(relatedType) == nil {
+				problem(setting, "links to '%s', which isn't registered", relatedType)
+			}
+		}
+		if strict && !ValidMemberName(name) {
+			problem(setting, "isn't a valid member name, so DefaultParseOptions would reject it")
+		}
+		if r.Schema != nil && r.Schema.Attributes[name] != nil {
+			problem(setting, "is also declared as an attribute by Schema")
+		}
+	}
+
+	for _, name := range sortedKeys(r.Includes) {
+		if _, declared := r.Relationships[name]; !declared && len(r.Relationships) > 0 {
+			problem("Includes."+name, "resolves a relationship that isn't declared in Relationships")
+		}
+	}
+
+	for _, name := range sortedKeys(r.Computed) {
+		setting := "Computed." + name
+
+		if _, declared := r.Relationships[name]; declared {
+			problem(setting, "is also declared as a relationship")
+		}
+		if r.Schema != nil && r.Schema.Attributes[name] != nil && r.Schema.Attributes[name].Required {
+			problem(setting, "is required by Schema, but clients can't write computed attributes")
+		}
+		if strict && !ValidMemberName(name) {
+			problem(setting, "isn't a valid member name, so DefaultParseOptions would reject it")
+		}
+	}
+
+	if r.Schema != nil && !r.Schema.AdditionalAttributes {
+		for _, name := range sortedKeys(r.Deprecated) {
+			_, isRelationship := r.Relationships[name]
+			_, isComputed := r.Computed[name]
+			if r.Schema.Attributes[name] == nil && !isRelationship && !isComputed {
+				problem("Deprecated."+name, "isn't an attribute, computed attribute, or relationship of the resource")
+			}
+		}
+	}
+
+	if r.MetaOnly {
+		if r.Schema != nil && len(r.Schema.Attributes) > 0 {
+			problem("MetaOnly", "resources don't accept attributes, but Schema declares some")
+		}
+		if r.Limits != nil {
+			problem("MetaOnly", "resources don't accept attributes, so Limits never apply")
+		}
+		if len(r.Computed) > 0 {
+			problem("MetaOnly", "resources carry no attributes, but Computed adds some")
+		}
+	}
+
+	if r.Deprecation != nil && r.Deprecation.Replacement != "" {
+		if r.Deprecation.Replacement == r.Type {
+			problem("Deprecation.Replacement", "can't be the deprecated type itself")
+		} else if Registered(r.Deprecation.Replacement) == nil {
+			problem("Deprecation.Replacement", "'%s' isn't registered", r.Deprecation.Replacement)
+		}
+	}
+
+	if r.Externalization != nil {
+		if r.Externalization.Store == nil {
+			problem("Externalization.Store", "must be set to externalize attributes")
+		}
+		if r.Externalization.Threshold < 0 {
+			problem("Externalization.Threshold", "must not be negative")
+		}
+		for _, name := range r.Externalization.Attributes {
+			if _, isRelationship := r.Relationships[name]; isRelationship {
+				problem("Externalization.Attributes", "'%s' is a relationship, only attributes can be externalized", name)
+			}
+		}
+	}
+
+	return errs
+}
+
+// sortedKeys returns the keys of a map with string keys, sorted
+func sortedKeys(m interface{}) []string {
+	keys := []string{}
+	switch typed := m.(type) {
+	case map[string]RelationshipDeclaration:
+		for key := range typed {
+			keys = append(keys, key)
+		}
+	case map[string]IncludeResolver:
+		for key := range typed {
+			keys = append(keys, key)
+		}
+	case map[string]ComputedAttribute:
+		for key := range typed {
+			keys = append(keys, key)
+		}
+	case map[string]string:
+		for key := range typed {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	return keys
+}
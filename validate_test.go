@@ -0,0 +1,100 @@
+package jsh
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestValidate(t *testing.T) {
+
+	Convey("Configuration Validation Tests", t, func() {
+
+		Convey("should accept a consistent configuration", func() {
+			Register(&Resource{
+				Type:          "posts",
+				Relationships: map[string]RelationshipDeclaration{"author": {Type: "writers", Cardinality: ToOne}},
+			})
+			Register(&Resource{Type: "writers"})
+			Reset(func() {
+				Unregister("posts")
+				Unregister("writers")
+			})
+
+			So(Validate(), ShouldBeNil)
+		})
+
+		Convey("should list every problem", func() {
+			Register(&Resource{
+				Type:          "posts",
+				Relationships: map[string]RelationshipDeclaration{"author": {Type: "writers", Cardinality: ToOne}},
+				Includes:      map[string]IncludeResolver{"tags": nil},
+				Computed:      map[string]ComputedAttribute{"summary": nil},
+				Schema: &Schema{Attributes: map[string]*AttributeSchema{
+					"summary": {Type: StringAttribute, Required: true},
+				}},
+				Deprecation: &TypeDeprecation{Replacement: "articles"},
+			})
+			Register(&Resource{Type: "permissions", MetaOnly: true, Limits: &AttributeLimits{}})
+			Reset(func() {
+				Unregister("posts")
+				Unregister("permissions")
+			})
+
+			err := Validate()
+			So(err, ShouldNotBeNil)
+
+			errs := err.(ConfigurationErrors)
+			So(len(errs), ShouldEqual, 5)
+			So(errs[0].Resource, ShouldEqual, "permissions")
+			So(errs[0].Setting, ShouldEqual, "MetaOnly")
+			So(errs[1].Setting, ShouldEqual, "Relationships.author")
+			So(errs[1].Problem, ShouldEqual, "links to 'writers', which isn't registered")
+			So(errs[2].Setting, ShouldEqual, "Includes.tags")
+			So(errs[3].Setting, ShouldEqual, "Computed.summary")
+			So(errs[4].Setting, ShouldEqual, "Deprecation.Replacement")
+
+			So(err.Error(), ShouldStartWith, "jsh: 5 configuration problem(s):")
+			So(err.Error(), ShouldContainSubstring, "resource 'posts' Relationships.author: links to 'writers'")
+		})
+
+		Convey("should check package level settings", func() {
+			limit := DefaultPageLimit
+			DefaultPageLimit = MaxPageLimit + 1
+			Profiles = []string{"not a uri"}
+			Reset(func() {
+				DefaultPageLimit = limit
+				Profiles = nil
+			})
+
+			errs := Validate().(ConfigurationErrors)
+			So(len(errs), ShouldEqual, 2)
+			So(errs[0].Setting, ShouldEqual, "DefaultPageLimit")
+			So(errs[1].Setting, ShouldEqual, "Profiles")
+		})
+
+		Convey("should reject types sharing a collection path", func() {
+			URLs = &URLBuilder{Plurals: map[string]string{"person": "people"}}
+			Register(&Resource{Type: "person"})
+			Register(&Resource{Type: "people"})
+			Reset(func() {
+				URLs = nil
+				Unregister("person")
+				Unregister("people")
+			})
+
+			errs := Validate().(ConfigurationErrors)
+			So(len(errs), ShouldEqual, 1)
+			So(errs[0].Resource, ShouldEqual, "person")
+		})
+
+		Convey("->ParseOptions.Validate()", func() {
+			options := &ParseOptions{ClientIDs: ForbidClientIDs, ValidateID: UUIDValidator}
+			So(options.Validate(), ShouldNotBeNil)
+			So(StrictParseOptions.Validate(), ShouldBeNil)
+
+			options = &ParseOptions{ClientIDs: RequireClientIDs, GenerateID: UUIDv4Generator}
+			So(options.Validate(), ShouldNotBeNil)
+		})
+	})
+}